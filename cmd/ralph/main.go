@@ -2,13 +2,20 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/log"
 
 	"github.com/vx/ralph-go/internal/auto"
+	"github.com/vx/ralph-go/internal/doctor"
 	ralphInit "github.com/vx/ralph-go/internal/init"
+	"github.com/vx/ralph-go/internal/live"
+	"github.com/vx/ralph-go/internal/runner"
+	"github.com/vx/ralph-go/internal/state"
 	"github.com/vx/ralph-go/internal/status"
 	"github.com/vx/ralph-go/internal/tui"
 	"github.com/vx/ralph-go/internal/tui/layout"
@@ -17,8 +24,49 @@ import (
 func main() {
 	log.SetLevel(log.DebugLevel)
 
+	logPrompts = removeFlag("--log-prompts")
+	checkoutBase = removeFlag("--checkout-base")
+	resumeOnRetry = removeFlag("--resume-on-retry")
+	var err error
+	if prdDirFlag, err = removeValueFlag("--prd-dir"); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		os.Exit(1)
+	}
+	if followTolerance, err = parseFollowTolerance(); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		os.Exit(1)
+	}
+	if idleWarning, err = parseIdleWarning(); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		os.Exit(1)
+	}
+	if autoResponses, err = parseAutoResponses(); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		os.Exit(1)
+	}
+	if verifyCommand, err = removeValueFlag("--verify"); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		os.Exit(1)
+	}
+	if runMeta, err = parseRunMeta(); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		os.Exit(1)
+	}
+	if clipLimits, err = parseMaxLineLength(); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		os.Exit(1)
+	}
+	if scanBufferSize, err = parseScanBuffer(); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		os.Exit(1)
+	}
+	if maxSpawnsPerRoot, maxTotalSpawns, err = parseMaxSpawns(); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		os.Exit(1)
+	}
+
 	if len(os.Args) < 2 {
-		if auto.PRDDirExists() {
+		if hasPRDDir() {
 			runTUIManifest()
 		} else {
 			printUsage()
@@ -34,14 +82,14 @@ func main() {
 		fmt.Println(layout.AppName + " " + layout.AppVersion)
 		os.Exit(0)
 	case "--headless":
-		if auto.PRDDirExists() {
+		if hasPRDDir() {
 			runAuto()
 		} else {
 			fmt.Println("Error: PRD/ directory not found. Run 'ralph init PRD.md' first.")
 			os.Exit(1)
 		}
 	case "run":
-		if auto.PRDDirExists() {
+		if hasPRDDir() {
 			runAuto()
 		} else {
 			fmt.Println("Error: PRD/ directory not found. Run 'ralph init PRD.md' first.")
@@ -51,6 +99,12 @@ func main() {
 		runInit()
 	case "status":
 		runStatus()
+	case "logs":
+		runLogs()
+	case "attach":
+		runAttach()
+	case "doctor":
+		runDoctor()
 	case "help":
 		if len(os.Args) > 2 {
 			printCommandHelp(os.Args[2])
@@ -63,41 +117,516 @@ func main() {
 	}
 }
 
+// logPrompts is set by the global --log-prompts flag
+var logPrompts bool
+
+// checkoutBase is set by the global --checkout-base flag
+var checkoutBase bool
+
+// resumeOnRetry is set by the global --resume-on-retry flag
+var resumeOnRetry bool
+
+// prdDirFlag is set by the global --prd-dir flag, overriding PRD/ discovery
+var prdDirFlag string
+
+// followTolerance is set by the global --follow-tolerance flag
+var followTolerance int
+
+// parseFollowTolerance removes --follow-tolerance from os.Args and returns
+// its value: 0 for the default, or negative for "0", which resumes following
+// only at the bottom itself
+func parseFollowTolerance() (int, error) {
+	value, err := removeValueFlag("--follow-tolerance")
+	if err != nil || value == "" {
+		return 0, err
+	}
+	lines, err := strconv.Atoi(value)
+	if err != nil || lines < 0 {
+		return 0, fmt.Errorf("invalid --follow-tolerance %q: must be a number of lines", value)
+	}
+	if lines == 0 {
+		return -1, nil
+	}
+	return lines, nil
+}
+
+// idleWarning is set by the global --idle-warning flag
+var idleWarning time.Duration
+
+// parseIdleWarning removes --idle-warning from os.Args and returns its value:
+// 0 for the default, or negative for "0" or "off", which turn warnings off
+func parseIdleWarning() (time.Duration, error) {
+	value, err := removeValueFlag("--idle-warning")
+	if err != nil || value == "" {
+		return 0, err
+	}
+	if value == "0" || value == "off" {
+		return -1, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid --idle-warning %q: must be a duration like 2m, or off", value)
+	}
+	return d, nil
+}
+
+// autoResponses are set by the global --auto-respond flags
+var autoResponses []runner.AutoResponse
+
+// parseAutoResponses removes every --auto-respond PATTERN=RESPONSE from
+// os.Args and returns them in the order given
+func parseAutoResponses() ([]runner.AutoResponse, error) {
+	specs, err := removeValueFlags("--auto-respond")
+	if err != nil {
+		return nil, err
+	}
+	var responses []runner.AutoResponse
+	for _, spec := range specs {
+		resp, err := runner.ParseAutoResponse(spec)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, resp)
+	}
+	return responses, nil
+}
+
+// verifyCommand is set by the global --verify flag
+var verifyCommand string
+
+// clipLimits are set by the global --max-line-length flag
+var clipLimits runner.ClipLimits
+
+// parseMaxLineLength removes --max-line-length from os.Args and returns how
+// much of each output line the inspect view shows: the defaults when it isn't
+// given, or everything for "0" or "off"
+func parseMaxLineLength() (runner.ClipLimits, error) {
+	value, err := removeValueFlag("--max-line-length")
+	if err != nil || value == "" {
+		return runner.ClipLimits{}, err
+	}
+	if value == "0" || value == "off" {
+		return runner.ClipLimits{Assistant: -1, ToolResult: -1}, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return runner.ClipLimits{}, fmt.Errorf("invalid --max-line-length %q: must be a number of characters, or off", value)
+	}
+	return runner.ClipLimits{Assistant: n, ToolResult: n}, nil
+}
+
+// scanBufferSize is set by the global --scan-buffer flag
+var scanBufferSize int
+
+// parseScanBuffer removes --scan-buffer from os.Args and returns the longest
+// output line read, in bytes, with an optional K or M suffix, or 0 for the
+// default when it isn't given
+func parseScanBuffer() (int, error) {
+	value, err := removeValueFlag("--scan-buffer")
+	if err != nil || value == "" {
+		return 0, err
+	}
+	multiplier := 1
+	number := value
+	switch {
+	case strings.HasSuffix(strings.ToUpper(value), "K"):
+		multiplier, number = 1024, value[:len(value)-1]
+	case strings.HasSuffix(strings.ToUpper(value), "M"):
+		multiplier, number = 1024*1024, value[:len(value)-1]
+	}
+	n, err := strconv.Atoi(number)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid --scan-buffer %q: must be a number of bytes, such as 4M", value)
+	}
+	return n * multiplier, nil
+}
+
+// maxSpawnsPerRoot and maxTotalSpawns are set by the global --max-spawns
+// flag
+var maxSpawnsPerRoot, maxTotalSpawns int
+
+// parseMaxSpawns removes --max-spawns N[,TOTAL] from os.Args and returns the
+// caps on sub-features spawned under one root feature and overall: 0 for
+// the defaults when it isn't given, or -1 for no cap with "off"
+func parseMaxSpawns() (perRoot, total int, err error) {
+	value, err := removeValueFlag("--max-spawns")
+	if err != nil || value == "" {
+		return 0, 0, err
+	}
+	if value == "0" || value == "off" {
+		return -1, -1, nil
+	}
+	invalid := fmt.Errorf("invalid --max-spawns %q: must be N or N,TOTAL, such as 10,50, or off", value)
+	first, second, hasTotal := strings.Cut(value, ",")
+	if perRoot, err = strconv.Atoi(first); err != nil || perRoot <= 0 {
+		return 0, 0, invalid
+	}
+	if hasTotal {
+		if total, err = strconv.Atoi(second); err != nil || total <= 0 {
+			return 0, 0, invalid
+		}
+	}
+	return perRoot, total, nil
+}
+
+// runMeta is set by the global --meta flags
+var runMeta map[string]string
+
+// parseRunMeta removes every --meta key=value from os.Args and returns the
+// pairs; a repeated key keeps its last value
+func parseRunMeta() (map[string]string, error) {
+	specs, err := removeValueFlags("--meta")
+	if err != nil || len(specs) == 0 {
+		return nil, err
+	}
+	meta := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		key, value, err := state.ParseRunMeta(spec)
+		if err != nil {
+			return nil, err
+		}
+		meta[key] = value
+	}
+	return meta, nil
+}
+
+// hasPRDDir reports whether a PRD directory was given or exists in the
+// current directory
+func hasPRDDir() bool {
+	return prdDirFlag != "" || auto.PRDDirExists()
+}
+
+// removeFlag reports whether a boolean flag was given, removing it from os.Args
+// so the remaining arguments parse as before
+func removeFlag(flag string) bool {
+	found := false
+	args := os.Args[:1]
+	for _, arg := range os.Args[1:] {
+		if arg == flag {
+			found = true
+			continue
+		}
+		args = append(args, arg)
+	}
+	os.Args = args
+	return found
+}
+
+// removeValueFlag returns the value of a flag given as "--flag value" or
+// "--flag=value", removing it from os.Args
+func removeValueFlag(flag string) (string, error) {
+	values, err := removeValueFlags(flag)
+	if err != nil || len(values) == 0 {
+		return "", err
+	}
+	return values[len(values)-1], nil
+}
+
+// removeValueFlags is removeValueFlag for a flag that may be repeated,
+// returning every value in order
+func removeValueFlags(flag string) ([]string, error) {
+	var values []string
+	args := os.Args[:1]
+	for i := 1; i < len(os.Args); i++ {
+		arg := os.Args[i]
+		switch {
+		case arg == flag:
+			if i+1 >= len(os.Args) {
+				return nil, fmt.Errorf("%s requires a value", flag)
+			}
+			i++
+			values = append(values, os.Args[i])
+		case strings.HasPrefix(arg, flag+"="):
+			values = append(values, strings.TrimPrefix(arg, flag+"="))
+		default:
+			args = append(args, arg)
+		}
+	}
+	os.Args = args
+	return values, nil
+}
+
 func runAuto() {
-	result, err := auto.Run()
+	opts, err := parseRunOptions(os.Args[2:])
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		os.Exit(1)
+	}
+	opts.LogPrompts = logPrompts
+	opts.CheckoutBase = checkoutBase
+	opts.PRDDir = prdDirFlag
+	opts.AutoResponses = autoResponses
+	opts.VerifyCommand = verifyCommand
+	opts.RunMeta = runMeta
+	opts.ScanBufferSize = scanBufferSize
+
+	results, err := auto.RunWithOptions(opts)
 	if err != nil {
 		log.Error("Auto run failed", "error", err)
+		auto.PrintSummaries(results)
+		if opts.CIAnnotations {
+			auto.PrintAnnotations(opts, results)
+		}
 		fmt.Printf("\nError: %s\n", err)
 		os.Exit(1)
 	}
 
-	auto.PrintSummary(result)
-	os.Exit(auto.ExitCode(result))
+	auto.PrintSummaries(results)
+	if opts.CIAnnotations {
+		auto.PrintAnnotations(opts, results)
+	}
+	os.Exit(auto.RunPostRun(opts, results, auto.ExitCodeAll(results)))
+}
+
+func parseRunOptions(args []string) (auto.Options, error) {
+	opts := auto.Options{Count: 1}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--count" || arg == "-n":
+			if i+1 >= len(args) {
+				return opts, fmt.Errorf("%s requires a value", arg)
+			}
+			i++
+			count, err := strconv.Atoi(args[i])
+			if err != nil || count < 1 {
+				return opts, fmt.Errorf("invalid count %q: must be a positive integer", args[i])
+			}
+			opts.Count = count
+		case strings.HasPrefix(arg, "--count="):
+			value := strings.TrimPrefix(arg, "--count=")
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return opts, fmt.Errorf("invalid count %q: must be a positive integer", value)
+			}
+			opts.Count = count
+		case arg == "--fail-fast":
+			opts.FailFast = true
+		case arg == "--open-editor-on-fail":
+			opts.OpenEditorOnFail = true
+		case arg == "--parallel-roots":
+			opts.ParallelRoots = true
+		case arg == "--ci-annotations":
+			opts.CIAnnotations = true
+		case arg == "--explain":
+			opts.Explain = true
+		case arg == "--keep-going-on-budget":
+			opts.KeepGoingOnBudget = true
+		case arg == "--timeout":
+			if i+1 >= len(args) {
+				return opts, fmt.Errorf("%s requires a value", arg)
+			}
+			i++
+			timeout, err := parseTimeout(args[i])
+			if err != nil {
+				return opts, err
+			}
+			opts.Timeout = timeout
+		case strings.HasPrefix(arg, "--timeout="):
+			timeout, err := parseTimeout(strings.TrimPrefix(arg, "--timeout="))
+			if err != nil {
+				return opts, err
+			}
+			opts.Timeout = timeout
+		case arg == "--group":
+			if i+1 >= len(args) {
+				return opts, fmt.Errorf("%s requires a value", arg)
+			}
+			i++
+			opts.Group = args[i]
+		case strings.HasPrefix(arg, "--group="):
+			opts.Group = strings.TrimPrefix(arg, "--group=")
+		case arg == "--post-run":
+			if i+1 >= len(args) {
+				return opts, fmt.Errorf("%s requires a value", arg)
+			}
+			i++
+			opts.PostRun = args[i]
+		case strings.HasPrefix(arg, "--post-run="):
+			opts.PostRun = strings.TrimPrefix(arg, "--post-run=")
+		case arg == "--since-commit":
+			if i+1 >= len(args) {
+				return opts, fmt.Errorf("%s requires a value", arg)
+			}
+			i++
+			opts.SinceCommit = args[i]
+		case strings.HasPrefix(arg, "--since-commit="):
+			opts.SinceCommit = strings.TrimPrefix(arg, "--since-commit=")
+		case arg == "--cost-csv":
+			if i+1 >= len(args) {
+				return opts, fmt.Errorf("%s requires a value", arg)
+			}
+			i++
+			opts.CostCSV = args[i]
+		case strings.HasPrefix(arg, "--cost-csv="):
+			opts.CostCSV = strings.TrimPrefix(arg, "--cost-csv=")
+		}
+	}
+
+	return opts, nil
+}
+
+func parseTimeout(value string) (time.Duration, error) {
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q: must be a positive duration like 30m", value)
+	}
+	return timeout, nil
 }
 
 func runStatus() {
-	if err := status.Run(); err != nil {
+	estimate := false
+	tree := false
+	for _, arg := range os.Args[2:] {
+		switch arg {
+		case "--estimate":
+			estimate = true
+		case "--tree":
+			tree = true
+		}
+	}
+
+	if err := status.Run(prdDirFlag, estimate, tree); err != nil {
 		log.Fatal("Status failed", "error", err)
 	}
 }
 
+// logsPollInterval is how often 'ralph logs --follow' checks for new output
+const logsPollInterval = 500 * time.Millisecond
+
+// followReader reads a log that is still being written, waiting for more
+// output at the end instead of returning io.EOF
+type followReader struct {
+	r io.Reader
+}
+
+func (f followReader) Read(p []byte) (int, error) {
+	for {
+		n, err := f.r.Read(p)
+		if n > 0 || err != io.EOF {
+			return n, err
+		}
+		time.Sleep(logsPollInterval)
+	}
+}
+
+func runLogs() {
+	follow := false
+	var featureID string
+	for _, arg := range os.Args[2:] {
+		if arg == "--follow" || arg == "-f" {
+			follow = true
+		} else if !strings.HasPrefix(arg, "-") && featureID == "" {
+			featureID = arg
+		}
+	}
+	if featureID == "" {
+		fmt.Println("Error: ralph logs requires a feature ID")
+		os.Exit(1)
+	}
+
+	prdDir, err := auto.ResolvePRDDir(prdDirFlag)
+	if err != nil {
+		log.Fatal("Failed to find PRD directory", "error", err)
+	}
+	path := runner.OutputLogPath(auto.WorkDir(prdDir), featureID)
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			fmt.Printf("Error: no output log for feature %s (%s)\n", featureID, path)
+			os.Exit(1)
+		}
+		log.Fatal("Failed to open output log", "error", err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if follow {
+		r = followReader{r: f}
+	}
+	if err := runner.FormatLog(r, os.Stdout, runner.LogOptions{ScanBufferSize: scanBufferSize, Clip: clipLimits}); err != nil {
+		log.Fatal("Failed to read output log", "error", err)
+	}
+}
+
+func runDoctor() {
+	if failed := doctor.Run(prdDirFlag, os.Stdout); failed {
+		os.Exit(1)
+	}
+}
+
+func runAttach() {
+	prdDir, err := auto.ResolvePRDDir(prdDirFlag)
+	if err != nil {
+		log.Fatal("Failed to find PRD directory", "error", err)
+	}
+
+	status, err := live.Read(auto.WorkDir(prdDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			fmt.Printf("Error: no headless run to attach to (%s not found)\n", live.StatusFile)
+			os.Exit(1)
+		}
+		log.Fatal("Failed to read live status", "error", err)
+	}
+	if !status.Active(time.Now()) {
+		fmt.Printf("Error: no headless run is active. %s\n", status.Describe(time.Now()))
+		os.Exit(1)
+	}
+
+	if err := tui.RunAttach(prdDir, tui.Options{FollowTolerance: followTolerance, ClipLimits: clipLimits, ScanBufferSize: scanBufferSize}); err != nil {
+		log.Fatal("Error running TUI", "error", err)
+	}
+}
+
 func runInit() {
 	force := false
+	prune := false
 	var prdPath string
 
+	fromDir, err := removeValueFlag("--from-dir")
+	if err != nil {
+		fmt.Printf("Error: %s\n", err)
+		os.Exit(1)
+	}
+
 	for _, arg := range os.Args[2:] {
 		if arg == "--force" || arg == "-f" {
 			force = true
+		} else if arg == "--prune" {
+			prune = true
 		} else if !strings.HasPrefix(arg, "-") && prdPath == "" {
 			prdPath = arg
 		}
 	}
 
+	if fromDir != "" {
+		fmt.Printf("Scanning %s for a starter PRD...\n", fromDir)
+		path, err := ralphInit.InitFromDir(fromDir, force)
+		if err != nil {
+			log.Fatal("Init failed", "error", err)
+		}
+		fmt.Printf("  Created %s\n", path)
+		fmt.Println()
+		fmt.Println("Edit the candidate features, then run 'ralph init PRD.md' to create the PRD/ directory.")
+		return
+	}
+
 	if prdPath != "" {
 		if _, err := os.Stat(prdPath); os.IsNotExist(err) {
 			log.Fatal("PRD file not found", "path", prdPath)
 		}
 
+		if prune {
+			fmt.Printf("Removing features no longer in %s...\n", prdPath)
+			fmt.Println()
+			if err := ralphInit.PruneFromPRD(prdPath); err != nil {
+				log.Fatal("Prune failed", "error", err)
+			}
+			return
+		}
+
 		fmt.Printf("Initializing PRD directory structure from %s...\n", prdPath)
 		fmt.Println()
 
@@ -119,12 +648,12 @@ func runInit() {
 }
 
 func runTUIManifest() {
-	prdDir, err := auto.FindPRDDir()
+	prdDir, err := auto.ResolvePRDDir(prdDirFlag)
 	if err != nil {
 		log.Fatal("Failed to find PRD directory", "error", err)
 	}
 
-	if err := tui.RunWithManifest(prdDir); err != nil {
+	if err := tui.RunWithManifest(prdDir, tui.Options{LogPrompts: logPrompts, CheckoutBase: checkoutBase, ResumeOnRetry: resumeOnRetry, FollowTolerance: followTolerance, IdleWarning: idleWarning, AutoResponses: autoResponses, VerifyCommand: verifyCommand, RunMeta: runMeta, ClipLimits: clipLimits, ScanBufferSize: scanBufferSize, MaxSpawnsPerRoot: maxSpawnsPerRoot, MaxTotalSpawns: maxTotalSpawns}); err != nil {
 		log.Fatal("Error running TUI", "error", err)
 	}
 }
@@ -134,11 +663,17 @@ func runTUI(prdPath string) {
 		log.Fatal("PRD file not found", "path", prdPath)
 	}
 
+	// An explicit --prd-dir always uses manifest mode
+	if prdDirFlag != "" {
+		runTUIManifest()
+		return
+	}
+
 	// Check if PRD/ directory exists - if so, use manifest mode
 	if auto.PRDDirExists() {
 		prdDir, err := auto.FindPRDDir()
 		if err == nil {
-			if err := tui.RunWithManifest(prdDir); err != nil {
+			if err := tui.RunWithManifest(prdDir, tui.Options{LogPrompts: logPrompts, CheckoutBase: checkoutBase, ResumeOnRetry: resumeOnRetry, FollowTolerance: followTolerance, IdleWarning: idleWarning, AutoResponses: autoResponses, VerifyCommand: verifyCommand, RunMeta: runMeta, ClipLimits: clipLimits, ScanBufferSize: scanBufferSize, MaxSpawnsPerRoot: maxSpawnsPerRoot, MaxTotalSpawns: maxTotalSpawns}); err != nil {
 				log.Fatal("Error running TUI", "error", err)
 			}
 			return
@@ -146,7 +681,7 @@ func runTUI(prdPath string) {
 	}
 
 	// Legacy mode - parse PRD file directly
-	if err := tui.Run(prdPath); err != nil {
+	if err := tui.Run(prdPath, tui.Options{LogPrompts: logPrompts, CheckoutBase: checkoutBase, ResumeOnRetry: resumeOnRetry, FollowTolerance: followTolerance, IdleWarning: idleWarning, AutoResponses: autoResponses, VerifyCommand: verifyCommand, RunMeta: runMeta, ClipLimits: clipLimits, ScanBufferSize: scanBufferSize, MaxSpawnsPerRoot: maxSpawnsPerRoot, MaxTotalSpawns: maxTotalSpawns}); err != nil {
 		log.Fatal("Error running TUI", "error", err)
 	}
 }
@@ -159,6 +694,7 @@ Usage:
   ralph run               Run next feature headless and exit
   ralph <PRD.md>          Run TUI (uses PRD/ if exists, else legacy mode)
   ralph status            Show current PRD progress
+  ralph logs <id>         Show a feature's stored output
   ralph init <PRD.md>     Create PRD/ directory structure from PRD file
   ralph help [command]    Show help for a command
 
@@ -176,17 +712,40 @@ func printHelp() {
 Usage:
   ralph                         Run TUI (requires PRD/ directory)
   ralph run                     Run next feature headless and exit
+  ralph run --count N           Run up to N features headless and exit
+  ralph run --fail-fast         Stop at the first failed feature
+  ralph run --timeout D         Stop a feature that runs longer than D
+  ralph run --group NAME        Only run features in the named epic/group
+  ralph run --since-commit REF  Only run features whose Files: changed since REF
+  ralph run --parallel-roots    Run runnable features concurrently
+  ralph run --post-run CMD      Run CMD after the run finishes
+  ralph run --ci-annotations    Print GitHub Actions annotations for failures
+  ralph run --cost-csv PATH     Write each attempt's tokens and cost to a CSV
+  ralph run --explain           Show each feature's model and why it was chosen
+  ralph run --keep-going-on-budget
+                                Keep starting features past 90% of the PRD budget
+  ralph run --open-editor-on-fail
+                                Open a failed feature's spec and error log in $EDITOR
   ralph --headless              Same as 'ralph run'
   ralph <PRD.md>                Run TUI (uses PRD/ if exists, else legacy mode)
-  ralph status                  Show current PRD progress
+  ralph status [--estimate]     Show current PRD progress (and projected cost)
+  ralph status --tree           Show features as a tree of sub-features with cost
+  ralph logs <id> [--follow]    Show (and tail) a feature's stored output
+  ralph attach                  Follow a running 'ralph run' in a read-only TUI
   ralph init [--force]          Initialize a new ralph project in current directory
   ralph init <PRD.md> [--force] Create PRD/ directory structure from PRD file
+  ralph init <dir/> [--force]   Same, merging the directory's .md files in name order
+  ralph init <PRD.md> --prune   Remove features no longer in the PRD from PRD/
+  ralph init --from-dir DIR     Generate a starter PRD.md from an existing project
   ralph help [command]          Show help for a command
 
 Commands:
   (no args)   Run TUI if PRD/ exists, otherwise show usage
   run         Run next pending feature headless and exit
   status      Show feature status, dependencies, and progress summary
+  logs        Show a feature's output from .ralph/logs/
+  attach      Watch a headless run from another terminal
+  doctor      Check that claude, the PRD directory and config are ready
   init        Create project files, or generate PRD/ directory from PRD file
   help        Show help for a command
 
@@ -194,6 +753,44 @@ Options:
   -h, --help      Show this help message
   -v, --version   Show version
   --headless      Run headless mode (same as 'ralph run')
+  --log-prompts   Write the full prompt of every attempt to .ralph/prompts/
+  --prd-dir DIR   Use DIR as the PRD directory instead of ./PRD
+  --checkout-base Run 'git checkout' of a feature's Base: before it starts;
+                  the feature fails to start while others are running
+  --resume-on-retry
+                  Retrying with 'r' continues the feature's last Claude
+                  session (claude --resume) instead of starting cold
+  --follow-tolerance N
+                  In the inspect view, scrolling down to within N lines of
+                  the bottom resumes following output (default 2; 0 to
+                  resume only at the bottom itself)
+  --idle-warning D
+                  Flag a running feature as idle in the task list after D
+                  without output (default 2m; 0 or off to disable)
+  --auto-respond PATTERN=RESPONSE
+                  Stopgap for tools that ask for approval despite
+                  --dangerously-skip-permissions: when a line of output
+                  matches the regular expression PATTERN, write RESPONSE
+                  and a newline to claude's stdin. Repeat for more prompts;
+                  stdin is closed once claude reports its result.
+  --verify CMD    Run CMD through sh (e.g. 'go test ./...') after a feature
+                  exits cleanly; if it fails, the feature is marked failed
+                  even though Claude reported success
+  --meta KEY=VALUE
+                  Tag the run with metadata, such as git_sha or ticket,
+                  recorded in progress.json and shown by 'ralph status'.
+                  Repeat for more pairs.
+  --max-line-length N
+                  In the inspect view, 'ralph logs' and 'ralph attach', clip
+                  assistant text and tool results to N characters (default
+                  200 and 500; 0 or off to show them in full). Press F in
+                  the inspect view to see clipped lines in full.
+  --scan-buffer N Read output lines of up to N bytes, with an optional K or
+                  M suffix (default 1M). Longer lines, such as a huge tool
+                  result, are skipped with a warning.
+  --max-spawns N[,TOTAL]
+                  Let a feature spawn at most N sub-features, and the run
+                  TOTAL (default 20,100; 0 or off for no cap)
 
 Workflow:
 
@@ -205,22 +802,39 @@ Workflow:
 Headless Mode (ralph run):
   Finds the next runnable feature (respecting dependencies), runs it to
   completion, and exits. Useful for CI/CD or scripted execution.
+  With --count N (-n N), keeps going until N features have completed or
+  failed, or no runnable feature remains. With --fail-fast, stops at the
+  first feature that fails. A failed feature is run again up to the PRD's
+  Retries: times first.
 
   Exit codes:
-    0 = Feature completed successfully, or no work to do
-    1 = Feature failed
+    0 = All features completed successfully, or no work to do
+    1 = A feature failed, or progress could not be saved
+    2 = A feature exceeded its budget, or the PRD budget stopped the run
+    3 = Dependencies are invalid (e.g. a cycle)
+    4 = A feature ran past --timeout
+    5 = Features succeeded but the post-run command failed
 
 TUI Controls:
   j/k or ↑/↓    Navigate features
   Space         Expand/collapse child features
+  z/Z           Collapse/expand all features
+  n/N           Jump to next/previous failed feature
   Enter         Inspect running instance output
   s             Start selected feature
   S             Start ALL (auto mode)
   r             Retry failed/completed feature
   R             Reset feature (clear attempts)
   x             Stop running feature
+  Ctrl+x        Stop and reset feature in one step
   X             Stop ALL (exit auto mode)
+  m             Cycle pending feature's model
+  e             Edit feature notes in $EDITOR
+  i             Show dependency chain with statuses
   c             Toggle cost display
+  f             Filter activity to selected feature
+  l             Toggle status legend
+  o             Cycle task list sort order
   ?             Show help
   q             Quit (saves progress)
 
@@ -229,6 +843,7 @@ Inspect View:
   g/G           Top/bottom
   f             Follow mode (auto-scroll)
   a             Toggle action timeline
+  1-9           Check a task off, or back on
   Esc           Back to main view
 
 For more information, see the README.md file.`)
@@ -240,32 +855,171 @@ func printCommandHelp(cmd string) {
 		fmt.Println(`ralph status - Show current PRD progress
 
 Usage:
-  ralph status
+  ralph status [--estimate] [--tree]
+
+Options:
+  --estimate   Project the input cost of each remaining feature's prompt
+               (~4 chars per token at the model's input price)
+  --tree       Draw features as a tree, with spawned sub-features nested
+               under their parent and each node's status and cost
 
 Displays a formatted overview of all features in the PRD/ directory including:
-  - Feature status (pending, running, completed, failed, blocked)
+  - Feature status (pending, running, completed, failed, blocked, interrupted)
   - Dependencies for each feature
   - Which dependencies are pending for blocked features
   - Summary counts of all feature states
+  - Estimated cost per model recorded in progress.json, with features
+    that switched models split by how long each model ran
+
+Features that progress.json still records as running are shown as
+interrupted: they were left behind by a run that crashed or was killed.
+Opening ralph offers to reset them to pending.
 
 Status icons:
   ✓  Completed
   ●  Running
+  ■  Interrupted
   ✗  Failed
   ○  Pending (ready to run)
   ◌  Blocked (waiting on dependencies)`)
+	case "logs":
+		fmt.Println(`ralph logs - Show a feature's stored output
+
+Usage:
+  ralph logs <featureID> [--follow]
+
+Every run writes Claude's raw stream-json output for a feature to
+.ralph/logs/<featureID>.jsonl in the PRD directory's parent, replacing the
+log of the previous attempt.
+This command prints that log formatted like the TUI's inspect view.
+
+Options:
+  -f, --follow   Keep printing new output as the feature writes it
+                 (Ctrl+C to stop)`)
+	case "attach":
+		fmt.Println(`ralph attach - Follow a running headless run
+
+Usage:
+  ralph attach
+
+While 'ralph run' works it keeps .ralph/live.json up to date with its pid,
+the features it is running and how many have finished. This command opens
+the TUI on that run, re-reading the manifest, the live status and the
+inspected feature's output log every second.
+
+The TUI is read-only: keys that start, stop, retry, reset or edit features
+are disabled, and q detaches without affecting the run. Run it from the
+directory 'ralph run' was started in.`)
+	case "doctor":
+		fmt.Println(`ralph doctor - Check the environment before a run
+
+Usage:
+  ralph doctor
+
+Runs preflight checks and prints a line for each:
+  - claude is on PATH
+  - claude is logged in (ANTHROPIC_API_KEY or stored credentials; a
+    warning only, since credentials may be in the system keychain)
+  - the PRD directory has a valid manifest and a feature.md per feature
+  - dependencies have no cycles and refer to known features
+  - the manifest's settings and progress.json are well-formed, and every
+    feature's model has pricing
+  - git is on PATH when a feature sets Base:
+
+Exit codes:
+  0 = All checks passed (warnings allowed)
+  1 = A check failed`)
+	case "run":
+		fmt.Println(`ralph run - Run features headless and exit
+
+Usage:
+  ralph run [--count N] [--fail-fast] [--timeout D] [--group NAME]
+            [--open-editor-on-fail] [--parallel-roots] [--post-run CMD]
+            [--since-commit REF] [--ci-annotations] [--cost-csv PATH]
+            [--explain] [--keep-going-on-budget]
+
+Finds the next runnable feature (respecting dependencies), runs it to
+completion, and exits. Useful for CI/CD or scripted execution.
+
+Options:
+  -n, --count N   Run up to N features before exiting (default 1)
+  --fail-fast     Stop scheduling features after the first failure
+  --timeout D     Stop a feature that runs longer than D (e.g. 30m, 2h),
+                  in place of any Timeout: the PRD sets for it
+  --group NAME    Only run features under "# Epic: NAME" or "## Group: NAME"
+  --since-commit REF
+                  Only run features whose Files: match a path in
+                  'git diff --name-only REF' or a new untracked file, both
+                  relative to the PRD directory's parent. Features without
+                  Files: are skipped.
+  --open-editor-on-fail
+                  Open a failed feature's feature.md and error log
+                  (.ralph/failures/<id>.log) in $EDITOR before moving on.
+                  Ignored when stdin isn't a terminal.
+  --parallel-roots
+                  Start every runnable feature at once, up to Concurrent:
+                  in the PRD (default 3). Execution: parallel features may
+                  overlap anything; sequential features run one at a time.
+                  Combine with --count to run more than one feature.
+  --post-run CMD  Run CMD through sh once the run finishes (overrides the
+                  PRD's Post-Run:). RALPH_STATUS, RALPH_EXIT_CODE,
+                  RALPH_FEATURES_RUN, RALPH_COMPLETED, RALPH_FAILED,
+                  RALPH_COMPLETED_IDS, RALPH_FAILED_IDS and RALPH_PRD_DIR
+                  describe the run. If it fails after a successful run,
+                  ralph exits 5.
+  --ci-annotations
+                  After the summary, print '::error file=...::message'
+                  workflow commands for failed features, and '::warning'
+                  for optional failures, features that completed with
+                  warnings and features skipped behind a failed dependency,
+                  so GitHub Actions shows them on the feature.md.
+  --cost-csv PATH Once the run finishes, write a cost ledger to PATH: one
+                  row per attempt with the feature, attempt number, model,
+                  input/output/cache tokens, estimated cost in USD and when
+                  it finished.
+  --explain       Print the model each feature started on and why: the
+                  Model: it sets, or for Model: auto the inputs the
+                  selector went on (leaf task, task count, complexity).
+  --keep-going-on-budget
+                  With a PRD Budget:, keep starting features once the run
+                  has spent 90% of it, instead of stopping there as the TUI
+                  would to ask. A feature is still stopped when the budget
+                  runs out.
+  --log-prompts   Write the full prompt of every attempt to .ralph/prompts/
+  --prd-dir DIR   Use DIR as the PRD directory instead of ./PRD
+  --checkout-base Run 'git checkout' of a feature's Base: before it starts;
+                  the feature fails to start while others are running
+  --auto-respond PATTERN=RESPONSE
+                  Write RESPONSE to claude's stdin when a line of output
+                  matches PATTERN, for tools that ask for approval anyway
+  --verify CMD    Run CMD through sh after each feature exits cleanly and
+                  mark the feature failed if it fails
+  --meta KEY=VALUE
+                  Tag the run with metadata shown by 'ralph status'
+
+Exit codes:
+  0 = All features completed successfully, or no work to do
+  1 = A feature failed, or progress could not be saved
+  2 = A feature exceeded its budget
+  3 = Dependencies are invalid (e.g. a cycle)
+  4 = A feature ran past --timeout
+  5 = Features succeeded but the post-run command failed`)
 	case "init":
 		fmt.Println(`ralph init - Initialize a ralph project or PRD directory structure
 
 Usage:
   ralph init [--force]
   ralph init <PRD.md> [--force]
+  ralph init <dir/> [--force]
+  ralph init <PRD.md|dir/> --prune
+  ralph init --from-dir DIR [--force]
 
 Without PRD file:
   Creates project scaffolding in the current directory:
     .claude/CLAUDE.md   PRD authoring guide for Claude Code
     PRD.md              Template PRD file to fill in
     input_design/       Directory for design assets
+    .gitignore          Ignores .ralph/ and progress.json (added once)
 
 With PRD file:
   Creates PRD/ directory structure from existing PRD:
@@ -273,8 +1027,28 @@ With PRD file:
     PRD/02-feature-name/feature.md   Second feature spec with global context
     ...
 
+With a directory of PRD files:
+  Merges its .md files in filename order into one PRD/ directory, numbering
+  features across files. Each feature keeps its own file's global context.
+  A numeric Depends: refers to a feature in the same file; depend on a
+  feature in another file by its title.
+
+With --prune:
+  Removes the features of an existing PRD/ directory whose titles are no
+  longer in the PRD, with their sub-features and directories, and drops
+  other features' dependencies on them. The remaining features keep their
+  IDs and progress. Running features are left alone. Without --prune,
+  'ralph init' lists the removed features when PRD/ already exists.
+
+With --from-dir:
+  Scans an existing project (top-level directories, README, TODO/FIXME
+  comments) and writes a starter DIR/PRD.md with one candidate feature per
+  directory, seeded with its TODOs, for you to edit.
+
 Options:
-  -f, --force   Overwrite existing files/directories
+  -f, --force       Overwrite existing files/directories
+  --prune           Remove features no longer in the PRD from PRD/
+  --from-dir DIR    Generate PRD.md from the project in DIR
 
 Workflow:
   1. Run 'ralph init' to create project template
@@ -3,6 +3,7 @@ package actions
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -14,6 +15,7 @@ const (
 	ActionTask     ActionType = "task"
 	ActionAgent    ActionType = "agent"
 	ActionBash     ActionType = "bash"
+	ActionTestRun  ActionType = "test_run" // A Bash command that runs tests
 	ActionRead     ActionType = "read"
 	ActionWrite    ActionType = "write"
 	ActionEdit     ActionType = "edit"
@@ -27,13 +29,18 @@ type Action struct {
 	Type      ActionType `json:"type"`
 	Tool      string     `json:"tool"`
 	Target    string     `json:"target"`
+	Path      string     `json:"path,omitempty"` // Full file path for file actions; Target is shortened for display
 	Timestamp time.Time  `json:"timestamp"`
 	Raw       string     `json:"raw,omitempty"`
+	// LinesAdded and LinesRemoved are inferred from Edit and Write inputs
+	LinesAdded   int `json:"lines_added,omitempty"`
+	LinesRemoved int `json:"lines_removed,omitempty"`
 }
 
 type ActionSummary struct {
 	Files    int
 	Commands int
+	TestRuns int
 	Agents   int
 	Reads    int
 	Fetches  int
@@ -48,6 +55,9 @@ func (s ActionSummary) String() string {
 	if s.Commands > 0 {
 		parts = append(parts, fmt.Sprintf("%d cmds", s.Commands))
 	}
+	if s.TestRuns > 0 {
+		parts = append(parts, fmt.Sprintf("%d test runs", s.TestRuns))
+	}
 	if s.Agents > 0 {
 		parts = append(parts, fmt.Sprintf("%d agents", s.Agents))
 	}
@@ -64,7 +74,7 @@ func (s ActionSummary) String() string {
 }
 
 func (s ActionSummary) IsEmpty() bool {
-	return s.Files == 0 && s.Commands == 0 && s.Agents == 0 && s.Reads == 0 && s.Fetches == 0 && s.Searches == 0
+	return s.Files == 0 && s.Commands == 0 && s.TestRuns == 0 && s.Agents == 0 && s.Reads == 0 && s.Fetches == 0 && s.Searches == 0
 }
 
 type ActionStore struct {
@@ -103,6 +113,8 @@ func (s *ActionStore) GetSummary(featureID string) ActionSummary {
 			summary.Files++
 		case ActionBash:
 			summary.Commands++
+		case ActionTestRun:
+			summary.TestRuns++
 		case ActionTask, ActionAgent:
 			summary.Agents++
 		case ActionRead:
@@ -170,19 +182,27 @@ func ExtractAction(tool string, toolInput json.RawMessage, timestamp time.Time)
 
 	case "bash":
 		action.Type = ActionBash
+		if IsTestCommand(input.Command) {
+			action.Type = ActionTestRun
+		}
 		action.Target = truncate(input.Command, 60)
 
 	case "read":
 		action.Type = ActionRead
 		action.Target = shortenPath(input.FilePath)
+		action.Path = input.FilePath
 
 	case "write":
 		action.Type = ActionWrite
 		action.Target = shortenPath(input.FilePath)
+		action.Path = input.FilePath
+		action.LinesAdded = len(splitLines(input.Content))
 
 	case "edit":
 		action.Type = ActionEdit
 		action.Target = shortenPath(input.FilePath)
+		action.Path = input.FilePath
+		action.LinesAdded, action.LinesRemoved = lineDiff(input.OldString, input.NewString)
 
 	case "webfetch":
 		action.Type = ActionWebFetch
@@ -211,6 +231,49 @@ func ExtractAction(tool string, toolInput json.RawMessage, timestamp time.Time)
 	return action
 }
 
+// testCommandRegex matches a test runner at the start of a command or of
+// one chained with &&, ; or |, after any VAR=value assignments
+var testCommandRegex = regexp.MustCompile(`(?:^|[;&|(])\s*(?:\w+=\S*\s+)*` +
+	`(?:go test|(?:python3? -m )?pytest|(?:npm|yarn|pnpm)(?: run)? test|(?:npx )?(?:jest|vitest)|cargo test|make test)(?:\s|$)`)
+
+// IsTestCommand reports whether a Bash command runs tests, such as
+// "go test ./...", "pytest", "npm test" or "jest"
+func IsTestCommand(command string) bool {
+	return testCommandRegex.MatchString(strings.TrimSpace(command))
+}
+
+// lineDiff counts the lines an edit adds and removes, ignoring the unchanged
+// lines it shares with the old text at either end
+func lineDiff(oldText, newText string) (added, removed int) {
+	oldLines := splitLines(oldText)
+	newLines := splitLines(newText)
+
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	return len(newLines) - prefix - suffix, len(oldLines) - prefix - suffix
+}
+
+// splitLines splits text into lines, without a trailing empty line
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// FormatLineChanges renders line counts as "+added/-removed"
+func FormatLineChanges(added, removed int) string {
+	return fmt.Sprintf("+%d/-%d", added, removed)
+}
+
 func truncate(s string, maxLen int) string {
 	s = strings.TrimSpace(s)
 	s = strings.ReplaceAll(s, "\n", " ")
@@ -254,6 +317,8 @@ func actionIcon(t ActionType) string {
 		return "🤖"
 	case ActionBash:
 		return "⚡"
+	case ActionTestRun:
+		return "🧪"
 	case ActionRead:
 		return "📖"
 	case ActionWrite:
@@ -27,6 +27,11 @@ func TestActionSummaryString(t *testing.T) {
 			summary:  ActionSummary{Commands: 2},
 			expected: "2 cmds",
 		},
+		{
+			name:     "test runs",
+			summary:  ActionSummary{Commands: 1, TestRuns: 3},
+			expected: "1 cmds, 3 test runs",
+		},
 		{
 			name:     "mixed",
 			summary:  ActionSummary{Files: 2, Commands: 1, Agents: 1},
@@ -121,8 +126,15 @@ func TestExtractAction(t *testing.T) {
 		{
 			name:       "bash command",
 			tool:       "Bash",
-			toolInput:  `{"command": "go test ./..."}`,
+			toolInput:  `{"command": "go build ./..."}`,
 			expectType: ActionBash,
+			expectTgt:  "go build ./...",
+		},
+		{
+			name:       "bash test command",
+			tool:       "Bash",
+			toolInput:  `{"command": "go test ./..."}`,
+			expectType: ActionTestRun,
 			expectTgt:  "go test ./...",
 		},
 		{
@@ -233,6 +245,79 @@ func TestExtractAction(t *testing.T) {
 	}
 }
 
+func TestIsTestCommand(t *testing.T) {
+	tests := []struct {
+		command string
+		want    bool
+	}{
+		{"go test ./...", true},
+		{"go test -run TestFoo ./internal/...", true},
+		{"pytest", true},
+		{"python -m pytest tests/", true},
+		{"npm test", true},
+		{"npm run test -- --watch=false", true},
+		{"yarn test", true},
+		{"jest src/", true},
+		{"npx jest --coverage", true},
+		{"cargo test", true},
+		{"cd backend && go test ./...", true},
+		{"CGO_ENABLED=0 go test ./...", true},
+		{"go build ./...", false},
+		{"ls -la", false},
+		{"npm install", false},
+		{"cat pytest.ini", false},
+		{"grep -r jest package.json", false},
+		{"go vet ./...", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			if got := IsTestCommand(tt.command); got != tt.want {
+				t.Errorf("IsTestCommand(%q) = %v, want %v", tt.command, got, tt.want)
+			}
+			want := ActionBash
+			if tt.want {
+				want = ActionTestRun
+			}
+			input, _ := json.Marshal(ToolInput{Command: tt.command})
+			if action := ExtractAction("Bash", input, time.Now()); action.Type != want {
+				t.Errorf("ExtractAction type = %s, want %s", action.Type, want)
+			}
+		})
+	}
+}
+
+func TestExtractActionLineChanges(t *testing.T) {
+	tests := []struct {
+		name          string
+		tool          string
+		toolInput     string
+		expectAdded   int
+		expectRemoved int
+	}{
+		{"write counts every line", "Write", `{"file_path":"a.go","content":"one\ntwo\nthree\n"}`, 3, 0},
+		{"empty write", "Write", `{"file_path":"a.go"}`, 0, 0},
+		{"single line replaced", "Edit", `{"file_path":"a.go","old_string":"x := 1","new_string":"x := 2"}`, 1, 1},
+		{"shared context is ignored", "Edit", `{"file_path":"a.go","old_string":"func f() {\n\treturn\n}","new_string":"func f() {\n\tlog()\n\tlog()\n\treturn\n}"}`, 2, 0},
+		{"lines deleted", "Edit", `{"file_path":"a.go","old_string":"a\nb\nc","new_string":"a"}`, 0, 2},
+		{"read has no changes", "Read", `{"file_path":"a.go"}`, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			action := ExtractAction(tt.tool, json.RawMessage(tt.toolInput), time.Now())
+			if action.LinesAdded != tt.expectAdded || action.LinesRemoved != tt.expectRemoved {
+				t.Errorf("expected +%d/-%d, got +%d/-%d", tt.expectAdded, tt.expectRemoved, action.LinesAdded, action.LinesRemoved)
+			}
+		})
+	}
+}
+
+func TestFormatLineChanges(t *testing.T) {
+	if got := FormatLineChanges(120, 15); got != "+120/-15" {
+		t.Errorf("expected +120/-15, got %q", got)
+	}
+}
+
 func TestTruncate(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -0,0 +1,122 @@
+package auto
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vx/ralph-go/internal/manifest"
+	"github.com/vx/ralph-go/internal/runner"
+)
+
+// PrintAnnotations prints a GitHub Actions workflow command for each feature
+// that needs attention: an error for a failed feature, and a warning for a
+// failed optional feature, one that completed with warnings, or one skipped
+// because its dependencies failed
+func PrintAnnotations(opts Options, results []*Result) {
+	prdDir, _ := ResolvePRDDir(opts.PRDDir)
+	var m *manifest.Manifest
+	if prdDir != "" {
+		m, _ = LoadManifest(prdDir)
+	}
+	for _, line := range annotations(prdDir, m, results) {
+		fmt.Println(line)
+	}
+}
+
+// annotations returns the workflow commands for results. Each points at the
+// feature's feature.md when the manifest knows where it is.
+func annotations(prdDir string, m *manifest.Manifest, results []*Result) []string {
+	var lines []string
+	for _, result := range results {
+		if result.NoWork {
+			for _, bf := range result.Blocked {
+				msg := fmt.Sprintf("Skipped %s (%s): waiting on %s", bf.Title, bf.ID, strings.Join(bf.PendingDepTitles, ", "))
+				lines = append(lines, annotation("warning", featureFilePath(prdDir, m, bf.ID), "Feature skipped", msg))
+			}
+			if result.Status == "invalid" {
+				lines = append(lines, annotation("error", "", "Invalid dependencies", result.Error))
+			}
+			continue
+		}
+
+		file := featureFilePath(prdDir, m, result.FeatureID)
+		switch {
+		case result.Status == "failed" && result.Optional:
+			lines = append(lines, annotation("warning", file, "Optional feature failed", failureMessage(result)))
+		case result.Status == "failed":
+			lines = append(lines, annotation("error", file, "Feature failed", failureMessage(result)))
+		case result.Status == manifest.StatusSkipped:
+			lines = append(lines, annotation("warning", file, "Feature skipped", failureMessage(result)+" (On-Failure: skip)"))
+		case result.Status == runner.StatusCompletedWithWarnings:
+			msg := fmt.Sprintf("%s (%s) completed with warnings", result.FeatureTitle, result.FeatureID)
+			lines = append(lines, annotation("warning", file, "Feature completed with warnings", msg))
+		}
+		if result.SaveError != "" {
+			msg := fmt.Sprintf("Progress for %s (%s) was not saved: %s", result.FeatureTitle, result.FeatureID, result.SaveError)
+			lines = append(lines, annotation("error", file, "Progress not saved", msg))
+		}
+	}
+	return lines
+}
+
+// failureMessage describes why a feature failed
+func failureMessage(result *Result) string {
+	msg := fmt.Sprintf("%s (%s) failed", result.FeatureTitle, result.FeatureID)
+	if result.Reason != "" && result.Reason != ReasonFeatureFailed {
+		msg += " [" + result.Reason + "]"
+	}
+	if result.Error != "" {
+		msg += ": " + result.Error
+	}
+	return msg
+}
+
+// featureFilePath returns the path of a feature's feature.md relative to the
+// current directory, or "" if it isn't known
+func featureFilePath(prdDir string, m *manifest.Manifest, id string) string {
+	if m == nil {
+		return ""
+	}
+	f := m.GetFeature(id)
+	if f == nil {
+		return ""
+	}
+	path := filepath.Join(prdDir, f.Dir, FeatureFile)
+	if cwd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(cwd, path); err == nil {
+			path = rel
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+// annotation formats a workflow command such as
+// "::error file=PRD/01-login/feature.md,title=Feature failed::message"
+func annotation(level, file, title, msg string) string {
+	var props []string
+	if file != "" {
+		props = append(props, "file="+escapeAnnotationProperty(file))
+	}
+	if title != "" {
+		props = append(props, "title="+escapeAnnotationProperty(title))
+	}
+	command := "::" + level
+	if len(props) > 0 {
+		command += " " + strings.Join(props, ",")
+	}
+	return command + "::" + escapeAnnotationData(msg)
+}
+
+// escapeAnnotationData escapes a workflow command message so newlines don't
+// end the command
+func escapeAnnotationData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeAnnotationProperty escapes a workflow command property value, which
+// also can't contain the property and message separators
+func escapeAnnotationProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,66 @@
+package auto
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/vx/ralph-go/internal/manifest"
+)
+
+func TestAnnotationsFailedFeature(t *testing.T) {
+	tmpDir := setupRunnableFeatures(t, "01")
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	stubExecuteFeature(t, "failed")
+
+	results, err := RunWithOptions(Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	prdDir := filepath.Join(tmpDir, "PRD")
+	m, err := LoadManifest(prdDir)
+	if err != nil {
+		t.Fatalf("failed to load manifest: %v", err)
+	}
+
+	got := annotations(prdDir, m, results)
+	want := []string{"::error file=PRD/01-feature/feature.md,title=Feature failed::Feature 01 (01) failed: stub failure"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("annotations = %q, want %q", got, want)
+	}
+}
+
+func TestAnnotationsWarnings(t *testing.T) {
+	m := manifest.New("", "Test")
+	m.Features = []manifest.ManifestFeature{
+		{ID: "01", Dir: "01-lint", Title: "Lint"},
+		{ID: "02", Dir: "02-docs", Title: "Docs"},
+	}
+	results := []*Result{
+		{FeatureID: "01", FeatureTitle: "Lint", Status: "failed", Optional: true, Error: "line 1\nline 2"},
+		{NoWork: true, Status: "blocked", Blocked: []BlockedFeature{
+			{ID: "02", Title: "Docs", PendingDeps: []string{"01"}, PendingDepTitles: []string{"Lint"}},
+		}},
+	}
+
+	got := annotations("PRD", m, results)
+	want := []string{
+		"::warning file=PRD/01-lint/feature.md,title=Optional feature failed::Lint (01) failed: line 1%0Aline 2",
+		"::warning file=PRD/02-docs/feature.md,title=Feature skipped::Skipped Docs (02): waiting on Lint",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("annotations = %q, want %q", got, want)
+	}
+}
+
+func TestEscapeAnnotationProperty(t *testing.T) {
+	got := escapeAnnotationProperty("a:b,c%d")
+	if want := "a%3Ab%2Cc%25d"; got != want {
+		t.Errorf("escapeAnnotationProperty = %q, want %q", got, want)
+	}
+}
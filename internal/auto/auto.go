@@ -7,8 +7,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/vx/ralph-go/internal/automodel"
+	"github.com/vx/ralph-go/internal/live"
 	"github.com/vx/ralph-go/internal/manifest"
+	"github.com/vx/ralph-go/internal/parser"
 	"github.com/vx/ralph-go/internal/runner"
+	"github.com/vx/ralph-go/internal/state"
 )
 
 const (
@@ -18,6 +22,24 @@ const (
 	DefaultRetries = 3
 )
 
+// Exit codes returned by ExitCode, so CI can tell why a run stopped
+const (
+	ExitSuccess        = 0
+	ExitFeatureFailed  = 1
+	ExitBudgetExceeded = 2
+	ExitInvalid        = 3
+	ExitTimeout        = 4
+	ExitPostRunFailed  = 5
+)
+
+// Terminating reasons recorded in Result.Reason
+const (
+	ReasonFeatureFailed  = "feature_failed"
+	ReasonBudgetExceeded = "budget_exceeded"
+	ReasonInvalid        = "invalid_dependencies"
+	ReasonTimeout        = "timeout"
+)
+
 type Result struct {
 	FeatureID    string
 	FeatureTitle string
@@ -28,6 +50,20 @@ type Result struct {
 	Blocked      []BlockedFeature
 	Archived     bool
 	ArchivePath  string
+	SaveError    string // Set when the final status could not be written to the manifest
+	FailFast     bool   // Set when --fail-fast stopped the run after this feature
+	Reason       string // Why the feature or run stopped unsuccessfully, e.g. ReasonTimeout
+	Optional     bool   // The feature is optional, so its failure doesn't fail the run
+	OnFailure    string // The feature's On-Failure: action
+	Aborted      bool   // Set when the feature's On-Failure: abort stopped the run
+	// BudgetStopped is the global budget's status when reaching its
+	// threshold, or running out, stopped the run after this feature
+	BudgetStopped string
+	// ModelExplanation is the model the feature started on and why, set
+	// with --explain
+	ModelExplanation string
+	// Retries is how many times the feature was run again after failing
+	Retries int
 }
 
 type BlockedFeature struct {
@@ -66,6 +102,44 @@ func FindPRDDir() (string, error) {
 	return prdDir, nil
 }
 
+// ResolvePRDDir returns the PRD directory to use. An explicit directory (from
+// --prd-dir) takes precedence over discovering PRD/ in the current directory.
+func ResolvePRDDir(explicit string) (string, error) {
+	if explicit == "" {
+		return FindPRDDir()
+	}
+
+	prdDir, err := filepath.Abs(explicit)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve PRD directory %s: %w", explicit, err)
+	}
+
+	info, err := os.Stat(prdDir)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("PRD directory not found: %s", explicit)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to access PRD directory %s: %w", explicit, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("PRD directory is not a directory: %s", explicit)
+	}
+
+	manifestPath := filepath.Join(prdDir, ManifestFile)
+	if _, err := os.Stat(manifestPath); os.IsNotExist(err) {
+		return "", fmt.Errorf("manifest.json not found in %s (run 'ralph init PRD.md' first)", explicit)
+	}
+
+	return prdDir, nil
+}
+
+// WorkDir returns the directory features of the PRD in prdDir run in: the
+// PRD directory's parent, as in the TUI. The live status and output logs of
+// a run are written beneath it.
+func WorkDir(prdDir string) string {
+	return filepath.Dir(prdDir)
+}
+
 func LoadManifest(prdDir string) (*manifest.Manifest, error) {
 	return manifest.Load(prdDir)
 }
@@ -76,88 +150,425 @@ func GetFeaturePrompt(prdDir string, feature *manifest.ManifestFeature) (string,
 	if err != nil {
 		return "", fmt.Errorf("failed to read feature file %s: %w", featurePath, err)
 	}
-	return string(content), nil
+	return parser.ApplyTaskStates(string(content), taskStates(prdDir, feature.ID)), nil
 }
 
-func Run() (*Result, error) {
-	prdDir, err := FindPRDDir()
+// ModelInputs returns what auto model selection goes on for a feature's
+// prompt: whether it is a leaf task and how many tasks it has
+func ModelInputs(prompt string) (isLeafTask bool, taskCount int) {
+	taskCount = parser.CountTasks(prompt)
+	return taskCount <= 2, taskCount
+}
+
+// ExplainModel describes the model a feature starts on and why
+func ExplainModel(feature *manifest.ManifestFeature, prompt string) string {
+	switch {
+	case automodel.IsAutoMode(feature.Model):
+		return "auto, starting on " + automodel.Explain(ModelInputs(prompt)).String()
+	case feature.Model == "":
+		return automodel.ModelSonnet + " (default, no Model: set)"
+	default:
+		return feature.Model + " (set by Model:)"
+	}
+}
+
+// taskStates returns the tasks of a feature checked off or on by hand in the
+// TUI's progress.json, if there is one
+func taskStates(prdDir, featureID string) map[string]bool {
+	progress, err := state.LoadProgressFromPath(filepath.Join(prdDir, "progress.json"))
 	if err != nil {
-		return nil, err
+		return nil
 	}
+	return progress.GetTaskStates(featureID)
+}
 
-	m, err := LoadManifest(prdDir)
+// Options configures a headless run
+type Options struct {
+	// Count is the maximum number of features to run before exiting (default 1)
+	Count int
+	// LogPrompts writes the full prompt of every attempt to .ralph/prompts/
+	LogPrompts bool
+	// PRDDir overrides discovery of PRD/ in the current directory
+	PRDDir string
+	// ClaudeArgs are extra flags passed to claude; defaults to the manifest's
+	ClaudeArgs []string
+	// FailFast stops scheduling further features after the first failure
+	FailFast bool
+	// CheckoutBase checks out a feature's Base revision before it starts
+	CheckoutBase bool
+	// Timeout stops a feature that runs longer than this, in place of the
+	// feature's own Timeout (0 = the feature's Timeout, if any)
+	Timeout time.Duration
+	// Group limits the run to features of one epic or group (empty = all)
+	Group string
+	// OpenEditorOnFail opens a failed feature's spec and error log in $EDITOR
+	// before moving on; ignored when stdin isn't a terminal
+	OpenEditorOnFail bool
+	// WarningThreshold is the tool errors plus skipped tests that mark a
+	// feature completed_with_warnings; defaults to the manifest's, then
+	// runner.DefaultWarningThreshold
+	WarningThreshold int
+	// ParallelRoots runs runnable features concurrently, up to the manifest's
+	// Concurrent limit: parallel features may overlap, while sequential ones
+	// run one at a time
+	ParallelRoots bool
+	// PostRun is a shell command run once the run finishes; defaults to the
+	// manifest's
+	PostRun string
+	// SinceCommit limits the run to features whose Files match a path
+	// changed since this git ref (empty = all)
+	SinceCommit string
+	// CIAnnotations prints GitHub Actions annotations for failed, skipped
+	// and optional features once the run finishes
+	CIAnnotations bool
+	// CostCSV writes a row per attempt with its model, tokens and cost to
+	// this path once the run finishes (empty = none)
+	CostCSV string
+	// Explain prints the model each feature started on and the inputs auto
+	// model selection chose it from
+	Explain bool
+	// AutoResponses answer approval prompts a tool prints by writing to
+	// claude's stdin (empty = claude gets no stdin)
+	AutoResponses []runner.AutoResponse
+	// VerifyCommand runs after a feature exits cleanly and fails the feature
+	// if it fails (empty = no verification)
+	VerifyCommand string
+	// RunMeta tags the run with key/value pairs, recorded in progress.json
+	RunMeta map[string]string
+	// KeepGoingOnBudget acknowledges the global budget's threshold up front,
+	// so the run carries on past it until the budget runs out
+	KeepGoingOnBudget bool
+	// ScanBufferSize is the longest output line read, in bytes; longer lines
+	// are skipped (0 = runner.DefaultScanBufferSize)
+	ScanBufferSize int
+	// Retries is how many times a feature that fails is run again before it
+	// counts as failed; defaults to the manifest's (0 = none). Features
+	// stopped by their budget or timeout aren't retried.
+	Retries int
+
+	// publisher keeps .ralph/live.json current for 'ralph attach'
+	publisher *live.Publisher
+	// ledger accumulates the usage of each attempt for CostCSV
+	ledger *costLedger
+	// budget tracks spending against the manifest's global budget
+	budget *runBudget
+}
+
+// Run runs the next runnable feature to completion
+func Run() (*Result, error) {
+	results, err := RunWithOptions(Options{Count: 1})
 	if err != nil {
 		return nil, err
 	}
+	return results[0], nil
+}
 
-	feature := m.GetNextRunnableFeature()
-	if feature == nil {
-		return handleNoRunnableFeature(m)
+// RunWithOptions runs up to opts.Count runnable features in dependency order,
+// stopping early when no runnable feature remains, progress can't be saved, or
+// a feature fails under opts.FailFast.
+// It always returns at least one result on success; a NoWork result if
+// nothing could be run, including when the dependencies contain a cycle.
+func RunWithOptions(opts Options) ([]*Result, error) {
+	prdDir, err := ResolvePRDDir(opts.PRDDir)
+	if err != nil {
+		return nil, err
 	}
 
-	prompt, err := GetFeaturePrompt(prdDir, feature)
+	m, err := LoadManifest(prdDir)
 	if err != nil {
 		return nil, err
 	}
+	for _, warning := range m.SchemaWarnings() {
+		fmt.Printf("Warning: manifest.json: %s\n", warning)
+	}
 
-	result := &Result{
-		FeatureID:    feature.ID,
-		FeatureTitle: feature.Title,
+	if _, err := m.ValidateDependencies(); err != nil {
+		return []*Result{{
+			NoWork: true,
+			Status: "invalid",
+			Reason: ReasonInvalid,
+			Error:  err.Error(),
+		}}, nil
 	}
 
-	startTime := time.Now()
+	if opts.Group != "" && !m.HasGroup(opts.Group) {
+		return nil, fmt.Errorf("no features in group %q", opts.Group)
+	}
 
-	if err := m.UpdateFeatureStatus(feature.ID, "running"); err != nil {
-		return nil, fmt.Errorf("failed to update feature status: %w", err)
+	sel := selection{group: opts.Group}
+	if opts.SinceCommit != "" {
+		sel.ids, err = sinceCommitSelection(m, WorkDir(prdDir), opts.SinceCommit)
+		if err != nil {
+			return nil, err
+		}
+		if len(sel.ids) == 0 {
+			return []*Result{{NoWork: true, Status: "no_matching_changes"}}, nil
+		}
 	}
-	if err := m.Save(); err != nil {
-		return nil, fmt.Errorf("failed to save manifest: %w", err)
+
+	recordRunMeta(prdDir, opts.RunMeta)
+
+	count := opts.Count
+	if count <= 0 {
+		count = 1
 	}
 
-	workDir, err := os.Getwd()
+	publisher, err := live.Start(WorkDir(prdDir), prdDir)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get working directory: %w", err)
+		fmt.Printf("Warning: could not publish live status: %s\n", err)
 	}
+	defer publisher.Close()
+	opts.publisher = publisher
 
-	runnerMgr := runner.NewManagerWithConfig(workDir, runner.Config{
-		MaxRetries:    DefaultRetries,
-		MaxConcurrent: 1,
-	})
+	if opts.CostCSV != "" {
+		opts.ledger = newCostLedger()
+		defer writeCostLedger(opts.CostCSV, opts.ledger)
+	}
 
-	instance, err := runnerMgr.StartInstance(feature.ID, feature.Model, prompt)
-	if err != nil {
-		result.Status = "failed"
-		result.Error = err.Error()
-		result.Duration = time.Since(startTime)
+	opts.budget = newRunBudget(m, opts.KeepGoingOnBudget)
 
-		_ = m.UpdateFeatureStatus(feature.ID, "failed")
-		_ = m.Save()
+	if opts.ParallelRoots {
+		results, err := runParallelRoots(prdDir, m, sel, opts, count)
+		if err != nil || len(results) > 0 {
+			return results, err
+		}
+		result, err := noWorkResult(m, sel)
+		if err != nil {
+			return nil, err
+		}
+		return []*Result{result}, nil
+	}
+
+	var results []*Result
+	for len(results) < count {
+		feature := nextSchedulable(m, sel, nil)
+		if feature == nil {
+			if len(results) == 0 {
+				result, err := noWorkResult(m, sel)
+				if err != nil {
+					return nil, err
+				}
+				results = append(results, result)
+			}
+			break
+		}
 
-		return result, nil
+		result, err := runFeature(prdDir, m, feature, opts)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, result)
+		if result.SaveError != "" {
+			break
+		}
+		if abortsRun(result) {
+			result.Aborted = len(results) < count
+			break
+		}
+		if opts.FailFast && result.Status == "failed" && !result.Optional {
+			result.FailFast = len(results) < count
+			break
+		}
+		if stop, status := opts.budget.stopsRun(); stop {
+			if len(results) < count {
+				result.BudgetStopped = status
+			}
+			break
+		}
+	}
+
+	return results, nil
+}
+
+// executorFactory replaces how executeFeature runs claude, so tests can
+// inject failures; nil runs the claude binary
+var executorFactory runner.ExecutorFactory
+
+// executeFeature starts a claude instance for the feature and blocks until it
+// finishes, returning the final status, error message and, on failure, the
+// terminating reason. The instance is stopped if it exceeds the feature's
+// budget or its timeout, see newFeatureLimits. It is a variable so tests can
+// substitute a fake executor.
+var executeFeature = func(workDir string, feature *manifest.ManifestFeature, prompt string, opts Options) (status string, errMsg string, reason string) {
+	warningThreshold := opts.WarningThreshold
+	if warningThreshold <= 0 {
+		warningThreshold = runner.DefaultWarningThreshold
+	}
+	// Retries are run by featureRun.execute, not the runner, so the config
+	// leaves MaxRetries unset
+	runnerMgr := runner.NewManagerWithConfig(workDir, runner.Config{
+		MaxConcurrent:    1,
+		WarningThreshold: warningThreshold,
+		LoopThreshold:    runner.DefaultLoopThreshold,
+		LoopWindow:       runner.DefaultLoopWindow,
+		// Nobody is watching a headless run, so stop a looping feature
+		// rather than let it burn the budget
+		CancelOnLoop: true,
+	})
+	runnerMgr.SetExecutorFactory(executorFactory)
+	runnerMgr.SetPromptLogging(opts.LogPrompts)
+	runnerMgr.SetCheckoutBase(opts.CheckoutBase)
+	runnerMgr.SetAutoResponses(opts.AutoResponses)
+	runnerMgr.SetVerifyCommand(opts.VerifyCommand)
+	runnerMgr.SetScanBufferSize(opts.ScanBufferSize)
+	opts.budget.apply(runnerMgr)
+	if err := runnerMgr.SetExtraArgs(opts.ClaudeArgs); err != nil {
+		return "failed", err.Error(), ReasonFeatureFailed
+	}
+
+	isLeafTask, taskCount := ModelInputs(prompt)
+	instance, err := runnerMgr.StartInstanceWithOptions(feature.ID, feature.Model, prompt, runner.StartInstanceOptions{
+		IsLeafTask:   isLeafTask,
+		TaskCount:    taskCount,
+		Base:         feature.Base,
+		BudgetTokens: feature.BudgetTokens,
+		BudgetUSD:    feature.BudgetUSD,
+	})
+	if err != nil {
+		return "failed", err.Error(), ReasonFeatureFailed
 	}
+	instance.SetBudget(feature.BudgetTokens, feature.BudgetUSD)
+	opts.budget.start(instance)
+	defer recordAttempt(opts.ledger, feature, instance)
+	defer opts.budget.record(instance)
 
+	limits := newFeatureLimits(feature, opts, time.Now())
 	for {
 		status := instance.GetStatus()
-		if status == "completed" || status == "failed" {
+		if manifest.IsCompleted(status) || status == "failed" {
 			break
 		}
+		if errMsg, reason := limits.exceeded(instance, time.Now()); reason != "" {
+			instance.Stop()
+			return "failed", errMsg, reason
+		}
 		time.Sleep(100 * time.Millisecond)
 	}
 
-	result.Status = instance.GetStatus()
-	result.Duration = time.Since(startTime)
-	if result.Status == "failed" {
-		result.Error = instance.GetError()
+	status = instance.GetStatus()
+	if status == "failed" {
+		errMsg = instance.GetError()
+		reason = ReasonFeatureFailed
 	}
+	return status, errMsg, reason
+}
 
-	if err := m.UpdateFeatureStatus(feature.ID, result.Status); err != nil {
+func runFeature(prdDir string, m *manifest.Manifest, feature *manifest.ManifestFeature, opts Options) (*Result, error) {
+	run, err := beginFeature(prdDir, m, feature, opts)
+	if err != nil {
+		return nil, err
+	}
+	run.execute(feature)
+	return finishFeature(prdDir, m, feature, run)
+}
+
+// featureRun carries a feature from beginFeature, through executeFeature, to
+// finishFeature
+type featureRun struct {
+	result    *Result
+	prompt    string
+	workDir   string
+	opts      Options
+	startTime time.Time
+	// startErr is why the feature couldn't be started, such as an unreadable
+	// feature.md; the feature fails without running
+	startErr error
+}
+
+// execute runs the feature and records its outcome in the result, running it
+// again up to opts.Retries times while it fails. A feature that can't start,
+// or whose run panics, fails on its own so the rest of the run carries on.
+func (run *featureRun) execute(feature *manifest.ManifestFeature) {
+	if run.startErr != nil {
+		run.result.Status, run.result.Error, run.result.Reason = "failed", run.startErr.Error(), ReasonFeatureFailed
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			run.result.Status, run.result.Error, run.result.Reason = "failed", fmt.Sprintf("feature crashed: %v", r), ReasonFeatureFailed
+		}
+	}()
+	for attempt := 1; ; attempt++ {
+		run.result.Status, run.result.Error, run.result.Reason = executeFeature(run.workDir, feature, run.prompt, run.opts)
+		if run.result.Reason != ReasonFeatureFailed || attempt > run.opts.Retries {
+			return
+		}
+		if stop, _ := run.opts.budget.stopsRun(); stop {
+			return
+		}
+		run.result.Retries++
+		fmt.Printf("Feature %s failed, retrying (%d of %d): %s\n", feature.ID, attempt, run.opts.Retries, run.result.Error)
+	}
+}
+
+// beginFeature reads the feature's prompt and marks it running in the
+// manifest. A prompt that can't be read is kept as the run's startErr rather
+// than returned, since it only fails this feature.
+func beginFeature(prdDir string, m *manifest.Manifest, feature *manifest.ManifestFeature, opts Options) (*featureRun, error) {
+	prompt, promptErr := GetFeaturePrompt(prdDir, feature)
+
+	run := &featureRun{
+		result: &Result{
+			FeatureID:    feature.ID,
+			FeatureTitle: feature.Title,
+			Optional:     feature.Optional,
+			OnFailure:    feature.OnFailure,
+		},
+		prompt:    prompt,
+		startTime: time.Now(),
+		startErr:  promptErr,
+	}
+	if opts.Explain && promptErr == nil {
+		run.result.ModelExplanation = ExplainModel(feature, prompt)
+	}
+
+	if err := m.UpdateFeatureStatus(feature.ID, "running"); err != nil {
 		return nil, fmt.Errorf("failed to update feature status: %w", err)
 	}
 	if err := m.Save(); err != nil {
 		return nil, fmt.Errorf("failed to save manifest: %w", err)
 	}
+	opts.publisher.FeatureStarted(feature.ID, feature.Title)
+
+	run.workDir = WorkDir(prdDir)
 
-	if result.Status == "completed" {
+	if len(opts.ClaudeArgs) == 0 {
+		opts.ClaudeArgs = m.ClaudeArgs
+	}
+	if opts.WarningThreshold == 0 {
+		opts.WarningThreshold = m.Warnings
+	}
+	if opts.Retries == 0 {
+		opts.Retries = m.Retries
+	}
+	run.opts = opts
+	return run, nil
+}
+
+// finishFeature records the outcome of an executed feature in the manifest
+func finishFeature(prdDir string, m *manifest.Manifest, feature *manifest.ManifestFeature, run *featureRun) (*Result, error) {
+	result := run.result
+	result.Duration = time.Since(run.startTime)
+	if result.Status == "failed" && result.OnFailure == parser.OnFailureSkip {
+		result.Status = manifest.StatusSkipped
+	}
+	run.opts.publisher.FeatureFinished(feature.ID, result.Status, result.Optional)
+
+	if err := m.UpdateFeatureStatus(feature.ID, result.Status); err != nil {
+		return nil, fmt.Errorf("failed to update feature status: %w", err)
+	}
+	// The feature has already run, so report a save failure in the result
+	// rather than discarding its outcome
+	if err := m.Save(); err != nil {
+		result.SaveError = err.Error()
+		return result, nil
+	}
+
+	if err := openEditorOnFailure(prdDir, run.workDir, feature, result, run.opts); err != nil {
+		fmt.Printf("Warning: could not open editor: %s\n", err)
+	}
+
+	if manifest.IsCompleted(result.Status) {
 		if archived, archivePath := checkAndArchivePRD(prdDir, m); archived {
 			result.Archived = true
 			result.ArchivePath = archivePath
@@ -167,6 +578,12 @@ func Run() (*Result, error) {
 	return result, nil
 }
 
+// abortsRun reports whether a feature's failure stops the run because of its
+// On-Failure: abort
+func abortsRun(result *Result) bool {
+	return result.Status == "failed" && result.OnFailure == parser.OnFailureAbort
+}
+
 func checkAndArchivePRD(prdDir string, m *manifest.Manifest) (bool, string) {
 	total, completed, _, _, _, _ := m.GetSummary()
 	if completed != total {
@@ -182,10 +599,10 @@ func checkAndArchivePRD(prdDir string, m *manifest.Manifest) (bool, string) {
 		return false, ""
 	}
 
-	workDir := filepath.Dir(prdDir)
-	sourcePath := filepath.Join(workDir, sourcePRD)
+	sourcePath := filepath.Join(WorkDir(prdDir), sourcePRD)
 
-	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
+	// A PRD merged from a directory of files isn't archived
+	if info, err := os.Stat(sourcePath); err != nil || info.IsDir() {
 		return false, ""
 	}
 
@@ -206,6 +623,30 @@ func checkAndArchivePRD(prdDir string, m *manifest.Manifest) (bool, string) {
 	return true, archivePath
 }
 
+// noWorkResult explains why no feature of the selection (its group and
+// --since-commit features, if set) could be run
+func noWorkResult(m *manifest.Manifest, sel selection) (*Result, error) {
+	if sel.group == "" && sel.ids == nil {
+		return handleNoRunnableFeature(m)
+	}
+	if selectionCompleted(m, sel) {
+		return &Result{NoWork: true, Status: "all_completed"}, nil
+	}
+	result, err := handleNoRunnableFeature(m)
+	if err != nil {
+		return nil, err
+	}
+	// Features outside the selection wouldn't have run anyway
+	var blocked []BlockedFeature
+	for _, bf := range result.Blocked {
+		if f := m.GetFeature(bf.ID); f != nil && sel.includes(f) {
+			blocked = append(blocked, bf)
+		}
+	}
+	result.Blocked = blocked
+	return result, nil
+}
+
 func handleNoRunnableFeature(m *manifest.Manifest) (*Result, error) {
 	total, completed, running, failed, pending, blocked := m.GetSummary()
 
@@ -239,6 +680,22 @@ func handleNoRunnableFeature(m *manifest.Manifest) (*Result, error) {
 	return result, nil
 }
 
+// selectionCompleted reports whether every feature of the selection that
+// isn't disabled has completed
+func selectionCompleted(m *manifest.Manifest, sel selection) bool {
+	selected := 0
+	for _, feature := range m.GetRootFeatures() {
+		if feature.Disabled || !sel.includes(&feature) {
+			continue
+		}
+		if !manifest.IsCompleted(feature.Status) {
+			return false
+		}
+		selected++
+	}
+	return selected > 0
+}
+
 func getBlockedFeatures(m *manifest.Manifest) []BlockedFeature {
 	blocked := m.GetBlockedFeatures()
 	result := make([]BlockedFeature, 0, len(blocked))
@@ -272,9 +729,30 @@ func PrintSummary(result *Result) {
 	fmt.Printf("Feature: %s - %s\n", result.FeatureID, result.FeatureTitle)
 	fmt.Printf("Status:  %s\n", result.Status)
 	fmt.Printf("Duration: %s\n", result.Duration.Round(time.Second))
+	if result.ModelExplanation != "" {
+		fmt.Printf("Model:   %s\n", result.ModelExplanation)
+	}
 	if result.Error != "" {
 		fmt.Printf("Error:   %s\n", result.Error)
 	}
+	if result.Optional && result.Status == "failed" {
+		fmt.Printf("Note:    optional feature, dependents can still run\n")
+	}
+	if result.Status == manifest.StatusSkipped {
+		fmt.Printf("Note:    skipped after failing (On-Failure: skip), dependents won't run\n")
+	}
+	if result.SaveError != "" {
+		fmt.Printf("Warning: progress not saved, the feature will run again next time: %s\n", result.SaveError)
+	}
+	if result.FailFast {
+		fmt.Printf("Stopping: --fail-fast is set, no further features were started\n")
+	}
+	if result.Aborted {
+		fmt.Printf("Stopping: On-Failure: abort is set, no further features were started\n")
+	}
+	if result.BudgetStopped != "" {
+		fmt.Printf("Stopping: global budget at %s, no further features were started (--keep-going-on-budget continues past 90%%)\n", result.BudgetStopped)
+	}
 	if result.Archived {
 		fmt.Printf("\nAll features completed. PRD archived to: %s\n", result.ArchivePath)
 	}
@@ -294,6 +772,10 @@ func printNoWorkSummary(result *Result) {
 	case "all_blocked":
 		fmt.Println("No runnable features. All pending features are blocked:")
 		printBlockedFeatures(result.Blocked)
+	case "invalid":
+		fmt.Printf("Cannot run: %s\n", result.Error)
+	case "no_matching_changes":
+		fmt.Println("No features declare files changed since the given commit.")
 	default:
 		fmt.Println("No runnable features found.")
 	}
@@ -306,12 +788,87 @@ func printBlockedFeatures(blocked []BlockedFeature) {
 	}
 }
 
+// PrintSummaries prints the summary of every feature run in this invocation
+func PrintSummaries(results []*Result) {
+	for _, result := range results {
+		PrintSummary(result)
+	}
+	if len(results) > 1 {
+		fmt.Printf("%s\n\n", describeResults(results))
+	}
+}
+
+// describeResults counts the results by outcome, with features skipped by
+// On-Failure: skip and failed optional features apart from failures, as
+// neither fails the run
+func describeResults(results []*Result) string {
+	var completed, failed, optionalFailed, skipped int
+	for _, result := range results {
+		switch {
+		case manifest.IsCompleted(result.Status):
+			completed++
+		case result.Status == manifest.StatusSkipped:
+			skipped++
+		case result.Optional && result.Status == "failed":
+			optionalFailed++
+		default:
+			failed++
+		}
+	}
+	line := fmt.Sprintf("Ran %d features: %d completed, %d failed", len(results), completed, failed)
+	if optionalFailed > 0 {
+		line += fmt.Sprintf(", %d optional failed", optionalFailed)
+	}
+	if skipped > 0 {
+		line += fmt.Sprintf(", %d skipped", skipped)
+	}
+	return line
+}
+
+// ExitCodeAll returns the exit code of the first unsuccessful result in the
+// invocation, or 0 if every feature succeeded
+func ExitCodeAll(results []*Result) int {
+	for _, result := range results {
+		if code := ExitCode(result); code != 0 {
+			return code
+		}
+	}
+	return 0
+}
+
+// ExitCode maps a result to a process exit code: 0 on success, no work, a
+// failed optional feature or one skipped by On-Failure: skip, 1 for a failed
+// feature or unsaved progress, 2 when a budget was exceeded or the global
+// budget stopped the run, 3 for invalid dependencies and 4 when a feature
+// timed out. An optional feature returns 0 however it failed, its budget and
+// timeout included; only the global budget stopping the run after it, which
+// leaves other features unrun, still returns 2.
 func ExitCode(result *Result) int {
+	if result.BudgetStopped != "" && result.SaveError == "" {
+		return ExitBudgetExceeded
+	}
+	if result.Optional && result.Status == "failed" && result.SaveError == "" {
+		return ExitSuccess
+	}
+	if result.Status == manifest.StatusSkipped && result.SaveError == "" {
+		return ExitSuccess
+	}
+	switch result.Reason {
+	case ReasonBudgetExceeded:
+		return ExitBudgetExceeded
+	case ReasonInvalid:
+		return ExitInvalid
+	case ReasonTimeout:
+		return ExitTimeout
+	}
 	if result.NoWork {
-		return 0
+		return ExitSuccess
+	}
+	if result.SaveError != "" {
+		return ExitFeatureFailed
 	}
-	if result.Status == "completed" {
-		return 0
+	if manifest.IsCompleted(result.Status) {
+		return ExitSuccess
 	}
-	return 1
+	return ExitFeatureFailed
 }
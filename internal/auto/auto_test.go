@@ -1,13 +1,21 @@
 package auto
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/vx/ralph-go/internal/live"
 	"github.com/vx/ralph-go/internal/manifest"
+	"github.com/vx/ralph-go/internal/parser"
+	"github.com/vx/ralph-go/internal/runner"
+	"github.com/vx/ralph-go/internal/state"
 )
 
 func TestPRDDirExists(t *testing.T) {
@@ -137,6 +145,45 @@ func TestGetFeaturePrompt(t *testing.T) {
 	}
 }
 
+func TestGetFeaturePromptAppliesCheckedOffTasks(t *testing.T) {
+	prdDir := filepath.Join(t.TempDir(), "PRD")
+	featureDir := filepath.Join(prdDir, "01-auth")
+	os.MkdirAll(featureDir, 0755)
+	os.WriteFile(filepath.Join(featureDir, "feature.md"), []byte("## Auth\n\n- [ ] Add login handler\n- [ ] Add logout\n"), 0644)
+
+	p := state.NewProgress()
+	p.SetPathDirect(filepath.Join(prdDir, "progress.json"))
+	p.SetTaskCompleted("01", parser.TaskID("Add login handler"), true)
+	if err := p.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	prompt, err := GetFeaturePrompt(prdDir, &manifest.ManifestFeature{ID: "01", Dir: "01-auth"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "## Auth\n\n- [x] Add login handler\n- [ ] Add logout\n"; prompt != want {
+		t.Errorf("expected the checked off task in the prompt, got %q", prompt)
+	}
+}
+
+func TestExplainModel(t *testing.T) {
+	prompt := "## Auth\n\n- [ ] Add login handler\n- [ ] Add logout\n- [ ] Add sessions\n"
+	tests := []struct {
+		model string
+		want  string
+	}{
+		{"auto", "auto, starting on haiku: moderate task (<=5 tasks) - starting with haiku (leaf: no, tasks: 3, complexity: moderate)"},
+		{"opus", "opus (set by Model:)"},
+		{"", "sonnet (default, no Model: set)"},
+	}
+	for _, tt := range tests {
+		if got := ExplainModel(&manifest.ManifestFeature{ID: "01", Model: tt.model}, prompt); got != tt.want {
+			t.Errorf("ExplainModel(%q) = %q, want %q", tt.model, got, tt.want)
+		}
+	}
+}
+
 func TestExitCode(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -163,6 +210,56 @@ func TestExitCode(t *testing.T) {
 			result:   &Result{NoWork: true, Status: "all_completed"},
 			expected: 0,
 		},
+		{
+			name:     "feature failure returns 1",
+			result:   &Result{Status: "failed", Reason: ReasonFeatureFailed},
+			expected: ExitFeatureFailed,
+		},
+		{
+			name:     "unsaved progress returns 1",
+			result:   &Result{Status: "completed", SaveError: "disk full"},
+			expected: ExitFeatureFailed,
+		},
+		{
+			name:     "budget exceeded returns 2",
+			result:   &Result{Status: "failed", Reason: ReasonBudgetExceeded},
+			expected: ExitBudgetExceeded,
+		},
+		{
+			name:     "dependency cycle returns 3",
+			result:   &Result{NoWork: true, Status: "invalid", Reason: ReasonInvalid},
+			expected: ExitInvalid,
+		},
+		{
+			name:     "timeout returns 4",
+			result:   &Result{Status: "failed", Reason: ReasonTimeout},
+			expected: ExitTimeout,
+		},
+		{
+			name:     "optional feature failure returns 0",
+			result:   &Result{Status: "failed", Reason: ReasonFeatureFailed, Optional: true},
+			expected: ExitSuccess,
+		},
+		{
+			name:     "optional feature over its budget returns 0",
+			result:   &Result{Status: "failed", Reason: ReasonBudgetExceeded, Optional: true},
+			expected: ExitSuccess,
+		},
+		{
+			name:     "optional feature timing out returns 0",
+			result:   &Result{Status: "failed", Reason: ReasonTimeout, Optional: true},
+			expected: ExitSuccess,
+		},
+		{
+			name:     "optional feature with unsaved progress returns 1",
+			result:   &Result{Status: "failed", Optional: true, SaveError: "disk full"},
+			expected: ExitFeatureFailed,
+		},
+		{
+			name:     "feature skipped by On-Failure returns 0",
+			result:   &Result{Status: manifest.StatusSkipped, Reason: ReasonFeatureFailed, OnFailure: parser.OnFailureSkip},
+			expected: ExitSuccess,
+		},
 	}
 
 	for _, tt := range tests {
@@ -175,6 +272,23 @@ func TestExitCode(t *testing.T) {
 	}
 }
 
+func TestDescribeResults(t *testing.T) {
+	results := []*Result{
+		{Status: "completed"},
+		{Status: manifest.StatusCompletedWithWarnings},
+		{Status: "failed"},
+		{Status: "failed", Optional: true},
+		{Status: manifest.StatusSkipped, OnFailure: parser.OnFailureSkip},
+	}
+	want := "Ran 5 features: 2 completed, 1 failed, 1 optional failed, 1 skipped"
+	if got := describeResults(results); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if got := describeResults(results[:3]); got != "Ran 3 features: 2 completed, 1 failed" {
+		t.Errorf("expected no optional or skipped counts when there are none, got %q", got)
+	}
+}
+
 func TestHandleNoRunnableFeature(t *testing.T) {
 	t.Run("all completed", func(t *testing.T) {
 		tmpDir := t.TempDir()
@@ -336,6 +450,25 @@ func TestCheckAndArchivePRD(t *testing.T) {
 		}
 	})
 
+	t.Run("archives PRD with leftover spawned children", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		prdDir := filepath.Join(tmpDir, "PRD")
+		os.Mkdir(prdDir, 0755)
+		os.WriteFile(filepath.Join(tmpDir, "test.md"), []byte("# Test PRD"), 0644)
+
+		m := manifest.New("test.md", "Test")
+		m.Features = []manifest.ManifestFeature{
+			{ID: "01", Title: "Feature 1", Status: "completed"},
+			{ID: "01-child-1", Title: "Child", Status: "failed", ParentID: "01"},
+		}
+		m.SetPath(filepath.Join(prdDir, "manifest.json"))
+		m.Save()
+
+		if archived, _ := checkAndArchivePRD(prdDir, m); !archived {
+			t.Error("expected spawned children not to hold back archiving")
+		}
+	})
+
 	t.Run("does not archive when not all features completed", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		prdDir := filepath.Join(tmpDir, "PRD")
@@ -423,3 +556,769 @@ func TestCheckAndArchivePRD(t *testing.T) {
 		}
 	})
 }
+
+func setupRunnableFeatures(t *testing.T, ids ...string) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	prdDir := filepath.Join(tmpDir, "PRD")
+
+	m := manifest.New("", "Test")
+	for _, id := range ids {
+		dir := id + "-feature"
+		os.MkdirAll(filepath.Join(prdDir, dir), 0755)
+		os.WriteFile(filepath.Join(prdDir, dir, FeatureFile), []byte("## Feature "+id), 0644)
+		m.Features = append(m.Features, manifest.ManifestFeature{
+			ID: id, Dir: dir, Title: "Feature " + id, Status: "pending",
+		})
+	}
+	m.SetPath(filepath.Join(prdDir, "manifest.json"))
+	if err := m.Save(); err != nil {
+		t.Fatalf("failed to save manifest: %v", err)
+	}
+	return tmpDir
+}
+
+func stubExecuteFeature(t *testing.T, status string) *[]string {
+	t.Helper()
+	var started []string
+	orig := executeFeature
+	executeFeature = func(workDir string, feature *manifest.ManifestFeature, prompt string, opts Options) (string, string, string) {
+		started = append(started, feature.ID)
+		if status == "failed" {
+			return status, "stub failure", ReasonFeatureFailed
+		}
+		return status, "", ""
+	}
+	t.Cleanup(func() { executeFeature = orig })
+	return &started
+}
+
+func TestRunWithOptionsPublishesLiveStatus(t *testing.T) {
+	tmpDir := setupRunnableFeatures(t, "01", "02")
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	var during *live.Status
+	orig := executeFeature
+	executeFeature = func(workDir string, feature *manifest.ManifestFeature, prompt string, opts Options) (string, string, string) {
+		if feature.ID == "02" {
+			during, _ = live.Read(tmpDir)
+			return "failed", "stub failure", ReasonFeatureFailed
+		}
+		return "completed", "", ""
+	}
+	defer func() { executeFeature = orig }()
+
+	if _, err := RunWithOptions(Options{Count: 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if during == nil || len(during.Running) != 1 || during.Running[0].ID != "02" || during.Completed != 1 {
+		t.Errorf("expected 02 running after 01 completed, got %+v", during)
+	}
+	final, err := live.Read(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to read live status: %v", err)
+	}
+	if !final.Done || len(final.Running) != 0 || final.Completed != 1 || final.Failed != 1 {
+		t.Errorf("unexpected final live status: %+v", final)
+	}
+}
+
+func TestRunWithOptionsCount(t *testing.T) {
+	t.Run("starts exactly N features when more are available", func(t *testing.T) {
+		tmpDir := setupRunnableFeatures(t, "01", "02", "03", "04")
+		origDir, _ := os.Getwd()
+		defer os.Chdir(origDir)
+		os.Chdir(tmpDir)
+
+		started := stubExecuteFeature(t, "completed")
+
+		results, err := RunWithOptions(Options{Count: 2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(*started) != 2 {
+			t.Fatalf("expected 2 features started, got %d: %v", len(*started), *started)
+		}
+		if (*started)[0] != "01" || (*started)[1] != "02" {
+			t.Errorf("expected features 01 and 02 started, got %v", *started)
+		}
+		if len(results) != 2 {
+			t.Errorf("expected 2 results, got %d", len(results))
+		}
+
+		m, _ := manifest.Load(filepath.Join(tmpDir, "PRD"))
+		if f := m.GetFeature("03"); f == nil || f.Status != "pending" {
+			t.Error("expected feature 03 to remain pending")
+		}
+	})
+
+	t.Run("counts failures toward the limit", func(t *testing.T) {
+		tmpDir := setupRunnableFeatures(t, "01", "02", "03")
+		origDir, _ := os.Getwd()
+		defer os.Chdir(origDir)
+		os.Chdir(tmpDir)
+
+		started := stubExecuteFeature(t, "failed")
+
+		results, err := RunWithOptions(Options{Count: 2})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(*started) != 2 {
+			t.Errorf("expected 2 features started, got %d", len(*started))
+		}
+		if ExitCodeAll(results) != 1 {
+			t.Error("expected exit code 1 when a feature failed")
+		}
+	})
+
+	t.Run("stops early when no runnable feature remains", func(t *testing.T) {
+		tmpDir := setupRunnableFeatures(t, "01", "02")
+		origDir, _ := os.Getwd()
+		defer os.Chdir(origDir)
+		os.Chdir(tmpDir)
+
+		started := stubExecuteFeature(t, "completed")
+
+		results, err := RunWithOptions(Options{Count: 5})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(*started) != 2 {
+			t.Errorf("expected 2 features started, got %d", len(*started))
+		}
+		if len(results) != 2 {
+			t.Errorf("expected 2 results, got %d", len(results))
+		}
+		if ExitCodeAll(results) != 0 {
+			t.Error("expected exit code 0 when all features completed")
+		}
+	})
+}
+
+func TestRunWithOptionsFailFast(t *testing.T) {
+	tmpDir := setupRunnableFeatures(t, "01", "02", "03")
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	started := stubExecuteFeature(t, "failed")
+
+	results, err := RunWithOptions(Options{Count: 3, FailFast: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*started) != 1 || (*started)[0] != "01" {
+		t.Fatalf("expected only feature 01 to start, got %v", *started)
+	}
+	if len(results) != 1 || !results[0].FailFast {
+		t.Errorf("expected a single result marked as stopped by fail-fast, got %+v", results)
+	}
+	if ExitCodeAll(results) == 0 {
+		t.Error("expected non-zero exit code under fail-fast")
+	}
+
+	m, _ := manifest.Load(filepath.Join(tmpDir, "PRD"))
+	for _, id := range []string{"02", "03"} {
+		if f := m.GetFeature(id); f == nil || f.Status != "pending" {
+			t.Errorf("expected feature %s to remain pending", id)
+		}
+	}
+}
+
+func TestRunWithOptionsReportsSaveFailure(t *testing.T) {
+	tmpDir := setupRunnableFeatures(t, "01", "02")
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	manifestPath := filepath.Join(tmpDir, "PRD", "manifest.json")
+	orig := executeFeature
+	defer func() { executeFeature = orig }()
+	started := 0
+	executeFeature = func(workDir string, feature *manifest.ManifestFeature, prompt string, opts Options) (string, string, string) {
+		started++
+		// Replace the manifest with a directory so the final save fails
+		os.Remove(manifestPath)
+		os.Mkdir(manifestPath, 0755)
+		return "completed", "", ""
+	}
+
+	results, err := RunWithOptions(Options{Count: 2})
+	if err != nil {
+		t.Fatalf("expected save failure to be reported in the result, got error: %v", err)
+	}
+	if len(results) != 1 || started != 1 {
+		t.Fatalf("expected run to stop after the failed save, got %d results and %d starts", len(results), started)
+	}
+	if results[0].SaveError == "" {
+		t.Error("expected SaveError to be set")
+	}
+	if results[0].Status != "completed" {
+		t.Errorf("expected feature outcome to be kept, got %s", results[0].Status)
+	}
+	if ExitCodeAll(results) != 1 {
+		t.Error("expected exit code 1 when progress could not be saved")
+	}
+}
+
+// startFailExecutor fails to start, as when claude can't be launched
+type startFailExecutor struct{}
+
+func (startFailExecutor) Pipes() (io.Reader, io.Reader, error) {
+	return strings.NewReader(""), strings.NewReader(""), nil
+}
+func (startFailExecutor) Start() error { return errors.New("exec: claude: permission denied") }
+func (startFailExecutor) Wait() error  { return nil }
+
+// doneExecutor replays a successful claude run
+type doneExecutor struct{}
+
+func (doneExecutor) Pipes() (io.Reader, io.Reader, error) {
+	return strings.NewReader(`{"type":"result","subtype":"success","result":"done"}` + "\n"), strings.NewReader(""), nil
+}
+func (doneExecutor) Start() error { return nil }
+func (doneExecutor) Wait() error  { return nil }
+
+func TestRunWithOptionsContinuesPastStartError(t *testing.T) {
+	tmpDir := setupRunnableFeatures(t, "01", "02", "03")
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	starts := 0
+	executorFactory = func(ctx context.Context, dir string, args []string) runner.Executor {
+		starts++
+		if starts == 2 {
+			return startFailExecutor{}
+		}
+		return doneExecutor{}
+	}
+	t.Cleanup(func() { executorFactory = nil })
+
+	results, err := RunWithOptions(Options{Count: 3})
+	if err != nil {
+		t.Fatalf("expected the start error to fail only its feature, got error: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected all 3 features to run, got %d results", len(results))
+	}
+	if results[1].Status != "failed" || !strings.Contains(results[1].Error, "permission denied") {
+		t.Errorf("expected feature 02 to fail with the start error, got %s %q", results[1].Status, results[1].Error)
+	}
+
+	m, _ := manifest.Load(filepath.Join(tmpDir, "PRD"))
+	for id, want := range map[string]string{"01": "completed", "02": "failed", "03": "completed"} {
+		if f := m.GetFeature(id); f == nil || f.Status != want {
+			t.Errorf("expected feature %s to be %s, got %+v", id, want, f)
+		}
+	}
+}
+
+func TestRunWithOptionsContinuesPastUnreadablePrompt(t *testing.T) {
+	tmpDir := setupRunnableFeatures(t, "01", "02")
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+	os.Remove(filepath.Join(tmpDir, "PRD", "01-feature", FeatureFile))
+
+	started := stubExecuteFeature(t, "completed")
+
+	results, err := RunWithOptions(Options{Count: 2})
+	if err != nil {
+		t.Fatalf("expected the missing prompt to fail only its feature, got error: %v", err)
+	}
+	if len(results) != 2 || results[0].Status != "failed" || results[0].Error == "" {
+		t.Fatalf("expected feature 01 to fail and the run to continue, got %+v", results)
+	}
+	if len(*started) != 1 || (*started)[0] != "02" {
+		t.Errorf("expected only feature 02 to be executed, got %v", *started)
+	}
+}
+
+func TestRunWithOptionsRecoversFeaturePanic(t *testing.T) {
+	tmpDir := setupRunnableFeatures(t, "01", "02")
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	orig := executeFeature
+	t.Cleanup(func() { executeFeature = orig })
+	executeFeature = func(workDir string, feature *manifest.ManifestFeature, prompt string, opts Options) (string, string, string) {
+		if feature.ID == "01" {
+			panic("worktree vanished")
+		}
+		return "completed", "", ""
+	}
+
+	results, err := RunWithOptions(Options{Count: 2, ParallelRoots: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	statuses := map[string]string{}
+	for _, r := range results {
+		statuses[r.FeatureID] = r.Status
+	}
+	if statuses["01"] != "failed" || statuses["02"] != "completed" {
+		t.Errorf("expected 01 failed and 02 completed, got %v", statuses)
+	}
+}
+
+func TestResolvePRDDir(t *testing.T) {
+	writeManifest := func(t *testing.T, prdDir string) {
+		t.Helper()
+		os.MkdirAll(prdDir, 0755)
+		m := manifest.New("", "Test")
+		m.SetPath(filepath.Join(prdDir, ManifestFile))
+		if err := m.Save(); err != nil {
+			t.Fatalf("failed to save manifest: %v", err)
+		}
+	}
+
+	tmpDir := t.TempDir()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	defaultDir := filepath.Join(tmpDir, PRDDirName)
+	writeManifest(t, defaultDir)
+	explicitDir := filepath.Join(tmpDir, "prds", "billing")
+	writeManifest(t, explicitDir)
+
+	t.Run("prefers explicit directory over discovered PRD/", func(t *testing.T) {
+		got, err := ResolvePRDDir("prds/billing")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != explicitDir {
+			t.Errorf("expected %s, got %s", explicitDir, got)
+		}
+	})
+
+	t.Run("falls back to discovery when no directory given", func(t *testing.T) {
+		got, err := ResolvePRDDir("")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != defaultDir {
+			t.Errorf("expected %s, got %s", defaultDir, got)
+		}
+	})
+
+	t.Run("does not fall back when explicit directory is invalid", func(t *testing.T) {
+		os.MkdirAll(filepath.Join(tmpDir, "empty"), 0755)
+		if _, err := ResolvePRDDir("empty"); err == nil {
+			t.Error("expected error when explicit directory has no manifest")
+		}
+		if _, err := ResolvePRDDir("missing"); err == nil {
+			t.Error("expected error when explicit directory does not exist")
+		}
+	})
+}
+
+func TestRunWithOptionsTerminatingReasons(t *testing.T) {
+	for _, tt := range []struct {
+		reason   string
+		expected int
+	}{
+		{ReasonFeatureFailed, ExitFeatureFailed},
+		{ReasonBudgetExceeded, ExitBudgetExceeded},
+		{ReasonTimeout, ExitTimeout},
+	} {
+		t.Run(tt.reason, func(t *testing.T) {
+			tmpDir := setupRunnableFeatures(t, "01", "02")
+			origDir, _ := os.Getwd()
+			defer os.Chdir(origDir)
+			os.Chdir(tmpDir)
+
+			orig := executeFeature
+			defer func() { executeFeature = orig }()
+			executeFeature = func(workDir string, feature *manifest.ManifestFeature, prompt string, opts Options) (string, string, string) {
+				if feature.ID == "01" {
+					return "completed", "", ""
+				}
+				return "failed", "stub failure", tt.reason
+			}
+
+			results, err := RunWithOptions(Options{Count: 2})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(results) != 2 || results[1].Reason != tt.reason {
+				t.Fatalf("expected second result to carry reason %s, got %+v", tt.reason, results)
+			}
+			if code := ExitCodeAll(results); code != tt.expected {
+				t.Errorf("expected exit code %d, got %d", tt.expected, code)
+			}
+		})
+	}
+}
+
+func TestRunWithOptionsDependencyCycle(t *testing.T) {
+	tmpDir := setupRunnableFeatures(t, "01", "02")
+	prdDir := filepath.Join(tmpDir, "PRD")
+	m, err := manifest.Load(prdDir)
+	if err != nil {
+		t.Fatalf("failed to load manifest: %v", err)
+	}
+	m.Features[0].DependsOn = []string{"02"}
+	m.Features[1].DependsOn = []string{"01"}
+	if err := m.Save(); err != nil {
+		t.Fatalf("failed to save manifest: %v", err)
+	}
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	started := stubExecuteFeature(t, "completed")
+
+	results, err := RunWithOptions(Options{Count: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*started) != 0 {
+		t.Errorf("expected no features started, got %v", *started)
+	}
+	if len(results) != 1 || results[0].Reason != ReasonInvalid {
+		t.Fatalf("expected a single invalid result, got %+v", results)
+	}
+	if code := ExitCodeAll(results); code != ExitInvalid {
+		t.Errorf("expected exit code %d, got %d", ExitInvalid, code)
+	}
+}
+
+func TestRunWithOptionsGroup(t *testing.T) {
+	tmpDir := setupRunnableFeatures(t, "01", "02", "03")
+	prdDir := filepath.Join(tmpDir, "PRD")
+	m, _ := manifest.Load(prdDir)
+	m.Features[1].Group = "Billing"
+	m.Features[2].Group = "Billing"
+	if err := m.Save(); err != nil {
+		t.Fatalf("failed to save manifest: %v", err)
+	}
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	started := stubExecuteFeature(t, "completed")
+
+	if _, err := RunWithOptions(Options{Count: 5, Group: "billing"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(*started) != 2 || (*started)[0] != "02" || (*started)[1] != "03" {
+		t.Errorf("expected only the Billing features 02 and 03 to run, got %v", *started)
+	}
+
+	results, err := RunWithOptions(Options{Count: 1, Group: "Billing"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !results[0].NoWork || results[0].Status != "all_completed" {
+		t.Errorf("expected a completed group to report all_completed, got %+v", results[0])
+	}
+
+	if _, err := RunWithOptions(Options{Group: "Shipping"}); err == nil {
+		t.Error("expected an error for an unknown group")
+	}
+}
+
+func TestRunWithOptionsSkipsDisabledFeatures(t *testing.T) {
+	tmpDir := setupRunnableFeatures(t, "01", "02", "03")
+	prdDir := filepath.Join(tmpDir, "PRD")
+	m, _ := manifest.Load(prdDir)
+	m.Features[0].Disabled = true
+	m.Features[2].DependsOn = []string{"01"}
+	if err := m.Save(); err != nil {
+		t.Fatalf("failed to save manifest: %v", err)
+	}
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	started := stubExecuteFeature(t, "completed")
+
+	results, err := RunWithOptions(Options{Count: 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(*started, ",") != "02,03" {
+		t.Errorf("expected only features 02 and 03 to run, got %v", *started)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	results, err = RunWithOptions(Options{Count: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !results[0].NoWork || results[0].Status != "all_completed" {
+		t.Errorf("expected all features completed once only the disabled one is left, got %s", results[0].Status)
+	}
+}
+
+func TestRunWithOptionsIgnoresSpawnedChildren(t *testing.T) {
+	tmpDir := setupRunnableFeatures(t, "01")
+	prdDir := filepath.Join(tmpDir, "PRD")
+	m, _ := manifest.Load(prdDir)
+	m.Features = append(m.Features,
+		manifest.ManifestFeature{ID: "01-child-1", Title: "Child", Status: "failed", ParentID: "01"},
+		manifest.ManifestFeature{ID: "01-child-2", Title: "Leftover", Status: "pending", ParentID: "01"},
+	)
+	if err := m.Save(); err != nil {
+		t.Fatalf("failed to save manifest: %v", err)
+	}
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	started := stubExecuteFeature(t, "completed")
+
+	if _, err := RunWithOptions(Options{Count: 5}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(*started, ",") != "01" {
+		t.Errorf("expected only the root feature to run, got %v", *started)
+	}
+
+	results, err := RunWithOptions(Options{Count: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !results[0].NoWork || results[0].Status != "all_completed" {
+		t.Errorf("expected all features completed despite the persisted children, got %s", results[0].Status)
+	}
+}
+
+// runWithFailingFirst runs the given features with 01 failing and the rest
+// completing, after configure has set up the manifest, and returns the
+// results along with the manifest as the run left it
+func runWithFailingFirst(t *testing.T, ids []string, configure func(m *manifest.Manifest), opts Options) ([]*Result, *manifest.Manifest) {
+	t.Helper()
+	tmpDir := setupRunnableFeatures(t, ids...)
+	prdDir := filepath.Join(tmpDir, "PRD")
+	m, _ := manifest.Load(prdDir)
+	configure(m)
+	if err := m.Save(); err != nil {
+		t.Fatalf("failed to save manifest: %v", err)
+	}
+
+	origDir, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(origDir) })
+	os.Chdir(tmpDir)
+
+	orig := executeFeature
+	executeFeature = func(workDir string, feature *manifest.ManifestFeature, prompt string, opts Options) (string, string, string) {
+		if feature.ID == "01" {
+			return "failed", "stub failure", ReasonFeatureFailed
+		}
+		return "completed", "", ""
+	}
+	t.Cleanup(func() { executeFeature = orig })
+
+	results, err := RunWithOptions(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m, _ = manifest.Load(prdDir)
+	return results, m
+}
+
+func TestRunWithOptionsOptionalFailureDoesNotBlock(t *testing.T) {
+	results, _ := runWithFailingFirst(t, []string{"01", "02"}, func(m *manifest.Manifest) {
+		m.Features[0].Optional = true
+		m.Features[1].DependsOn = []string{"01"}
+	}, Options{Count: 2, FailFast: true})
+
+	if len(results) != 2 {
+		t.Fatalf("expected the dependent to run after the optional failure, got %d results", len(results))
+	}
+	if results[0].FailFast {
+		t.Error("expected --fail-fast to ignore an optional failure")
+	}
+	if results[1].FeatureID != "02" || results[1].Status != "completed" {
+		t.Errorf("expected feature 02 completed, got %s %s", results[1].FeatureID, results[1].Status)
+	}
+	if code := ExitCodeAll(results); code != ExitSuccess {
+		t.Errorf("expected exit code 0, got %d", code)
+	}
+}
+
+// runWithOnFailure runs three features with 01 failing under the given
+// On-Failure: action; 03 depends on 01
+func runWithOnFailure(t *testing.T, action string) ([]*Result, *manifest.Manifest) {
+	t.Helper()
+	return runWithFailingFirst(t, []string{"01", "02", "03"}, func(m *manifest.Manifest) {
+		m.Features[0].OnFailure = action
+		m.Features[2].DependsOn = []string{"01"}
+	}, Options{Count: 3})
+}
+
+func TestRunWithOptionsOnFailure(t *testing.T) {
+	t.Run("skip marks the feature skipped and continues", func(t *testing.T) {
+		results, m := runWithOnFailure(t, parser.OnFailureSkip)
+
+		if len(results) != 2 || results[1].FeatureID != "02" {
+			t.Fatalf("expected 01 then 02 to run, got %d results", len(results))
+		}
+		if results[0].Status != manifest.StatusSkipped {
+			t.Errorf("expected result status skipped, got %q", results[0].Status)
+		}
+		if f := m.GetFeature("01"); f.Status != manifest.StatusSkipped {
+			t.Errorf("expected manifest status skipped, got %q", f.Status)
+		}
+		if f := m.GetFeature("03"); f.Status != "pending" {
+			t.Errorf("expected the dependent to stay pending, got %q", f.Status)
+		}
+		if code := ExitCodeAll(results); code != ExitSuccess {
+			t.Errorf("expected exit code 0, got %d", code)
+		}
+	})
+
+	t.Run("abort stops the run", func(t *testing.T) {
+		results, m := runWithOnFailure(t, parser.OnFailureAbort)
+
+		if len(results) != 1 {
+			t.Fatalf("expected the run to stop after 01, got %d results", len(results))
+		}
+		if results[0].Status != "failed" || !results[0].Aborted {
+			t.Errorf("expected 01 failed and aborting the run, got %q aborted=%v", results[0].Status, results[0].Aborted)
+		}
+		if f := m.GetFeature("02"); f.Status != "pending" {
+			t.Errorf("expected 02 not to run, got %q", f.Status)
+		}
+		if code := ExitCodeAll(results); code != ExitFeatureFailed {
+			t.Errorf("expected exit code 1, got %d", code)
+		}
+	})
+
+	t.Run("continue leaves the feature failed and carries on", func(t *testing.T) {
+		results, m := runWithOnFailure(t, parser.OnFailureContinue)
+
+		if len(results) != 2 || results[1].FeatureID != "02" {
+			t.Fatalf("expected 01 then 02 to run, got %d results", len(results))
+		}
+		if results[0].Status != "failed" || results[0].Aborted {
+			t.Errorf("expected 01 failed without aborting, got %q aborted=%v", results[0].Status, results[0].Aborted)
+		}
+		if f := m.GetFeature("01"); f.Status != "failed" {
+			t.Errorf("expected manifest status failed, got %q", f.Status)
+		}
+		if code := ExitCodeAll(results); code != ExitFeatureFailed {
+			t.Errorf("expected exit code 1, got %d", code)
+		}
+	})
+}
+
+func TestRecordRunMetaReplacesEarlierRun(t *testing.T) {
+	prdDir := t.TempDir()
+	progress := state.NewProgress()
+	progress.SetPathDirect(filepath.Join(prdDir, "progress.json"))
+	progress.InitFeature("01", "Kept")
+	progress.SetRunMeta("ticket", "PROJ-1")
+	progress.SetRunMeta("env", "staging")
+	if err := progress.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	recordRunMeta(prdDir, map[string]string{"git_sha": "abc123", "ticket": "PROJ-2"})
+
+	loaded, err := state.LoadProgressFromPath(filepath.Join(prdDir, "progress.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := state.FormatRunMeta(loaded.GetRunMeta()); got != "git_sha=abc123 ticket=PROJ-2" {
+		t.Errorf("unexpected run metadata %q", got)
+	}
+	if loaded.GetFeature("01") == nil {
+		t.Error("expected existing progress to be kept")
+	}
+
+	recordRunMeta(prdDir, nil)
+	loaded, err = state.LoadProgressFromPath(filepath.Join(prdDir, "progress.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.GetRunMeta(); len(got) != 0 {
+		t.Errorf("expected a run without metadata to clear it, got %v", got)
+	}
+}
+
+func TestRunWithOptionsRetriesFromManifest(t *testing.T) {
+	tmpDir := setupRunnableFeatures(t, "01")
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	prdDir := filepath.Join(tmpDir, "PRD")
+	m, err := manifest.Load(prdDir)
+	if err != nil {
+		t.Fatalf("failed to load manifest: %v", err)
+	}
+	m.Retries = 2
+	if err := m.Save(); err != nil {
+		t.Fatalf("failed to save manifest: %v", err)
+	}
+
+	t.Run("retries a failed feature", func(t *testing.T) {
+		attempts := 0
+		orig := executeFeature
+		executeFeature = func(workDir string, feature *manifest.ManifestFeature, prompt string, opts Options) (string, string, string) {
+			attempts++
+			if attempts == 1 {
+				return "failed", "stub failure", ReasonFeatureFailed
+			}
+			return "completed", "", ""
+		}
+		defer func() { executeFeature = orig }()
+
+		results, err := RunWithOptions(Options{Count: 1})
+		if err != nil {
+			t.Fatalf("RunWithOptions: %v", err)
+		}
+		if attempts != 2 || results[0].Status != "completed" || results[0].Retries != 1 {
+			t.Errorf("expected one retry to complete the feature, got %d attempts, status %q, %d retries",
+				attempts, results[0].Status, results[0].Retries)
+		}
+	})
+
+	t.Run("stops after the manifest's retries", func(t *testing.T) {
+		m.UpdateFeatureStatus("01", "pending")
+		m.Save()
+		started := stubExecuteFeature(t, "failed")
+
+		results, err := RunWithOptions(Options{Count: 1})
+		if err != nil {
+			t.Fatalf("RunWithOptions: %v", err)
+		}
+		if len(*started) != 3 || results[0].Status != "failed" || results[0].Retries != 2 {
+			t.Errorf("expected 3 attempts, got %d, status %q, %d retries", len(*started), results[0].Status, results[0].Retries)
+		}
+	})
+
+	t.Run("doesn't retry a timeout", func(t *testing.T) {
+		m.UpdateFeatureStatus("01", "pending")
+		m.Save()
+		attempts := 0
+		orig := executeFeature
+		executeFeature = func(workDir string, feature *manifest.ManifestFeature, prompt string, opts Options) (string, string, string) {
+			attempts++
+			return "failed", "feature timed out after 1s", ReasonTimeout
+		}
+		defer func() { executeFeature = orig }()
+
+		if _, err := RunWithOptions(Options{Count: 1}); err != nil {
+			t.Fatalf("RunWithOptions: %v", err)
+		}
+		if attempts != 1 {
+			t.Errorf("expected a timed out feature not to be retried, got %d attempts", attempts)
+		}
+	})
+}
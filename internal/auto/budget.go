@@ -0,0 +1,113 @@
+package auto
+
+import (
+	"sync"
+
+	"github.com/vx/ralph-go/internal/manifest"
+	"github.com/vx/ralph-go/internal/runner"
+)
+
+// runBudget tracks what a headless run spends against the manifest's global
+// budget. Each feature runs on a runner.Manager of its own, so the run's
+// spending is kept here: what finished features spent plus what running
+// ones have spent so far, which parallel features all check against.
+//
+// The TUI stops starting features at the budget's threshold until it's
+// acknowledged, which nobody can do headless, so the run stops there unless
+// keepGoing pre-acknowledges it. Running out of budget always stops the
+// features that are running.
+type runBudget struct {
+	mu          sync.Mutex
+	tokens      int64
+	usd         float64
+	keepGoing   bool
+	spentTokens int64
+	spentUSD    float64
+	running     map[*runner.Instance]bool
+}
+
+// newRunBudget returns the run's budget, or nil if the manifest sets no
+// global budget
+func newRunBudget(m *manifest.Manifest, keepGoing bool) *runBudget {
+	if !m.HasGlobalBudget() {
+		return nil
+	}
+	tokens, usd := m.GetGlobalBudget()
+	return &runBudget{tokens: tokens, usd: usd, keepGoing: keepGoing, running: make(map[*runner.Instance]bool)}
+}
+
+// apply sets the global budget, and what the run has spent so far, on a
+// feature's manager, so its start-time budget checks count the whole run
+func (b *runBudget) apply(mgr *runner.Manager) {
+	if b == nil {
+		return
+	}
+	tokens, usd := b.spent()
+	mgr.SetGlobalBudget(b.tokens, b.usd)
+	mgr.AddPriorUsage(tokens, usd)
+	if b.keepGoing {
+		mgr.AcknowledgeBudget()
+	}
+}
+
+// start counts a started feature's usage toward the run's spending as it
+// runs
+func (b *runBudget) start(instance *runner.Instance) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.running[instance] = true
+}
+
+// record moves a finished feature's usage into what the run has spent
+func (b *runBudget) record(instance *runner.Instance) {
+	if b == nil {
+		return
+	}
+	u := instance.GetUsage()
+	cost := instance.GetEstimatedCost()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.running, instance)
+	b.spentTokens += u.TotalTokens
+	b.spentUSD += cost
+}
+
+// spent returns what the run has spent, counting running features' usage
+// so far
+func (b *runBudget) spent() (tokens int64, usd float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	tokens, usd = b.spentTokens, b.spentUSD
+	for instance := range b.running {
+		u := instance.GetUsage()
+		tokens += u.TotalTokens
+		usd += instance.GetEstimatedCost()
+	}
+	return tokens, usd
+}
+
+// exceeded reports whether the run has spent its whole budget
+func (b *runBudget) exceeded() bool {
+	if b == nil {
+		return false
+	}
+	tokens, usd := b.spent()
+	_, _, overBudget := runner.CheckBudget(b.tokens, b.usd, tokens, usd)
+	return overBudget
+}
+
+// stopsRun reports whether no further feature should start: the run is out
+// of budget, or has reached the threshold without it being acknowledged. The
+// budget's status is returned to explain why.
+func (b *runBudget) stopsRun() (bool, string) {
+	if b == nil {
+		return false, ""
+	}
+	tokens, usd := b.spent()
+	_, atThreshold, overBudget := runner.CheckBudget(b.tokens, b.usd, tokens, usd)
+	stop := overBudget || (atThreshold && !b.keepGoing)
+	return stop, runner.FormatBudgetStatus(b.tokens, b.usd, tokens, usd)
+}
@@ -0,0 +1,231 @@
+package auto
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vx/ralph-go/internal/manifest"
+	"github.com/vx/ralph-go/internal/runner"
+)
+
+// usageExecutor replays a successful claude run that used tokens
+type usageExecutor struct {
+	tokens int
+}
+
+func (e usageExecutor) Pipes() (io.Reader, io.Reader, error) {
+	result := fmt.Sprintf(`{"type":"result","subtype":"success","result":"done","usage":{"input_tokens":%d,"output_tokens":0}}`, e.tokens)
+	return strings.NewReader(result + "\n"), strings.NewReader(""), nil
+}
+func (usageExecutor) Start() error { return nil }
+func (usageExecutor) Wait() error  { return nil }
+
+// setupBudgetRun sets up features with a 1000 token PRD budget, where the
+// n'th feature started uses tokens[n]
+func setupBudgetRun(t *testing.T, tokens ...int) string {
+	t.Helper()
+	ids := make([]string, len(tokens))
+	for i := range tokens {
+		ids[i] = fmt.Sprintf("%02d", i+1)
+	}
+	tmpDir := setupRunnableFeatures(t, ids...)
+	m, err := manifest.Load(filepath.Join(tmpDir, "PRD"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.BudgetTokens = 1000
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	starts := 0
+	executorFactory = func(ctx context.Context, dir string, args []string) runner.Executor {
+		starts++
+		return usageExecutor{tokens: tokens[starts-1]}
+	}
+	t.Cleanup(func() { executorFactory = nil })
+
+	origDir, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(origDir) })
+	os.Chdir(tmpDir)
+	return tmpDir
+}
+
+func TestRunWithOptionsStopsAtBudgetThreshold(t *testing.T) {
+	tmpDir := setupBudgetRun(t, 900, 50)
+
+	results, err := RunWithOptions(Options{Count: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected the run to stop at the threshold after 1 feature, got %d results", len(results))
+	}
+	if results[0].Status != "completed" || results[0].BudgetStopped == "" {
+		t.Errorf("expected a completed feature that stopped the run, got %+v", results[0])
+	}
+	if ExitCodeAll(results) != ExitBudgetExceeded {
+		t.Errorf("expected exit code %d, got %d", ExitBudgetExceeded, ExitCodeAll(results))
+	}
+
+	m, _ := manifest.Load(filepath.Join(tmpDir, "PRD"))
+	if f := m.GetFeature("02"); f == nil || f.Status != "pending" {
+		t.Error("expected feature 02 to remain pending")
+	}
+}
+
+func TestRunWithOptionsKeepGoingOnBudget(t *testing.T) {
+	setupBudgetRun(t, 900, 50)
+
+	results, err := RunWithOptions(Options{Count: 2, KeepGoingOnBudget: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected the run to carry on past the threshold, got %d results", len(results))
+	}
+	for _, result := range results {
+		if result.Status != "completed" || result.BudgetStopped != "" {
+			t.Errorf("expected feature %s to complete without stopping the run, got %+v", result.FeatureID, result)
+		}
+	}
+	if ExitCodeAll(results) != ExitSuccess {
+		t.Errorf("expected exit code 0, got %d", ExitCodeAll(results))
+	}
+}
+
+func TestRunWithOptionsKeepGoingOnBudgetStopsWhenSpent(t *testing.T) {
+	tmpDir := setupBudgetRun(t, 900, 200, 10)
+
+	results, err := RunWithOptions(Options{Count: 3, KeepGoingOnBudget: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected no feature to start once the budget ran out, got %d results", len(results))
+	}
+	if ExitCodeAll(results) != ExitBudgetExceeded {
+		t.Errorf("expected exit code %d, got %d", ExitBudgetExceeded, ExitCodeAll(results))
+	}
+
+	m, _ := manifest.Load(filepath.Join(tmpDir, "PRD"))
+	if f := m.GetFeature("03"); f == nil || f.Status != "pending" {
+		t.Error("expected feature 03 to remain pending")
+	}
+}
+
+// spendingExecutor stands in for a claude process that uses tokens and then
+// keeps running until it's stopped
+type spendingExecutor struct {
+	ctx    context.Context
+	tokens int
+}
+
+func (e spendingExecutor) Pipes() (io.Reader, io.Reader, error) {
+	pr, pw := io.Pipe()
+	go func() {
+		fmt.Fprintf(pw, `{"type":"assistant","usage":{"input_tokens":%d,"output_tokens":0}}`+"\n", e.tokens)
+		<-e.ctx.Done()
+		pw.Close()
+	}()
+	return pr, strings.NewReader(""), nil
+}
+func (spendingExecutor) Start() error { return nil }
+func (e spendingExecutor) Wait() error {
+	<-e.ctx.Done()
+	return e.ctx.Err()
+}
+
+func TestRunWithOptionsParallelFeaturesShareBudget(t *testing.T) {
+	tmpDir := setupBudgetRun(t, 0, 0)
+	m, err := manifest.Load(filepath.Join(tmpDir, "PRD"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range m.Features {
+		m.Features[i].Execution = "parallel"
+	}
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+	executorFactory = func(ctx context.Context, dir string, args []string) runner.Executor {
+		return spendingExecutor{ctx: ctx, tokens: 600}
+	}
+
+	// Neither feature is over the 1000 token budget on its own, only
+	// together
+	results, err := RunWithOptions(Options{Count: 2, ParallelRoots: true, KeepGoingOnBudget: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected both features to run, got %d results", len(results))
+	}
+	for _, result := range results {
+		if result.Reason != ReasonBudgetExceeded {
+			t.Errorf("expected feature %s to be stopped by the shared budget, got %+v", result.FeatureID, result)
+		}
+	}
+}
+
+func TestRunWithOptionsWritesCostLedger(t *testing.T) {
+	tmpDir := setupBudgetRun(t, 100, 200)
+	csvPath := filepath.Join(tmpDir, "costs.csv")
+
+	if _, err := RunWithOptions(Options{Count: 2, CostCSV: csvPath}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatalf("expected a cost ledger: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and a row per attempt, got:\n%s", data)
+	}
+	for i, want := range []string{"01,Feature 01,1,", "02,Feature 02,1,"} {
+		if !strings.HasPrefix(lines[i+1], want) {
+			t.Errorf("expected row %d to start %q, got %q", i+1, want, lines[i+1])
+		}
+	}
+	if !strings.Contains(lines[2], ",200,0,0,0,") {
+		t.Errorf("expected feature 02's 200 input tokens, got %q", lines[2])
+	}
+}
+
+func TestRunWithOptionsOptionalOverBudgetExitsZero(t *testing.T) {
+	tmpDir := setupRunnableFeatures(t, "01")
+	m, err := manifest.Load(filepath.Join(tmpDir, "PRD"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Features[0].Optional = true
+	m.Features[0].BudgetTokens = 100
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+	executorFactory = func(ctx context.Context, dir string, args []string) runner.Executor {
+		return spendingExecutor{ctx: ctx, tokens: 500}
+	}
+	t.Cleanup(func() { executorFactory = nil })
+	origDir, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(origDir) })
+	os.Chdir(tmpDir)
+
+	results, err := RunWithOptions(Options{Count: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 || results[0].Reason != ReasonBudgetExceeded {
+		t.Fatalf("expected the optional feature stopped by its budget, got %+v", results)
+	}
+	if code := ExitCodeAll(results); code != ExitSuccess {
+		t.Errorf("expected an optional feature over budget to exit 0, got %d", code)
+	}
+}
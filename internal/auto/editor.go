@@ -0,0 +1,96 @@
+package auto
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/vx/ralph-go/internal/manifest"
+)
+
+// FailureLogDir holds the error log written for each failed feature
+const FailureLogDir = ".ralph/failures"
+
+// stdinIsTerminal reports whether stdin is an interactive terminal. It is a
+// variable so tests can simulate either case.
+var stdinIsTerminal = func() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// runEditor opens files in editor and waits for it to exit. It is a variable
+// so tests can run without an editor.
+var runEditor = func(editor string, files []string) error {
+	fields := strings.Fields(editor)
+	cmd := exec.Command(fields[0], append(fields[1:], files...)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// shouldOpenEditor reports whether a finished feature should be opened in
+// $EDITOR: only failures, only when asked, and only when someone is at the
+// terminal to close the editor again
+func shouldOpenEditor(opts Options, result *Result) bool {
+	return opts.OpenEditorOnFail && result.Status == "failed" && stdinIsTerminal()
+}
+
+// EditorCommand returns $VISUAL, then $EDITOR, then vi
+func EditorCommand() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.TrimSpace(os.Getenv(env)); editor != "" {
+			return editor
+		}
+	}
+	return "vi"
+}
+
+// openEditorOnFailure writes the feature's error log and opens it with the
+// feature spec in the user's editor, returning once the editor exits
+func openEditorOnFailure(prdDir, workDir string, feature *manifest.ManifestFeature, result *Result, opts Options) error {
+	if !shouldOpenEditor(opts, result) {
+		return nil
+	}
+
+	logPath, err := writeFailureLog(workDir, feature, result)
+	if err != nil {
+		return err
+	}
+	specPath := filepath.Join(prdDir, feature.Dir, FeatureFile)
+
+	return runEditor(EditorCommand(), []string{specPath, logPath})
+}
+
+// writeFailureLog writes the outcome of a failed feature to
+// .ralph/failures/<id>.log and returns its path
+func writeFailureLog(workDir string, feature *manifest.ManifestFeature, result *Result) (string, error) {
+	dir := filepath.Join(workDir, FailureLogDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Feature: %s %s\n", feature.ID, feature.Title))
+	sb.WriteString(fmt.Sprintf("Failed at: %s\n", time.Now().Format(time.RFC3339)))
+	sb.WriteString(fmt.Sprintf("Duration: %s\n", result.Duration.Round(time.Second)))
+	if result.Reason != "" {
+		sb.WriteString(fmt.Sprintf("Reason: %s\n", result.Reason))
+	}
+	sb.WriteString("\n")
+	if result.Error != "" {
+		sb.WriteString(result.Error)
+		sb.WriteString("\n")
+	} else {
+		sb.WriteString("No error message was reported.\n")
+	}
+
+	path := filepath.Join(dir, feature.ID+".log")
+	if err := os.WriteFile(path, []byte(sb.String()), 0644); err != nil {
+		return "", fmt.Errorf("failed to write failure log: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,107 @@
+package auto
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vx/ralph-go/internal/manifest"
+)
+
+func stubTerminal(t *testing.T, tty bool) {
+	t.Helper()
+	orig := stdinIsTerminal
+	stdinIsTerminal = func() bool { return tty }
+	t.Cleanup(func() { stdinIsTerminal = orig })
+}
+
+func stubEditor(t *testing.T) *[][]string {
+	t.Helper()
+	var calls [][]string
+	orig := runEditor
+	runEditor = func(editor string, files []string) error {
+		calls = append(calls, append([]string{editor}, files...))
+		return nil
+	}
+	t.Cleanup(func() { runEditor = orig })
+	return &calls
+}
+
+func TestShouldOpenEditor(t *testing.T) {
+	tests := []struct {
+		name   string
+		flag   bool
+		tty    bool
+		status string
+		want   bool
+	}{
+		{"failed on a terminal", true, true, "failed", true},
+		{"failed without a terminal", true, false, "failed", false},
+		{"flag not set", false, true, "failed", false},
+		{"completed feature", true, true, "completed", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubTerminal(t, tt.tty)
+			got := shouldOpenEditor(Options{OpenEditorOnFail: tt.flag}, &Result{Status: tt.status})
+			if got != tt.want {
+				t.Errorf("shouldOpenEditor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenEditorOnFailure(t *testing.T) {
+	workDir := t.TempDir()
+	prdDir := filepath.Join(workDir, "PRD")
+	feature := &manifest.ManifestFeature{ID: "02", Dir: "02-auth", Title: "Auth"}
+	result := &Result{Status: "failed", Error: "tests failed: 3", Reason: ReasonFeatureFailed}
+	opts := Options{OpenEditorOnFail: true}
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "code -w")
+
+	t.Run("opens spec and error log on a terminal", func(t *testing.T) {
+		stubTerminal(t, true)
+		calls := stubEditor(t)
+
+		if err := openEditorOnFailure(prdDir, workDir, feature, result, opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(*calls) != 1 {
+			t.Fatalf("expected the editor to open once, got %d", len(*calls))
+		}
+
+		call := (*calls)[0]
+		logPath := filepath.Join(workDir, FailureLogDir, "02.log")
+		want := []string{"code -w", filepath.Join(prdDir, "02-auth", FeatureFile), logPath}
+		if strings.Join(call, "|") != strings.Join(want, "|") {
+			t.Errorf("expected editor call %v, got %v", want, call)
+		}
+
+		data, err := os.ReadFile(logPath)
+		if err != nil {
+			t.Fatalf("expected failure log to be written: %v", err)
+		}
+		if !strings.Contains(string(data), "tests failed: 3") || !strings.Contains(string(data), "Reason: feature_failed") {
+			t.Errorf("expected failure log to include the error and reason, got:\n%s", data)
+		}
+	})
+
+	t.Run("does nothing without a terminal", func(t *testing.T) {
+		stubTerminal(t, false)
+		calls := stubEditor(t)
+		noTTYDir := t.TempDir()
+
+		if err := openEditorOnFailure(prdDir, noTTYDir, feature, result, opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(*calls) != 0 {
+			t.Errorf("expected no editor without a terminal, got %v", *calls)
+		}
+		if _, err := os.Stat(filepath.Join(noTTYDir, FailureLogDir)); !os.IsNotExist(err) {
+			t.Error("expected no failure log without a terminal")
+		}
+	})
+}
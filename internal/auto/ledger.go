@@ -0,0 +1,69 @@
+package auto
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/vx/ralph-go/internal/manifest"
+	"github.com/vx/ralph-go/internal/runner"
+	"github.com/vx/ralph-go/internal/state"
+)
+
+// costLedger accumulates a row per attempt for CostCSV. Attempts are
+// numbered within this run.
+type costLedger struct {
+	mu       sync.Mutex
+	entries  []state.LedgerEntry
+	attempts map[string]int
+}
+
+func newCostLedger() *costLedger {
+	return &costLedger{attempts: make(map[string]int)}
+}
+
+// recordAttempt adds a finished attempt's usage to the run's cost ledger, if
+// one is being kept
+func recordAttempt(ledger *costLedger, feature *manifest.ManifestFeature, instance *runner.Instance) {
+	if ledger == nil {
+		return
+	}
+	u := instance.GetUsage()
+	entry := state.LedgerEntry{FeatureID: feature.ID, Title: feature.Title, AttemptUsage: state.AttemptUsage{
+		Model:        instance.GetCurrentModel(),
+		InputTokens:  u.InputTokens,
+		OutputTokens: u.OutputTokens,
+		CacheRead:    u.CacheReadTokens,
+		CacheWrite:   u.CacheWriteTokens,
+		Cost:         instance.GetEstimatedCost(),
+		Timestamp:    time.Now(),
+	}}
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+	ledger.attempts[feature.ID]++
+	entry.Attempt = ledger.attempts[feature.ID]
+	ledger.entries = append(ledger.entries, entry)
+}
+
+// writeCostLedger writes the ledger to path as CSV. The features have already
+// run, so a failure is reported rather than failing the run.
+func writeCostLedger(path string, ledger *costLedger) {
+	if err := saveCostLedger(path, ledger); err != nil {
+		fmt.Printf("Warning: could not write cost ledger: %s\n", err)
+	}
+}
+
+func saveCostLedger(path string, ledger *costLedger) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	ledger.mu.Lock()
+	defer ledger.mu.Unlock()
+	if err := state.WriteLedgerCSV(f, ledger.entries); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
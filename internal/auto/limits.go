@@ -0,0 +1,48 @@
+package auto
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/vx/ralph-go/internal/manifest"
+	"github.com/vx/ralph-go/internal/runner"
+)
+
+// featureLimits stop a headless feature before it finishes on its own: its
+// budget, the run's global budget and its timeout. Nobody is watching to
+// stop it by hand, and these are the conditions ExitBudgetExceeded and
+// ExitTimeout report.
+type featureLimits struct {
+	timeout  time.Duration
+	deadline time.Time // Zero when the feature has no timeout
+	budget   *runBudget
+}
+
+// newFeatureLimits returns the limits of a feature started at start. Its
+// timeout is opts.Timeout, the --timeout given on the command line, or the
+// feature's own Timeout if no --timeout was given.
+func newFeatureLimits(feature *manifest.ManifestFeature, opts Options, start time.Time) featureLimits {
+	l := featureLimits{timeout: opts.Timeout, budget: opts.budget}
+	if l.timeout <= 0 {
+		l.timeout = feature.TimeoutDuration()
+	}
+	if l.timeout > 0 {
+		l.deadline = start.Add(l.timeout)
+	}
+	return l
+}
+
+// exceeded returns the error and reason for the first limit the running
+// instance has passed at now, or an empty reason if it is within them all
+func (l featureLimits) exceeded(instance *runner.Instance, now time.Time) (errMsg, reason string) {
+	if _, _, overBudget := instance.CheckBudget(); overBudget {
+		return "feature exceeded its budget", ReasonBudgetExceeded
+	}
+	if l.budget.exceeded() {
+		return "run exceeded the global budget", ReasonBudgetExceeded
+	}
+	if !l.deadline.IsZero() && now.After(l.deadline) {
+		return fmt.Sprintf("feature timed out after %s", l.timeout), ReasonTimeout
+	}
+	return "", ""
+}
@@ -0,0 +1,73 @@
+package auto
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vx/ralph-go/internal/manifest"
+	"github.com/vx/ralph-go/internal/runner"
+)
+
+// startSpendingInstance starts an instance that has used tokens and keeps
+// running until the test ends
+func startSpendingInstance(t *testing.T, tokens int) *runner.Instance {
+	t.Helper()
+	mgr := runner.NewManager(t.TempDir())
+	mgr.SetExecutorFactory(func(ctx context.Context, dir string, args []string) runner.Executor {
+		return spendingExecutor{ctx: ctx, tokens: tokens}
+	})
+	inst, err := mgr.StartInstance("01", "sonnet", "prompt")
+	if err != nil {
+		t.Fatalf("StartInstance: %v", err)
+	}
+	t.Cleanup(inst.Stop)
+	deadline := time.Now().Add(5 * time.Second)
+	for inst.GetUsage().TotalTokens == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("instance reported no usage")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return inst
+}
+
+func TestFeatureLimitsTimeout(t *testing.T) {
+	inst := startSpendingInstance(t, 10)
+	start := time.Now()
+	limits := newFeatureLimits(&manifest.ManifestFeature{ID: "01"}, Options{Timeout: time.Minute}, start)
+
+	if _, reason := limits.exceeded(inst, start.Add(30*time.Second)); reason != "" {
+		t.Errorf("expected no limit passed within the timeout, got %q", reason)
+	}
+	errMsg, reason := limits.exceeded(inst, start.Add(2*time.Minute))
+	if reason != ReasonTimeout || errMsg != "feature timed out after 1m0s" {
+		t.Errorf("expected a timeout, got %q (%q)", reason, errMsg)
+	}
+}
+
+func TestFeatureLimitsTimeoutPrecedence(t *testing.T) {
+	feature := &manifest.ManifestFeature{ID: "01", Timeout: "10m"}
+	start := time.Now()
+
+	if l := newFeatureLimits(feature, Options{}, start); l.timeout != 10*time.Minute {
+		t.Errorf("expected the feature's Timeout without --timeout, got %s", l.timeout)
+	}
+	if l := newFeatureLimits(feature, Options{Timeout: time.Minute}, start); l.timeout != time.Minute {
+		t.Errorf("expected --timeout to override the feature's Timeout, got %s", l.timeout)
+	}
+}
+
+func TestFeatureLimitsBudget(t *testing.T) {
+	inst := startSpendingInstance(t, 600)
+	limits := newFeatureLimits(&manifest.ManifestFeature{ID: "01"}, Options{}, time.Now())
+
+	if _, reason := limits.exceeded(inst, time.Now()); reason != "" {
+		t.Errorf("expected no limit without a budget, got %q", reason)
+	}
+	inst.SetBudget(500, 0)
+	errMsg, reason := limits.exceeded(inst, time.Now())
+	if reason != ReasonBudgetExceeded || errMsg != "feature exceeded its budget" {
+		t.Errorf("expected the feature's budget to be exceeded, got %q (%q)", reason, errMsg)
+	}
+}
@@ -0,0 +1,32 @@
+package auto
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/vx/ralph-go/internal/state"
+)
+
+// recordRunMeta replaces the run metadata in prdDir's progress.json with
+// meta, creating it if needed, so 'ralph status' shows what this run was for
+// rather than an earlier one. A failure is reported rather than failing the
+// run.
+func recordRunMeta(prdDir string, meta map[string]string) {
+	if err := saveRunMeta(prdDir, meta); err != nil {
+		fmt.Printf("Warning: could not record run metadata: %s\n", err)
+	}
+}
+
+func saveRunMeta(prdDir string, meta map[string]string) error {
+	progressPath := filepath.Join(prdDir, "progress.json")
+	progress, err := state.LoadProgressFromPath(progressPath)
+	if err != nil {
+		progress = state.NewProgress()
+	}
+	if len(meta) == 0 && len(progress.GetRunMeta()) == 0 {
+		return nil
+	}
+	progress.SetPathDirect(progressPath)
+	progress.ReplaceRunMeta(meta)
+	return progress.Save()
+}
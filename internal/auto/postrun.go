@@ -0,0 +1,86 @@
+package auto
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/vx/ralph-go/internal/manifest"
+)
+
+// postRunCommand returns the hook to run after a headless run: opts.PostRun,
+// otherwise the manifest's Post-Run, otherwise nothing
+func postRunCommand(opts Options) (command string, prdDir string) {
+	prdDir, err := ResolvePRDDir(opts.PRDDir)
+	if err != nil {
+		return strings.TrimSpace(opts.PostRun), ""
+	}
+	if command := strings.TrimSpace(opts.PostRun); command != "" {
+		return command, prdDir
+	}
+	m, err := LoadManifest(prdDir)
+	if err != nil {
+		return "", prdDir
+	}
+	return strings.TrimSpace(m.PostRun), prdDir
+}
+
+// postRunEnv returns the RALPH_* variables that summarise a finished run for
+// the post-run hook
+func postRunEnv(prdDir string, results []*Result, exitCode int) []string {
+	var run, completed, failed []string
+	for _, result := range results {
+		if result.NoWork {
+			continue
+		}
+		run = append(run, result.FeatureID)
+		if manifest.IsCompleted(result.Status) {
+			completed = append(completed, result.FeatureID)
+		} else if result.Status == "failed" {
+			failed = append(failed, result.FeatureID)
+		}
+	}
+
+	status := "success"
+	if exitCode != ExitSuccess {
+		status = "failed"
+	}
+
+	return []string{
+		"RALPH_STATUS=" + status,
+		"RALPH_EXIT_CODE=" + strconv.Itoa(exitCode),
+		"RALPH_PRD_DIR=" + prdDir,
+		"RALPH_FEATURES_RUN=" + strconv.Itoa(len(run)),
+		"RALPH_COMPLETED=" + strconv.Itoa(len(completed)),
+		"RALPH_FAILED=" + strconv.Itoa(len(failed)),
+		"RALPH_COMPLETED_IDS=" + strings.Join(completed, ","),
+		"RALPH_FAILED_IDS=" + strings.Join(failed, ","),
+	}
+}
+
+// RunPostRun runs the post-run hook, if one is configured, after a headless
+// run has finished with exitCode. The command runs through sh in the current
+// directory with the run summary in RALPH_* environment variables. It returns
+// the exit code ralph should finish with: exitCode when the run already
+// failed, otherwise ExitPostRunFailed if the hook failed.
+func RunPostRun(opts Options, results []*Result, exitCode int) int {
+	command, prdDir := postRunCommand(opts)
+	if command == "" {
+		return exitCode
+	}
+
+	fmt.Printf("\nPost-run: %s\n", command)
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Env = append(os.Environ(), postRunEnv(prdDir, results, exitCode)...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		fmt.Printf("Post-run command failed: %s\n", err)
+		if exitCode == ExitSuccess {
+			return ExitPostRunFailed
+		}
+	}
+	return exitCode
+}
@@ -0,0 +1,125 @@
+package auto
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vx/ralph-go/internal/manifest"
+)
+
+// readEnvDump parses the KEY=value lines written by `env > file`
+func readEnvDump(t *testing.T, path string) map[string]string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("post-run command did not run: %v", err)
+	}
+	env := make(map[string]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		if key, value, ok := strings.Cut(line, "="); ok {
+			env[key] = value
+		}
+	}
+	return env
+}
+
+func TestRunPostRun(t *testing.T) {
+	tmpDir := setupRunnableFeatures(t, "01", "02")
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	stubExecuteFeature(t, "completed")
+	opts := Options{Count: 2, PostRun: "env > post-run.env"}
+	results, err := RunWithOptions(opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	code := RunPostRun(opts, results, ExitCodeAll(results))
+	if code != ExitSuccess {
+		t.Errorf("expected exit code %d, got %d", ExitSuccess, code)
+	}
+
+	env := readEnvDump(t, filepath.Join(tmpDir, "post-run.env"))
+	expected := map[string]string{
+		"RALPH_STATUS":        "success",
+		"RALPH_EXIT_CODE":     "0",
+		"RALPH_FEATURES_RUN":  "2",
+		"RALPH_COMPLETED":     "2",
+		"RALPH_FAILED":        "0",
+		"RALPH_COMPLETED_IDS": "01,02",
+		"RALPH_FAILED_IDS":    "",
+	}
+	for key, want := range expected {
+		if got, ok := env[key]; !ok || got != want {
+			t.Errorf("%s: expected %q, got %q (set: %v)", key, want, got, ok)
+		}
+	}
+	if !strings.HasSuffix(env["RALPH_PRD_DIR"], "PRD") {
+		t.Errorf("expected RALPH_PRD_DIR to point at PRD/, got %q", env["RALPH_PRD_DIR"])
+	}
+}
+
+func TestRunPostRunExitCode(t *testing.T) {
+	tmpDir := setupRunnableFeatures(t, "01")
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	failed := []*Result{{FeatureID: "01", Status: "failed", Reason: ReasonFeatureFailed}}
+	completed := []*Result{{FeatureID: "01", Status: "completed"}}
+
+	tests := []struct {
+		name     string
+		command  string
+		results  []*Result
+		expected int
+	}{
+		{"no hook keeps exit code", "", failed, ExitFeatureFailed},
+		{"successful hook keeps success", "true", completed, ExitSuccess},
+		{"failing hook fails a successful run", "exit 3", completed, ExitPostRunFailed},
+		{"failing hook keeps the run's failure", "exit 3", failed, ExitFeatureFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{PostRun: tt.command}
+			if got := RunPostRun(opts, tt.results, ExitCodeAll(tt.results)); got != tt.expected {
+				t.Errorf("expected exit code %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRunPostRunUsesManifestCommand(t *testing.T) {
+	tmpDir := setupRunnableFeatures(t, "01")
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	m, err := manifest.Load(filepath.Join(tmpDir, "PRD"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.PostRun = "touch from-manifest"
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	results := []*Result{{FeatureID: "01", Status: "failed"}}
+	if code := RunPostRun(Options{}, results, ExitFeatureFailed); code != ExitFeatureFailed {
+		t.Errorf("expected exit code %d, got %d", ExitFeatureFailed, code)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "from-manifest")); err != nil {
+		t.Errorf("expected the manifest's Post-Run to run after a failed run: %v", err)
+	}
+
+	// --post-run overrides the PRD's command
+	RunPostRun(Options{PostRun: "touch from-flag"}, results, ExitFeatureFailed)
+	if _, err := os.Stat(filepath.Join(tmpDir, "from-flag")); err != nil {
+		t.Errorf("expected --post-run to run: %v", err)
+	}
+}
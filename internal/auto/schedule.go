@@ -0,0 +1,120 @@
+package auto
+
+import (
+	"github.com/vx/ralph-go/internal/manifest"
+	"github.com/vx/ralph-go/internal/runner"
+)
+
+// executedFeature is a feature whose executeFeature call has returned
+type executedFeature struct {
+	feature manifest.ManifestFeature
+	run     *featureRun
+}
+
+// isSequential reports whether a feature must not overlap other sequential
+// features. Features without an execution mode are sequential.
+func isSequential(f *manifest.ManifestFeature) bool {
+	return f.Execution != "parallel"
+}
+
+// nextSchedulable returns the first runnable feature of the selection that
+// may start alongside the running ones, or nil if there is none. running maps
+// the ID of each running feature to whether it is sequential.
+func nextSchedulable(m *manifest.Manifest, sel selection, running map[string]bool) *manifest.ManifestFeature {
+	sequentialRunning := false
+	for _, sequential := range running {
+		sequentialRunning = sequentialRunning || sequential
+	}
+
+	for _, feature := range m.GetAllRunnableFeatures() {
+		if !sel.includes(&feature) {
+			continue
+		}
+		if _, ok := running[feature.ID]; ok {
+			continue
+		}
+		if sequentialRunning && isSequential(&feature) {
+			continue
+		}
+		return &feature
+	}
+	return nil
+}
+
+// runParallelRoots runs up to count features, starting every runnable
+// feature that fits under the manifest's concurrency limit. Manifest updates
+// happen on this goroutine; only executeFeature runs concurrently. Once a
+// feature fails under opts.FailFast or On-Failure: abort, progress can't be
+// saved, or the global budget stops the run, no further features start, but
+// those already running are waited for. Running features share the global
+// budget, so all of them are stopped once together they have spent it.
+func runParallelRoots(prdDir string, m *manifest.Manifest, sel selection, opts Options, count int) ([]*Result, error) {
+	limit := m.Concurrent
+	if limit <= 0 {
+		limit = runner.DefaultConfig().MaxConcurrent
+	}
+
+	done := make(chan executedFeature)
+	running := make(map[string]bool)
+	var results []*Result
+	var firstErr error
+	started := 0
+	stopping := false
+
+	for {
+		for !stopping && started < count && len(running) < limit {
+			feature := nextSchedulable(m, sel, running)
+			if feature == nil {
+				break
+			}
+			run, err := beginFeature(prdDir, m, feature, opts)
+			if err != nil {
+				firstErr = err
+				stopping = true
+				break
+			}
+			running[feature.ID] = isSequential(feature)
+			started++
+
+			go func(feature manifest.ManifestFeature, run *featureRun) {
+				run.execute(&feature)
+				done <- executedFeature{feature: feature, run: run}
+			}(*feature, run)
+		}
+
+		if len(running) == 0 {
+			break
+		}
+
+		executed := <-done
+		delete(running, executed.feature.ID)
+		result, err := finishFeature(prdDir, m, &executed.feature, executed.run)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			stopping = true
+			continue
+		}
+		results = append(results, result)
+		if result.SaveError != "" {
+			stopping = true
+		}
+		if abortsRun(result) {
+			result.Aborted = !stopping && started < count
+			stopping = true
+		}
+		if opts.FailFast && result.Status == "failed" && !result.Optional {
+			result.FailFast = !stopping && started < count
+			stopping = true
+		}
+		if stop, status := opts.budget.stopsRun(); stop && !stopping {
+			if started < count {
+				result.BudgetStopped = status
+			}
+			stopping = true
+		}
+	}
+
+	return results, firstErr
+}
@@ -0,0 +1,136 @@
+package auto
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vx/ralph-go/internal/manifest"
+)
+
+func TestRunWithOptionsParallelRoots(t *testing.T) {
+	tmpDir := setupRunnableFeatures(t, "01", "02", "03", "04", "05")
+	prdDir := filepath.Join(tmpDir, "PRD")
+	m, _ := manifest.Load(prdDir)
+	m.Concurrent = 4
+	m.Features[0].Execution = "sequential"
+	m.Features[1].Execution = "sequential"
+	m.Features[2].Execution = "parallel"
+	m.Features[3].Execution = "parallel"
+	m.Features[4].Execution = "parallel"
+	m.Features[4].DependsOn = []string{"03"}
+	if err := m.Save(); err != nil {
+		t.Fatalf("failed to save manifest: %v", err)
+	}
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	var mu sync.Mutex
+	active := make(map[string]bool)
+	finished := make(map[string]bool)
+	var problems []string
+	report := func(problem string) {
+		mu.Lock()
+		problems = append(problems, problem)
+		mu.Unlock()
+	}
+	// 03 and 04 each wait for the other to start, so they only finish if
+	// they overlap
+	parallelStarted := map[string]chan struct{}{
+		"03": make(chan struct{}),
+		"04": make(chan struct{}),
+	}
+
+	orig := executeFeature
+	executeFeature = func(workDir string, feature *manifest.ManifestFeature, prompt string, opts Options) (string, string, string) {
+		mu.Lock()
+		if (feature.ID == "01" && active["02"]) || (feature.ID == "02" && active["01"]) {
+			problems = append(problems, "sequential features 01 and 02 overlapped")
+		}
+		if feature.ID == "05" && !finished["03"] {
+			problems = append(problems, "05 started before its dependency 03 finished")
+		}
+		active[feature.ID] = true
+		mu.Unlock()
+
+		switch feature.ID {
+		case "03", "04":
+			other := "04"
+			if feature.ID == "04" {
+				other = "03"
+			}
+			close(parallelStarted[feature.ID])
+			select {
+			case <-parallelStarted[other]:
+			case <-time.After(2 * time.Second):
+				report("parallel feature " + feature.ID + " never overlapped " + other)
+			}
+		default:
+			time.Sleep(20 * time.Millisecond)
+		}
+
+		mu.Lock()
+		delete(active, feature.ID)
+		finished[feature.ID] = true
+		mu.Unlock()
+		return "completed", "", ""
+	}
+	t.Cleanup(func() { executeFeature = orig })
+
+	results, err := RunWithOptions(Options{Count: 5, ParallelRoots: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, problem := range problems {
+		t.Error(problem)
+	}
+	if len(results) != 5 {
+		t.Fatalf("expected 5 results, got %d", len(results))
+	}
+	for _, r := range results {
+		if r.Status != "completed" {
+			t.Errorf("expected %s completed, got %s", r.FeatureID, r.Status)
+		}
+	}
+
+	m, _ = manifest.Load(prdDir)
+	for _, f := range m.Features {
+		if f.Status != "completed" {
+			t.Errorf("expected manifest to record %s completed, got %s", f.ID, f.Status)
+		}
+	}
+}
+
+func TestRunWithOptionsParallelRootsFailFast(t *testing.T) {
+	tmpDir := setupRunnableFeatures(t, "01", "02")
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	var mu sync.Mutex
+	var started []string
+	orig := executeFeature
+	executeFeature = func(workDir string, feature *manifest.ManifestFeature, prompt string, opts Options) (string, string, string) {
+		mu.Lock()
+		started = append(started, feature.ID)
+		mu.Unlock()
+		return "failed", "stub failure", ReasonFeatureFailed
+	}
+	t.Cleanup(func() { executeFeature = orig })
+
+	// Both features are sequential, so 02 can only start after 01 fails
+	results, err := RunWithOptions(Options{Count: 2, ParallelRoots: true, FailFast: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(started) != 1 || len(results) != 1 {
+		t.Fatalf("expected only 01 to run, started %v", started)
+	}
+	if !results[0].FailFast {
+		t.Error("expected the result to record that --fail-fast stopped the run")
+	}
+}
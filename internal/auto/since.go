@@ -0,0 +1,126 @@
+package auto
+
+import (
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/vx/ralph-go/internal/manifest"
+)
+
+// gitChangedFiles returns the files changed between ref and the working
+// tree in dir, including untracked files that aren't ignored, relative to
+// dir. It is a variable so tests can supply a changed-file list without a git
+// repository.
+var gitChangedFiles = func(dir, ref string) ([]string, error) {
+	changed, err := gitLines(dir, "diff", "--name-only", "--relative", ref)
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := gitLines(dir, "ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, err
+	}
+	return append(changed, untracked...), nil
+}
+
+// gitLines runs git with args in dir and returns the non-empty lines it
+// prints
+func gitLines(dir string, args ...string) ([]string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		command := "git " + strings.Join(args, " ")
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("%s failed: %s", command, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, fmt.Errorf("%s failed: %w", command, err)
+	}
+
+	var lines []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// selection narrows which runnable features a run may start
+type selection struct {
+	// group limits the run to one epic or group (empty = all)
+	group string
+	// ids limits the run to these feature IDs (nil = all)
+	ids map[string]bool
+}
+
+// includes reports whether the feature is part of the selection
+func (s selection) includes(f *manifest.ManifestFeature) bool {
+	if s.group != "" && !strings.EqualFold(f.Group, s.group) {
+		return false
+	}
+	if s.ids != nil && !s.ids[f.ID] {
+		return false
+	}
+	return true
+}
+
+// featuresTouching returns the IDs of the features whose Files match any of
+// the changed paths. Features that declare no files are never selected.
+func featuresTouching(m *manifest.Manifest, changed []string) map[string]bool {
+	ids := make(map[string]bool)
+	for _, feature := range m.GetRootFeatures() {
+		for _, pattern := range feature.Files {
+			if anyPathMatches(pattern, changed) {
+				ids[feature.ID] = true
+				break
+			}
+		}
+	}
+	return ids
+}
+
+func anyPathMatches(pattern string, changed []string) bool {
+	for _, file := range changed {
+		if pathMatches(pattern, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// pathMatches reports whether a changed file falls under a feature's file
+// pattern: the same path, a file inside the named directory, or a glob
+// matching the file or one of its parent directories
+func pathMatches(pattern, file string) bool {
+	pattern = path.Clean(strings.TrimPrefix(strings.TrimSpace(pattern), "./"))
+	file = path.Clean(file)
+
+	if pattern == "." {
+		return true
+	}
+	if file == pattern || strings.HasPrefix(file, pattern+"/") {
+		return true
+	}
+	if !strings.ContainsAny(pattern, "*?[") {
+		return false
+	}
+	for dir := file; dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if ok, _ := path.Match(pattern, dir); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sinceCommitSelection returns the IDs of the features touched by the changes
+// since ref in workDir, where the features run
+func sinceCommitSelection(m *manifest.Manifest, workDir, ref string) (map[string]bool, error) {
+	changed, err := gitChangedFiles(workDir, ref)
+	if err != nil {
+		return nil, err
+	}
+	return featuresTouching(m, changed), nil
+}
@@ -0,0 +1,171 @@
+package auto
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vx/ralph-go/internal/manifest"
+)
+
+func stubGitChangedFiles(t *testing.T, files []string, err error) *string {
+	t.Helper()
+	var gotRef string
+	orig := gitChangedFiles
+	gitChangedFiles = func(dir, ref string) ([]string, error) {
+		gotRef = ref
+		return files, err
+	}
+	t.Cleanup(func() { gitChangedFiles = orig })
+	return &gotRef
+}
+
+func TestPathMatches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		file    string
+		want    bool
+	}{
+		{"internal/auth/login.go", "internal/auth/login.go", true},
+		{"internal/auth", "internal/auth/login.go", true},
+		{"internal/auth/", "internal/auth/login.go", true},
+		{"./internal/auth", "internal/auth/login.go", true},
+		{"internal/auth", "internal/authz/policy.go", false},
+		{"cmd/*.go", "cmd/main.go", true},
+		{"cmd/*.go", "cmd/ralph/main.go", false},
+		{"internal/*", "internal/auth/login.go", true},
+		{"*.md", "README.md", true},
+		{"*.md", "docs/guide.md", false},
+		{"docs/guide.md", "docs/guide.mdx", false},
+	}
+	for _, tt := range tests {
+		if got := pathMatches(tt.pattern, tt.file); got != tt.want {
+			t.Errorf("pathMatches(%q, %q) = %v, want %v", tt.pattern, tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestRunWithOptionsSinceCommit(t *testing.T) {
+	tmpDir := setupRunnableFeatures(t, "01", "02", "03", "04")
+	prdDir := filepath.Join(tmpDir, "PRD")
+	m, _ := manifest.Load(prdDir)
+	m.Features[0].Files = []string{"internal/auth/"}
+	m.Features[1].Files = []string{"web/*.ts"}
+	m.Features[2].Files = []string{"docs/api.md", "cmd/server"}
+	if err := m.Save(); err != nil {
+		t.Fatalf("failed to save manifest: %v", err)
+	}
+
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	os.Chdir(tmpDir)
+
+	t.Run("runs only features touching changed files", func(t *testing.T) {
+		gotRef := stubGitChangedFiles(t, []string{"internal/auth/login.go", "cmd/server/main.go", "README.md"}, nil)
+		started := stubExecuteFeature(t, "completed")
+
+		results, err := RunWithOptions(Options{Count: 10, SinceCommit: "main"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if *gotRef != "main" {
+			t.Errorf("expected git diff against main, got %q", *gotRef)
+		}
+		if strings.Join(*started, ",") != "01,03" {
+			t.Errorf("expected only features 01 and 03 to run, got %v", *started)
+		}
+		if len(results) != 2 {
+			t.Errorf("expected 2 results, got %d", len(results))
+		}
+	})
+
+	t.Run("reports the matching features completed when run again", func(t *testing.T) {
+		stubGitChangedFiles(t, []string{"internal/auth/login.go", "cmd/server/main.go"}, nil)
+		started := stubExecuteFeature(t, "completed")
+
+		results, err := RunWithOptions(Options{Count: 10, SinceCommit: "main"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(*started) != 0 {
+			t.Errorf("expected no features to run, got %v", *started)
+		}
+		if !results[0].NoWork || results[0].Status != "all_completed" {
+			t.Errorf("expected all_completed for the matching features, got %+v", results[0])
+		}
+	})
+
+	t.Run("reports no work when no feature matches", func(t *testing.T) {
+		stubGitChangedFiles(t, []string{"README.md"}, nil)
+		started := stubExecuteFeature(t, "completed")
+
+		results, err := RunWithOptions(Options{Count: 10, SinceCommit: "HEAD~1"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(*started) != 0 {
+			t.Errorf("expected no features to run, got %v", *started)
+		}
+		if !results[0].NoWork || results[0].Status != "no_matching_changes" {
+			t.Errorf("expected no_matching_changes, got %+v", results[0])
+		}
+		if ExitCode(results[0]) != ExitSuccess {
+			t.Errorf("expected exit code 0 with no matching changes, got %d", ExitCode(results[0]))
+		}
+	})
+
+	t.Run("returns git errors", func(t *testing.T) {
+		stubGitChangedFiles(t, nil, errors.New("git diff nope failed: unknown revision"))
+		stubExecuteFeature(t, "completed")
+
+		if _, err := RunWithOptions(Options{SinceCommit: "nope"}); err == nil {
+			t.Error("expected an error for an unknown ref")
+		}
+	})
+}
+
+func TestGitChangedFilesIncludesNewFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=ralph", "-c", "user.email=ralph@example.com"}, args...)...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		path := filepath.Join(repo, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The project sits in app/ of the repository, as the PRD directory's
+	// parent might
+	write("app/cmd/main.go", "package main\n")
+	write("app/.gitignore", "*.log\n")
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-q", "-m", "initial")
+	write("app/cmd/main.go", "package main\n\nfunc main() {}\n")
+	write("app/internal/auth/login.go", "package auth\n")
+	write("app/debug.log", "ignored\n")
+
+	files, err := gitChangedFiles(filepath.Join(repo, "app"), "HEAD")
+	if err != nil {
+		t.Fatalf("gitChangedFiles failed: %v", err)
+	}
+	got := strings.Join(files, ",")
+	if got != "cmd/main.go,internal/auth/login.go" {
+		t.Errorf("expected the changed and the new file relative to app/, got %v", files)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -65,6 +66,7 @@ type ModelSwitch struct {
 	ToModel    string           `json:"to_model"`
 	Reason     EscalationReason `json:"reason"`
 	Details    string           `json:"details,omitempty"`
+	Evidence   string           `json:"evidence,omitempty"` // What triggered the switch, e.g. 2 consecutive Bash errors
 	TokenCount int64            `json:"token_count,omitempty"`
 }
 
@@ -78,6 +80,11 @@ type Selector struct {
 	isLeafTask    bool
 	taskCount     int
 	config        Config
+	// Consecutive tool errors since the last successful tool result, and
+	// the tools that produced them, for the switch evidence
+	consecutiveErrors int
+	errorTools        []string
+	lastTool          string // Tool of the latest tool_use, for results that don't name it
 }
 
 func NewSelector(featureID string, isLeafTask bool, taskCount int) *Selector {
@@ -125,6 +132,59 @@ func (s *Selector) initialModelDetails() string {
 	return "complex task (>5 tasks) - starting with sonnet"
 }
 
+// Explanation is the model a selector starts on and the inputs that decided
+// it
+type Explanation struct {
+	Model      string
+	IsLeafTask bool
+	TaskCount  int
+	Complexity string // "simple", "moderate" or "complex", from the task count
+	Reason     string
+}
+
+// Explain returns the model a selector for a feature with these inputs
+// starts on, and why
+func Explain(isLeafTask bool, taskCount int) Explanation {
+	s := &Selector{isLeafTask: isLeafTask, taskCount: taskCount}
+	return s.Explain()
+}
+
+// Explain returns why the selector started on its initial model
+func (s *Selector) Explain() Explanation {
+	complexity := "complex"
+	if s.taskCount <= 2 {
+		complexity = "simple"
+	} else if s.taskCount <= 5 {
+		complexity = "moderate"
+	}
+	return Explanation{
+		Model:      s.selectInitialModel(),
+		IsLeafTask: s.isLeafTask,
+		TaskCount:  s.taskCount,
+		Complexity: complexity,
+		Reason:     s.initialModelDetails(),
+	}
+}
+
+// Inputs lists the heuristic inputs, e.g. "leaf: no, tasks: 4, complexity:
+// moderate"
+func (e Explanation) Inputs() string {
+	leaf := "no"
+	if e.IsLeafTask {
+		leaf = "yes"
+	}
+	return fmt.Sprintf("leaf: %s, tasks: %d, complexity: %s", leaf, e.TaskCount, e.Complexity)
+}
+
+// Details is the reason followed by the inputs
+func (e Explanation) Details() string {
+	return fmt.Sprintf("%s (%s)", e.Reason, e.Inputs())
+}
+
+func (e Explanation) String() string {
+	return e.Model + ": " + e.Details()
+}
+
 func (s *Selector) CurrentModel() string {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -149,10 +209,14 @@ func (s *Selector) ProcessLine(line string) (modelChanged bool, newModel string)
 	}
 
 	switch msg.Type {
+	case "tool_use":
+		s.lastTool = msg.Tool
 	case "tool_result":
 		if msg.IsError {
 			return s.handleToolError(msg)
 		}
+		s.consecutiveErrors = 0
+		s.errorTools = nil
 		return s.checkToolResultComplexity(msg)
 	case "assistant":
 		return s.checkAssistantComplexity(msg)
@@ -176,6 +240,13 @@ type StreamMessage struct {
 
 func (s *Selector) handleToolError(msg StreamMessage) (bool, string) {
 	s.errorCount++
+	s.consecutiveErrors++
+	tool := msg.Tool
+	if tool == "" {
+		tool = s.lastTool
+	}
+	s.errorTools = append(s.errorTools, tool)
+	evidence := s.toolErrorEvidence(msg.Result)
 
 	threshold := s.config.ErrorThreshold
 	if threshold <= 0 {
@@ -184,16 +255,16 @@ func (s *Selector) handleToolError(msg StreamMessage) (bool, string) {
 
 	if s.errorCount >= threshold && s.currentModel == ModelHaiku {
 		details := fmt.Sprintf("%d+ tool errors with haiku", s.errorCount)
-		return s.escalateTo(ModelSonnet, ReasonMultipleErrors, details)
+		return s.escalateTo(ModelSonnet, ReasonMultipleErrors, details, evidence)
 	}
 
 	if isCompilationError(msg.Result) || isTestError(msg.Result) {
 		s.testFailCount++
 		if s.testFailCount >= threshold && s.currentModel == ModelHaiku {
-			return s.escalateTo(ModelSonnet, ReasonTestFailure, "repeated test/build failures")
+			return s.escalateTo(ModelSonnet, ReasonTestFailure, "repeated test/build failures", evidence)
 		}
 		if s.testFailCount >= threshold && s.currentModel == ModelSonnet {
-			return s.escalateTo(ModelOpus, ReasonTestFailure, "repeated test/build failures at sonnet level")
+			return s.escalateTo(ModelOpus, ReasonTestFailure, "repeated test/build failures at sonnet level", evidence)
 		}
 	}
 
@@ -204,7 +275,8 @@ func (s *Selector) handleError(msg StreamMessage) (bool, string) {
 	s.errorCount++
 
 	if s.errorCount >= 2 && s.currentModel == ModelHaiku {
-		return s.escalateTo(ModelSonnet, ReasonMultipleErrors, "multiple errors encountered")
+		evidence := fmt.Sprintf("%d errors; last: %s", s.errorCount, quoteSnippet(msg.Result))
+		return s.escalateTo(ModelSonnet, ReasonMultipleErrors, "multiple errors encountered", evidence)
 	}
 
 	return false, s.currentModel
@@ -217,21 +289,25 @@ func (s *Selector) checkToolResultComplexity(msg StreamMessage) (bool, string) {
 
 	result := msg.Result
 
-	if isArchitecturalContent(result) {
+	if match := matchPatterns(architecturalPatterns, result); match != "" {
+		evidence := fmt.Sprintf("tool result mentions %q: %s", match, quoteSnippet(result))
 		if s.currentModel == ModelHaiku {
-			return s.escalateTo(ModelSonnet, ReasonArchitectural, "architectural complexity detected")
+			return s.escalateTo(ModelSonnet, ReasonArchitectural, "architectural complexity detected", evidence)
 		}
 		if s.currentModel == ModelSonnet && len(result) > 5000 {
-			return s.escalateTo(ModelOpus, ReasonArchitectural, "major architectural decisions needed")
+			evidence = fmt.Sprintf("%d-character tool result mentions %q", len(result), match)
+			return s.escalateTo(ModelOpus, ReasonArchitectural, "major architectural decisions needed", evidence)
 		}
 	}
 
-	if isDebuggingScenario(result) && s.currentModel != ModelOpus {
+	if match := matchPatterns(debugPatterns, result); match != "" && s.currentModel != ModelOpus {
 		if s.currentModel == ModelHaiku {
-			return s.escalateTo(ModelSonnet, ReasonDebugging, "debugging scenario detected")
+			evidence := fmt.Sprintf("tool result mentions %q: %s", match, quoteSnippet(result))
+			return s.escalateTo(ModelSonnet, ReasonDebugging, "debugging scenario detected", evidence)
 		}
-		if containsComplexDebugging(result) {
-			return s.escalateTo(ModelOpus, ReasonDebugging, "complex debugging required")
+		if complex := matchPatterns(complexDebugPatterns, result); complex != "" {
+			evidence := fmt.Sprintf("tool result mentions %q: %s", complex, quoteSnippet(result))
+			return s.escalateTo(ModelOpus, ReasonDebugging, "complex debugging required", evidence)
 		}
 	}
 
@@ -244,18 +320,20 @@ func (s *Selector) checkAssistantComplexity(msg StreamMessage) (bool, string) {
 	if containsModelEscalationRequest(content) {
 		targetModel := extractRequestedModel(content)
 		if targetModel != "" && targetModel != s.currentModel {
+			evidence := "assistant wrote " + quoteSnippet(content)
 			if isHigherTier(targetModel, s.currentModel) {
-				return s.escalateTo(targetModel, ReasonExplicitRequest, "explicit escalation in output")
+				return s.escalateTo(targetModel, ReasonExplicitRequest, "explicit escalation in output", evidence)
 			}
 			if isLowerTier(targetModel, s.currentModel) && s.errorCount == 0 {
-				return s.deescalateTo(targetModel, "explicit de-escalation in output")
+				return s.deescalateTo(targetModel, "explicit de-escalation in output", evidence)
 			}
 		}
 	}
 
 	if s.currentModel != ModelOpus {
-		if s.containsEscalationKeywords(content) && s.currentModel == ModelHaiku {
-			return s.escalateTo(ModelSonnet, ReasonArchitectural, "escalation keywords detected")
+		if keyword := s.escalationKeyword(content); keyword != "" && s.currentModel == ModelHaiku {
+			evidence := fmt.Sprintf("assistant output mentions %q: %s", keyword, quoteSnippet(content))
+			return s.escalateTo(ModelSonnet, ReasonArchitectural, "escalation keywords detected", evidence)
 		}
 	}
 
@@ -268,16 +346,18 @@ func (s *Selector) checkAssistantComplexity(msg StreamMessage) (bool, string) {
 	return false, s.currentModel
 }
 
-func (s *Selector) containsEscalationKeywords(content string) bool {
+// escalationKeyword returns the first configured escalation keyword or
+// architectural phrase found in content, or ""
+func (s *Selector) escalationKeyword(content string) string {
 	lower := strings.ToLower(content)
 
 	for _, keyword := range s.config.EscalateKeywords {
 		if strings.Contains(lower, strings.ToLower(keyword)) {
-			return true
+			return keyword
 		}
 	}
 
-	return isArchitecturalContent(content)
+	return matchPatterns(architecturalPatterns, content)
 }
 
 func (s *Selector) checkDeescalation(content string) (bool, string) {
@@ -304,7 +384,7 @@ func (s *Selector) checkDeescalation(content string) (bool, string) {
 		targetModel := s.getDeescalationTarget()
 		if targetModel != s.currentModel {
 			details := "de-escalation keywords: " + strings.Join(matchedKeywords, ", ")
-			return s.deescalateTo(targetModel, details)
+			return s.deescalateTo(targetModel, details, "assistant wrote "+quoteSnippet(content))
 		}
 	}
 
@@ -322,7 +402,7 @@ func (s *Selector) getDeescalationTarget() string {
 	}
 }
 
-func (s *Selector) deescalateTo(model string, details string) (bool, string) {
+func (s *Selector) deescalateTo(model string, details, evidence string) (bool, string) {
 	if !isLowerTier(model, s.currentModel) {
 		return false, s.currentModel
 	}
@@ -336,6 +416,7 @@ func (s *Selector) deescalateTo(model string, details string) (bool, string) {
 		ToModel:   model,
 		Reason:    ReasonDeescalate,
 		Details:   details,
+		Evidence:  evidence,
 	}
 	s.switches = append(s.switches, sw)
 
@@ -343,7 +424,8 @@ func (s *Selector) deescalateTo(model string, details string) (bool, string) {
 		"featureID", s.featureID,
 		"from", oldModel,
 		"to", model,
-		"details", details)
+		"details", details,
+		"evidence", evidence)
 
 	return true, model
 }
@@ -357,7 +439,7 @@ func isLowerTier(target, current string) bool {
 	return tiers[target] < tiers[current]
 }
 
-func (s *Selector) escalateTo(model string, reason EscalationReason, details string) (bool, string) {
+func (s *Selector) escalateTo(model string, reason EscalationReason, details, evidence string) (bool, string) {
 	if !isHigherTier(model, s.currentModel) {
 		return false, s.currentModel
 	}
@@ -371,6 +453,7 @@ func (s *Selector) escalateTo(model string, reason EscalationReason, details str
 		ToModel:   model,
 		Reason:    reason,
 		Details:   details,
+		Evidence:  evidence,
 	}
 	s.switches = append(s.switches, sw)
 
@@ -379,7 +462,8 @@ func (s *Selector) escalateTo(model string, reason EscalationReason, details str
 		"from", oldModel,
 		"to", model,
 		"reason", string(reason),
-		"details", details)
+		"details", details,
+		"evidence", evidence)
 
 	return true, model
 }
@@ -433,12 +517,67 @@ var architecturalPatterns = []*regexp.Regexp{
 }
 
 func isArchitecturalContent(content string) bool {
-	for _, pattern := range architecturalPatterns {
-		if pattern.MatchString(content) {
-			return true
+	return matchPatterns(architecturalPatterns, content) != ""
+}
+
+// matchPatterns returns the text matched by the first pattern that matches
+// content, or ""
+func matchPatterns(patterns []*regexp.Regexp, content string) string {
+	for _, pattern := range patterns {
+		if match := pattern.FindString(content); match != "" {
+			return match
+		}
+	}
+	return ""
+}
+
+// maxEvidenceSnippet is how many characters of triggering output a switch's
+// evidence quotes
+const maxEvidenceSnippet = 80
+
+// quoteSnippet quotes the first non-blank line of content, shortened to
+// maxEvidenceSnippet characters
+func quoteSnippet(content string) string {
+	line := ""
+	for _, l := range strings.Split(content, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			line = l
+			break
+		}
+	}
+	if runes := []rune(line); len(runes) > maxEvidenceSnippet {
+		line = string(runes[:maxEvidenceSnippet-3]) + "..."
+	}
+	return strconv.Quote(line)
+}
+
+// toolErrorEvidence describes the tool errors seen so far, e.g. `2
+// consecutive Bash errors; last: "exit status 1"`
+func (s *Selector) toolErrorEvidence(lastResult string) string {
+	var what string
+	if tools := uniqueNonEmpty(s.errorTools); len(tools) == 1 {
+		what = fmt.Sprintf("%d consecutive %s errors", s.consecutiveErrors, tools[0])
+	} else if len(tools) > 1 {
+		what = fmt.Sprintf("%d consecutive tool errors (%s)", s.consecutiveErrors, strings.Join(tools, ", "))
+	} else {
+		what = fmt.Sprintf("%d consecutive tool errors", s.consecutiveErrors)
+	}
+	if s.errorCount > s.consecutiveErrors {
+		what += fmt.Sprintf(", %d in total", s.errorCount)
+	}
+	return what + "; last: " + quoteSnippet(lastResult)
+}
+
+func uniqueNonEmpty(values []string) []string {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, v := range values {
+		if v != "" && !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
 		}
 	}
-	return false
+	return unique
 }
 
 var debugPatterns = []*regexp.Regexp{
@@ -453,12 +592,7 @@ var debugPatterns = []*regexp.Regexp{
 }
 
 func isDebuggingScenario(content string) bool {
-	for _, pattern := range debugPatterns {
-		if pattern.MatchString(content) {
-			return true
-		}
-	}
-	return false
+	return matchPatterns(debugPatterns, content) != ""
 }
 
 var complexDebugPatterns = []*regexp.Regexp{
@@ -470,15 +604,6 @@ var complexDebugPatterns = []*regexp.Regexp{
 	regexp.MustCompile(`(?i)heap\s*corruption`),
 }
 
-func containsComplexDebugging(content string) bool {
-	for _, pattern := range complexDebugPatterns {
-		if pattern.MatchString(content) {
-			return true
-		}
-	}
-	return false
-}
-
 func isCompilationError(result string) bool {
 	lower := strings.ToLower(result)
 	return strings.Contains(lower, "compilation failed") ||
@@ -2,6 +2,8 @@ package automodel
 
 import (
 	"encoding/json"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -38,6 +40,36 @@ func TestNewSelector(t *testing.T) {
 	}
 }
 
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		name      string
+		isLeaf    bool
+		taskCount int
+		want      string
+	}{
+		{"leaf task", true, 1, "haiku: leaf task - starting with haiku (leaf: yes, tasks: 1, complexity: simple)"},
+		{"simple task", false, 2, "haiku: simple task (<=2 tasks) - starting with haiku (leaf: no, tasks: 2, complexity: simple)"},
+		{"moderate task", false, 4, "haiku: moderate task (<=5 tasks) - starting with haiku (leaf: no, tasks: 4, complexity: moderate)"},
+		{"complex task", false, 8, "sonnet: complex task (>5 tasks) - starting with sonnet (leaf: no, tasks: 8, complexity: complex)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := Explain(tt.isLeaf, tt.taskCount)
+			if got := e.String(); got != tt.want {
+				t.Errorf("unexpected explanation:\n got %q\nwant %q", got, tt.want)
+			}
+			s := NewSelector("test-feature", tt.isLeaf, tt.taskCount)
+			if e.Model != s.CurrentModel() {
+				t.Errorf("explained %s but the selector started on %s", e.Model, s.CurrentModel())
+			}
+			if s.Explain() != e {
+				t.Errorf("expected the selector's explanation to match, got %+v", s.Explain())
+			}
+		})
+	}
+}
+
 func TestEscalateOnToolError(t *testing.T) {
 	s := NewSelector("test-feature", true, 1)
 	if s.CurrentModel() != ModelHaiku {
@@ -778,3 +810,67 @@ func TestContainsModelEscalationRequestWithHaiku(t *testing.T) {
 		}
 	}
 }
+
+func TestToolErrorEscalationRecordsEvidence(t *testing.T) {
+	s := NewSelector("test-feature", true, 1)
+
+	s.ProcessLine(`{"type":"tool_use","tool":"Bash","tool_input":{"command":"go build ./..."}}`)
+	s.ProcessLine(`{"type":"tool_result","is_error":true,"result":"exit status 1"}`)
+	s.ProcessLine(`{"type":"tool_use","tool":"Bash","tool_input":{"command":"go build ./..."}}`)
+	changed, _ := s.ProcessLine(`{"type":"tool_result","is_error":true,"result":"\n./main.go:3:1: syntax error: non-declaration statement\nmore output"}`)
+	if !changed {
+		t.Fatal("expected escalation on the 2nd tool error")
+	}
+
+	switches := s.Switches()
+	last := switches[len(switches)-1]
+	if last.Reason != ReasonMultipleErrors {
+		t.Errorf("expected reason %s, got %s", ReasonMultipleErrors, last.Reason)
+	}
+	want := `2 consecutive Bash errors; last: "./main.go:3:1: syntax error: non-declaration statement"`
+	if last.Evidence != want {
+		t.Errorf("expected evidence %q, got %q", want, last.Evidence)
+	}
+}
+
+func TestToolErrorEvidenceCountsOnlyConsecutiveErrors(t *testing.T) {
+	config := DefaultConfig()
+	config.ErrorThreshold = 3
+	s := NewSelectorWithConfig("test-feature", true, 1, config)
+
+	s.ProcessLine(`{"type":"tool_result","tool":"Edit","is_error":true,"result":"old_string not found"}`)
+	s.ProcessLine(`{"type":"tool_result","tool":"Read","result":"package main"}`)
+	s.ProcessLine(`{"type":"tool_result","tool":"Bash","is_error":true,"result":"command not found"}`)
+	changed, _ := s.ProcessLine(`{"type":"tool_result","tool":"Edit","is_error":true,"result":"file not read"}`)
+	if !changed {
+		t.Fatal("expected escalation on the 3rd tool error")
+	}
+
+	switches := s.Switches()
+	want := `2 consecutive tool errors (Bash, Edit), 3 in total; last: "file not read"`
+	if got := switches[len(switches)-1].Evidence; got != want {
+		t.Errorf("expected evidence %q, got %q", want, got)
+	}
+}
+
+func TestKeywordEscalationRecordsEvidence(t *testing.T) {
+	s := NewSelector("test-feature", true, 1)
+
+	changed, _ := s.ProcessLine(`{"type":"assistant","message":{"content":"This needs a refactor of the storage layer."}}`)
+	if !changed {
+		t.Fatal("expected escalation on keyword")
+	}
+
+	switches := s.Switches()
+	want := `assistant output mentions "refactor": "This needs a refactor of the storage layer."`
+	if got := switches[len(switches)-1].Evidence; got != want {
+		t.Errorf("expected evidence %q, got %q", want, got)
+	}
+}
+
+func TestQuoteSnippetTruncates(t *testing.T) {
+	got := quoteSnippet(strings.Repeat("x", 100))
+	if want := strconv.Quote(strings.Repeat("x", 77) + "..."); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,262 @@
+// Package doctor checks that the environment and PRD directory are ready for
+// a run, for 'ralph doctor'
+package doctor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/vx/ralph-go/internal/auto"
+	"github.com/vx/ralph-go/internal/manifest"
+	"github.com/vx/ralph-go/internal/runner"
+	"github.com/vx/ralph-go/internal/state"
+	"github.com/vx/ralph-go/internal/usage"
+)
+
+// Outcome of a check. Warnings are printed but don't fail the doctor.
+const (
+	Pass = "pass"
+	Warn = "warn"
+	Fail = "fail"
+)
+
+// Check is the result of one preflight check
+type Check struct {
+	Name    string
+	Outcome string
+	Detail  string
+}
+
+// env is what the checks read from the system, replaced in tests
+type env struct {
+	lookPath func(file string) (string, error)
+	getenv   func(key string) string
+	homeDir  string
+}
+
+func systemEnv() env {
+	home, _ := os.UserHomeDir()
+	return env{lookPath: exec.LookPath, getenv: os.Getenv, homeDir: home}
+}
+
+// Run runs every check against prdDir, or PRD/ in the current directory if
+// prdDir is empty, and prints the results to w. It reports whether any check
+// failed.
+func Run(prdDir string, w io.Writer) bool {
+	checks := runChecks(prdDir, systemEnv())
+	failed := false
+	for _, c := range checks {
+		icon := "✓"
+		switch c.Outcome {
+		case Warn:
+			icon = "⚠"
+		case Fail:
+			icon = "✗"
+			failed = true
+		}
+		fmt.Fprintf(w, "%s %s", icon, c.Name)
+		if c.Detail != "" {
+			fmt.Fprintf(w, ": %s", c.Detail)
+		}
+		fmt.Fprintln(w)
+	}
+	if failed {
+		fmt.Fprintln(w, "\nSome checks failed.")
+	} else {
+		fmt.Fprintln(w, "\nReady to run.")
+	}
+	return failed
+}
+
+// runChecks runs the environment checks, then the checks on the PRD
+// directory, which are skipped when it can't be loaded
+func runChecks(prdDir string, e env) []Check {
+	checks := []Check{
+		checkClaude(e),
+		checkAuth(e),
+	}
+
+	resolved, m, check := checkPRDDir(prdDir)
+	checks = append(checks, check)
+	if m == nil {
+		return checks
+	}
+	return append(checks,
+		checkDependencies(m),
+		checkConfig(resolved, m),
+		checkGit(m, e),
+	)
+}
+
+// checkClaude checks that the claude CLI is on PATH
+func checkClaude(e env) Check {
+	c := Check{Name: "claude CLI"}
+	path, err := e.lookPath("claude")
+	if err != nil {
+		c.Outcome = Fail
+		c.Detail = "'claude' not found on PATH"
+		return c
+	}
+	c.Outcome = Pass
+	c.Detail = path
+	return c
+}
+
+// checkAuth looks for an API key or the credentials claude stores once
+// logged in. Credentials may also live in the system keychain, where they
+// can't be seen, so finding none is only a warning.
+func checkAuth(e env) Check {
+	c := Check{Name: "claude authentication"}
+	if e.getenv("ANTHROPIC_API_KEY") != "" {
+		c.Outcome = Pass
+		c.Detail = "ANTHROPIC_API_KEY is set"
+		return c
+	}
+	if e.homeDir != "" {
+		for _, name := range []string{filepath.Join(".claude", ".credentials.json"), ".claude.json"} {
+			if _, err := os.Stat(filepath.Join(e.homeDir, name)); err == nil {
+				c.Outcome = Pass
+				c.Detail = "found ~/" + name
+				return c
+			}
+		}
+	}
+	c.Outcome = Warn
+	c.Detail = "no ANTHROPIC_API_KEY or stored login found; run 'claude' once to log in"
+	return c
+}
+
+// checkPRDDir checks that the PRD directory has a valid manifest and a
+// feature.md for each root feature, returning the loaded manifest
+func checkPRDDir(prdDir string) (string, *manifest.Manifest, Check) {
+	c := Check{Name: "PRD directory"}
+	resolved, err := auto.ResolvePRDDir(prdDir)
+	if err != nil {
+		c.Outcome = Fail
+		c.Detail = err.Error()
+		return "", nil, c
+	}
+	m, err := auto.LoadManifest(resolved)
+	if err != nil {
+		c.Outcome = Fail
+		c.Detail = err.Error()
+		return resolved, nil, c
+	}
+
+	var missing []string
+	for _, f := range m.Features {
+		if !f.IsRootFeature() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(resolved, f.Dir, auto.FeatureFile)); err != nil {
+			missing = append(missing, f.ID)
+		}
+	}
+	if len(missing) > 0 {
+		c.Outcome = Fail
+		c.Detail = fmt.Sprintf("missing %s for feature %s", auto.FeatureFile, strings.Join(missing, ", "))
+		return resolved, m, c
+	}
+	if warnings := m.SchemaWarnings(); len(warnings) > 0 {
+		c.Outcome = Warn
+		c.Detail = fmt.Sprintf("%s: %s", resolved, strings.Join(warnings, "; "))
+		return resolved, m, c
+	}
+	c.Outcome = Pass
+	c.Detail = fmt.Sprintf("%s, %d features", resolved, len(m.Features))
+	return resolved, m, c
+}
+
+// checkDependencies checks for dependency cycles and unknown dependencies
+func checkDependencies(m *manifest.Manifest) Check {
+	c := Check{Name: "Dependencies"}
+	warnings, err := m.ValidateDependencies()
+	if err != nil {
+		c.Outcome = Fail
+		c.Detail = err.Error()
+		return c
+	}
+	if len(warnings) > 0 {
+		c.Outcome = Warn
+		c.Detail = strings.Join(warnings, "; ")
+		return c
+	}
+	c.Outcome = Pass
+	c.Detail = "no cycles"
+	return c
+}
+
+// checkConfig checks the run settings in the manifest and progress.json:
+// extra claude args ralph can pass, non-negative limits, models that have
+// pricing, and a progress.json that parses
+func checkConfig(prdDir string, m *manifest.Manifest) Check {
+	c := Check{Name: "Configuration"}
+	if err := runner.ValidateExtraArgs(m.ClaudeArgs); err != nil {
+		c.Outcome = Fail
+		c.Detail = err.Error()
+		return c
+	}
+	if m.Concurrent < 0 || m.Retries < 0 || m.Warnings < 0 {
+		c.Outcome = Fail
+		c.Detail = "Concurrent, Retries and Warnings must not be negative"
+		return c
+	}
+	if m.BudgetTokens < 0 || m.BudgetUSD < 0 {
+		c.Outcome = Fail
+		c.Detail = "budget must not be negative"
+		return c
+	}
+	progressPath := filepath.Join(prdDir, "progress.json")
+	if _, err := os.Stat(progressPath); err == nil {
+		if _, err := state.LoadProgressFromPath(progressPath); err != nil {
+			c.Outcome = Fail
+			c.Detail = err.Error()
+			return c
+		}
+	}
+
+	var unpriced []string
+	for _, f := range m.Features {
+		if f.Model == "" || f.Model == "auto" {
+			continue
+		}
+		if _, priced := usage.LookupPricing(f.Model); !priced {
+			unpriced = append(unpriced, fmt.Sprintf("feature %s model %q", f.ID, f.Model))
+		}
+	}
+	if len(unpriced) > 0 {
+		c.Outcome = Warn
+		c.Detail = strings.Join(unpriced, ", ") + " has no pricing; costs are estimated at sonnet rates"
+		return c
+	}
+	c.Outcome = Pass
+	return c
+}
+
+// checkGit checks that git is available when a feature sets Base:, which
+// --checkout-base checks out with git
+func checkGit(m *manifest.Manifest, e env) Check {
+	c := Check{Name: "git"}
+	var based []string
+	for _, f := range m.Features {
+		if f.Base != "" {
+			based = append(based, f.ID)
+		}
+	}
+	if len(based) == 0 {
+		c.Outcome = Pass
+		c.Detail = "not needed: no feature sets Base:"
+		return c
+	}
+	if _, err := e.lookPath("git"); err != nil {
+		c.Outcome = Fail
+		c.Detail = fmt.Sprintf("'git' not found on PATH, needed for Base: on feature %s", strings.Join(based, ", "))
+		return c
+	}
+	c.Outcome = Pass
+	return c
+}
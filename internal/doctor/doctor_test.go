@@ -0,0 +1,179 @@
+package doctor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vx/ralph-go/internal/manifest"
+)
+
+// fakeEnv finds only the named executables and has no environment
+func fakeEnv(home string, found ...string) env {
+	return env{
+		lookPath: func(file string) (string, error) {
+			for _, f := range found {
+				if f == file {
+					return "/usr/bin/" + file, nil
+				}
+			}
+			return "", errors.New("not found")
+		},
+		getenv:  func(string) string { return "" },
+		homeDir: home,
+	}
+}
+
+// writePRDDir writes a PRD directory with the given features, each with a
+// feature.md
+func writePRDDir(t *testing.T, features ...manifest.ManifestFeature) string {
+	t.Helper()
+	prdDir := t.TempDir()
+	m := manifest.New("PRD.md", "Doctor Test")
+	for _, f := range features {
+		if f.Dir == "" {
+			f.Dir = f.ID + "-feature"
+		}
+		os.MkdirAll(filepath.Join(prdDir, f.Dir), 0755)
+		os.WriteFile(filepath.Join(prdDir, f.Dir, "feature.md"), []byte("## "+f.Title+"\n"), 0644)
+		m.Features = append(m.Features, f)
+	}
+	m.SetPath(filepath.Join(prdDir, "manifest.json"))
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+	return prdDir
+}
+
+func TestCheckClaude(t *testing.T) {
+	if c := checkClaude(fakeEnv("")); c.Outcome != Fail {
+		t.Errorf("expected a failure without claude on PATH, got %+v", c)
+	}
+	if c := checkClaude(fakeEnv("", "claude")); c.Outcome != Pass || c.Detail != "/usr/bin/claude" {
+		t.Errorf("expected a pass with claude's path, got %+v", c)
+	}
+}
+
+func TestCheckAuth(t *testing.T) {
+	home := t.TempDir()
+	if c := checkAuth(fakeEnv(home)); c.Outcome != Warn {
+		t.Errorf("expected a warning without credentials, got %+v", c)
+	}
+
+	os.MkdirAll(filepath.Join(home, ".claude"), 0755)
+	os.WriteFile(filepath.Join(home, ".claude", ".credentials.json"), []byte("{}"), 0600)
+	if c := checkAuth(fakeEnv(home)); c.Outcome != Pass {
+		t.Errorf("expected stored credentials to pass, got %+v", c)
+	}
+
+	e := fakeEnv("")
+	e.getenv = func(key string) string {
+		if key == "ANTHROPIC_API_KEY" {
+			return "sk-test"
+		}
+		return ""
+	}
+	if c := checkAuth(e); c.Outcome != Pass {
+		t.Errorf("expected an API key to pass, got %+v", c)
+	}
+}
+
+func TestCheckPRDDir(t *testing.T) {
+	if _, m, c := checkPRDDir(filepath.Join(t.TempDir(), "missing")); c.Outcome != Fail || m != nil {
+		t.Errorf("expected a missing directory to fail, got %+v", c)
+	}
+
+	prdDir := writePRDDir(t,
+		manifest.ManifestFeature{ID: "01", Title: "A"},
+		manifest.ManifestFeature{ID: "02", Title: "B"},
+	)
+	if _, m, c := checkPRDDir(prdDir); c.Outcome != Pass || m == nil {
+		t.Errorf("expected a valid directory to pass, got %+v", c)
+	}
+
+	os.Remove(filepath.Join(prdDir, "02-feature", "feature.md"))
+	if _, _, c := checkPRDDir(prdDir); c.Outcome != Fail || !strings.Contains(c.Detail, "feature 02") {
+		t.Errorf("expected a missing feature.md to fail naming 02, got %+v", c)
+	}
+}
+
+func TestCheckDependencies(t *testing.T) {
+	m := manifest.New("PRD.md", "Test")
+	m.Features = []manifest.ManifestFeature{
+		{ID: "01", DependsOn: []string{"02"}},
+		{ID: "02", DependsOn: []string{"01"}},
+	}
+	if c := checkDependencies(m); c.Outcome != Fail {
+		t.Errorf("expected a cycle to fail, got %+v", c)
+	}
+
+	m.Features[1].DependsOn = []string{"09"}
+	if c := checkDependencies(m); c.Outcome != Warn || !strings.Contains(c.Detail, "09") {
+		t.Errorf("expected an unknown dependency to warn, got %+v", c)
+	}
+
+	m.Features[1].DependsOn = nil
+	if c := checkDependencies(m); c.Outcome != Pass {
+		t.Errorf("expected acyclic dependencies to pass, got %+v", c)
+	}
+}
+
+func TestCheckConfig(t *testing.T) {
+	prdDir := t.TempDir()
+	m := manifest.New("PRD.md", "Test")
+	m.Features = []manifest.ManifestFeature{{ID: "01", Model: "sonnet"}}
+	if c := checkConfig(prdDir, m); c.Outcome != Pass {
+		t.Errorf("expected a valid config to pass, got %+v", c)
+	}
+
+	m.ClaudeArgs = []string{"--model=opus"}
+	if c := checkConfig(prdDir, m); c.Outcome != Fail {
+		t.Errorf("expected a reserved claude arg to fail, got %+v", c)
+	}
+	m.ClaudeArgs = nil
+
+	m.Concurrent = -1
+	if c := checkConfig(prdDir, m); c.Outcome != Fail {
+		t.Errorf("expected a negative limit to fail, got %+v", c)
+	}
+	m.Concurrent = 0
+
+	m.Features[0].Model = "gpt"
+	if c := checkConfig(prdDir, m); c.Outcome != Warn {
+		t.Errorf("expected an unpriced model to warn, got %+v", c)
+	}
+	m.Features[0].Model = "sonnet"
+
+	os.WriteFile(filepath.Join(prdDir, "progress.json"), []byte("{not json"), 0644)
+	if c := checkConfig(prdDir, m); c.Outcome != Fail || !strings.Contains(c.Detail, "progress") {
+		t.Errorf("expected a corrupt progress.json to fail, got %+v", c)
+	}
+}
+
+func TestCheckGit(t *testing.T) {
+	m := manifest.New("PRD.md", "Test")
+	m.Features = []manifest.ManifestFeature{{ID: "01"}}
+	if c := checkGit(m, fakeEnv("")); c.Outcome != Pass {
+		t.Errorf("expected git not to be needed without Base:, got %+v", c)
+	}
+
+	m.Features[0].Base = "v1.0"
+	if c := checkGit(m, fakeEnv("")); c.Outcome != Fail {
+		t.Errorf("expected Base: without git to fail, got %+v", c)
+	}
+	if c := checkGit(m, fakeEnv("", "git")); c.Outcome != Pass {
+		t.Errorf("expected Base: with git to pass, got %+v", c)
+	}
+}
+
+func TestRunChecksSkipsPRDChecksWithoutManifest(t *testing.T) {
+	checks := runChecks(filepath.Join(t.TempDir(), "missing"), fakeEnv("", "claude"))
+	if len(checks) != 3 {
+		t.Fatalf("expected only the environment and PRD directory checks, got %+v", checks)
+	}
+	if checks[2].Outcome != Fail {
+		t.Errorf("expected the PRD directory check to fail, got %+v", checks[2])
+	}
+}
@@ -180,6 +180,66 @@ func appendToGitignore(path, entry string) error {
 	return err
 }
 
+// gitignoreHeader marks the section of .gitignore managed by ralph
+const gitignoreHeader = "# ralph-go artifacts"
+
+// gitignoreEntries are the ralph-generated files kept out of git: logs,
+// prompt dumps and other runtime data live under .ralph/, progress under
+// progress.json
+var gitignoreEntries = []string{
+	".ralph/",
+	"progress.json",
+}
+
+// appendGitignoreSection adds the entries missing from the .gitignore at path
+// under header, returning the entries added. Running it again is a no-op.
+func appendGitignoreSection(path, header string, entries []string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	existing := make(map[string]bool)
+	for _, line := range strings.Split(string(content), "\n") {
+		existing[strings.TrimSpace(line)] = true
+	}
+
+	var missing []string
+	for _, entry := range entries {
+		if !existing[entry] {
+			missing = append(missing, entry)
+		}
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	var sb strings.Builder
+	if len(content) > 0 && content[len(content)-1] != '\n' {
+		sb.WriteString("\n")
+	}
+	if !existing[header] {
+		if len(content) > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(header + "\n")
+	}
+	for _, entry := range missing {
+		sb.WriteString(entry + "\n")
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(sb.String()); err != nil {
+		return nil, err
+	}
+	return missing, nil
+}
+
 func Run(force bool) error {
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -219,10 +279,12 @@ func Run(force bool) error {
 	fmt.Println("  Created input_design/")
 
 	gitignorePath := filepath.Join(cwd, ".gitignore")
-	if err := appendToGitignore(gitignorePath, ".ralph/"); err != nil {
+	if added, err := appendGitignoreSection(gitignorePath, gitignoreHeader, gitignoreEntries); err != nil {
 		fmt.Printf("  Warning: could not update .gitignore: %v\n", err)
+	} else if len(added) > 0 {
+		fmt.Printf("  Added %s to .gitignore\n", strings.Join(added, ", "))
 	} else {
-		fmt.Println("  Added .ralph/ to .gitignore")
+		fmt.Println("  .gitignore already ignores ralph artifacts")
 	}
 
 	fmt.Println()
@@ -0,0 +1,66 @@
+package init
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runInitIn(t *testing.T, dir string) {
+	t.Helper()
+	origDir, _ := os.Getwd()
+	defer os.Chdir(origDir)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("failed to chdir: %v", err)
+	}
+	if err := Run(false); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+}
+
+func TestRunWritesGitignoreIdempotently(t *testing.T) {
+	tempDir := t.TempDir()
+	gitignorePath := filepath.Join(tempDir, ".gitignore")
+	if err := os.WriteFile(gitignorePath, []byte("node_modules/\n.ralph/"), 0644); err != nil {
+		t.Fatalf("failed to write .gitignore: %v", err)
+	}
+
+	runInitIn(t, tempDir)
+	first, _ := os.ReadFile(gitignorePath)
+
+	runInitIn(t, tempDir)
+	second, _ := os.ReadFile(gitignorePath)
+
+	if string(first) != string(second) {
+		t.Errorf("second init changed .gitignore:\n%s\n---\n%s", first, second)
+	}
+
+	content := string(second)
+	if !strings.HasPrefix(content, "node_modules/\n") {
+		t.Error("existing .gitignore content should be preserved")
+	}
+	for _, entry := range append([]string{gitignoreHeader}, gitignoreEntries...) {
+		if count := strings.Count(content, entry+"\n"); count != 1 {
+			t.Errorf("%q should appear exactly once in .gitignore, found %d times", entry, count)
+		}
+	}
+}
+
+func TestAppendGitignoreSectionCreatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".gitignore")
+
+	added, err := appendGitignoreSection(path, gitignoreHeader, gitignoreEntries)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(added) != len(gitignoreEntries) {
+		t.Errorf("expected all entries added, got %v", added)
+	}
+
+	content, _ := os.ReadFile(path)
+	want := gitignoreHeader + "\n.ralph/\nprogress.json\n"
+	if string(content) != want {
+		t.Errorf("expected %q, got %q", want, content)
+	}
+}
@@ -10,21 +10,32 @@ import (
 	"github.com/vx/ralph-go/internal/parser"
 )
 
+// InitFromPRD creates the PRD/ directory from a PRD file, or from a directory
+// of PRD files merged in filename order
 func InitFromPRD(prdPath string, force bool) error {
-	prd, err := parser.ParsePRD(prdPath)
+	prds, err := parsePRDs(prdPath)
 	if err != nil {
-		return fmt.Errorf("failed to parse PRD: %w", err)
+		return err
 	}
 
-	if len(prd.Features) == 0 {
+	featureCount := 0
+	for _, prd := range prds {
+		featureCount += len(prd.Features)
+	}
+	if featureCount == 0 {
 		return fmt.Errorf("no features found in PRD file")
 	}
 
-	prdDir := filepath.Dir(prdPath)
+	prdDir := filepath.Dir(filepath.Clean(prdPath))
 	outputDir := filepath.Join(prdDir, "PRD")
 
-	if _, err := os.Stat(outputDir); err == nil {
+	if info, err := os.Stat(outputDir); err == nil {
+		// On a case-insensitive filesystem a prd/ spec directory is PRD/
+		if source, err := os.Stat(prdPath); err == nil && os.SameFile(info, source) {
+			return fmt.Errorf("%s is where the PRD/ directory goes; rename it first", prdPath)
+		}
 		if !force {
+			reportRemovedFeatures(outputDir, prds)
 			return fmt.Errorf("PRD/ directory already exists (use --force to overwrite)")
 		}
 		if err := os.RemoveAll(outputDir); err != nil {
@@ -37,31 +48,39 @@ func InitFromPRD(prdPath string, force bool) error {
 	}
 	fmt.Println("  Created PRD/")
 
-	globalContext := buildGlobalContext(prd)
+	i := 0
+	for _, prd := range prds {
+		globalContext := buildGlobalContext(prd)
 
-	for i, feature := range prd.Features {
-		dirName := fmt.Sprintf("%02d-%s", i+1, sanitizeDirName(feature.Title))
-		featureDir := filepath.Join(outputDir, dirName)
+		for _, feature := range prd.Features {
+			i++
+			dirName := fmt.Sprintf("%02d-%s", i, sanitizeDirName(feature.Title))
+			featureDir := filepath.Join(outputDir, dirName)
 
-		if err := os.MkdirAll(featureDir, 0755); err != nil {
-			return fmt.Errorf("failed to create feature directory %s: %w", dirName, err)
-		}
+			if err := os.MkdirAll(featureDir, 0755); err != nil {
+				return fmt.Errorf("failed to create feature directory %s: %w", dirName, err)
+			}
 
-		featureContent := buildFeatureMD(globalContext, feature)
-		featurePath := filepath.Join(featureDir, "feature.md")
+			featureContent := buildFeatureMD(globalContext, feature)
+			featurePath := filepath.Join(featureDir, "feature.md")
 
-		if err := os.WriteFile(featurePath, []byte(featureContent), 0644); err != nil {
-			return fmt.Errorf("failed to write feature.md for %s: %w", feature.Title, err)
-		}
+			if err := os.WriteFile(featurePath, []byte(featureContent), 0644); err != nil {
+				return fmt.Errorf("failed to write feature.md for %s: %w", feature.Title, err)
+			}
 
-		fmt.Printf("  Created %s/feature.md\n", dirName)
+			fmt.Printf("  Created %s/feature.md\n", dirName)
+		}
 	}
 
-	m, err := manifest.GenerateFromPRD(prd, prdPath)
+	m, err := manifest.GenerateFromPRDs(prds, prdPath)
 	if err != nil {
 		return fmt.Errorf("failed to generate manifest: %w", err)
 	}
 
+	for _, warning := range duplicateTitles(m) {
+		fmt.Printf("  Warning: %s\n", warning)
+	}
+
 	m.ResolveDependencies()
 
 	removed := m.RemoveMissingDependencies()
@@ -99,6 +118,122 @@ func InitFromPRD(prdPath string, force bool) error {
 	return nil
 }
 
+// PruneFromPRD removes the features of an existing PRD/ directory that are
+// no longer in the PRD file or directory at prdPath: their directories, their
+// manifest entries and other features' dependencies on them. Running features
+// are left alone. The remaining features keep their IDs and progress.
+func PruneFromPRD(prdPath string) error {
+	prds, err := parsePRDs(prdPath)
+	if err != nil {
+		return err
+	}
+
+	outputDir := filepath.Join(filepath.Dir(filepath.Clean(prdPath)), "PRD")
+	m, err := manifest.Load(outputDir)
+	if err != nil {
+		return fmt.Errorf("no PRD/ directory to prune (run 'ralph init %s' first): %w", prdPath, err)
+	}
+
+	var ids []string
+	for _, f := range m.RemovedFeatures(prds) {
+		if f.Status == "running" {
+			fmt.Printf("  Warning: feature %s (%s) is running; not removing it\n", f.ID, f.Title)
+			continue
+		}
+		ids = append(ids, f.ID)
+	}
+	if len(ids) == 0 {
+		fmt.Println("  No features to remove: PRD/ matches the PRD")
+		return nil
+	}
+
+	pruned, warnings := m.PruneFeatures(ids)
+	for _, warning := range warnings {
+		fmt.Printf("  Warning: %s\n", warning)
+	}
+	if err := m.Save(); err != nil {
+		return fmt.Errorf("failed to save manifest: %w", err)
+	}
+	for _, f := range pruned {
+		if f.Dir != "" {
+			if err := os.RemoveAll(filepath.Join(outputDir, f.Dir)); err != nil {
+				fmt.Printf("  Warning: failed to remove %s/: %v\n", f.Dir, err)
+				continue
+			}
+		}
+		fmt.Printf("  Removed %s (%s)\n", f.ID, f.Title)
+	}
+	return nil
+}
+
+// reportRemovedFeatures lists the features of an existing PRD/ directory that
+// are no longer in prds, and how to remove them
+func reportRemovedFeatures(outputDir string, prds []*parser.PRD) {
+	m, err := manifest.Load(outputDir)
+	if err != nil {
+		return
+	}
+	removed := m.RemovedFeatures(prds)
+	for _, f := range removed {
+		fmt.Printf("  Feature %s (%s) is no longer in the PRD\n", f.ID, f.Title)
+	}
+	if len(removed) > 0 {
+		fmt.Println("  Use --prune to remove them from PRD/ and keep the rest")
+	}
+}
+
+// parsePRDs parses the PRD file at path or, if path is a directory, each of
+// its .md files in filename order
+func parsePRDs(path string) ([]*parser.PRD, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		prd, err := parser.ParsePRD(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse PRD: %w", err)
+		}
+		return []*parser.PRD{prd}, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(path, "*.md"))
+	if err != nil {
+		return nil, err
+	}
+	var prds []*parser.PRD
+	for _, file := range files {
+		if info, err := os.Stat(file); err != nil || info.IsDir() {
+			continue
+		}
+		prd, err := parser.ParsePRD(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		prds = append(prds, prd)
+	}
+	if len(prds) == 0 {
+		return nil, fmt.Errorf("no .md files found in %s", path)
+	}
+	return prds, nil
+}
+
+// duplicateTitles warns about features that share a title, since a Depends:
+// on the title from another file matches only the first of them
+func duplicateTitles(m *manifest.Manifest) []string {
+	var warnings []string
+	seen := make(map[string]string)
+	for _, f := range m.Features {
+		title := strings.ToLower(f.Title)
+		if first, ok := seen[title]; ok {
+			warnings = append(warnings, fmt.Sprintf("features %s and %s are both titled %q; dependencies on it by title use %s", first, f.ID, f.Title, first))
+			continue
+		}
+		seen[title] = f.ID
+	}
+	return warnings
+}
+
 func printSummary(m *manifest.Manifest) {
 	features := m.AllFeatures()
 	fmt.Println()
@@ -204,7 +339,14 @@ func buildFeatureMD(globalContext string, feature parser.Feature) string {
 
 	sb.WriteString("Model: ")
 	sb.WriteString(feature.Model)
-	sb.WriteString("\n\n")
+	sb.WriteString("\n")
+
+	if feature.Base != "" {
+		sb.WriteString("Base: ")
+		sb.WriteString(feature.Base)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
 
 	if len(feature.Tasks) > 0 {
 		for _, task := range feature.Tasks {
@@ -225,5 +367,11 @@ func buildFeatureMD(globalContext string, feature parser.Feature) string {
 		}
 	}
 
+	if feature.PromptSuffix != "" {
+		sb.WriteString("\n```prompt\n")
+		sb.WriteString(feature.PromptSuffix)
+		sb.WriteString("\n```\n")
+	}
+
 	return sb.String()
 }
@@ -5,6 +5,9 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/vx/ralph-go/internal/manifest"
+	"github.com/vx/ralph-go/internal/parser"
 )
 
 func TestSanitizeDirName(t *testing.T) {
@@ -194,6 +197,89 @@ Model: sonnet
 	}
 }
 
+func TestPruneFromPRD(t *testing.T) {
+	tempDir := t.TempDir()
+	prdPath := filepath.Join(tempDir, "PRD.md")
+	writePRD := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(prdPath, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write test PRD: %v", err)
+		}
+	}
+
+	writePRD(`# Shop
+
+## Schema
+
+- [ ] Create tables
+
+## Auth
+
+- [ ] Add login
+
+## Checkout
+
+Depends: Auth
+
+- [ ] Add cart
+`)
+	if err := InitFromPRD(prdPath, false); err != nil {
+		t.Fatalf("InitFromPRD failed: %v", err)
+	}
+	prdDir := filepath.Join(tempDir, "PRD")
+	m, _ := manifest.Load(prdDir)
+	m.UpdateFeatureStatus("01", "completed")
+	m.Save()
+
+	writePRD(`# Shop
+
+## Schema
+
+- [ ] Create tables
+
+## Checkout
+
+- [ ] Add cart
+`)
+
+	if err := InitFromPRD(prdPath, false); err == nil {
+		t.Fatal("expected init without --prune to leave the existing PRD/ alone")
+	}
+	m, _ = manifest.Load(prdDir)
+	if removed := m.RemovedFeatures([]*parser.PRD{mustParsePRD(t, prdPath)}); len(removed) != 1 || removed[0].ID != "02" {
+		t.Fatalf("expected feature 02 to be detected as removed, got %+v", removed)
+	}
+	if _, err := os.Stat(filepath.Join(prdDir, "02-auth")); err != nil {
+		t.Fatal("expected 02-auth/ to be kept without --prune")
+	}
+
+	if err := PruneFromPRD(prdPath); err != nil {
+		t.Fatalf("PruneFromPRD failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(prdDir, "02-auth")); !os.IsNotExist(err) {
+		t.Error("expected 02-auth/ to be removed")
+	}
+	m, _ = manifest.Load(prdDir)
+	if len(m.Features) != 2 || m.GetFeature("02") != nil {
+		t.Fatalf("expected 02 to be pruned from the manifest, got %+v", m.Features)
+	}
+	if got := m.GetFeature("01").Status; got != "completed" {
+		t.Errorf("expected 01 to keep its status, got %q", got)
+	}
+	if deps := m.GetFeature("03").DependsOn; len(deps) != 0 {
+		t.Errorf("expected the dependency on 02 to be dropped, got %v", deps)
+	}
+}
+
+func mustParsePRD(t *testing.T, path string) *parser.PRD {
+	t.Helper()
+	prd, err := parser.ParsePRD(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return prd
+}
+
 func TestInitFromPRDNoFeatures(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -437,3 +523,108 @@ Depends: 99
 		t.Error("manifest.json should be created even with missing dep warning")
 	}
 }
+
+func TestInitFromPRDDirectory(t *testing.T) {
+	tempDir := t.TempDir()
+	specDir := filepath.Join(tempDir, "prd")
+	if err := os.MkdirAll(specDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"01-backend.md": `# Backend
+
+## Database Schema
+
+- [ ] Create tables
+
+## API
+
+Depends: 1
+
+- [ ] Add endpoints
+`,
+		"02-frontend.md": `# Frontend
+
+## Login Page
+
+Depends: Database Schema
+
+- [ ] Build the form
+
+## Dashboard
+
+Depends: 1, API
+
+- [ ] Show stats
+
+## Reports
+
+Depends: 4
+
+- [ ] Export CSV
+`,
+		"notes.txt": "not a PRD",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(specDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	if err := InitFromPRD(specDir+string(filepath.Separator), false); err != nil {
+		t.Fatalf("InitFromPRD failed: %v", err)
+	}
+
+	m, err := manifest.Load(filepath.Join(tempDir, "PRD"))
+	if err != nil {
+		t.Fatalf("failed to load manifest: %v", err)
+	}
+	if m.Title != "Backend" {
+		t.Errorf("expected the first file's title, got %q", m.Title)
+	}
+
+	want := []struct {
+		id, dir, title string
+		deps           []string
+	}{
+		{"01", "01-database-schema", "Database Schema", nil},
+		{"02", "02-api", "API", []string{"01"}},
+		{"03", "03-login-page", "Login Page", []string{"01"}},
+		{"04", "04-dashboard", "Dashboard", []string{"03", "02"}},
+		{"05", "05-reports", "Reports", nil},
+	}
+	if len(m.Features) != len(want) {
+		t.Fatalf("expected %d features, got %d", len(want), len(m.Features))
+	}
+	for i, w := range want {
+		f := m.Features[i]
+		if f.ID != w.id || f.Dir != w.dir || f.Title != w.title {
+			t.Errorf("feature %d: expected %s %s %q, got %s %s %q", i, w.id, w.dir, w.title, f.ID, f.Dir, f.Title)
+		}
+		if strings.Join(f.DependsOn, ",") != strings.Join(w.deps, ",") {
+			t.Errorf("feature %s: expected deps %v, got %v", f.ID, w.deps, f.DependsOn)
+		}
+		if _, err := os.Stat(filepath.Join(tempDir, "PRD", w.dir, "feature.md")); err != nil {
+			t.Errorf("expected %s/feature.md: %v", w.dir, err)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "PRD", "03-login-page", "feature.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(content), "# Frontend") {
+		t.Errorf("expected the feature to keep its own file's context, got:\n%s", content)
+	}
+}
+
+func TestInitFromPRDEmptyDirectory(t *testing.T) {
+	specDir := filepath.Join(t.TempDir(), "prd")
+	os.MkdirAll(specDir, 0755)
+
+	err := InitFromPRD(specDir, false)
+	if err == nil || !strings.Contains(err.Error(), "no .md files") {
+		t.Errorf("expected a no .md files error, got %v", err)
+	}
+}
@@ -0,0 +1,343 @@
+package init
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// maxScannedFiles bounds the TODO scan on large trees
+	maxScannedFiles = 2000
+	// maxScannedFileSize skips generated or data files when scanning for TODOs
+	maxScannedFileSize = 1 << 20
+	// maxTODOsPerFeature keeps generated task lists short enough to edit
+	maxTODOsPerFeature = 10
+)
+
+// skippedDirs are never turned into features or scanned for TODOs
+var skippedDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"dist":         true,
+	"build":        true,
+	"target":       true,
+	"bin":          true,
+	"testdata":     true,
+	"PRD":          true,
+	"input_design": true,
+}
+
+// languageMarkers maps build files to the language they indicate
+var languageMarkers = []struct {
+	file     string
+	language string
+}{
+	{"go.mod", "Go"},
+	{"package.json", "JavaScript/TypeScript"},
+	{"Cargo.toml", "Rust"},
+	{"pyproject.toml", "Python"},
+	{"requirements.txt", "Python"},
+	{"pom.xml", "Java"},
+	{"build.gradle", "Java/Kotlin"},
+	{"Gemfile", "Ruby"},
+	{"mix.exs", "Elixir"},
+}
+
+// todoRegex matches TODO and FIXME comments in common comment syntaxes,
+// with or without an owner as in "TODO(alice):"
+var todoRegex = regexp.MustCompile(`(?://|#|--|/\*|;|<!--)\s*(?:TODO|FIXME)\b(?:\([^)]*\))?[:\s]*(.*)`)
+
+// todoComment is a TODO or FIXME found while scanning a project
+type todoComment struct {
+	Path string // Relative to the project root
+	Line int
+	Text string
+}
+
+// projectScan is what GeneratePRDFromDir knows about an existing project
+type projectScan struct {
+	Name        string
+	Description string
+	Languages   []string
+	Dirs        []string
+	TODOs       map[string][]todoComment // Keyed by top-level dir, "" for root files
+}
+
+// scanProject collects the top-level directories, README summary, languages
+// and TODO comments of the project at root
+func scanProject(root string) (*projectScan, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", root, err)
+	}
+
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", root, err)
+	}
+
+	scan := &projectScan{
+		Name:  filepath.Base(abs),
+		TODOs: make(map[string][]todoComment),
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() && !isSkippedDir(entry.Name()) {
+			scan.Dirs = append(scan.Dirs, entry.Name())
+		}
+	}
+	sort.Strings(scan.Dirs)
+
+	for _, marker := range languageMarkers {
+		if _, err := os.Stat(filepath.Join(root, marker.file)); err == nil && !containsString(scan.Languages, marker.language) {
+			scan.Languages = append(scan.Languages, marker.language)
+		}
+	}
+
+	if name, description := readmeSummary(root); name != "" || description != "" {
+		if name != "" {
+			scan.Name = name
+		}
+		scan.Description = description
+	}
+
+	scanned := 0
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != root && isSkippedDir(d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if scanned >= maxScannedFiles {
+			return filepath.SkipAll
+		}
+		scanned++
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return nil
+		}
+		group := ""
+		if parts := strings.SplitN(filepath.ToSlash(rel), "/", 2); len(parts) == 2 {
+			group = parts[0]
+		}
+		scan.TODOs[group] = append(scan.TODOs[group], findTODOs(path, filepath.ToSlash(rel))...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+
+	return scan, nil
+}
+
+func isSkippedDir(name string) bool {
+	return strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") || skippedDirs[name]
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// readmeSummary returns the README's H1 and its first paragraph of prose
+func readmeSummary(root string) (name, description string) {
+	var content []byte
+	for _, candidate := range []string{"README.md", "README", "readme.md", "README.txt"} {
+		data, err := os.ReadFile(filepath.Join(root, candidate))
+		if err == nil {
+			content = data
+			break
+		}
+	}
+	if content == nil {
+		return "", ""
+	}
+
+	var paragraph []string
+	for _, line := range strings.Split(string(content), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "# ") && name == "":
+			name = strings.TrimSpace(strings.TrimPrefix(trimmed, "# "))
+		case trimmed == "":
+			if len(paragraph) > 0 {
+				return name, strings.Join(paragraph, " ")
+			}
+		case strings.HasPrefix(trimmed, "#"), strings.HasPrefix(trimmed, "!["),
+			strings.HasPrefix(trimmed, "[!["), strings.HasPrefix(trimmed, "<"):
+			// Headings, badges and HTML aren't a description
+			if len(paragraph) > 0 {
+				return name, strings.Join(paragraph, " ")
+			}
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	return name, strings.Join(paragraph, " ")
+}
+
+// findTODOs returns the TODO comments in a text file; binary and large files
+// are skipped
+func findTODOs(path, rel string) []todoComment {
+	info, err := os.Stat(path)
+	if err != nil || info.Size() > maxScannedFileSize {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	head := data
+	if len(head) > 512 {
+		head = head[:512]
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return nil
+	}
+
+	var todos []todoComment
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	for scanner.Scan() {
+		line++
+		match := todoRegex.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		text := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(match[1]), "*/"))
+		text = strings.TrimSpace(strings.TrimSuffix(text, "-->"))
+		if text == "" {
+			continue
+		}
+		todos = append(todos, todoComment{Path: rel, Line: line, Text: text})
+	}
+	return todos
+}
+
+// GeneratePRDFromDir scans the project at root and returns a starter PRD with
+// one candidate feature per top-level directory, seeded with its TODOs
+func GeneratePRDFromDir(root string) (string, error) {
+	scan, err := scanProject(root)
+	if err != nil {
+		return "", err
+	}
+	return buildScaffoldPRD(scan), nil
+}
+
+// buildScaffoldPRD renders the scan as a PRD. Context sections use H3 since
+// every H2 is parsed as a feature.
+func buildScaffoldPRD(scan *projectScan) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# %s\n\n", scan.Name))
+	if scan.Description != "" {
+		sb.WriteString(scan.Description + "\n\n")
+	} else {
+		sb.WriteString("Brief description of what this project does.\n\n")
+	}
+
+	sb.WriteString("### Technology Stack\n\n")
+	if len(scan.Languages) > 0 {
+		sb.WriteString(fmt.Sprintf("- Language: %s\n", strings.Join(scan.Languages, ", ")))
+	} else {
+		sb.WriteString("- Language:\n")
+	}
+	sb.WriteString("- Framework:\n\n")
+
+	sb.WriteString("### Architecture Notes\n\n")
+	if len(scan.Dirs) > 0 {
+		sb.WriteString("Top-level directories:\n\n")
+		for _, dir := range scan.Dirs {
+			sb.WriteString(fmt.Sprintf("- `%s/`\n", dir))
+		}
+		sb.WriteString("\n")
+	} else {
+		sb.WriteString("High-level architecture decisions.\n\n")
+	}
+
+	n := 0
+	for _, dir := range scan.Dirs {
+		n++
+		title := fmt.Sprintf("Feature %d: %s", n, featureTitleFromDir(dir))
+		writeScaffoldFeature(&sb, title, fmt.Sprintf("Changes to `%s/`. Edit this description and the tasks below.", dir), scan.TODOs[dir])
+	}
+	if todos := scan.TODOs[""]; len(todos) > 0 {
+		n++
+		writeScaffoldFeature(&sb, fmt.Sprintf("Feature %d: Project-wide TODOs", n), "Outstanding TODOs in top-level files.", todos)
+	}
+	if n == 0 {
+		writeScaffoldFeature(&sb, "Feature 1: Core Feature", "Description of this feature.", nil)
+	}
+
+	return sb.String()
+}
+
+func writeScaffoldFeature(sb *strings.Builder, title, description string, todos []todoComment) {
+	sb.WriteString("---\n\n")
+	sb.WriteString(fmt.Sprintf("## %s\n\n", title))
+	sb.WriteString(description + "\n\n")
+	sb.WriteString("Execution: sequential\n")
+	sb.WriteString("Model: sonnet\n\n")
+
+	if len(todos) > maxTODOsPerFeature {
+		todos = todos[:maxTODOsPerFeature]
+	}
+	for _, todo := range todos {
+		sb.WriteString(fmt.Sprintf("- [ ] %s (%s:%d)\n", todo.Text, todo.Path, todo.Line))
+	}
+	if len(todos) == 0 {
+		sb.WriteString("- [ ] Describe the first change\n")
+	}
+	sb.WriteString("- [ ] Write tests\n\n")
+	sb.WriteString("Acceptance: Tests pass\n\n")
+}
+
+// featureTitleFromDir turns a directory name like "user_service" into
+// "User Service"
+func featureTitleFromDir(dir string) string {
+	words := strings.FieldsFunc(dir, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.' || r == ' '
+	})
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	if len(words) == 0 {
+		return dir
+	}
+	return strings.Join(words, " ")
+}
+
+// InitFromDir writes a starter PRD.md to the project at root, generated from
+// its structure, README and TODO comments
+func InitFromDir(root string, force bool) (string, error) {
+	prdPath := filepath.Join(root, "PRD.md")
+	if _, err := os.Stat(prdPath); err == nil && !force {
+		return "", fmt.Errorf("%s already exists (use --force to overwrite)", prdPath)
+	}
+
+	content, err := GeneratePRDFromDir(root)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.WriteFile(prdPath, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write PRD.md: %w", err)
+	}
+	return prdPath, nil
+}
@@ -0,0 +1,107 @@
+package init
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vx/ralph-go/internal/parser"
+)
+
+func writeProjectFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create dir for %s: %v", rel, err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", rel, err)
+	}
+}
+
+func TestGeneratePRDFromDir(t *testing.T) {
+	root := t.TempDir()
+	writeProjectFile(t, root, "README.md", "# Widget Shop\n\n[![build](badge.svg)](ci)\n\nAn online shop for widgets.\nBuilt for speed.\n\n## Install\n\nRun make.\n")
+	writeProjectFile(t, root, "go.mod", "module example.com/widgets\n")
+	writeProjectFile(t, root, "main.go", "package main\n\n// TODO: read the port from config\nfunc main() {}\n")
+	writeProjectFile(t, root, "cmd/server/main.go", "package main\n")
+	writeProjectFile(t, root, "internal/cart/cart.go", "package cart\n\n// TODO: persist carts in the database\n// FIXME(alice): totals ignore discounts\n")
+	writeProjectFile(t, root, "user_service/handler.py", "# TODO add rate limiting\n")
+	writeProjectFile(t, root, "node_modules/dep/index.js", "// TODO: not ours\n")
+	writeProjectFile(t, root, ".git/HEAD", "ref: refs/heads/main\n")
+
+	content, err := GeneratePRDFromDir(root)
+	if err != nil {
+		t.Fatalf("GeneratePRDFromDir failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"# Widget Shop",
+		"An online shop for widgets. Built for speed.",
+		"- Language: Go",
+		"## Feature 1: Cmd",
+		"## Feature 2: Internal",
+		"## Feature 3: User Service",
+		"## Feature 4: Project-wide TODOs",
+		"- [ ] persist carts in the database (internal/cart/cart.go:3)",
+		"- [ ] totals ignore discounts (internal/cart/cart.go:4)",
+		"- [ ] add rate limiting (user_service/handler.py:1)",
+		"- [ ] read the port from config (main.go:3)",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected generated PRD to contain %q, got:\n%s", want, content)
+		}
+	}
+	for _, unwanted := range []string{"Node Modules", "not ours", ".git"} {
+		if strings.Contains(content, unwanted) {
+			t.Errorf("expected generated PRD not to contain %q", unwanted)
+		}
+	}
+
+	prd, err := parser.ParsePRDContent(content)
+	if err != nil {
+		t.Fatalf("generated PRD does not parse: %v", err)
+	}
+	if len(prd.Features) != 4 {
+		t.Fatalf("expected 4 parsed features, got %d", len(prd.Features))
+	}
+	if prd.Features[1].Title != "Feature 2: Internal" || len(prd.Features[1].Tasks) != 3 {
+		t.Errorf("expected Internal feature with 2 TODOs and a test task, got %q with %d tasks",
+			prd.Features[1].Title, len(prd.Features[1].Tasks))
+	}
+}
+
+func TestGeneratePRDFromEmptyDir(t *testing.T) {
+	content, err := GeneratePRDFromDir(t.TempDir())
+	if err != nil {
+		t.Fatalf("GeneratePRDFromDir failed: %v", err)
+	}
+	prd, err := parser.ParsePRDContent(content)
+	if err != nil {
+		t.Fatalf("generated PRD does not parse: %v", err)
+	}
+	if len(prd.Features) != 1 {
+		t.Errorf("expected a single placeholder feature, got %d", len(prd.Features))
+	}
+}
+
+func TestInitFromDirRespectsForce(t *testing.T) {
+	root := t.TempDir()
+	writeProjectFile(t, root, "src/app.js", "// TODO: add routing\n")
+
+	prdPath, err := InitFromDir(root, false)
+	if err != nil {
+		t.Fatalf("InitFromDir failed: %v", err)
+	}
+	if data, _ := os.ReadFile(prdPath); !strings.Contains(string(data), "## Feature 1: Src") {
+		t.Errorf("expected PRD.md to be written, got:\n%s", data)
+	}
+
+	if _, err := InitFromDir(root, false); err == nil {
+		t.Error("expected an error when PRD.md exists without --force")
+	}
+	if _, err := InitFromDir(root, true); err != nil {
+		t.Errorf("expected --force to overwrite PRD.md, got %v", err)
+	}
+}
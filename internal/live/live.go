@@ -0,0 +1,229 @@
+// Package live publishes the state of a headless run to .ralph/live.json so
+// 'ralph attach' can follow it from another terminal
+package live
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// StatusFile is where a headless run publishes its status, relative to the
+// directory it runs in
+const StatusFile = ".ralph/live.json"
+
+// HeartbeatInterval is how often a running publisher rewrites the status
+// file even when nothing has changed
+const HeartbeatInterval = 2 * time.Second
+
+// StaleAfter is how long a status can go without a heartbeat before readers
+// assume the run was killed
+const StaleAfter = 5 * HeartbeatInterval
+
+// Feature is a feature the run is currently executing
+type Feature struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// Status is the snapshot of a headless run written to the status file
+type Status struct {
+	PID       int       `json:"pid"`
+	PRDDir    string    `json:"prd_dir"`
+	StartedAt time.Time `json:"started_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Running   []Feature `json:"running"`
+	Completed int       `json:"completed"`
+	Failed    int       `json:"failed"` // Failed features that aren't optional
+	// OptionalFailed are optional features that failed without failing the
+	// run, and Skipped those skipped by On-Failure: skip
+	OptionalFailed int  `json:"optional_failed,omitempty"`
+	Skipped        int  `json:"skipped,omitempty"`
+	Done           bool `json:"done"`
+}
+
+// Active reports whether the run is still going: it hasn't finished and its
+// last heartbeat is recent
+func (s *Status) Active(now time.Time) bool {
+	return !s.Done && now.Sub(s.UpdatedAt) < StaleAfter
+}
+
+// Describe summarizes the run for a status line
+func (s *Status) Describe(now time.Time) string {
+	switch {
+	case s.Done:
+		return "Run finished: " + s.counts()
+	case !s.Active(now):
+		return fmt.Sprintf("Run (pid %d) stopped updating at %s", s.PID, s.UpdatedAt.Local().Format("15:04:05"))
+	default:
+		return fmt.Sprintf("Following run (pid %d): %d running, %s", s.PID, len(s.Running), s.counts())
+	}
+}
+
+// counts describes the finished features, leaving out optional failures
+// and skips when there are none
+func (s *Status) counts() string {
+	counts := fmt.Sprintf("%d completed, %d failed", s.Completed, s.Failed)
+	if s.OptionalFailed > 0 {
+		counts += fmt.Sprintf(", %d optional failed", s.OptionalFailed)
+	}
+	if s.Skipped > 0 {
+		counts += fmt.Sprintf(", %d skipped", s.Skipped)
+	}
+	return counts
+}
+
+// Path returns the status file for a run in workDir
+func Path(workDir string) string {
+	return filepath.Join(workDir, StatusFile)
+}
+
+// Read loads the status of the run in workDir. The error satisfies
+// os.IsNotExist when no run has published one.
+func Read(workDir string) (*Status, error) {
+	data, err := os.ReadFile(Path(workDir))
+	if err != nil {
+		return nil, err
+	}
+	var s Status
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", Path(workDir), err)
+	}
+	return &s, nil
+}
+
+// Write replaces the status file for the run in workDir. It writes to a
+// temporary file and renames it so readers never see a partial status.
+func Write(workDir string, s *Status) error {
+	path := Path(workDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Publisher keeps the status file of a running process up to date. A nil
+// Publisher does nothing, so callers needn't check whether publishing is on.
+type Publisher struct {
+	workDir string
+
+	mu      sync.Mutex
+	status  Status
+	running map[string]Feature
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Start publishes a new run of prdDir in workDir and keeps its heartbeat
+// going until Close
+func Start(workDir, prdDir string) (*Publisher, error) {
+	now := time.Now()
+	p := &Publisher{
+		workDir: workDir,
+		status: Status{
+			PID:       os.Getpid(),
+			PRDDir:    prdDir,
+			StartedAt: now,
+		},
+		running: make(map[string]Feature),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if err := p.publish(); err != nil {
+		return nil, err
+	}
+	go p.heartbeat()
+	return p, nil
+}
+
+// FeatureStarted records that a feature began executing
+func (p *Publisher) FeatureStarted(id, title string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.running[id] = Feature{ID: id, Title: title, StartedAt: time.Now()}
+	p.mu.Unlock()
+	p.publish()
+}
+
+// FeatureFinished records a feature's final status. A failed optional
+// feature and a skipped one are counted apart from failures, as they don't
+// fail the run.
+func (p *Publisher) FeatureFinished(id, status string, optional bool) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	delete(p.running, id)
+	switch {
+	case status == "skipped":
+		p.status.Skipped++
+	case status == "failed" && optional:
+		p.status.OptionalFailed++
+	case status == "failed":
+		p.status.Failed++
+	default:
+		p.status.Completed++
+	}
+	p.mu.Unlock()
+	p.publish()
+}
+
+// Close stops the heartbeat and marks the run done
+func (p *Publisher) Close() {
+	if p == nil {
+		return
+	}
+	close(p.stop)
+	<-p.done
+	p.mu.Lock()
+	p.status.Done = true
+	p.running = make(map[string]Feature)
+	p.mu.Unlock()
+	p.publish()
+}
+
+func (p *Publisher) heartbeat() {
+	defer close(p.done)
+	ticker := time.NewTicker(HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.publish()
+		}
+	}
+}
+
+// publish writes the current status. Failures after Start are ignored:
+// readers treat a status that stops updating as a run that went away.
+func (p *Publisher) publish() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.status.UpdatedAt = time.Now()
+	p.status.Running = make([]Feature, 0, len(p.running))
+	for _, f := range p.running {
+		p.status.Running = append(p.status.Running, f)
+	}
+	sort.Slice(p.status.Running, func(i, j int) bool {
+		return p.status.Running[i].ID < p.status.Running[j].ID
+	})
+	return Write(p.workDir, &p.status)
+}
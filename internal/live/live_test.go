@@ -0,0 +1,127 @@
+package live
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadRunningStatus(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	written := &Status{
+		PID:       4242,
+		PRDDir:    "PRD",
+		StartedAt: now.Add(-time.Minute),
+		UpdatedAt: now,
+		Running:   []Feature{{ID: "02", Title: "Auth", StartedAt: now.Add(-10 * time.Second)}},
+		Completed: 1,
+	}
+	if err := Write(dir, written); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	s, err := Read(dir)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if s.PID != 4242 || len(s.Running) != 1 || s.Running[0].ID != "02" {
+		t.Errorf("unexpected status: %+v", s)
+	}
+	if !s.Active(now) {
+		t.Error("expected a fresh status to be active")
+	}
+	if got := s.Describe(now); got != "Following run (pid 4242): 1 running, 1 completed, 0 failed" {
+		t.Errorf("unexpected description %q", got)
+	}
+}
+
+func TestReadStaleStatus(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	if err := Write(dir, &Status{PID: 7, UpdatedAt: now.Add(-StaleAfter - time.Second)}); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	s, err := Read(dir)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if s.Active(now) {
+		t.Error("expected a status without a recent heartbeat to be inactive")
+	}
+	if got := s.Describe(now); !strings.Contains(got, "stopped updating") {
+		t.Errorf("expected a stale description, got %q", got)
+	}
+}
+
+func TestReadMissingStatus(t *testing.T) {
+	_, err := Read(t.TempDir())
+	if !os.IsNotExist(err) {
+		t.Errorf("expected a not-exist error, got %v", err)
+	}
+}
+
+func TestPublisherLifecycle(t *testing.T) {
+	dir := t.TempDir()
+	p, err := Start(dir, "PRD")
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	p.FeatureStarted("01", "Schema")
+	p.FeatureStarted("02", "Auth")
+	s, err := Read(dir)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if s.PID != os.Getpid() || len(s.Running) != 2 || s.Running[0].ID != "01" {
+		t.Errorf("unexpected status while running: %+v", s)
+	}
+
+	p.FeatureFinished("01", "completed", false)
+	p.FeatureFinished("02", "failed", false)
+	p.Close()
+
+	s, err = Read(dir)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if !s.Done || len(s.Running) != 0 || s.Completed != 1 || s.Failed != 1 {
+		t.Errorf("unexpected final status: %+v", s)
+	}
+	if s.Active(time.Now()) {
+		t.Error("expected a finished run to be inactive")
+	}
+}
+
+func TestPublisherCountsSkippedAndOptionalApart(t *testing.T) {
+	dir := t.TempDir()
+	p, err := Start(dir, "PRD")
+	if err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	p.FeatureFinished("01", "completed", false)
+	p.FeatureFinished("02", "failed", true)
+	p.FeatureFinished("03", "skipped", false)
+	p.Close()
+
+	s, err := Read(dir)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if s.Completed != 1 || s.Failed != 0 || s.OptionalFailed != 1 || s.Skipped != 1 {
+		t.Errorf("expected the optional failure and the skip counted apart from failures, got %+v", s)
+	}
+	if got := s.Describe(time.Now()); got != "Run finished: 1 completed, 0 failed, 1 optional failed, 1 skipped" {
+		t.Errorf("unexpected description: %q", got)
+	}
+}
+
+func TestNilPublisher(t *testing.T) {
+	var p *Publisher
+	p.FeatureStarted("01", "Schema")
+	p.FeatureFinished("01", "completed", false)
+	p.Close()
+}
@@ -3,6 +3,7 @@ package manifest
 import (
 	"fmt"
 	"sort"
+	"strings"
 )
 
 type DependencyGraph struct {
@@ -146,12 +147,22 @@ func (m *Manifest) ValidateDependencies() (warnings []string, err error) {
 }
 
 func (m *Manifest) GetNextRunnableFeature() *ManifestFeature {
+	return m.GetNextRunnableFeatureInGroup("")
+}
+
+// GetNextRunnableFeatureInGroup returns the first runnable feature of the
+// named group, matched case-insensitively, or of any group if group is empty.
+// Spawned sub-features are never returned; only their parent can start them.
+func (m *Manifest) GetNextRunnableFeatureInGroup(group string) *ManifestFeature {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
 	for i := range m.Features {
 		feature := &m.Features[i]
-		if feature.Status != "pending" {
+		if feature.Status != "pending" || feature.Disabled || !feature.IsRootFeature() {
+			continue
+		}
+		if group != "" && !strings.EqualFold(feature.Group, group) {
 			continue
 		}
 		if m.isDependencySatisfiedUnlocked(feature.ID) {
@@ -167,7 +178,7 @@ func (m *Manifest) GetAllRunnableFeatures() []ManifestFeature {
 
 	var runnable []ManifestFeature
 	for _, feature := range m.Features {
-		if feature.Status != "pending" {
+		if feature.Status != "pending" || feature.Disabled || !feature.IsRootFeature() {
 			continue
 		}
 		if m.isDependencySatisfiedUnlocked(feature.ID) {
@@ -183,7 +194,7 @@ func (m *Manifest) GetBlockedFeatures() []ManifestFeature {
 
 	var blocked []ManifestFeature
 	for _, feature := range m.Features {
-		if feature.Status != "pending" {
+		if feature.Status != "pending" || feature.Disabled || !feature.IsRootFeature() {
 			continue
 		}
 		if !m.isDependencySatisfiedUnlocked(feature.ID) {
@@ -287,3 +298,48 @@ func (m *Manifest) RemoveMissingDependencies() []string {
 
 	return removed
 }
+
+// DependencyLink is one feature in another feature's upstream dependency
+// chain
+type DependencyLink struct {
+	Feature   ManifestFeature // Only ID is set when Missing
+	Depth     int             // 1 for a direct dependency, 2 for its dependencies, ...
+	Missing   bool            // The dependency isn't in the manifest
+	Satisfied bool            // No longer holds up its dependents
+}
+
+// GetDependencyChain returns everything featureID depends on, directly or
+// transitively, depth-first in DependsOn order so each dependency is followed
+// by its own. A feature reached along several paths is listed once, at its
+// first occurrence, which also keeps cycles from recursing forever.
+func (m *Manifest) GetDependencyChain(featureID string) []DependencyLink {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	feature := m.getFeatureUnlocked(featureID)
+	if feature == nil {
+		return nil
+	}
+
+	var chain []DependencyLink
+	visited := map[string]bool{featureID: true}
+	var walk func(deps []string, depth int)
+	walk = func(deps []string, depth int) {
+		for _, depID := range deps {
+			if visited[depID] {
+				continue
+			}
+			visited[depID] = true
+
+			dep := m.getFeatureUnlocked(depID)
+			if dep == nil {
+				chain = append(chain, DependencyLink{Feature: ManifestFeature{ID: depID}, Depth: depth, Missing: true})
+				continue
+			}
+			chain = append(chain, DependencyLink{Feature: *dep, Depth: depth, Satisfied: satisfiesDependents(dep)})
+			walk(dep.DependsOn, depth+1)
+		}
+	}
+	walk(feature.DependsOn, 1)
+	return chain
+}
@@ -173,6 +173,25 @@ func TestManifest_GetNextRunnableFeature_DepsCompleted(t *testing.T) {
 	}
 }
 
+func TestManifest_GetNextRunnableFeature_DepsCompletedWithWarnings(t *testing.T) {
+	m := &Manifest{
+		Features: []ManifestFeature{
+			{ID: "01", Title: "Feature 1", Status: StatusCompletedWithWarnings, DependsOn: []string{}},
+			{ID: "02", Title: "Feature 2", Status: "pending", DependsOn: []string{"01"}},
+		},
+	}
+
+	next := m.GetNextRunnableFeature()
+	if next == nil || next.ID != "02" {
+		t.Fatalf("expected feature 02 to be runnable after a completion with warnings, got %v", next)
+	}
+
+	_, completed, _, _, _, _ := m.GetSummary()
+	if completed != 1 {
+		t.Errorf("expected completion with warnings to count as completed, got %d", completed)
+	}
+}
+
 func TestManifest_GetNextRunnableFeature_DepsNotCompleted(t *testing.T) {
 	m := &Manifest{
 		Features: []ManifestFeature{
@@ -366,3 +385,123 @@ func indexOf(slice []string, item string) int {
 	}
 	return -1
 }
+
+func TestManifest_OptionalFailureSatisfiesDependents(t *testing.T) {
+	m := &Manifest{
+		Features: []ManifestFeature{
+			{ID: "01", Title: "Nice to have", Status: "failed", Optional: true},
+			{ID: "02", Title: "Required", Status: "failed"},
+			{ID: "03", Title: "After optional", Status: "pending", DependsOn: []string{"01"}},
+			{ID: "04", Title: "After required", Status: "pending", DependsOn: []string{"02"}},
+		},
+	}
+
+	next := m.GetNextRunnableFeature()
+	if next == nil || next.ID != "03" {
+		t.Fatalf("expected feature 03 runnable after an optional failure, got %v", next)
+	}
+	if pending := m.GetPendingDependencies("03"); len(pending) != 0 {
+		t.Errorf("expected no pending dependencies for 03, got %v", pending)
+	}
+
+	blocked := m.GetBlockedFeatures()
+	if len(blocked) != 1 || blocked[0].ID != "04" {
+		t.Errorf("expected only 04 blocked by a required failure, got %v", blocked)
+	}
+}
+
+func TestManifest_OptionalPendingStillBlocks(t *testing.T) {
+	m := &Manifest{
+		Features: []ManifestFeature{
+			{ID: "01", Title: "Nice to have", Status: "running", Optional: true},
+			{ID: "02", Title: "After optional", Status: "pending", DependsOn: []string{"01"}},
+		},
+	}
+
+	if next := m.GetNextRunnableFeature(); next != nil {
+		t.Errorf("expected dependents to wait until the optional feature finishes, got %s", next.ID)
+	}
+}
+
+func TestManifest_DisabledFeatureTreatedAsAbsent(t *testing.T) {
+	m := &Manifest{
+		Features: []ManifestFeature{
+			{ID: "01", Title: "Parked", Status: "pending", Disabled: true},
+			{ID: "02", Title: "After parked", Status: "pending", DependsOn: []string{"01"}},
+			{ID: "03", Title: "Done", Status: "completed"},
+		},
+	}
+
+	next := m.GetNextRunnableFeature()
+	if next == nil || next.ID != "02" {
+		t.Fatalf("expected 02 to run first, got %v", next)
+	}
+	if blocked := m.GetBlockedFeatures(); len(blocked) != 0 {
+		t.Errorf("expected no blocked features, got %d", len(blocked))
+	}
+
+	total, completed, _, _, pending, _ := m.GetSummary()
+	if total != 2 || completed != 1 || pending != 1 {
+		t.Errorf("expected the disabled feature left out of the summary, got total=%d completed=%d pending=%d",
+			total, completed, pending)
+	}
+}
+
+func TestManifest_GetDependencyChain(t *testing.T) {
+	m := &Manifest{
+		Features: []ManifestFeature{
+			{ID: "01", Title: "Schema", Status: "completed"},
+			{ID: "02", Title: "Models", Status: "completed", DependsOn: []string{"01"}},
+			{ID: "03", Title: "Auth", Status: "failed", DependsOn: []string{"01"}},
+			{ID: "04", Title: "API", Status: "pending", DependsOn: []string{"02", "03"}},
+			{ID: "05", Title: "UI", Status: "pending", DependsOn: []string{"04", "99"}},
+		},
+	}
+
+	chain := m.GetDependencyChain("05")
+
+	want := []struct {
+		id      string
+		depth   int
+		missing bool
+	}{
+		{"04", 1, false},
+		{"02", 2, false},
+		{"01", 3, false},
+		{"03", 2, false},
+		{"99", 1, true},
+	}
+	if len(chain) != len(want) {
+		t.Fatalf("expected %d links, got %d: %+v", len(want), len(chain), chain)
+	}
+	for i, w := range want {
+		link := chain[i]
+		if link.Feature.ID != w.id || link.Depth != w.depth || link.Missing != w.missing {
+			t.Errorf("link %d: expected %s at depth %d (missing %v), got %s at depth %d (missing %v)",
+				i, w.id, w.depth, w.missing, link.Feature.ID, link.Depth, link.Missing)
+		}
+	}
+	if chain[3].Feature.Status != "failed" || chain[3].Satisfied {
+		t.Errorf("expected 03 to be failed and unsatisfied, got %+v", chain[3])
+	}
+	if !chain[1].Satisfied {
+		t.Error("expected completed 02 to be satisfied")
+	}
+}
+
+func TestManifest_GetDependencyChain_Cycle(t *testing.T) {
+	m := &Manifest{
+		Features: []ManifestFeature{
+			{ID: "01", DependsOn: []string{"02"}},
+			{ID: "02", DependsOn: []string{"01"}},
+		},
+	}
+
+	chain := m.GetDependencyChain("01")
+	if len(chain) != 1 || chain[0].Feature.ID != "02" {
+		t.Errorf("expected only 02 in the chain, got %+v", chain)
+	}
+	if got := m.GetDependencyChain("missing"); got != nil {
+		t.Errorf("expected nil for an unknown feature, got %+v", got)
+	}
+}
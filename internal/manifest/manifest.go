@@ -17,6 +17,32 @@ import (
 
 const DefaultMaxDepth = 3
 
+// saveRetryDelay is how long Save waits before retrying a failed write
+const saveRetryDelay = 100 * time.Millisecond
+
+// StatusCompletedWithWarnings is the status of a feature that completed but
+// reported many tool errors or skipped tests. It satisfies dependencies and
+// counts as completed in summaries.
+const StatusCompletedWithWarnings = "completed_with_warnings"
+
+// StatusSkipped is the status of a feature that failed all its retries with
+// On-Failure: skip. It doesn't satisfy dependencies and counts as failed in
+// summaries.
+const StatusSkipped = "skipped"
+
+// IsCompleted reports whether status is a successful completion, with or
+// without warnings
+func IsCompleted(status string) bool {
+	return status == "completed" || status == StatusCompletedWithWarnings
+}
+
+// satisfiesDependents reports whether f no longer holds up the features that
+// depend on it: it completed, it is optional and failed, or it is disabled and
+// so treated as absent
+func satisfiesDependents(f *ManifestFeature) bool {
+	return f.Disabled || IsCompleted(f.Status) || (f.Optional && f.Status == "failed")
+}
+
 type EscalationConfig struct {
 	Enabled            bool     `json:"enabled"`
 	ErrorThreshold     int      `json:"error_threshold,omitempty"`
@@ -34,8 +60,16 @@ type Manifest struct {
 	Features     []ManifestFeature `json:"features"`
 	BudgetTokens int64             `json:"budget_tokens,omitempty"`
 	BudgetUSD    float64           `json:"budget_usd,omitempty"`
-	MaxDepth     int               `json:"max_depth,omitempty"`  // Max recursion depth (default: 3)
-	Escalation   *EscalationConfig `json:"escalation,omitempty"` // Model escalation configuration
+	ClaudeArgs   []string          `json:"claude_args,omitempty"` // Extra flags passed to every claude instance
+	PostRun      string            `json:"post_run,omitempty"`    // Shell command run after 'ralph run' finishes
+	Concurrent   int               `json:"concurrent,omitempty"`  // Max features running at once (0 = use config)
+	Retries      int               `json:"retries,omitempty"`     // Max retries per feature (0 = use config)
+	Warnings     int               `json:"warnings,omitempty"`    // Tool errors plus skipped tests that mark a warning (0 = default)
+	MaxDepth     int               `json:"max_depth,omitempty"`   // Max recursion depth (default: 3)
+	Escalation   *EscalationConfig `json:"escalation,omitempty"`  // Model escalation configuration
+
+	// schemaWarnings are what Load found that didn't stop it loading
+	schemaWarnings []string
 }
 
 type ManifestFeature struct {
@@ -49,6 +83,29 @@ type ManifestFeature struct {
 	Usage        *usage.TokenUsage `json:"usage,omitempty"`
 	BudgetTokens int64             `json:"budget_tokens,omitempty"`
 	BudgetUSD    float64           `json:"budget_usd,omitempty"`
+	Base         string            `json:"base,omitempty"`  // Git commit or tag the feature starts from
+	Group        string            `json:"group,omitempty"` // Epic or group from the PRD
+	// Optional features may fail without blocking dependents or the run
+	Optional bool `json:"optional,omitempty"`
+	// OnFailure is "skip", "abort" or "continue" (default): what happens once
+	// the feature has failed all its retries
+	OnFailure string `json:"on_failure,omitempty"`
+	// Disabled features are never run and don't count towards the PRD
+	Disabled bool `json:"disabled,omitempty"`
+	// Notes are free-form reviewer notes or links, kept across runs
+	Notes string `json:"notes,omitempty"`
+	// Files are the paths, directories or globs the feature touches, used by
+	// 'ralph run --since-commit' to select features
+	Files []string `json:"files,omitempty"`
+	// MaxChildrenConcurrent caps how many spawned children run at once, in
+	// place of the global concurrency limit (0 = no own limit)
+	MaxChildrenConcurrent int `json:"max_children_concurrent,omitempty"`
+	// Alias is a short name from the PRD's Id: or Alias: that Depends: can
+	// use in place of the feature's number or title
+	Alias string `json:"alias,omitempty"`
+	// Timeout is how long 'ralph run' lets the feature run, such as "30m",
+	// from its own or the PRD's Timeout:
+	Timeout string `json:"timeout,omitempty"`
 
 	// Recursive feature fields (RLM support)
 	ParentID      string   `json:"parent_id,omitempty"`      // Empty for root features
@@ -76,15 +133,30 @@ func Load(prdDir string) (*Manifest, error) {
 		return nil, fmt.Errorf("failed to read manifest: %w", err)
 	}
 
+	// Syntax errors are left to Unmarshal, which reports their position
+	var warnings []string
+	if json.Valid(data) {
+		if warnings, err = validateSchema(data); err != nil {
+			return nil, fmt.Errorf("invalid manifest %s: %w", manifestPath, err)
+		}
+	}
+
 	var m Manifest
 	if err := json.Unmarshal(data, &m); err != nil {
 		return nil, fmt.Errorf("failed to parse manifest: %w", err)
 	}
 
 	m.path = manifestPath
+	m.schemaWarnings = warnings
 	return &m, nil
 }
 
+// SchemaWarnings returns what Load found in manifest.json that didn't stop
+// it loading, such as unknown fields
+func (m *Manifest) SchemaWarnings() []string {
+	return m.schemaWarnings
+}
+
 func (m *Manifest) Save() error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -100,8 +172,12 @@ func (m *Manifest) Save() error {
 		return fmt.Errorf("failed to marshal manifest: %w", err)
 	}
 
+	// Retry once so a transient failure doesn't lose progress
 	if err := os.WriteFile(m.path, data, 0644); err != nil {
-		return fmt.Errorf("failed to write manifest: %w", err)
+		time.Sleep(saveRetryDelay)
+		if err := os.WriteFile(m.path, data, 0644); err != nil {
+			return fmt.Errorf("failed to write manifest: %w", err)
+		}
 	}
 
 	return nil
@@ -175,6 +251,21 @@ func (m *Manifest) GetFeatureByTitle(title string) *ManifestFeature {
 	return nil
 }
 
+// GetFeatureByAlias returns the feature with the given alias, matched
+// case-insensitively
+func (m *Manifest) GetFeatureByAlias(alias string) *ManifestFeature {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	alias = strings.TrimSpace(alias)
+	for i := range m.Features {
+		if m.Features[i].Alias != "" && strings.EqualFold(m.Features[i].Alias, alias) {
+			return &m.Features[i]
+		}
+	}
+	return nil
+}
+
 func (m *Manifest) AllFeatures() []ManifestFeature {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -187,6 +278,11 @@ func GenerateFromPRD(prd *parser.PRD, sourcePath string) (*Manifest, error) {
 	manifest := New(filepath.Base(sourcePath), prd.Title)
 	manifest.BudgetTokens = prd.BudgetTokens
 	manifest.BudgetUSD = prd.BudgetUSD
+	manifest.ClaudeArgs = prd.ClaudeArgs
+	manifest.PostRun = prd.PostRun
+	manifest.Concurrent = prd.MaxConcurrent
+	manifest.Retries = prd.MaxRetries
+	manifest.Warnings = prd.WarningThreshold
 
 	for i, feature := range prd.Features {
 		id := fmt.Sprintf("%02d", i+1)
@@ -204,6 +300,18 @@ func GenerateFromPRD(prd *parser.PRD, sourcePath string) (*Manifest, error) {
 			Model:        feature.Model,
 			BudgetTokens: feature.BudgetTokens,
 			BudgetUSD:    feature.BudgetUSD,
+			Base:         feature.Base,
+			Group:        feature.Group,
+			Optional:     feature.Optional,
+			OnFailure:    feature.OnFailure,
+			Disabled:     feature.Disabled,
+			Files:        feature.Files,
+
+			MaxChildrenConcurrent: feature.MaxChildrenConcurrent,
+			Alias:                 feature.Alias,
+		}
+		if feature.Timeout > 0 {
+			mf.Timeout = feature.Timeout.String()
 		}
 		manifest.Features = append(manifest.Features, mf)
 	}
@@ -211,6 +319,73 @@ func GenerateFromPRD(prd *parser.PRD, sourcePath string) (*Manifest, error) {
 	return manifest, nil
 }
 
+// unresolvedInFile marks a numeric dependency with no feature of that number
+// in its own file, so no other file's feature can match it
+const unresolvedInFile = " (not in the same file)"
+
+// GenerateFromPRDs builds one manifest from PRDs split across several files,
+// numbering their features in order. Dependencies are first resolved within
+// each file, so a numeric Depends: refers to the file's own features, and
+// one with no such feature is marked unresolved rather than matched against
+// another file; the rest are kept as written for ResolveDependencies to
+// match by title across files. PRD-wide settings come from the first file
+// that sets them.
+func GenerateFromPRDs(prds []*parser.PRD, sourcePath string) (*Manifest, error) {
+	manifest := New(filepath.Base(sourcePath), "")
+
+	for _, prd := range prds {
+		part, err := GenerateFromPRD(prd, sourcePath)
+		if err != nil {
+			return nil, err
+		}
+		part.ResolveDependencies()
+
+		if manifest.Title == "" {
+			manifest.Title = part.Title
+		}
+		if manifest.BudgetTokens == 0 {
+			manifest.BudgetTokens = part.BudgetTokens
+		}
+		if manifest.BudgetUSD == 0 {
+			manifest.BudgetUSD = part.BudgetUSD
+		}
+		if len(manifest.ClaudeArgs) == 0 {
+			manifest.ClaudeArgs = part.ClaudeArgs
+		}
+		if manifest.PostRun == "" {
+			manifest.PostRun = part.PostRun
+		}
+		if manifest.Concurrent == 0 {
+			manifest.Concurrent = part.Concurrent
+		}
+		if manifest.Retries == 0 {
+			manifest.Retries = part.Retries
+		}
+		if manifest.Warnings == 0 {
+			manifest.Warnings = part.Warnings
+		}
+
+		renumbered := make(map[string]string, len(part.Features))
+		for i, f := range part.Features {
+			renumbered[f.ID] = fmt.Sprintf("%02d", len(manifest.Features)+i+1)
+		}
+		for _, f := range part.Features {
+			f.ID = renumbered[f.ID]
+			f.Dir = fmt.Sprintf("%s-%s", f.ID, sanitizeDirName(f.Title))
+			for i, dep := range f.DependsOn {
+				if id, ok := renumbered[dep]; ok {
+					f.DependsOn[i] = id
+				} else if _, err := strconv.Atoi(dep); err == nil {
+					f.DependsOn[i] = dep + unresolvedInFile
+				}
+			}
+			manifest.Features = append(manifest.Features, f)
+		}
+	}
+
+	return manifest, nil
+}
+
 func ParseDependencies(rawContent, description string) []string {
 	deps := []string{}
 
@@ -249,6 +424,10 @@ func (m *Manifest) ResolveDependencyID(dep string) string {
 		}
 	}
 
+	if f := m.GetFeatureByAlias(dep); f != nil {
+		return f.ID
+	}
+
 	if f := m.GetFeatureByTitle(dep); f != nil {
 		return f.ID
 	}
@@ -287,6 +466,12 @@ func (m *Manifest) resolveDepIDUnlocked(dep string) string {
 		}
 	}
 
+	for _, f := range m.Features {
+		if f.Alias != "" && strings.EqualFold(f.Alias, dep) {
+			return f.ID
+		}
+	}
+
 	normalizedDep := strings.ToLower(dep)
 	for _, f := range m.Features {
 		if strings.ToLower(f.Title) == normalizedDep {
@@ -353,7 +538,7 @@ func (m *Manifest) IsDependencySatisfied(featureID string) bool {
 
 	for _, depID := range feature.DependsOn {
 		depFeature := m.getFeatureUnlocked(depID)
-		if depFeature == nil || depFeature.Status != "completed" {
+		if depFeature == nil || !satisfiesDependents(depFeature) {
 			return false
 		}
 	}
@@ -389,7 +574,7 @@ func (m *Manifest) GetPendingDependencies(featureID string) []string {
 	pending := []string{}
 	for _, depID := range feature.DependsOn {
 		depFeature := m.getFeatureUnlocked(depID)
-		if depFeature == nil || depFeature.Status != "completed" {
+		if depFeature == nil || !satisfiesDependents(depFeature) {
 			pending = append(pending, depID)
 		}
 	}
@@ -397,28 +582,127 @@ func (m *Manifest) GetPendingDependencies(featureID string) []string {
 	return pending
 }
 
+// GetSummary counts features by status. Skipped features and failed
+// optional features count as failed; see GetDetailedSummary to tell them
+// apart.
 func (m *Manifest) GetSummary() (total, completed, running, failed, pending, blocked int) {
+	s := m.GetDetailedSummary()
+	return s.Total, s.Completed, s.Running, s.Failed + s.Skipped + s.OptionalFailed, s.Pending, s.Blocked
+}
+
+// Summary counts the root features that aren't disabled by status. Each
+// feature is counted once, so the counts add up to Total. Spawned
+// sub-features are left out: they run as part of their parent.
+type Summary struct {
+	Total     int
+	Completed int
+	Running   int
+	Failed    int // Failed features that aren't optional
+	Pending   int
+	Blocked   int
+	Skipped   int
+	// OptionalFailed are optional features that failed without blocking
+	// their dependents or the run
+	OptionalFailed int
+}
+
+// GetDetailedSummary counts features by status, with skipped features and
+// failed optional features counted apart from other failures
+func (m *Manifest) GetDetailedSummary() Summary {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	total = len(m.Features)
+	var s Summary
 	for _, feature := range m.Features {
+		if feature.Disabled || !feature.IsRootFeature() {
+			continue
+		}
+		s.Total++
 		switch feature.Status {
-		case "completed":
-			completed++
+		case "completed", StatusCompletedWithWarnings:
+			s.Completed++
 		case "running":
-			running++
+			s.Running++
 		case "failed":
-			failed++
+			if feature.Optional {
+				s.OptionalFailed++
+			} else {
+				s.Failed++
+			}
+		case StatusSkipped:
+			s.Skipped++
+		default:
+			if m.isDependencySatisfiedUnlocked(feature.ID) {
+				s.Pending++
+			} else {
+				s.Blocked++
+			}
+		}
+	}
+	return s
+}
+
+// GroupSummary rolls up the statuses of the features in one group
+type GroupSummary struct {
+	Name      string
+	Total     int
+	Completed int
+	Running   int
+	Failed    int
+	Pending   int
+	Blocked   int
+}
+
+// GroupSummaries returns a status rollup for each feature group, in the order
+// the groups first appear. Features without a group are not included.
+func (m *Manifest) GroupSummaries() []GroupSummary {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var summaries []GroupSummary
+	index := make(map[string]int)
+	for _, feature := range m.Features {
+		if feature.Group == "" || feature.Disabled {
+			continue
+		}
+		i, ok := index[feature.Group]
+		if !ok {
+			i = len(summaries)
+			index[feature.Group] = i
+			summaries = append(summaries, GroupSummary{Name: feature.Group})
+		}
+
+		s := &summaries[i]
+		s.Total++
+		switch feature.Status {
+		case "completed", StatusCompletedWithWarnings:
+			s.Completed++
+		case "running":
+			s.Running++
+		case "failed", StatusSkipped:
+			s.Failed++
 		default:
 			if m.isDependencySatisfiedUnlocked(feature.ID) {
-				pending++
+				s.Pending++
 			} else {
-				blocked++
+				s.Blocked++
 			}
 		}
 	}
-	return
+	return summaries
+}
+
+// HasGroup reports whether any feature belongs to the named group, matched
+// case-insensitively
+func (m *Manifest) HasGroup(group string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, feature := range m.Features {
+		if feature.Group != "" && strings.EqualFold(feature.Group, group) {
+			return true
+		}
+	}
+	return false
 }
 
 func (m *Manifest) isDependencySatisfiedUnlocked(featureID string) bool {
@@ -440,7 +724,7 @@ func (m *Manifest) isDependencySatisfiedUnlocked(featureID string) bool {
 
 	for _, depID := range feature.DependsOn {
 		depFeature := m.getFeatureUnlocked(depID)
-		if depFeature == nil || depFeature.Status != "completed" {
+		if depFeature == nil || !satisfiesDependents(depFeature) {
 			return false
 		}
 	}
@@ -510,12 +794,26 @@ func (f *ManifestFeature) IsRootFeature() bool {
 	return f.ParentID == ""
 }
 
+// TimeoutDuration returns the feature's Timeout, or 0 if it has none or it
+// isn't a valid duration
+func (f *ManifestFeature) TimeoutDuration() time.Duration {
+	d, err := time.ParseDuration(f.Timeout)
+	if err != nil || d <= 0 {
+		return 0
+	}
+	return d
+}
+
 // HasChildren returns true if the feature has spawned sub-features
 func (f *ManifestFeature) HasChildren() bool {
 	return len(f.Children) > 0
 }
 
-// AddSubFeature adds a new sub-feature to the manifest and links it to its parent
+// AddSubFeature adds a new sub-feature to the manifest and links it to its
+// parent. A sub-feature without a group of its own inherits its parent's, so
+// --group runs and group rollups treat a subtree as one. Features have no
+// tags or priority to inherit: sub-features are scheduled in spawn order
+// under their parent's Max-Children-Concurrent.
 func (m *Manifest) AddSubFeature(parentID string, feature ManifestFeature) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -547,6 +845,9 @@ func (m *Manifest) AddSubFeature(parentID string, feature ManifestFeature) error
 	if feature.ContextBudget == 0 && parent.ContextBudget > 0 {
 		feature.ContextBudget = parent.ContextBudget / 2
 	}
+	if feature.Group == "" {
+		feature.Group = parent.Group
+	}
 
 	m.Features = append(m.Features, feature)
 	m.Features[parentIdx].Children = append(m.Features[parentIdx].Children, feature.ID)
@@ -749,3 +1050,61 @@ func (m *Manifest) UpdateFeatureModel(id, model string) error {
 	}
 	return fmt.Errorf("feature not found: %s", id)
 }
+
+// FeatureModels are the models a feature can be set to, in cycle order
+var FeatureModels = []string{"haiku", "sonnet", "opus", "auto"}
+
+// SetFeatureModel sets the model for a feature and saves the manifest
+func (m *Manifest) SetFeatureModel(id, model string) error {
+	valid := false
+	for _, candidate := range FeatureModels {
+		if model == candidate {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invalid model %q: must be one of haiku, sonnet, opus, auto", model)
+	}
+
+	if err := m.UpdateFeatureModel(id, model); err != nil {
+		return err
+	}
+	return m.Save()
+}
+
+// UpdateFeatureNotes replaces the notes attached to a feature
+func (m *Manifest) UpdateFeatureNotes(id, notes string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range m.Features {
+		if m.Features[i].ID == id {
+			m.Features[i].Notes = notes
+			m.Updated = time.Now()
+			return nil
+		}
+	}
+	return fmt.Errorf("feature not found: %s", id)
+}
+
+// SetFeatureNotes replaces the notes attached to a feature and saves the
+// manifest
+func (m *Manifest) SetFeatureNotes(id, notes string) error {
+	if err := m.UpdateFeatureNotes(id, notes); err != nil {
+		return err
+	}
+	return m.Save()
+}
+
+// GetFeatureNotes returns the notes attached to a feature, or "" if it has
+// none or doesn't exist
+func (m *Manifest) GetFeatureNotes(id string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if f := m.getFeatureUnlocked(id); f != nil {
+		return f.Notes
+	}
+	return ""
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/vx/ralph-go/internal/parser"
 )
@@ -81,6 +82,42 @@ func TestManifestSaveAndLoad(t *testing.T) {
 	}
 }
 
+func TestFeatureNotesRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	m := New("test.md", "Test Project")
+	m.SetPath(filepath.Join(tmpDir, "manifest.json"))
+	m.Features = []ManifestFeature{
+		{ID: "01", Dir: "01-feature-one", Title: "Feature One", Status: "pending"},
+		{ID: "02", Dir: "02-feature-two", Title: "Feature Two", Status: "pending"},
+	}
+
+	notes := "Reviewed by Sam\nSee https://example.com/pr/42"
+	if err := m.SetFeatureNotes("01", notes); err != nil {
+		t.Fatalf("failed to set notes: %v", err)
+	}
+	if err := m.SetFeatureNotes("99", "missing"); err == nil {
+		t.Error("expected an error for an unknown feature")
+	}
+
+	loaded, err := Load(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to load manifest: %v", err)
+	}
+	if got := loaded.GetFeatureNotes("01"); got != notes {
+		t.Errorf("expected notes %q, got %q", notes, got)
+	}
+	if got := loaded.GetFeatureNotes("02"); got != "" {
+		t.Errorf("expected no notes on feature 02, got %q", got)
+	}
+
+	// Features without notes leave the field out of the file
+	data, _ := os.ReadFile(filepath.Join(tmpDir, "manifest.json"))
+	if strings.Count(string(data), `"notes"`) != 1 {
+		t.Errorf("expected notes only on the annotated feature, got:\n%s", data)
+	}
+}
+
 func TestUpdateFeatureStatus(t *testing.T) {
 	m := New("test.md", "Test Project")
 	m.Features = []ManifestFeature{
@@ -102,6 +139,37 @@ func TestUpdateFeatureStatus(t *testing.T) {
 	}
 }
 
+func TestSetFeatureModel(t *testing.T) {
+	tmpDir := t.TempDir()
+	m := New("test.md", "Test Project")
+	m.SetPath(filepath.Join(tmpDir, "manifest.json"))
+	m.Features = []ManifestFeature{
+		{ID: "01", Title: "Feature One", Status: "pending", Model: "sonnet"},
+	}
+
+	if err := m.SetFeatureModel("01", "opus"); err != nil {
+		t.Fatalf("failed to set model: %v", err)
+	}
+
+	loaded, err := Load(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to load manifest: %v", err)
+	}
+	if f := loaded.GetFeature("01"); f == nil || f.Model != "opus" {
+		t.Errorf("expected persisted model 'opus', got %+v", f)
+	}
+
+	if err := m.SetFeatureModel("01", "gpt"); err == nil {
+		t.Error("expected error for invalid model")
+	}
+	if m.Features[0].Model != "opus" {
+		t.Errorf("invalid model should not change the feature, got %q", m.Features[0].Model)
+	}
+	if err := m.SetFeatureModel("99", "haiku"); err == nil {
+		t.Error("expected error for non-existent feature")
+	}
+}
+
 func TestGetFeature(t *testing.T) {
 	m := New("test.md", "Test Project")
 	m.Features = []ManifestFeature{
@@ -243,6 +311,46 @@ func TestResolveDependencyID(t *testing.T) {
 	}
 }
 
+func TestResolveDependencyByAlias(t *testing.T) {
+	prd, err := parser.ParsePRDContent(`# Project
+
+## Dashboard
+
+Depends: auth, 3
+
+- [ ] Build dashboard
+
+## Authentication
+
+Id: auth
+
+- [ ] Add login
+
+## Billing
+
+- [ ] Add invoices
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := GenerateFromPRD(prd, "PRD.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := m.ResolveDependencyID("AUTH"); got != "02" {
+		t.Errorf("expected alias to resolve to '02', got %q", got)
+	}
+	if got := m.ResolveDependencyID("Billing"); got != "03" {
+		t.Errorf("expected title to resolve to '03', got %q", got)
+	}
+
+	m.ResolveDependencies()
+	if deps := m.Features[0].DependsOn; len(deps) != 2 || deps[0] != "02" || deps[1] != "03" {
+		t.Errorf("expected dependencies [02 03], got %v", deps)
+	}
+}
+
 func TestResolveDependencies(t *testing.T) {
 	m := New("test.md", "Test Project")
 	m.Features = []ManifestFeature{
@@ -458,6 +566,105 @@ func TestGetSummary(t *testing.T) {
 	}
 }
 
+func TestGetDetailedSummarySkippedAndOptional(t *testing.T) {
+	m := New("test.md", "Test Project")
+	m.Features = []ManifestFeature{
+		{ID: "01", Status: "completed"},
+		{ID: "02", Status: "failed"},
+		{ID: "03", Status: "failed", Optional: true},
+		{ID: "04", Status: StatusSkipped},
+		{ID: "05", Status: "pending", DependsOn: []string{"03"}},
+		{ID: "06", Status: "pending", DependsOn: []string{"02"}},
+		{ID: "07", Status: "pending", Disabled: true},
+	}
+
+	want := Summary{Total: 6, Completed: 1, Failed: 1, OptionalFailed: 1, Skipped: 1, Pending: 1, Blocked: 1}
+	if got := m.GetDetailedSummary(); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	// GetSummary still counts skipped and optional failures as failed
+	total, _, _, failed, _, _ := m.GetSummary()
+	if total != 6 || failed != 3 {
+		t.Errorf("expected 6 total and 3 failed, got %d and %d", total, failed)
+	}
+}
+
+func TestGroupSummaries(t *testing.T) {
+	m := New("test.md", "Test Project")
+	m.Features = []ManifestFeature{
+		{ID: "01", Status: "completed", Group: "Accounts"},
+		{ID: "02", Status: "failed", Group: "Accounts"},
+		{ID: "03", Status: "pending", Group: "Accounts", DependsOn: []string{"02"}},
+		{ID: "04", Status: "running", Group: "Billing"},
+		{ID: "05", Status: "pending", Group: "Billing"},
+		{ID: "06", Status: "pending"},
+	}
+
+	summaries := m.GroupSummaries()
+	want := []GroupSummary{
+		{Name: "Accounts", Total: 3, Completed: 1, Failed: 1, Blocked: 1},
+		{Name: "Billing", Total: 2, Running: 1, Pending: 1},
+	}
+	if len(summaries) != len(want) {
+		t.Fatalf("expected %d group summaries, got %+v", len(want), summaries)
+	}
+	for i := range want {
+		if summaries[i] != want[i] {
+			t.Errorf("group %d: expected %+v, got %+v", i, want[i], summaries[i])
+		}
+	}
+
+	if !m.HasGroup("billing") || m.HasGroup("Shipping") {
+		t.Error("expected HasGroup to match existing groups case-insensitively")
+	}
+	if next := m.GetNextRunnableFeatureInGroup("Billing"); next == nil || next.ID != "05" {
+		t.Errorf("expected 05 as next runnable Billing feature, got %+v", next)
+	}
+	if next := m.GetNextRunnableFeatureInGroup("Accounts"); next != nil {
+		t.Errorf("expected no runnable Accounts feature while 03 is blocked, got %s", next.ID)
+	}
+}
+
+func TestGenerateFromPRDKeepsGroup(t *testing.T) {
+	prd := &parser.PRD{
+		Title: "Test",
+		Features: []parser.Feature{
+			{Title: "Login", Group: "Accounts"},
+			{Title: "Setup"},
+		},
+	}
+
+	m, err := GenerateFromPRD(prd, "PRD.md")
+	if err != nil {
+		t.Fatalf("GenerateFromPRD failed: %v", err)
+	}
+	if m.Features[0].Group != "Accounts" || m.Features[1].Group != "" {
+		t.Errorf("expected groups to carry over, got %q and %q", m.Features[0].Group, m.Features[1].Group)
+	}
+}
+
+func TestGenerateFromPRDKeepsTimeout(t *testing.T) {
+	prd := &parser.PRD{
+		Title: "Test",
+		Features: []parser.Feature{
+			{Title: "Migration", Timeout: 90 * time.Minute},
+			{Title: "Setup"},
+		},
+	}
+
+	m, err := GenerateFromPRD(prd, "PRD.md")
+	if err != nil {
+		t.Fatalf("GenerateFromPRD failed: %v", err)
+	}
+	if got := m.Features[0].TimeoutDuration(); got != 90*time.Minute {
+		t.Errorf("expected timeout 1h30m, got %v (%q)", got, m.Features[0].Timeout)
+	}
+	if m.Features[1].Timeout != "" || m.Features[1].TimeoutDuration() != 0 {
+		t.Errorf("expected no timeout, got %q", m.Features[1].Timeout)
+	}
+}
+
 func TestManifestJSONStructure(t *testing.T) {
 	m := New("PRD-TUI-REVAMP.md", "Ralph TUI Revamp v0.3.0")
 	m.Features = []ManifestFeature{
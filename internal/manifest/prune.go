@@ -0,0 +1,75 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vx/ralph-go/internal/parser"
+)
+
+// RemovedFeatures returns the root features whose titles no longer appear in
+// any of prds, in manifest order. Sub-features spawned at runtime aren't in
+// the PRD and go with their parent.
+func (m *Manifest) RemovedFeatures(prds []*parser.PRD) []ManifestFeature {
+	titles := make(map[string]bool)
+	for _, prd := range prds {
+		for _, f := range prd.Features {
+			titles[strings.ToLower(strings.TrimSpace(f.Title))] = true
+		}
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var removed []ManifestFeature
+	for _, f := range m.Features {
+		if f.ParentID == "" && !titles[strings.ToLower(strings.TrimSpace(f.Title))] {
+			removed = append(removed, f)
+		}
+	}
+	return removed
+}
+
+// PruneFeatures deletes the features with the given IDs along with their
+// sub-features, and drops the dependencies other features had on them. It
+// returns the pruned features in manifest order and a warning for each dependency dropped.
+func (m *Manifest) PruneFeatures(ids []string) (pruned []ManifestFeature, warnings []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	prune := make(map[string]bool)
+	for _, id := range ids {
+		if prune[id] || m.getFeatureUnlocked(id) == nil {
+			continue
+		}
+		prune[id] = true
+		var descendants []ManifestFeature
+		m.collectDescendantsUnlocked(id, &descendants)
+		for _, d := range descendants {
+			prune[d.ID] = true
+		}
+	}
+
+	kept := make([]ManifestFeature, 0, len(m.Features))
+	for _, f := range m.Features {
+		if prune[f.ID] {
+			pruned = append(pruned, f)
+			continue
+		}
+		kept = append(kept, f)
+	}
+
+	for i := range kept {
+		var deps []string
+		for _, dep := range kept[i].DependsOn {
+			if prune[dep] {
+				warnings = append(warnings, fmt.Sprintf("feature %s no longer depends on removed feature %s", kept[i].ID, dep))
+				continue
+			}
+			deps = append(deps, dep)
+		}
+		kept[i].DependsOn = deps
+	}
+	m.Features = kept
+	return pruned, warnings
+}
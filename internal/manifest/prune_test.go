@@ -0,0 +1,39 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/vx/ralph-go/internal/parser"
+)
+
+func TestPruneFeaturesRemovedFromPRD(t *testing.T) {
+	m := New("PRD.md", "Shop")
+	m.Features = []ManifestFeature{
+		{ID: "01", Title: "Schema"},
+		{ID: "02", Title: "Auth"},
+		{ID: "03", Title: "Checkout", DependsOn: []string{"01", "02"}},
+	}
+	if err := m.AddSubFeature("02", ManifestFeature{ID: "02.1", Title: "Login form"}); err != nil {
+		t.Fatal(err)
+	}
+
+	prd := &parser.PRD{Features: []parser.Feature{{Title: "schema"}, {Title: "Checkout"}}}
+	removed := m.RemovedFeatures([]*parser.PRD{prd})
+	if len(removed) != 1 || removed[0].ID != "02" {
+		t.Fatalf("expected only 02 to be removed, got %+v", removed)
+	}
+
+	pruned, warnings := m.PruneFeatures([]string{"02"})
+	if len(pruned) != 2 || pruned[0].ID != "02" || pruned[1].ID != "02.1" {
+		t.Errorf("expected 02 and its sub-feature to be pruned, got %+v", pruned)
+	}
+	if len(m.Features) != 2 {
+		t.Fatalf("expected 2 features left, got %+v", m.Features)
+	}
+	if deps := m.GetFeature("03").DependsOn; len(deps) != 1 || deps[0] != "01" {
+		t.Errorf("expected 03 to depend on 01 only, got %v", deps)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("expected a warning for the dropped dependency, got %v", warnings)
+	}
+}
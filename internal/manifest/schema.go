@@ -0,0 +1,221 @@
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// kindNames describe the JSON kinds a manifest field may expect
+var kindNames = map[string]string{
+	"string":  "a string",
+	"number":  "a number",
+	"integer": "a whole number",
+	"boolean": "a boolean",
+	"array":   "an array",
+	"object":  "an object",
+}
+
+var (
+	featureType = reflect.TypeOf(ManifestFeature{})
+	timeType    = reflect.TypeOf(time.Time{})
+)
+
+// errUnknownField marks a field the manifest doesn't define. It is only a
+// warning, since it may be a typo but may as well be written by a newer ralph.
+var errUnknownField = errors.New("unknown field")
+
+// validateSchema checks manifest JSON against the fields of Manifest and
+// ManifestFeature, so a hand-edited manifest.json with a value of the wrong
+// type is reported by feature and field rather than as an opaque unmarshal
+// error. Every problem found is returned; unknown fields are returned as
+// warnings rather than failing the load.
+func validateSchema(data []byte) (warnings []string, err error) {
+	if jsonKind(data) != "object" {
+		return nil, errors.New("manifest must be a JSON object")
+	}
+	var problems []error
+	for _, problem := range checkObject(data, reflect.TypeOf(Manifest{}), "") {
+		if errors.Is(problem, errUnknownField) {
+			warnings = append(warnings, problem.Error())
+			continue
+		}
+		problems = append(problems, problem)
+	}
+	return warnings, errors.Join(problems...)
+}
+
+// checkObject checks each field of the JSON object raw against the struct
+// type t. prefix locates the object in error messages.
+func checkObject(raw json.RawMessage, t reflect.Type, prefix string) []error {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return []error{fmt.Errorf("%s%v", prefix, err)}
+	}
+	fields := jsonFields(t)
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []error
+	for _, name := range names {
+		fieldType, ok := fields[name]
+		if !ok {
+			problems = append(problems, fmt.Errorf("%s%w '%s'", prefix, errUnknownField, name))
+			continue
+		}
+		problems = append(problems, checkValue(obj[name], fieldType, name, prefix)...)
+	}
+	return problems
+}
+
+// checkValue checks the value of the named field against its Go type.
+// Features are checked field by field; other arrays only by element kind.
+func checkValue(raw json.RawMessage, t reflect.Type, name, prefix string) []error {
+	got := jsonKind(raw)
+	if got == "null" {
+		return nil
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	want := expectedKind(t)
+
+	switch want {
+	case "integer":
+		if got != "number" || strings.ContainsAny(string(raw), ".eE") {
+			return []error{fmt.Errorf("%s'%s' must be %s", prefix, name, kindNames[want])}
+		}
+		return nil
+	case "array":
+		if got != "array" {
+			return []error{fmt.Errorf("%s'%s' must be %s", prefix, name, kindNames[want])}
+		}
+		var elems []json.RawMessage
+		if err := json.Unmarshal(raw, &elems); err != nil {
+			return []error{fmt.Errorf("%s'%s': %v", prefix, name, err)}
+		}
+		if t.Elem() == featureType {
+			var problems []error
+			for i, elem := range elems {
+				problems = append(problems, checkFeature(elem, i)...)
+			}
+			return problems
+		}
+		elemKind := expectedKind(t.Elem())
+		for _, elem := range elems {
+			if !kindMatches(jsonKind(elem), elemKind, elem) {
+				return []error{fmt.Errorf("%s'%s' must be an array of %ss", prefix, name, elemKind)}
+			}
+		}
+		return nil
+	case "object":
+		if got != "object" {
+			return []error{fmt.Errorf("%s'%s' must be %s", prefix, name, kindNames[want])}
+		}
+		if t.Kind() == reflect.Struct {
+			return checkObject(raw, t, fmt.Sprintf("%s'%s': ", prefix, name))
+		}
+		return nil
+	}
+
+	if got != want {
+		return []error{fmt.Errorf("%s'%s' must be %s", prefix, name, kindNames[want])}
+	}
+	return nil
+}
+
+// checkFeature checks the i'th entry of features, naming it by its ID when
+// it has one
+func checkFeature(raw json.RawMessage, i int) []error {
+	prefix := fmt.Sprintf("feature #%d: ", i+1)
+	if jsonKind(raw) != "object" {
+		return []error{fmt.Errorf("%smust be an object", prefix)}
+	}
+	var header struct {
+		ID any `json:"id"`
+	}
+	if err := json.Unmarshal(raw, &header); err == nil {
+		if id, ok := header.ID.(string); ok && id != "" {
+			prefix = fmt.Sprintf("feature %s: ", id)
+		}
+	}
+	return checkObject(raw, featureType, prefix)
+}
+
+// kindMatches reports whether a JSON value of kind got satisfies want
+func kindMatches(got, want string, raw json.RawMessage) bool {
+	if want == "integer" {
+		return got == "number" && !strings.ContainsAny(string(raw), ".eE")
+	}
+	return got == want
+}
+
+// jsonFields maps the JSON names of t's exported fields to their types
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// expectedKind returns the JSON kind a value of Go type t decodes from
+func expectedKind(t reflect.Type) string {
+	if t == timeType {
+		return "string"
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	}
+	return "object"
+}
+
+// jsonKind returns the kind of a JSON value from its first character
+func jsonKind(raw json.RawMessage) string {
+	s := strings.TrimSpace(string(raw))
+	if s == "" {
+		return ""
+	}
+	switch s[0] {
+	case '"':
+		return "string"
+	case '[':
+		return "array"
+	case '{':
+		return "object"
+	case 't', 'f':
+		return "boolean"
+	case 'n':
+		return "null"
+	}
+	return "number"
+}
@@ -0,0 +1,126 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeManifest(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "manifest.json"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestLoadReportsSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     []string
+	}{
+		{
+			name:     "depends_on not an array",
+			manifest: `{"title": "T", "features": [{"id": "01", "title": "A"}, {"id": "03", "title": "B", "depends_on": "01"}]}`,
+			want:     []string{"feature 03: 'depends_on' must be an array"},
+		},
+		{
+			name:     "depends_on with numbers",
+			manifest: `{"features": [{"id": "02", "depends_on": [1]}]}`,
+			want:     []string{"feature 02: 'depends_on' must be an array of strings"},
+		},
+		{
+			name:     "wrong scalar types",
+			manifest: `{"concurrent": "3", "features": [{"id": "04", "optional": "yes", "budget_tokens": 1.5}]}`,
+			want: []string{
+				"'concurrent' must be a whole number",
+				"feature 04: 'optional' must be a boolean",
+				"feature 04: 'budget_tokens' must be a whole number",
+			},
+		},
+		{
+			name:     "nested object",
+			manifest: `{"escalation": {"enabled": 1}, "features": [{"id": "05", "usage": {"input_tokens": "many"}}]}`,
+			want: []string{
+				"'escalation': 'enabled' must be a boolean",
+				"feature 05: 'usage': 'input_tokens' must be a whole number",
+			},
+		},
+		{
+			name:     "feature without an id",
+			manifest: `{"features": [{"id": "01"}, {"title": 7}]}`,
+			want:     []string{"feature #2: 'title' must be a string"},
+		},
+		{
+			name:     "features not an array",
+			manifest: `{"features": {"id": "01"}}`,
+			want:     []string{"'features' must be an array"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeManifest(t, tt.manifest))
+			if err == nil {
+				t.Fatal("expected a schema error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected %q in error, got:\n%v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadWarnsOfUnknownFields(t *testing.T) {
+	m, err := Load(writeManifest(t, `{"added_later": true, "features": [{"id": "01", "dependson": ["02"], "optional": "yes"}]}`))
+	if err == nil || !strings.Contains(err.Error(), "feature 01: 'optional' must be a boolean") {
+		t.Fatalf("expected a type error alongside the unknown field, got %v", err)
+	}
+	if strings.Contains(err.Error(), "unknown field") {
+		t.Errorf("expected unknown fields not to be errors, got %v", err)
+	}
+
+	m, err = Load(writeManifest(t, `{"added_later": true, "features": [{"id": "01", "dependson": ["02"]}]}`))
+	if err != nil {
+		t.Fatalf("expected unknown fields not to fail the load, got %v", err)
+	}
+	want := []string{"unknown field 'added_later'", "feature 01: unknown field 'dependson'"}
+	if got := m.SchemaWarnings(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected warnings %q, got %q", want, got)
+	}
+}
+
+func TestLoadAcceptsSavedManifest(t *testing.T) {
+	dir := t.TempDir()
+	m := New("PRD.md", "Project")
+	m.Escalation = &EscalationConfig{Enabled: true, ErrorThreshold: 3}
+	m.Features = append(m.Features, ManifestFeature{
+		ID:        "01",
+		Dir:       "01-a",
+		Title:     "A",
+		Status:    "pending",
+		DependsOn: []string{},
+		Files:     []string{"cmd/"},
+		BudgetUSD: 2.5,
+	})
+	m.SetPath(filepath.Join(dir, "manifest.json"))
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(dir); err != nil {
+		t.Errorf("expected a saved manifest to pass validation, got %v", err)
+	}
+}
+
+func TestLoadLeavesSyntaxErrorsToUnmarshal(t *testing.T) {
+	_, err := Load(writeManifest(t, `{"features": [`))
+	if err == nil || !strings.Contains(err.Error(), "failed to parse manifest") {
+		t.Errorf("expected a parse error, got %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// includer inlines the files named by Include: lines, resolving relative
+// paths against the including file and rejecting include cycles
+type includer struct {
+	// dir is the directory relative paths in the PRD resolve against
+	dir string
+	// stack holds the absolute paths of the files being included, outermost
+	// first, starting with the PRD itself when it was read from disk
+	stack []string
+}
+
+func newIncluder(prdPath string) *includer {
+	if prdPath == "" {
+		return &includer{dir: "."}
+	}
+	inc := &includer{dir: filepath.Dir(prdPath)}
+	if abs, err := filepath.Abs(prdPath); err == nil {
+		inc.stack = []string{abs}
+	}
+	return inc
+}
+
+// expand returns the content of the included file, with its own Include:
+// lines expanded, ending in a newline
+func (inc *includer) expand(path string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(inc.dir, path)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve include %s: %w", path, err)
+	}
+
+	for i, seen := range inc.stack {
+		if seen == abs {
+			return "", fmt.Errorf("include cycle: %s", inc.cycle(i, abs))
+		}
+	}
+
+	content, err := os.ReadFile(abs)
+	if err != nil {
+		return "", fmt.Errorf("failed to read include: %w", err)
+	}
+
+	nested := &includer{dir: filepath.Dir(abs), stack: append(inc.stack[:len(inc.stack):len(inc.stack)], abs)}
+	var sb strings.Builder
+	for _, line := range strings.Split(strings.TrimSuffix(normalizeNewlines(string(content)), "\n"), "\n") {
+		if matches := includeRegex.FindStringSubmatch(line); matches != nil {
+			included, err := nested.expand(matches[1])
+			if err != nil {
+				return "", err
+			}
+			sb.WriteString(included)
+			continue
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+// cycle describes the include chain from stack[start] back to abs
+func (inc *includer) cycle(start int, abs string) string {
+	names := make([]string, 0, len(inc.stack)-start+1)
+	for _, path := range inc.stack[start:] {
+		names = append(names, filepath.Base(path))
+	}
+	names = append(names, filepath.Base(abs))
+	return strings.Join(names, " -> ")
+}
@@ -0,0 +1,93 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+func TestParsePRD_Include(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "shared", "standards.md"),
+		"Use gofmt.\nInclude: architecture.md\n")
+	writeFile(t, filepath.Join(dir, "shared", "architecture.md"),
+		"Handlers call services, never the database.\n")
+	prdPath := filepath.Join(dir, "PRD.md")
+	writeFile(t, prdPath, "# Project\n\n"+
+		"Intro.\n"+
+		"Include: shared/standards.md\n"+
+		"Outro.\n\n"+
+		"## Feature 1: Login\n\n- [ ] Add login\n")
+
+	prd, err := ParsePRD(prdPath)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "Intro.\nUse gofmt.\nHandlers call services, never the database.\nOutro."
+	if prd.Context != want {
+		t.Errorf("expected context %q, got %q", want, prd.Context)
+	}
+	if strings.Contains(prd.Context, "Include:") {
+		t.Errorf("Include: lines should be replaced, got %q", prd.Context)
+	}
+	if len(prd.Features) != 1 {
+		t.Errorf("expected 1 feature, got %d", len(prd.Features))
+	}
+}
+
+func TestParsePRD_IncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.md"), "A\nInclude: b.md\n")
+	writeFile(t, filepath.Join(dir, "b.md"), "B\nInclude: ./a.md\n")
+	prdPath := filepath.Join(dir, "PRD.md")
+	writeFile(t, prdPath, "# Project\n\nInclude: a.md\n")
+
+	_, err := ParsePRD(prdPath)
+	if err == nil {
+		t.Fatal("expected an include cycle error")
+	}
+	if !strings.Contains(err.Error(), "include cycle: a.md -> b.md -> a.md") {
+		t.Errorf("expected the cycle to be named, got %v", err)
+	}
+
+	writeFile(t, prdPath, "# Project\n\nInclude: PRD.md\n")
+	if _, err := ParsePRD(prdPath); err == nil || !strings.Contains(err.Error(), "include cycle: PRD.md -> PRD.md") {
+		t.Errorf("expected a PRD including itself to be a cycle, got %v", err)
+	}
+}
+
+func TestParsePRD_IncludeMissingFile(t *testing.T) {
+	dir := t.TempDir()
+	prdPath := filepath.Join(dir, "PRD.md")
+	writeFile(t, prdPath, "# Project\n\nInclude: missing.md\n")
+
+	if _, err := ParsePRD(prdPath); err == nil {
+		t.Error("expected an error for a missing include")
+	}
+}
+
+func TestIncludeWindowsLineEndings(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "shared.md"), "\ufeffUse Go 1.25.\r\nKeep it simple.\r\n")
+	writeFile(t, filepath.Join(dir, "PRD.md"), "# Project\n\nInclude: shared.md\n\n## Feature 1\n\n- [ ] Task\n")
+
+	prd, err := ParsePRD(filepath.Join(dir, "PRD.md"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "Use Go 1.25.\nKeep it simple."; prd.Context != want {
+		t.Errorf("expected context %q, got %q", want, prd.Context)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type PRD struct {
@@ -15,9 +16,22 @@ type PRD struct {
 	Context       string
 	Features      []Feature
 	RawContent    string
-	BudgetTokens  int64   // Global token budget limit (0 = no limit)
-	BudgetUSD     float64 // Global USD budget limit (0 = no limit)
-	ContextBudget int64   // Global context budget (0 = use default)
+	BudgetTokens  int64    // Global token budget limit (0 = no limit)
+	BudgetUSD     float64  // Global USD budget limit (0 = no limit)
+	ContextBudget int64    // Global context budget (0 = use default)
+	ClaudeArgs    []string // Extra flags passed to every claude instance
+	PostRun       string   // Shell command run after 'ralph run' finishes
+	MaxConcurrent int      // Max features running at once (0 = use config)
+	MaxRetries    int      // Max retries per feature (0 = use config)
+	// WarningThreshold is the tool errors plus skipped tests that mark a
+	// successful feature completed_with_warnings (0 = use default)
+	WarningThreshold int
+	// Timeout is the default time limit for features that don't set their
+	// own (0 = no limit)
+	Timeout time.Duration
+	// Model is the default model for features that don't set their own
+	// (empty = sonnet)
+	Model string
 }
 
 type Feature struct {
@@ -25,17 +39,40 @@ type Feature struct {
 	Title              string
 	Description        string
 	ExecutionMode      string // "sequential" or "parallel"
-	Model              string // "sonnet", "opus", "haiku"
+	Model              string // "sonnet", "opus", "haiku" or "auto", defaulting to the PRD's Model:
 	Tasks              []Task
 	AcceptanceCriteria []string
 	RawContent         string
 	DependsOn          []string
-	BudgetTokens       int64   // Token budget limit (0 = no limit)
-	BudgetUSD          float64 // USD budget limit (0 = no limit)
-	ContextBudget      int64   // Context budget for recursion (0 = use default)
-	IsolationLevel     string  // "strict" or "lenient" (default: lenient)
+	BudgetTokens       int64    // Token budget limit (0 = no limit)
+	BudgetUSD          float64  // USD budget limit (0 = no limit)
+	ContextBudget      int64    // Context budget for recursion (0 = use default)
+	IsolationLevel     string   // "strict" or "lenient" (default: lenient)
+	PromptSuffix       string   // Custom instructions appended after the standard instructions
+	Base               string   // Git commit or tag the feature starts from
+	Group              string   // Epic or group the feature is listed under, if any
+	Optional           bool     // Failure doesn't block dependents or fail the run
+	OnFailure          string   // "skip", "abort" or "continue" (default) once all retries fail
+	Disabled           bool     // Excluded from runs and from dependency checks
+	Files              []string // Paths, directories or globs the feature touches
+	// MaxChildrenConcurrent caps how many of the feature's spawned children run
+	// at once, in place of the global concurrency limit (0 = no own limit)
+	MaxChildrenConcurrent int
+	// Alias is a short, stable name from Id: or Alias: that Depends: can
+	// refer to in place of the feature's number or title
+	Alias string
+	// Timeout stops the feature once it has run this long, defaulting to
+	// the PRD's Timeout: (0 = no limit)
+	Timeout time.Duration
 }
 
+// Actions for On-Failure:, taken once a feature has failed all its retries
+const (
+	OnFailureContinue = "continue" // Leave it failed and carry on with the run
+	OnFailureSkip     = "skip"     // Mark it skipped and carry on with the run
+	OnFailureAbort    = "abort"    // Stop the run
+)
+
 type Task struct {
 	ID          string
 	Description string
@@ -45,14 +82,34 @@ type Task struct {
 var (
 	h1Regex         = regexp.MustCompile(`^#\s+(.+)$`)
 	h2Regex         = regexp.MustCompile(`^##\s+(.+)$`)
+	groupRegex      = regexp.MustCompile(`(?i)^#{1,2}\s+(?:epic|group):\s*(.+)$`)
 	taskRegex       = regexp.MustCompile(`^[-*]\s+\[([ xX])\]\s+(.+)$`)
 	metaRegex       = regexp.MustCompile(`(?i)^(execution|mode|model|run):\s*(.+)$`)
 	criteriaRegex   = regexp.MustCompile(`(?i)^(acceptance|criteria|test):\s*(.+)$`)
 	dependsRegex    = regexp.MustCompile(`(?i)^depends:\s*(.+)$`)
+	filesRegex      = regexp.MustCompile(`(?i)^files:\s*(.+)$`)
 	budgetRegex     = regexp.MustCompile(`(?i)^budget:\s*(.+)$`)
 	tokensRegex     = regexp.MustCompile(`(?i)^tokens:\s*(.+)$`)
 	contextRegex    = regexp.MustCompile(`(?i)^context:\s*(.+)$`)
 	isolationRegex  = regexp.MustCompile(`(?i)^isolation:\s*(.+)$`)
+	maxChildRegex   = regexp.MustCompile(`(?i)^max-children-concurrent:\s*(\d+)\s*$`)
+	aliasRegex      = regexp.MustCompile(`(?i)^(?:id|alias):\s*([\w.-]+)\s*$`)
+	suffixRegex     = regexp.MustCompile(`(?i)^prompt-suffix:\s*(.+)$`)
+	baseRegex       = regexp.MustCompile(`(?i)^base:\s*(\S+)\s*$`)
+	optionalRegex   = regexp.MustCompile(`(?i)^optional:\s*(true|yes|false|no)\s*$`)
+	disabledRegex   = regexp.MustCompile(`(?i)^disabled:\s*(true|yes|false|no)\s*$`)
+	onFailureRegex  = regexp.MustCompile(`(?i)^on-failure:\s*(skip|abort|continue)\s*$`)
+	strikeRegex     = regexp.MustCompile(`^~~\s*(.+?)\s*~~$`)
+	claudeArgsRegex = regexp.MustCompile(`(?i)^claude-args:\s*(.+)$`)
+	postRunRegex    = regexp.MustCompile(`(?i)^post-run:\s*(.+)$`)
+	includeRegex    = regexp.MustCompile(`(?i)^include:\s*(.+?)\s*$`)
+	concurrentRegex = regexp.MustCompile(`(?i)^concurrent:\s*(\d+)$`)
+	retriesRegex    = regexp.MustCompile(`(?i)^retries:\s*(\d+)$`)
+	warningsRegex   = regexp.MustCompile(`(?i)^warnings:\s*(\d+)$`)
+	timeoutRegex    = regexp.MustCompile(`(?i)^timeout:\s*(\S+)\s*$`)
+	// openMetaRegex matches a metadata key whose value follows on indented
+	// continuation lines
+	openMetaRegex = regexp.MustCompile(`(?i)^(acceptance|criteria|test|depends|files):\s*$`)
 )
 
 func ParsePRD(path string) (*PRD, error) {
@@ -61,10 +118,25 @@ func ParsePRD(path string) (*PRD, error) {
 		return nil, fmt.Errorf("failed to read PRD file: %w", err)
 	}
 
-	return ParsePRDContent(string(content))
+	return parsePRDContent(string(content), newIncluder(path))
 }
 
+// ParsePRDContent parses a PRD held in memory. Include: paths are resolved
+// against the current directory.
 func ParsePRDContent(content string) (*PRD, error) {
+	return parsePRDContent(content, newIncluder(""))
+}
+
+// normalizeNewlines strips a leading UTF-8 byte order mark and converts CRLF
+// and lone CR line endings to LF, so PRDs written on Windows parse the same
+func normalizeNewlines(content string) string {
+	content = strings.TrimPrefix(content, "\ufeff")
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	return strings.ReplaceAll(content, "\r", "\n")
+}
+
+func parsePRDContent(content string, inc *includer) (*PRD, error) {
+	content = normalizeNewlines(content)
 	prd := &PRD{
 		RawContent: content,
 	}
@@ -74,10 +146,47 @@ func ParsePRDContent(content string) (*PRD, error) {
 	var currentSection string
 	var descriptionLines []string
 	var rawContentLines []string
+	var suffixLines []string
+	inPromptBlock := false
+	// continuing is the metadata key that indented lines currently extend
+	continuing := ""
+	currentGroup := ""
+
+	finishFeature := func() {
+		if currentFeature == nil {
+			return
+		}
+		currentFeature.Description = strings.TrimSpace(strings.Join(descriptionLines, "\n"))
+		currentFeature.RawContent = strings.TrimSpace(strings.Join(rawContentLines, "\n"))
+		currentFeature.PromptSuffix = strings.TrimSpace(strings.Join(suffixLines, "\n"))
+		currentFeature.AcceptanceCriteria = dropEmpty(currentFeature.AcceptanceCriteria)
+		prd.Features = append(prd.Features, *currentFeature)
+	}
 
 	for scanner.Scan() {
 		line := scanner.Text()
 
+		// Collect ```prompt fenced blocks as the feature's prompt suffix
+		if inPromptBlock {
+			rawContentLines = append(rawContentLines, line)
+			if strings.TrimSpace(line) == "```" {
+				inPromptBlock = false
+				continue
+			}
+			suffixLines = append(suffixLines, line)
+			continue
+		}
+
+		// "# Epic: Name" or "## Group: Name" tags the features that follow
+		if matches := groupRegex.FindStringSubmatch(line); matches != nil {
+			finishFeature()
+			currentFeature = nil
+			currentGroup = strings.TrimSpace(matches[1])
+			currentSection = "group"
+			continuing = ""
+			continue
+		}
+
 		if matches := h1Regex.FindStringSubmatch(line); matches != nil {
 			prd.Title = matches[1]
 			currentSection = "context"
@@ -85,20 +194,27 @@ func ParsePRDContent(content string) (*PRD, error) {
 		}
 
 		if matches := h2Regex.FindStringSubmatch(line); matches != nil {
-			if currentFeature != nil {
-				currentFeature.Description = strings.TrimSpace(strings.Join(descriptionLines, "\n"))
-				currentFeature.RawContent = strings.TrimSpace(strings.Join(rawContentLines, "\n"))
-				prd.Features = append(prd.Features, *currentFeature)
+			finishFeature()
+
+			// "## ~~Title~~" disables the feature
+			title := matches[1]
+			disabled := false
+			if struck := strikeRegex.FindStringSubmatch(strings.TrimSpace(title)); struck != nil {
+				title = struck[1]
+				disabled = true
 			}
 
 			currentFeature = &Feature{
-				Title:         matches[1],
-				ID:            generateID(matches[1]),
+				Title:         title,
+				ID:            generateID(title),
 				ExecutionMode: "sequential",
-				Model:         "sonnet",
+				Group:         currentGroup,
+				Disabled:      disabled,
 			}
 			currentSection = "feature"
+			continuing = ""
 			descriptionLines = nil
+			suffixLines = nil
 			rawContentLines = []string{line}
 			continue
 		}
@@ -118,6 +234,40 @@ func ParsePRDContent(content string) (*PRD, error) {
 			if matches := contextRegex.FindStringSubmatch(line); matches != nil {
 				prd.ContextBudget = parseContextValue(matches[1])
 			}
+			// Extra claude flags, split on whitespace
+			if matches := claudeArgsRegex.FindStringSubmatch(line); matches != nil {
+				prd.ClaudeArgs = strings.Fields(matches[1])
+			}
+			if matches := postRunRegex.FindStringSubmatch(line); matches != nil {
+				prd.PostRun = strings.TrimSpace(matches[1])
+			}
+			// Shared context from another file, inlined in place of the line
+			if matches := includeRegex.FindStringSubmatch(line); matches != nil {
+				included, err := inc.expand(matches[1])
+				if err != nil {
+					return nil, err
+				}
+				prd.Context += included
+				continue
+			}
+			// Run settings that would otherwise come from progress.json
+			if matches := concurrentRegex.FindStringSubmatch(line); matches != nil {
+				prd.MaxConcurrent, _ = strconv.Atoi(matches[1])
+			}
+			if matches := retriesRegex.FindStringSubmatch(line); matches != nil {
+				prd.MaxRetries, _ = strconv.Atoi(matches[1])
+			}
+			if matches := warningsRegex.FindStringSubmatch(line); matches != nil {
+				prd.WarningThreshold, _ = strconv.Atoi(matches[1])
+			}
+			if matches := timeoutRegex.FindStringSubmatch(line); matches != nil {
+				prd.Timeout = parseTimeoutValue(matches[1])
+			}
+			if matches := metaRegex.FindStringSubmatch(line); matches != nil && strings.EqualFold(matches[1], "model") {
+				if model := strings.ToLower(strings.TrimSpace(matches[2])); isKnownModel(model) {
+					prd.Model = model
+				}
+			}
 			prd.Context += line + "\n"
 			continue
 		}
@@ -126,6 +276,24 @@ func ParsePRDContent(content string) (*PRD, error) {
 			continue
 		}
 
+		// Indented lines directly beneath Acceptance:, Depends:, Files: or
+		// Prompt-Suffix: continue that value
+		if continuing != "" && isContinuationLine(line) {
+			appendContinuation(currentFeature, &suffixLines, continuing, strings.TrimSpace(line))
+			rawContentLines = append(rawContentLines, line)
+			continue
+		}
+		continuing = ""
+
+		if matches := openMetaRegex.FindStringSubmatch(line); matches != nil {
+			continuing = continuationKey(matches[1])
+			if continuing == "acceptance" {
+				currentFeature.AcceptanceCriteria = append(currentFeature.AcceptanceCriteria, "")
+			}
+			rawContentLines = append(rawContentLines, line)
+			continue
+		}
+
 		if matches := taskRegex.FindStringSubmatch(line); matches != nil {
 			task := Task{
 				ID:          generateID(matches[2]),
@@ -147,7 +315,7 @@ func ParsePRDContent(content string) (*PRD, error) {
 					currentFeature.ExecutionMode = "sequential"
 				}
 			case "model":
-				if value == "opus" || value == "haiku" || value == "sonnet" || value == "auto" {
+				if isKnownModel(value) {
 					currentFeature.Model = value
 				}
 			}
@@ -157,18 +325,21 @@ func ParsePRDContent(content string) (*PRD, error) {
 
 		if matches := criteriaRegex.FindStringSubmatch(line); matches != nil {
 			currentFeature.AcceptanceCriteria = append(currentFeature.AcceptanceCriteria, matches[2])
+			continuing = "acceptance"
 			rawContentLines = append(rawContentLines, line)
 			continue
 		}
 
 		if matches := dependsRegex.FindStringSubmatch(line); matches != nil {
-			depStr := strings.TrimSpace(matches[1])
-			for _, dep := range strings.Split(depStr, ",") {
-				dep = strings.TrimSpace(dep)
-				if dep != "" {
-					currentFeature.DependsOn = append(currentFeature.DependsOn, dep)
-				}
-			}
+			appendDependencies(currentFeature, matches[1])
+			continuing = "depends"
+			rawContentLines = append(rawContentLines, line)
+			continue
+		}
+
+		if matches := filesRegex.FindStringSubmatch(line); matches != nil {
+			appendFiles(currentFeature, matches[1])
+			continuing = "files"
 			rawContentLines = append(rawContentLines, line)
 			continue
 		}
@@ -205,6 +376,71 @@ func ParsePRDContent(content string) (*PRD, error) {
 			continue
 		}
 
+		// Check for a limit on concurrently running children
+		if matches := maxChildRegex.FindStringSubmatch(line); matches != nil {
+			currentFeature.MaxChildrenConcurrent, _ = strconv.Atoi(matches[1])
+			rawContentLines = append(rawContentLines, line)
+			continue
+		}
+
+		// Check for a time limit on the feature
+		if matches := timeoutRegex.FindStringSubmatch(line); matches != nil {
+			currentFeature.Timeout = parseTimeoutValue(matches[1])
+			rawContentLines = append(rawContentLines, line)
+			continue
+		}
+
+		// Check for a short name dependencies can refer to
+		if matches := aliasRegex.FindStringSubmatch(line); matches != nil {
+			currentFeature.Alias = matches[1]
+			rawContentLines = append(rawContentLines, line)
+			continue
+		}
+
+		// Check for custom prompt suffix
+		if matches := suffixRegex.FindStringSubmatch(line); matches != nil {
+			suffixLines = append(suffixLines, strings.TrimSpace(matches[1]))
+			continuing = "prompt-suffix"
+			rawContentLines = append(rawContentLines, line)
+			continue
+		}
+
+		// Check for the commit or tag the feature starts from
+		if matches := baseRegex.FindStringSubmatch(line); matches != nil {
+			currentFeature.Base = matches[1]
+			rawContentLines = append(rawContentLines, line)
+			continue
+		}
+
+		// Check for nice-to-have features
+		if matches := optionalRegex.FindStringSubmatch(line); matches != nil {
+			value := strings.ToLower(matches[1])
+			currentFeature.Optional = value == "true" || value == "yes"
+			rawContentLines = append(rawContentLines, line)
+			continue
+		}
+
+		// Check for what a failure after all retries does
+		if matches := onFailureRegex.FindStringSubmatch(line); matches != nil {
+			currentFeature.OnFailure = strings.ToLower(matches[1])
+			rawContentLines = append(rawContentLines, line)
+			continue
+		}
+
+		// Check for features excluded from runs
+		if matches := disabledRegex.FindStringSubmatch(line); matches != nil {
+			value := strings.ToLower(matches[1])
+			currentFeature.Disabled = value == "true" || value == "yes"
+			rawContentLines = append(rawContentLines, line)
+			continue
+		}
+
+		if strings.EqualFold(strings.TrimSpace(line), "```prompt") {
+			inPromptBlock = true
+			rawContentLines = append(rawContentLines, line)
+			continue
+		}
+
 		if strings.HasPrefix(strings.TrimSpace(line), "- ") && !strings.Contains(line, "[ ]") && !strings.Contains(line, "[x]") {
 			trimmed := strings.TrimPrefix(strings.TrimSpace(line), "- ")
 			if strings.HasPrefix(strings.ToLower(trimmed), "acceptance:") || strings.HasPrefix(strings.ToLower(trimmed), "criteria:") {
@@ -220,13 +456,21 @@ func ParsePRDContent(content string) (*PRD, error) {
 		rawContentLines = append(rawContentLines, line)
 	}
 
-	if currentFeature != nil {
-		currentFeature.Description = strings.TrimSpace(strings.Join(descriptionLines, "\n"))
-		currentFeature.RawContent = strings.TrimSpace(strings.Join(rawContentLines, "\n"))
-		prd.Features = append(prd.Features, *currentFeature)
-	}
+	finishFeature()
 
 	prd.Context = strings.TrimSpace(prd.Context)
+	defaultModel := prd.Model
+	if defaultModel == "" {
+		defaultModel = "sonnet"
+	}
+	for i := range prd.Features {
+		if prd.Features[i].Timeout == 0 {
+			prd.Features[i].Timeout = prd.Timeout
+		}
+		if prd.Features[i].Model == "" {
+			prd.Features[i].Model = defaultModel
+		}
+	}
 
 	if err := scanner.Err(); err != nil {
 		return nil, fmt.Errorf("error scanning PRD: %w", err)
@@ -235,6 +479,177 @@ func ParsePRDContent(content string) (*PRD, error) {
 	return prd, nil
 }
 
+// isContinuationLine reports whether a line is indented beneath the previous
+// metadata line
+func isContinuationLine(line string) bool {
+	return (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && strings.TrimSpace(line) != ""
+}
+
+// continuationKey normalizes a metadata key that accepts continuation lines
+func continuationKey(key string) string {
+	switch strings.ToLower(key) {
+	case "depends", "files":
+		return strings.ToLower(key)
+	}
+	return "acceptance"
+}
+
+// appendContinuation extends the value of the metadata key with a
+// continuation line: acceptance criteria are joined with a space, depends
+// and files lists are split on commas, and prompt suffixes keep their line
+// breaks
+func appendContinuation(f *Feature, suffixLines *[]string, key, value string) {
+	switch key {
+	case "acceptance":
+		last := len(f.AcceptanceCriteria) - 1
+		if f.AcceptanceCriteria[last] == "" {
+			f.AcceptanceCriteria[last] = value
+		} else {
+			f.AcceptanceCriteria[last] += " " + value
+		}
+	case "depends":
+		appendDependencies(f, value)
+	case "files":
+		appendFiles(f, value)
+	case "prompt-suffix":
+		*suffixLines = append(*suffixLines, value)
+	}
+}
+
+// appendDependencies adds the comma-separated feature references in value
+func appendDependencies(f *Feature, value string) {
+	for _, dep := range strings.Split(value, ",") {
+		dep = strings.TrimSpace(dep)
+		if dep != "" {
+			f.DependsOn = append(f.DependsOn, dep)
+		}
+	}
+}
+
+// appendFiles adds the comma-separated paths, directories or globs in value
+func appendFiles(f *Feature, value string) {
+	for _, file := range strings.Split(value, ",") {
+		file = strings.TrimSpace(file)
+		if file != "" {
+			f.Files = append(f.Files, file)
+		}
+	}
+}
+
+func dropEmpty(values []string) []string {
+	var result []string
+	for _, v := range values {
+		if v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Groups returns the names of the feature groups in the order they first
+// appear
+func (p *PRD) Groups() []string {
+	var groups []string
+	seen := make(map[string]bool)
+	for _, f := range p.Features {
+		if f.Group != "" && !seen[f.Group] {
+			seen[f.Group] = true
+			groups = append(groups, f.Group)
+		}
+	}
+	return groups
+}
+
+// GroupFeatures returns the features tagged with the named group, matched
+// case-insensitively
+func (p *PRD) GroupFeatures(group string) []Feature {
+	var features []Feature
+	for _, f := range p.Features {
+		if f.Group != "" && strings.EqualFold(f.Group, group) {
+			features = append(features, f)
+		}
+	}
+	return features
+}
+
+// Hash returns a SHA-256 of the PRD content, used to detect PRD changes between
+// runs. PRDs synthesized from a manifest have no raw content, so their feature
+// titles and descriptions are hashed instead.
+func (p *PRD) Hash() string {
+	h := sha256.New()
+	if p.RawContent != "" {
+		h.Write([]byte(p.RawContent))
+	} else {
+		h.Write([]byte(p.Title))
+		for _, f := range p.Features {
+			fmt.Fprintf(h, "\x00%s\x00%s\x00%s", f.ID, f.Title, f.Description)
+		}
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// CountTasks returns the number of tasks in content, such as a feature.md:
+// its unindented task checkboxes, as the parser counts them
+func CountTasks(content string) int {
+	count := 0
+	for _, line := range strings.Split(normalizeNewlines(content), "\n") {
+		if taskRegex.MatchString(line) {
+			count++
+		}
+	}
+	return count
+}
+
+// ApplyTaskStates marks the feature's tasks completed or not as states says,
+// keyed by task ID, in Tasks and in any task checkboxes in the description
+func (f *Feature) ApplyTaskStates(states map[string]bool) {
+	if len(states) == 0 {
+		return
+	}
+	tasks := make([]Task, len(f.Tasks))
+	copy(tasks, f.Tasks)
+	for i, task := range tasks {
+		if completed, ok := states[task.ID]; ok {
+			tasks[i].Completed = completed
+		}
+	}
+	f.Tasks = tasks
+	f.Description = ApplyTaskStates(f.Description, states)
+}
+
+// ApplyTaskStates rewrites the task checkboxes in content, such as a
+// feature.md, to match states, keyed by task ID. Tasks not in states are
+// left as written.
+func ApplyTaskStates(content string, states map[string]bool) string {
+	if len(states) == 0 {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		matches := taskRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if matches == nil {
+			continue
+		}
+		completed, ok := states[TaskID(matches[2])]
+		if !ok {
+			continue
+		}
+		mark := " "
+		if completed {
+			mark = "x"
+		}
+		box := strings.Index(line, "[")
+		lines[i] = line[:box+1] + mark + line[box+2:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// TaskID returns the ID of the task with the given description, the key
+// progress.json records it under
+func TaskID(description string) string {
+	return generateID(description)
+}
+
 func generateID(title string) string {
 	hash := sha256.Sum256([]byte(title))
 	return fmt.Sprintf("%x", hash[:8])
@@ -264,6 +679,11 @@ func (f *Feature) ToPromptWithProgress(context string, progressContent string) s
 	sb.WriteString(f.Description)
 	sb.WriteString("\n\n")
 
+	if f.Base != "" {
+		sb.WriteString("## Base Revision\n\n")
+		sb.WriteString(fmt.Sprintf("This feature starts from git revision `%s`. Build on the code as of that commit or tag.\n\n", f.Base))
+	}
+
 	if len(f.Tasks) > 0 {
 		sb.WriteString("## Tasks\n\n")
 		for _, task := range f.Tasks {
@@ -295,6 +715,12 @@ func (f *Feature) ToPromptWithProgress(context string, progressContent string) s
 	sb.WriteString("   - Any dependencies or setup required by future features\n")
 	sb.WriteString("   - Do NOT summarize or compact existing content - append new notes\n")
 
+	if f.PromptSuffix != "" {
+		sb.WriteString("\n## Additional Instructions\n\n")
+		sb.WriteString(f.PromptSuffix)
+		sb.WriteString("\n")
+	}
+
 	return sb.String()
 }
 
@@ -343,6 +769,25 @@ func parseBudgetValue(value string) (tokens int64, usd float64) {
 	return int64(val * multiplier), 0
 }
 
+// isKnownModel reports whether model is one Model: accepts
+func isKnownModel(model string) bool {
+	switch model {
+	case "opus", "haiku", "sonnet", "auto":
+		return true
+	}
+	return false
+}
+
+// parseTimeoutValue parses a Timeout: duration such as "30m", "2h" or
+// "90s", returning 0 for anything else
+func parseTimeoutValue(value string) time.Duration {
+	d, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil || d <= 0 {
+		return 0
+	}
+	return d
+}
+
 // parseContextValue parses a context budget value string
 // Supports formats: 50000, 50k, 1.5M, 100k tokens
 func parseContextValue(value string) int64 {
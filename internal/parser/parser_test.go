@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestParsePRDContent_BasicStructure(t *testing.T) {
@@ -363,6 +364,48 @@ Model: ` + tt.model + `
 	}
 }
 
+func TestParsePRDContent_DefaultModel(t *testing.T) {
+	content := `# Project
+
+Model: haiku
+
+## Docs
+
+- [ ] Task 1
+
+## Architecture
+
+Model: opus
+
+- [ ] Task 2
+`
+
+	prd, err := ParsePRDContent(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prd.Model != "haiku" {
+		t.Errorf("expected PRD model 'haiku', got %q", prd.Model)
+	}
+	if got := prd.Features[0].Model; got != "haiku" {
+		t.Errorf("expected the PRD's model for a feature without one, got %q", got)
+	}
+	if got := prd.Features[1].Model; got != "opus" {
+		t.Errorf("expected the feature's own model, got %q", got)
+	}
+
+	prd, err = ParsePRDContent("# Project\n\nModel: gpt\n\n## Feature\n\n- [ ] Task\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prd.Model != "" {
+		t.Errorf("expected an unknown PRD model to be ignored, got %q", prd.Model)
+	}
+	if got := prd.Features[0].Model; got != "sonnet" {
+		t.Errorf("expected default model 'sonnet', got %q", got)
+	}
+}
+
 func TestParsePRDContent_RawContent(t *testing.T) {
 	content := `# Project
 
@@ -1120,6 +1163,35 @@ func TestParseContextValue(t *testing.T) {
 	}
 }
 
+func TestParsePRDContent_MaxChildrenConcurrent(t *testing.T) {
+	content := `# Project
+
+## Feature 1
+
+Max-Children-Concurrent: 2
+
+- [ ] Task 1
+
+## Feature 2
+
+- [ ] Task 2
+`
+
+	prd, err := ParsePRDContent(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := prd.Features[0].MaxChildrenConcurrent; got != 2 {
+		t.Errorf("expected a limit of 2 children, got %d", got)
+	}
+	if got := prd.Features[1].MaxChildrenConcurrent; got != 0 {
+		t.Errorf("expected no limit by default, got %d", got)
+	}
+	if len(prd.Features[0].Tasks) != 1 {
+		t.Errorf("expected the annotation not to be taken as a task, got %d tasks", len(prd.Features[0].Tasks))
+	}
+}
+
 // Isolation level parsing tests
 
 func TestParsePRDContent_IsolationLevelStrict(t *testing.T) {
@@ -1276,3 +1348,721 @@ Budget: $5.00
 		t.Errorf("expected budget USD 5.00, got %f", f.BudgetUSD)
 	}
 }
+
+func TestParsePRDContent_PromptSuffixLine(t *testing.T) {
+	content := `# Project
+
+## Feature 1
+
+Prompt-Suffix: Use the existing logger package, never fmt.Println.
+
+- [ ] Task 1
+
+## Feature 2
+
+- [ ] Task 2
+`
+
+	prd, err := ParsePRDContent(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := prd.Features[0].PromptSuffix; got != "Use the existing logger package, never fmt.Println." {
+		t.Errorf("unexpected prompt suffix: %q", got)
+	}
+	if strings.Contains(prd.Features[0].Description, "Prompt-Suffix") {
+		t.Error("prompt suffix should not be part of the description")
+	}
+	if prd.Features[1].PromptSuffix != "" {
+		t.Errorf("expected empty prompt suffix for feature 2, got %q", prd.Features[1].PromptSuffix)
+	}
+}
+
+func TestParsePRDContent_PromptSuffixBlock(t *testing.T) {
+	content := "# Project\n\n## Feature 1\n\nSome description.\n\n```prompt\nKeep handlers thin.\n\n## Not a feature\n```\n\n- [ ] Task 1\n"
+
+	prd, err := ParsePRDContent(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(prd.Features) != 1 {
+		t.Fatalf("expected 1 feature, got %d", len(prd.Features))
+	}
+	f := prd.Features[0]
+	if f.PromptSuffix != "Keep handlers thin.\n\n## Not a feature" {
+		t.Errorf("unexpected prompt suffix: %q", f.PromptSuffix)
+	}
+	if f.Description != "Some description." {
+		t.Errorf("expected description without prompt block, got %q", f.Description)
+	}
+	if len(f.Tasks) != 1 {
+		t.Errorf("expected 1 task after prompt block, got %d", len(f.Tasks))
+	}
+}
+
+func TestFeature_ToPromptSuffixAfterInstructions(t *testing.T) {
+	f := &Feature{
+		Title:        "Test Feature",
+		Description:  "Description.",
+		PromptSuffix: "Prefer table-driven tests.",
+	}
+
+	prompt := f.ToPrompt("Context.")
+
+	instructionsIdx := strings.Index(prompt, "## Instructions")
+	suffixIdx := strings.Index(prompt, "Prefer table-driven tests.")
+	if suffixIdx == -1 {
+		t.Fatal("prompt should contain the prompt suffix")
+	}
+	if suffixIdx < instructionsIdx {
+		t.Error("prompt suffix should appear after the standard instructions")
+	}
+	if !strings.Contains(prompt, "## Additional Instructions") {
+		t.Error("prompt should contain additional instructions header")
+	}
+
+	f.PromptSuffix = ""
+	if strings.Contains(f.ToPrompt("Context."), "## Additional Instructions") {
+		t.Error("prompt should not contain additional instructions header without a suffix")
+	}
+}
+
+func TestParsePRDContent_ClaudeArgs(t *testing.T) {
+	content := `# Project
+
+Claude-Args: --mcp-config mcp.json --add-dir ../shared
+
+## Feature 1
+
+- [ ] Task 1
+`
+
+	prd, err := ParsePRDContent(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"--mcp-config", "mcp.json", "--add-dir", "../shared"}
+	if len(prd.ClaudeArgs) != len(expected) {
+		t.Fatalf("expected %d claude args, got %v", len(expected), prd.ClaudeArgs)
+	}
+	for i := range expected {
+		if prd.ClaudeArgs[i] != expected[i] {
+			t.Errorf("arg %d: expected %q, got %q", i, expected[i], prd.ClaudeArgs[i])
+		}
+	}
+}
+
+func TestParsePRDContent_PostRun(t *testing.T) {
+	content := `# Project
+
+Post-Run: go test ./... && gh pr create --fill
+
+## Feature 1
+
+- [ ] Task 1
+`
+
+	prd, err := ParsePRDContent(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prd.PostRun != "go test ./... && gh pr create --fill" {
+		t.Errorf("unexpected post-run command %q", prd.PostRun)
+	}
+}
+
+func TestParsePRDContent_RunSettings(t *testing.T) {
+	content := `# Project
+
+Concurrent: 2
+Retries: 5
+Warnings: 8
+
+## Feature 1
+
+Retries: 9
+
+- [ ] Task 1
+`
+
+	prd, err := ParsePRDContent(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if prd.MaxConcurrent != 2 {
+		t.Errorf("expected MaxConcurrent 2, got %d", prd.MaxConcurrent)
+	}
+	if prd.MaxRetries != 5 {
+		t.Errorf("expected MaxRetries 5, got %d", prd.MaxRetries)
+	}
+	if prd.WarningThreshold != 8 {
+		t.Errorf("expected WarningThreshold 8, got %d", prd.WarningThreshold)
+	}
+
+	// Unset values leave the config defaults in place
+	prd, _ = ParsePRDContent("# Project\n\n## Feature 1\n\n- [ ] Task 1\n")
+	if prd.MaxConcurrent != 0 || prd.MaxRetries != 0 {
+		t.Errorf("expected zero run settings, got concurrent=%d retries=%d", prd.MaxConcurrent, prd.MaxRetries)
+	}
+}
+
+func TestParsePRDContent_Base(t *testing.T) {
+	content := `# Project
+
+## Feature 1: Upgrade
+
+Base: v1.2.0
+
+- [ ] Task 1
+
+## Feature 2: Fresh
+
+- [ ] Task 2
+`
+
+	prd, err := ParsePRDContent(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if prd.Features[0].Base != "v1.2.0" {
+		t.Errorf("expected base 'v1.2.0', got %q", prd.Features[0].Base)
+	}
+	if prd.Features[1].Base != "" {
+		t.Errorf("expected no base for feature 2, got %q", prd.Features[1].Base)
+	}
+	if strings.Contains(prd.Features[0].Description, "Base:") {
+		t.Error("base line should not be part of the description")
+	}
+}
+
+func TestParsePRDContent_Optional(t *testing.T) {
+	content := `# Project
+
+## Feature 1: Dark mode
+
+Optional: true
+
+- [ ] Task 1
+
+## Feature 2: Login
+
+Optional: no
+
+- [ ] Task 2
+
+## Feature 3: Logout
+
+- [ ] Task 3
+`
+
+	prd, err := ParsePRDContent(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []bool{true, false, false}
+	for i, optional := range want {
+		if prd.Features[i].Optional != optional {
+			t.Errorf("feature %d: expected Optional %v, got %v", i+1, optional, prd.Features[i].Optional)
+		}
+	}
+	if strings.Contains(prd.Features[0].Description, "Optional:") {
+		t.Error("optional line should not be part of the description")
+	}
+}
+
+func TestParsePRDContent_OnFailure(t *testing.T) {
+	content := `# Project
+
+## Feature 1: Search index
+
+On-Failure: skip
+
+- [ ] Task 1
+
+## Feature 2: Migrations
+
+On-Failure: ABORT
+
+- [ ] Task 2
+
+## Feature 3: Docs
+
+On-Failure: continue
+
+## Feature 4: Logout
+
+On-Failure: explode
+`
+
+	prd, err := ParsePRDContent(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{OnFailureSkip, OnFailureAbort, OnFailureContinue, ""}
+	for i, action := range want {
+		if prd.Features[i].OnFailure != action {
+			t.Errorf("feature %d: expected OnFailure %q, got %q", i+1, action, prd.Features[i].OnFailure)
+		}
+	}
+	if strings.Contains(prd.Features[0].Description, "On-Failure:") {
+		t.Error("on-failure line should not be part of the description")
+	}
+	if !strings.Contains(prd.Features[3].Description, "On-Failure: explode") {
+		t.Error("an unknown action should be left in the description")
+	}
+}
+
+func TestParsePRDContent_Disabled(t *testing.T) {
+	content := `# Project
+
+## Feature 1: Dark mode
+
+Disabled: true
+
+- [ ] Task 1
+
+## ~~Feature 2: Login~~
+
+- [ ] Task 2
+
+## Feature 3: Logout
+
+Disabled: no
+
+- [ ] Task 3
+`
+
+	prd, err := ParsePRDContent(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []bool{true, true, false}
+	for i, disabled := range want {
+		if prd.Features[i].Disabled != disabled {
+			t.Errorf("feature %d: expected Disabled %v, got %v", i+1, disabled, prd.Features[i].Disabled)
+		}
+	}
+	if prd.Features[1].Title != "Feature 2: Login" {
+		t.Errorf("expected strike-through removed from title, got %q", prd.Features[1].Title)
+	}
+	if strings.Contains(prd.Features[0].Description, "Disabled:") {
+		t.Error("disabled line should not be part of the description")
+	}
+}
+
+func TestToPrompt_Base(t *testing.T) {
+	feature := Feature{Title: "Upgrade", Base: "a1b2c3d"}
+
+	prompt := feature.ToPrompt("context")
+
+	if !strings.Contains(prompt, "## Base Revision") || !strings.Contains(prompt, "`a1b2c3d`") {
+		t.Errorf("expected prompt to convey base revision, got:\n%s", prompt)
+	}
+
+	feature.Base = ""
+	if strings.Contains(feature.ToPrompt("context"), "Base Revision") {
+		t.Error("expected no base section without a base")
+	}
+}
+
+func TestPRD_Hash(t *testing.T) {
+	content := "# Project\n\n## Feature 1\n\n- [ ] Task 1\n"
+	a, _ := ParsePRDContent(content)
+	b, _ := ParsePRDContent(content)
+	c, _ := ParsePRDContent(content + "- [ ] Task 2\n")
+
+	if a.Hash() != b.Hash() {
+		t.Error("expected identical PRDs to hash the same")
+	}
+	if a.Hash() == c.Hash() {
+		t.Error("expected changed PRD to hash differently")
+	}
+
+	// PRDs synthesized from a manifest hash their features
+	synthetic := &PRD{Title: "Project", Features: []Feature{{ID: "01", Title: "One", Description: "desc"}}}
+	changed := &PRD{Title: "Project", Features: []Feature{{ID: "01", Title: "One", Description: "new desc"}}}
+	if synthetic.Hash() == changed.Hash() {
+		t.Error("expected changed feature description to hash differently")
+	}
+}
+
+func TestParsePRDContent_MultiLineMetadata(t *testing.T) {
+	content := "# Project\n\n" +
+		"## Feature 1: Auth\n\n" +
+		"Login and registration.\n\n" +
+		"Depends: setup,\n" +
+		"    database,\n" +
+		"\tconfig\n" +
+		"Acceptance: Registration creates a user and\n" +
+		"  returns 201 with the new user's ID\n" +
+		"Acceptance:\n" +
+		"    Login returns a JWT that expires\n" +
+		"    after one hour\n" +
+		"Acceptance: All tests pass\n" +
+		"Prompt-Suffix: Use bcrypt for hashing.\n" +
+		"  Never log passwords.\n\n" +
+		"- [ ] Add login\n" +
+		"  Indented note after a task stays in the description\n"
+
+	prd, err := ParsePRDContent(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f := prd.Features[0]
+
+	wantDeps := []string{"setup", "database", "config"}
+	if len(f.DependsOn) != len(wantDeps) {
+		t.Fatalf("expected dependencies %v, got %v", wantDeps, f.DependsOn)
+	}
+	for i, dep := range wantDeps {
+		if f.DependsOn[i] != dep {
+			t.Errorf("expected dependency %d to be %q, got %q", i, dep, f.DependsOn[i])
+		}
+	}
+
+	wantCriteria := []string{
+		"Registration creates a user and returns 201 with the new user's ID",
+		"Login returns a JWT that expires after one hour",
+		"All tests pass",
+	}
+	if len(f.AcceptanceCriteria) != len(wantCriteria) {
+		t.Fatalf("expected criteria %q, got %q", wantCriteria, f.AcceptanceCriteria)
+	}
+	for i, criterion := range wantCriteria {
+		if f.AcceptanceCriteria[i] != criterion {
+			t.Errorf("expected criterion %d to be %q, got %q", i, criterion, f.AcceptanceCriteria[i])
+		}
+	}
+
+	if f.PromptSuffix != "Use bcrypt for hashing.\nNever log passwords." {
+		t.Errorf("expected continued prompt suffix, got %q", f.PromptSuffix)
+	}
+	if strings.Contains(f.Description, "returns 201") || strings.Contains(f.Description, "database") {
+		t.Errorf("continuation lines should not leak into the description, got %q", f.Description)
+	}
+	if !strings.Contains(f.Description, "Indented note after a task") {
+		t.Errorf("indented lines outside metadata should stay in the description, got %q", f.Description)
+	}
+}
+
+func TestParsePRDContent_EmptyAcceptanceWithoutContinuation(t *testing.T) {
+	content := "# Project\n\n## Feature 1: A\n\nAcceptance:\n\nAcceptance: Works\n"
+
+	prd, err := ParsePRDContent(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := prd.Features[0].AcceptanceCriteria; len(got) != 1 || got[0] != "Works" {
+		t.Errorf("expected only the non-empty criterion, got %q", got)
+	}
+}
+
+func TestParsePRDContent_Files(t *testing.T) {
+	content := "# Project\n\n## Feature 1: Auth\n\n" +
+		"Files: internal/auth/, cmd/*.go\n" +
+		"  docs/auth.md\n\n" +
+		"## Feature 2: Docs\n\n- [ ] Write docs\n"
+
+	prd, err := ParsePRDContent(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"internal/auth/", "cmd/*.go", "docs/auth.md"}
+	if got := prd.Features[0].Files; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("expected files %q, got %q", want, got)
+	}
+	if got := prd.Features[1].Files; len(got) != 0 {
+		t.Errorf("expected no files for a feature without Files:, got %q", got)
+	}
+}
+
+func TestParsePRDContent_FeatureGroups(t *testing.T) {
+	content := "# Project\n\n" +
+		"Shared context.\n\n" +
+		"## Feature 0: Setup\n\n" +
+		"- [ ] Scaffold\n\n" +
+		"# Epic: Accounts\n\n" +
+		"Everything about users.\n\n" +
+		"## Feature 1: Login\n\n" +
+		"- [ ] Add login\n\n" +
+		"## Feature 2: Signup\n\n" +
+		"- [ ] Add signup\n\n" +
+		"## Group: Billing\n\n" +
+		"## Feature 3: Invoices\n\n" +
+		"- [ ] Add invoices\n"
+
+	prd, err := ParsePRDContent(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if prd.Title != "Project" {
+		t.Errorf("expected epic heading not to replace the title, got %q", prd.Title)
+	}
+	if len(prd.Features) != 4 {
+		t.Fatalf("expected group headings not to become features, got %d features", len(prd.Features))
+	}
+
+	wantGroups := []string{"", "Accounts", "Accounts", "Billing"}
+	for i, want := range wantGroups {
+		if got := prd.Features[i].Group; got != want {
+			t.Errorf("feature %q: expected group %q, got %q", prd.Features[i].Title, want, got)
+		}
+	}
+	if strings.Contains(prd.Features[0].RawContent, "Epic") {
+		t.Error("expected the epic heading to end the previous feature")
+	}
+	if strings.Contains(prd.Features[1].Description, "Everything about users") {
+		t.Error("expected group description not to leak into its first feature")
+	}
+
+	groups := prd.Groups()
+	if len(groups) != 2 || groups[0] != "Accounts" || groups[1] != "Billing" {
+		t.Errorf("expected groups [Accounts Billing], got %v", groups)
+	}
+	if features := prd.GroupFeatures("accounts"); len(features) != 2 {
+		t.Errorf("expected 2 features in accounts (case-insensitive), got %d", len(features))
+	}
+}
+
+func TestParsePRDContent_WindowsLineEndings(t *testing.T) {
+	content := strings.ReplaceAll(`# My Project
+
+Shared context.
+
+## Feature 1: Setup
+
+Model: opus
+Execution: parallel
+
+Initialize the project.
+
+- [ ] Create directory
+- [x] Add config
+
+Acceptance: Project builds
+
+## Feature 2: Core
+
+Depends: 01
+
+- [ ] Implement endpoints
+`, "\n", "\r\n")
+
+	prd, err := ParsePRDContent(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Contains(prd.RawContent, "\r") {
+		t.Error("expected \\r to be removed from raw content")
+	}
+	if prd.Title != "My Project" {
+		t.Errorf("expected title 'My Project', got %q", prd.Title)
+	}
+	if prd.Context != "Shared context." {
+		t.Errorf("expected context 'Shared context.', got %q", prd.Context)
+	}
+	if len(prd.Features) != 2 {
+		t.Fatalf("expected 2 features, got %d", len(prd.Features))
+	}
+
+	f1 := prd.Features[0]
+	if f1.Title != "Feature 1: Setup" {
+		t.Errorf("expected title 'Feature 1: Setup', got %q", f1.Title)
+	}
+	if f1.Model != "opus" {
+		t.Errorf("expected model opus, got %q", f1.Model)
+	}
+	if f1.ExecutionMode != "parallel" {
+		t.Errorf("expected execution parallel, got %q", f1.ExecutionMode)
+	}
+	if len(f1.Tasks) != 2 || f1.Tasks[0].Description != "Create directory" || !f1.Tasks[1].Completed {
+		t.Errorf("expected clean tasks, got %+v", f1.Tasks)
+	}
+	if len(f1.AcceptanceCriteria) != 1 || f1.AcceptanceCriteria[0] != "Project builds" {
+		t.Errorf("expected acceptance 'Project builds', got %q", f1.AcceptanceCriteria)
+	}
+	if strings.Contains(f1.Description, "\r") || strings.Contains(f1.RawContent, "\r") {
+		t.Errorf("expected no \\r in feature text, got %q", f1.Description)
+	}
+
+	f2 := prd.Features[1]
+	if len(f2.DependsOn) != 1 || f2.DependsOn[0] != "01" {
+		t.Errorf("expected depends [01], got %q", f2.DependsOn)
+	}
+}
+
+func TestParsePRD_ByteOrderMark(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "PRD.md")
+	content := "\ufeff# My Project\r\n\r\n## Feature 1: Setup\r\n\r\nModel: haiku\r\n\r\n- [ ] Create directory\r\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write PRD: %v", err)
+	}
+
+	prd, err := ParsePRD(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if prd.Title != "My Project" {
+		t.Errorf("expected title 'My Project', got %q", prd.Title)
+	}
+	if len(prd.Features) != 1 {
+		t.Fatalf("expected 1 feature, got %d", len(prd.Features))
+	}
+	if got := prd.Features[0].Model; got != "haiku" {
+		t.Errorf("expected model haiku, got %q", got)
+	}
+	if got := prd.Features[0].Tasks[0].Description; got != "Create directory" {
+		t.Errorf("expected task 'Create directory', got %q", got)
+	}
+}
+
+func TestApplyTaskStates(t *testing.T) {
+	prd, err := ParsePRDContent(`# Project
+
+## Feature 1: Auth
+
+- [ ] Add login handler
+- [x] Write migrations
+- [ ] Add logout
+`)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	f := prd.Features[0]
+	f.Description = "Implement auth.\n\n- [ ] Add login handler\n- [x] Write migrations\n  - [ ] Hash passwords"
+
+	f.ApplyTaskStates(map[string]bool{
+		generateID("Add login handler"): true,
+		generateID("Write migrations"):  false,
+		generateID("Hash passwords"):    true,
+	})
+
+	want := []bool{true, false, false}
+	for i, task := range f.Tasks {
+		if task.Completed != want[i] {
+			t.Errorf("task %q: expected completed %v", task.Description, want[i])
+		}
+	}
+	if prd.Features[0].Tasks[0].Completed {
+		t.Error("expected the parsed feature's tasks to be left alone")
+	}
+
+	wantDescription := "Implement auth.\n\n- [x] Add login handler\n- [ ] Write migrations\n  - [x] Hash passwords"
+	if f.Description != wantDescription {
+		t.Errorf("unexpected description:\n%s\nwant:\n%s", f.Description, wantDescription)
+	}
+}
+
+func TestApplyTaskStatesWithoutStates(t *testing.T) {
+	content := "- [ ] Add login handler"
+	if got := ApplyTaskStates(content, nil); got != content {
+		t.Errorf("expected content unchanged, got %q", got)
+	}
+}
+
+func TestParsePRDContent_Alias(t *testing.T) {
+	content := `# Project
+
+## Authentication
+
+Id: auth
+
+- [ ] Task 1
+
+## Dashboard
+
+Alias: dash-v2
+Depends: auth
+
+- [ ] Task 2
+`
+
+	prd, err := ParsePRDContent(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := prd.Features[0].Alias; got != "auth" {
+		t.Errorf("expected alias 'auth', got %q", got)
+	}
+	if got := prd.Features[1].Alias; got != "dash-v2" {
+		t.Errorf("expected alias 'dash-v2', got %q", got)
+	}
+	if strings.Contains(prd.Features[0].Description, "Id:") {
+		t.Errorf("expected the alias line not to be part of the description, got %q", prd.Features[0].Description)
+	}
+}
+
+func TestParsePRDContent_Timeout(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"30m", 30 * time.Minute},
+		{"2h", 2 * time.Hour},
+		{"90s", 90 * time.Second},
+		{"1h30m", 90 * time.Minute},
+		{"soon", 0},
+		{"-5m", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			prd, err := ParsePRDContent("# Project\n\nTimeout: " + tt.value + "\n\n## Feature\n\n- [ ] Task\n")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if prd.Timeout != tt.want {
+				t.Errorf("expected PRD timeout %v, got %v", tt.want, prd.Timeout)
+			}
+		})
+	}
+}
+
+func TestParsePRDContent_TimeoutDefault(t *testing.T) {
+	content := `# Project
+
+Timeout: 30m
+
+## Quick Fix
+
+- [ ] Task 1
+
+## Migration
+
+Timeout: 2h
+
+- [ ] Task 2
+`
+
+	prd, err := ParsePRDContent(content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := prd.Features[0].Timeout; got != 30*time.Minute {
+		t.Errorf("expected the PRD's timeout for a feature without one, got %v", got)
+	}
+	if got := prd.Features[1].Timeout; got != 2*time.Hour {
+		t.Errorf("expected the feature's own timeout, got %v", got)
+	}
+	if strings.Contains(prd.Features[1].Description, "Timeout:") {
+		t.Errorf("expected the timeout line not to be part of the description, got %q", prd.Features[1].Description)
+	}
+
+	prd, err = ParsePRDContent("# Project\n\n## Feature\n\n- [ ] Task\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if prd.Features[0].Timeout != 0 {
+		t.Errorf("expected no timeout without Timeout:, got %v", prd.Features[0].Timeout)
+	}
+}
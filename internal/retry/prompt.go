@@ -0,0 +1,65 @@
+package retry
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxExcerptLines limits how much prior output is quoted back into a prompt
+const maxExcerptLines = 30
+
+// PromptAugmentation returns guidance to append to the prompt of the given
+// attempt, based on how the previous attempt failed. The wording escalates
+// with each retry so the model doesn't repeat the same failure: attempt 2
+// focuses on the failing tests or error, attempt 3 and later ask for a
+// different approach. It returns "" for the first attempt.
+func PromptAugmentation(attempt int, ctx FailureContext) string {
+	if attempt <= 1 {
+		return ""
+	}
+
+	var sb strings.Builder
+	if attempt == 2 {
+		sb.WriteString("This is attempt 2; the previous attempt failed.")
+		if excerpt := tailLines(ctx.TestOutput); ctx.TestsFailed > 0 && excerpt != "" {
+			sb.WriteString(fmt.Sprintf(" Focus on the %d failing test(s) below and make them pass before changing anything else:\n\n", ctx.TestsFailed))
+			writeBlock(&sb, excerpt)
+		} else if excerpt := tailLines(ctx.LastError); excerpt != "" {
+			sb.WriteString(" Focus on fixing the error below before changing anything else:\n\n")
+			writeBlock(&sb, excerpt)
+		} else {
+			sb.WriteString(" Check what is already in place, then finish the remaining work and verify it with the tests.\n")
+		}
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("This is attempt %d; %d previous attempts failed. ", attempt, attempt-1))
+	sb.WriteString("Take a different approach rather than repeating the last one: revisit your assumptions, ")
+	sb.WriteString("reread the relevant code, and prefer a simpler design if the current one keeps breaking.")
+	if excerpt := tailLines(ctx.LastError); excerpt != "" {
+		sb.WriteString(" The last attempt failed with:\n\n")
+		writeBlock(&sb, excerpt)
+	} else {
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// tailLines returns the last maxExcerptLines lines of output
+func tailLines(output string) string {
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return ""
+	}
+	lines := strings.Split(output, "\n")
+	if len(lines) > maxExcerptLines {
+		lines = lines[len(lines)-maxExcerptLines:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func writeBlock(sb *strings.Builder, content string) {
+	sb.WriteString("```\n")
+	sb.WriteString(content)
+	sb.WriteString("\n```\n")
+}
@@ -0,0 +1,78 @@
+package retry
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPromptAugmentationFirstAttempt(t *testing.T) {
+	ctx := FailureContext{LastError: "boom", TestsFailed: 2, TestOutput: "--- FAIL: TestX"}
+	if got := PromptAugmentation(1, ctx); got != "" {
+		t.Errorf("expected no augmentation for the first attempt, got %q", got)
+	}
+}
+
+func TestPromptAugmentationSecondAttemptFocusesOnTests(t *testing.T) {
+	ctx := FailureContext{
+		LastError:   "exit status 1",
+		TestsFailed: 2,
+		TestOutput:  "--- FAIL: TestParse (0.00s)\n--- FAIL: TestRender (0.01s)",
+	}
+	got := PromptAugmentation(2, ctx)
+
+	for _, want := range []string{"attempt 2", "Focus on the 2 failing test(s) below", "--- FAIL: TestParse", "--- FAIL: TestRender"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected augmentation to contain %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "different approach") {
+		t.Error("attempt 2 should not ask for a different approach yet")
+	}
+}
+
+func TestPromptAugmentationSecondAttemptFallsBackToError(t *testing.T) {
+	ctx := FailureContext{LastError: "undefined: parseConfig"}
+	got := PromptAugmentation(2, ctx)
+
+	if !strings.Contains(got, "Focus on fixing the error below") || !strings.Contains(got, "undefined: parseConfig") {
+		t.Errorf("expected augmentation to quote the last error, got:\n%s", got)
+	}
+
+	if got := PromptAugmentation(2, FailureContext{}); !strings.Contains(got, "attempt 2") || strings.Contains(got, "```") {
+		t.Errorf("expected generic guidance without a quoted block, got:\n%s", got)
+	}
+}
+
+func TestPromptAugmentationLaterAttemptsAskForDifferentApproach(t *testing.T) {
+	ctx := FailureContext{LastError: "tests still failing", TestsFailed: 1, TestOutput: "--- FAIL: TestX"}
+
+	for _, attempt := range []int{3, 4} {
+		got := PromptAugmentation(attempt, ctx)
+		for _, want := range []string{
+			fmt.Sprintf("attempt %d; %d previous attempts failed", attempt, attempt-1),
+			"Take a different approach",
+			"tests still failing",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("attempt %d: expected augmentation to contain %q, got:\n%s", attempt, want, got)
+			}
+		}
+	}
+}
+
+func TestPromptAugmentationTruncatesLongOutput(t *testing.T) {
+	var lines []string
+	for i := 1; i <= maxExcerptLines+10; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	ctx := FailureContext{TestsFailed: 1, TestOutput: strings.Join(lines, "\n")}
+	got := PromptAugmentation(2, ctx)
+
+	if strings.Contains(got, "line 1\n") {
+		t.Error("expected the oldest output lines to be dropped")
+	}
+	if !strings.Contains(got, fmt.Sprintf("line %d", maxExcerptLines+10)) {
+		t.Error("expected the most recent output lines to be kept")
+	}
+}
@@ -2,6 +2,7 @@ package retry
 
 import (
 	"fmt"
+	"math/rand"
 	"strings"
 	"sync"
 	"time"
@@ -35,6 +36,11 @@ const (
 const (
 	DefaultMaxAdjustments = 3
 	DefaultMaxRetries     = 3
+	// DefaultRetryDelay is how long a failed feature waits before its retry
+	DefaultRetryDelay = 5 * time.Second
+	// DefaultJitter spreads retry delays by up to ±20% so features that
+	// fail together don't all retry against the API at once
+	DefaultJitter = 0.2
 )
 
 // Adjustment represents a single adjustment made during retry
@@ -196,6 +202,10 @@ type Config struct {
 	MaxRetries       int  `json:"max_retries"`
 	EnableEscalation bool `json:"enable_escalation"`
 	EnableSimplify   bool `json:"enable_simplify"`
+	// RetryDelay is how long to wait before a retry (0 = retry at once)
+	RetryDelay time.Duration `json:"retry_delay"`
+	// Jitter is the fraction the delay varies by at random, e.g. 0.2 for ±20%
+	Jitter float64 `json:"jitter"`
 }
 
 // DefaultConfig returns the default retry configuration
@@ -205,6 +215,8 @@ func DefaultConfig() Config {
 		MaxRetries:       DefaultMaxRetries,
 		EnableEscalation: true,
 		EnableSimplify:   true,
+		RetryDelay:       DefaultRetryDelay,
+		Jitter:           DefaultJitter,
 	}
 }
 
@@ -213,6 +225,7 @@ type Strategy struct {
 	mu      sync.RWMutex
 	config  Config
 	history map[string]*AdjustmentHistory
+	rng     *rand.Rand // Jitters retry delays
 }
 
 // NewStrategy creates a new retry strategy with default config
@@ -225,9 +238,32 @@ func NewStrategyWithConfig(config Config) *Strategy {
 	return &Strategy{
 		config:  config,
 		history: make(map[string]*AdjustmentHistory),
+		rng:     rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
 
+// SetSeed seeds the jitter applied to retry delays, so tests get the same
+// delays every run
+func (s *Strategy) SetSeed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rng = rand.New(rand.NewSource(seed))
+}
+
+// RetryDelay returns how long to wait before retrying: the configured delay
+// varied at random by up to the configured jitter either way
+func (s *Strategy) RetryDelay() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delay := s.config.RetryDelay
+	if delay <= 0 || s.config.Jitter <= 0 {
+		return delay
+	}
+	offset := (s.rng.Float64()*2 - 1) * s.config.Jitter
+	return delay + time.Duration(float64(delay)*offset)
+}
+
 // SetConfig updates the strategy configuration
 func (s *Strategy) SetConfig(config Config) {
 	s.mu.Lock()
@@ -278,6 +314,7 @@ type FailureContext struct {
 	TaskCount      int
 	CurrentModel   string
 	LastModel      string
+	TestOutput     string // Test runner output from the failed attempt
 }
 
 // RetryDecision contains the recommended adjustments for retry
@@ -291,6 +328,7 @@ type RetryDecision struct {
 	Details            string           `json:"details,omitempty"`
 	RemainingRetries   int              `json:"remaining_retries"`
 	RemainingAdjusts   int              `json:"remaining_adjusts"`
+	Delay              time.Duration    `json:"delay,omitempty"` // Wait before retrying, see RetryDelay
 }
 
 // DecideRetry analyzes the failure context and returns a retry decision
@@ -316,6 +354,7 @@ func (s *Strategy) DecideRetry(ctx FailureContext) RetryDecision {
 	}
 
 	decision.RemainingRetries = config.MaxRetries - ctx.AttemptNum
+	decision.Delay = s.RetryDelay()
 	decision.RemainingAdjusts = config.MaxAdjustments - adjustCount
 
 	if decision.RemainingAdjusts <= 0 {
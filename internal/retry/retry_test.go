@@ -392,6 +392,39 @@ func TestStrategyCanRetry(t *testing.T) {
 	}
 }
 
+func TestStrategyRetryDelayJitter(t *testing.T) {
+	s := NewStrategy()
+	s.SetSeed(42)
+
+	low := DefaultRetryDelay - DefaultRetryDelay/5
+	high := DefaultRetryDelay + DefaultRetryDelay/5
+	delays := make(map[time.Duration]bool)
+	for _, id := range []string{"01", "02", "03", "04", "05"} {
+		decision := s.DecideRetry(FailureContext{FeatureID: id, AttemptNum: 1, CurrentModel: "sonnet"})
+		if decision.Delay < low || decision.Delay > high {
+			t.Errorf("feature %s: expected delay within %v-%v, got %v", id, low, high, decision.Delay)
+		}
+		delays[decision.Delay] = true
+	}
+	if len(delays) < 2 {
+		t.Errorf("expected jitter to vary delays across features, got %v", delays)
+	}
+
+	// The same seed gives the same delays
+	first := NewStrategy()
+	first.SetSeed(7)
+	second := NewStrategy()
+	second.SetSeed(7)
+	if a, b := first.RetryDelay(), second.RetryDelay(); a != b {
+		t.Errorf("expected the same delay from the same seed, got %v and %v", a, b)
+	}
+
+	noJitter := NewStrategyWithConfig(Config{MaxRetries: 3, RetryDelay: time.Second})
+	if d := noJitter.RetryDelay(); d != time.Second {
+		t.Errorf("expected no jitter without Jitter set, got %v", d)
+	}
+}
+
 func TestStrategyCanAdjust(t *testing.T) {
 	s := NewStrategyWithConfig(Config{MaxAdjustments: 2})
 
@@ -8,4 +8,7 @@ var (
 	ErrInvalidSpawnData       = errors.New("invalid spawn request data")
 	ErrContextBudgetExhausted = errors.New("context budget exhausted")
 	ErrParentNotRunning       = errors.New("parent feature is not running")
+	ErrMaxSpawnsExceeded      = errors.New("maximum spawned sub-features exceeded")
+	ErrNotRootFeature         = errors.New("feature is not a root feature")
+	ErrFeatureNotTerminal     = errors.New("feature tree is still running")
 )
@@ -0,0 +1,84 @@
+package rlm
+
+import "time"
+
+// FeatureSummary is the compact record kept for a root feature tree after it
+// has been evicted from memory
+type FeatureSummary struct {
+	ID            string     `json:"id"`
+	Title         string     `json:"title"`
+	Status        string     `json:"status"`
+	SubFeatures   int        `json:"sub_features"`
+	Actions       int        `json:"actions"`
+	TotalTokens   int64      `json:"total_tokens"`
+	CostUSD       float64    `json:"cost_usd,omitempty"`
+	ResultContext string     `json:"result_context,omitempty"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+// isTerminalStatus reports whether a feature with this status will not run again
+func isTerminalStatus(status string) bool {
+	switch status {
+	case "completed", "failed", "skipped", "stopped":
+		return true
+	}
+	return false
+}
+
+// Evict drops a finished root feature and all its descendants from memory,
+// keeping only a FeatureSummary. Callers should persist whatever they need
+// from the tree first. It fails if the feature isn't a root or if any feature
+// in the tree can still run.
+func (m *Manager) Evict(rootID string) error {
+	tree := m.GetFeatureTree(rootID)
+	if tree == nil {
+		return ErrFeatureNotFound
+	}
+	root := tree[0]
+	if !root.IsRoot() {
+		return ErrNotRootFeature
+	}
+	for _, f := range tree {
+		if !isTerminalStatus(f.GetStatus()) {
+			return ErrFeatureNotTerminal
+		}
+	}
+
+	totals := m.GetTreeTotals(rootID)
+	root.mu.RLock()
+	summary := &FeatureSummary{
+		ID:            root.ID,
+		Title:         root.Title,
+		Status:        root.Status,
+		SubFeatures:   totals.Features - 1,
+		Actions:       totals.Actions,
+		TotalTokens:   totals.TotalTokens,
+		CostUSD:       totals.CostUSD,
+		ResultContext: root.ResultContext,
+		CompletedAt:   root.CompletedAt,
+	}
+	root.mu.RUnlock()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, f := range tree {
+		delete(m.features, f.ID)
+		delete(m.trackers, f.ID)
+	}
+	m.summaries[rootID] = summary
+
+	return nil
+}
+
+// GetSummary returns a copy of the summary of an evicted root feature, or nil
+// if the feature hasn't been evicted
+func (m *Manager) GetSummary(rootID string) *FeatureSummary {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	summary, ok := m.summaries[rootID]
+	if !ok {
+		return nil
+	}
+	copied := *summary
+	return &copied
+}
@@ -0,0 +1,110 @@
+package rlm
+
+import (
+	"errors"
+	"testing"
+)
+
+func newEvictTestTree(t *testing.T) (*Manager, *RecursiveFeature, *RecursiveFeature) {
+	t.Helper()
+	m := NewManager()
+	root := m.RegisterFeature("root", "Root Feature")
+	root.SetStatus("running")
+	root.TokenUsage.Update(1000, 200, 0, 0, 0.10)
+	root.AddAction(Action{Type: "tool", Name: "Edit"})
+
+	child, err := m.SpawnSubFeature("root", &SpawnRequest{Title: "Child"})
+	if err != nil {
+		t.Fatalf("SpawnSubFeature failed: %v", err)
+	}
+	child.TokenUsage.Update(500, 100, 0, 0, 0.05)
+	child.AddAction(Action{Type: "tool", Name: "Bash"})
+	child.AddAction(Action{Type: "tool", Name: "Read"})
+
+	return m, root, child
+}
+
+func TestEvictFreesTreeAndKeepsSummary(t *testing.T) {
+	m, root, child := newEvictTestTree(t)
+	child.SetStatus("completed")
+	root.SetStatus("completed")
+	root.SetResultContext("all done")
+
+	if err := m.Evict("root"); err != nil {
+		t.Fatalf("Evict failed: %v", err)
+	}
+
+	if m.GetFeature("root") != nil || m.GetFeature(child.ID) != nil {
+		t.Error("expected root and child to be removed from memory")
+	}
+	if m.GetTracker("root") != nil || m.GetTracker(child.ID) != nil {
+		t.Error("expected trackers to be removed")
+	}
+	if len(m.GetRootFeatures()) != 0 {
+		t.Error("expected no root features after eviction")
+	}
+
+	summary := m.GetSummary("root")
+	if summary == nil {
+		t.Fatal("expected summary to remain after eviction")
+	}
+	if summary.Title != "Root Feature" || summary.Status != "completed" {
+		t.Errorf("unexpected summary identity: %+v", summary)
+	}
+	if summary.SubFeatures != 1 {
+		t.Errorf("expected 1 sub-feature, got %d", summary.SubFeatures)
+	}
+	if summary.Actions != 3 {
+		t.Errorf("expected 3 actions, got %d", summary.Actions)
+	}
+	if summary.TotalTokens != 1800 {
+		t.Errorf("expected 1800 total tokens, got %d", summary.TotalTokens)
+	}
+	if summary.ResultContext != "all done" {
+		t.Errorf("expected result context to be kept, got %q", summary.ResultContext)
+	}
+	if summary.CompletedAt == nil {
+		t.Error("expected completion time to be kept")
+	}
+}
+
+func TestEvictRefusesUnfinishedTree(t *testing.T) {
+	m, root, child := newEvictTestTree(t)
+	root.SetStatus("completed")
+
+	if err := m.Evict("root"); !errors.Is(err, ErrFeatureNotTerminal) {
+		t.Errorf("expected ErrFeatureNotTerminal while a child runs, got %v", err)
+	}
+	if m.GetFeature(child.ID) == nil {
+		t.Error("expected tree to stay in memory")
+	}
+	if m.GetSummary("root") != nil {
+		t.Error("expected no summary for a tree that wasn't evicted")
+	}
+}
+
+func TestEvictRequiresRoot(t *testing.T) {
+	m, _, child := newEvictTestTree(t)
+	child.SetStatus("completed")
+
+	if err := m.Evict(child.ID); !errors.Is(err, ErrNotRootFeature) {
+		t.Errorf("expected ErrNotRootFeature, got %v", err)
+	}
+	if err := m.Evict("missing"); !errors.Is(err, ErrFeatureNotFound) {
+		t.Errorf("expected ErrFeatureNotFound, got %v", err)
+	}
+}
+
+func TestRegisterFeatureClearsSummary(t *testing.T) {
+	m, root, child := newEvictTestTree(t)
+	child.SetStatus("completed")
+	root.SetStatus("failed")
+	if err := m.Evict("root"); err != nil {
+		t.Fatalf("Evict failed: %v", err)
+	}
+
+	m.RegisterFeature("root", "Root Feature")
+	if m.GetSummary("root") != nil {
+		t.Error("expected re-registering a root to clear its stale summary")
+	}
+}
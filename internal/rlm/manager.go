@@ -13,17 +13,30 @@ type Manager struct {
 	features map[string]*RecursiveFeature
 	trackers map[string]*Tracker
 
+	// summaries holds evicted root feature trees
+	summaries map[string]*FeatureSummary
+
 	maxDepth      int
 	contextBudget int64
+
+	// Spawn guards against runaway parents
+	maxSpawnsPerRoot int
+	maxTotalSpawns   int
+	spawnsByRoot     map[string]int
+	totalSpawns      int
 }
 
 // NewManager creates a new RLM manager
 func NewManager() *Manager {
 	return &Manager{
-		features:      make(map[string]*RecursiveFeature),
-		trackers:      make(map[string]*Tracker),
-		maxDepth:      DefaultMaxDepth,
-		contextBudget: DefaultContextBudget,
+		features:         make(map[string]*RecursiveFeature),
+		trackers:         make(map[string]*Tracker),
+		summaries:        make(map[string]*FeatureSummary),
+		maxDepth:         DefaultMaxDepth,
+		contextBudget:    DefaultContextBudget,
+		maxSpawnsPerRoot: DefaultMaxSpawnsPerRoot,
+		maxTotalSpawns:   DefaultMaxTotalSpawns,
+		spawnsByRoot:     make(map[string]int),
 	}
 }
 
@@ -37,10 +50,14 @@ func NewManagerWithConfig(maxDepth int, contextBudget int64) *Manager {
 	}
 
 	return &Manager{
-		features:      make(map[string]*RecursiveFeature),
-		trackers:      make(map[string]*Tracker),
-		maxDepth:      maxDepth,
-		contextBudget: contextBudget,
+		features:         make(map[string]*RecursiveFeature),
+		trackers:         make(map[string]*Tracker),
+		summaries:        make(map[string]*FeatureSummary),
+		maxDepth:         maxDepth,
+		contextBudget:    contextBudget,
+		maxSpawnsPerRoot: DefaultMaxSpawnsPerRoot,
+		maxTotalSpawns:   DefaultMaxTotalSpawns,
+		spawnsByRoot:     make(map[string]int),
 	}
 }
 
@@ -55,6 +72,7 @@ func (m *Manager) RegisterFeature(id, title string) *RecursiveFeature {
 
 	m.features[id] = feature
 	m.trackers[id] = NewTracker(feature)
+	delete(m.summaries, id)
 
 	return feature
 }
@@ -97,6 +115,14 @@ func (m *Manager) SpawnSubFeature(parentID string, req *SpawnRequest) (*Recursiv
 		return nil, ErrParentNotRunning
 	}
 
+	rootID := m.rootIDUnlocked(parentID)
+	if m.maxSpawnsPerRoot > 0 && m.spawnsByRoot[rootID] >= m.maxSpawnsPerRoot {
+		return nil, fmt.Errorf("%w: root feature %s reached limit of %d", ErrMaxSpawnsExceeded, shortID(rootID), m.maxSpawnsPerRoot)
+	}
+	if m.maxTotalSpawns > 0 && m.totalSpawns >= m.maxTotalSpawns {
+		return nil, fmt.Errorf("%w: reached overall limit of %d", ErrMaxSpawnsExceeded, m.maxTotalSpawns)
+	}
+
 	childID := generateID(fmt.Sprintf("%s:%s", parentID, req.Title))
 
 	child, err := parent.NewChildFeature(childID, req.Title)
@@ -115,18 +141,61 @@ func (m *Manager) SpawnSubFeature(parentID string, req *SpawnRequest) (*Recursiv
 	if req.Model != "" {
 		child.Model = req.Model
 	}
+	if req.Group != "" {
+		child.Group = req.Group
+	}
 
 	if req.MaxDepth > 0 && req.MaxDepth < child.MaxDepth {
 		child.MaxDepth = req.MaxDepth
 	}
 
+	child.ExpectedOutputs = req.ExpectedOutputs
+
 	parent.AddSubFeature(child)
 	m.features[childID] = child
 	m.trackers[childID] = NewTracker(child)
+	m.spawnsByRoot[rootID]++
+	m.totalSpawns++
 
 	return child, nil
 }
 
+// rootIDUnlocked walks up the parent chain to find the root feature ID.
+// Caller must hold the lock.
+func (m *Manager) rootIDUnlocked(id string) string {
+	current := id
+	for {
+		f, ok := m.features[current]
+		if !ok || f.ParentID == "" {
+			return current
+		}
+		current = f.ParentID
+	}
+}
+
+// SetMaxSpawns configures the spawn caps per root feature and overall.
+// A value of zero or less disables the corresponding cap.
+func (m *Manager) SetMaxSpawns(perRoot, total int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.maxSpawnsPerRoot = perRoot
+	m.maxTotalSpawns = total
+}
+
+// GetSpawnCount returns the number of descendants spawned under a root feature
+func (m *Manager) GetSpawnCount(rootID string) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.spawnsByRoot[rootID]
+}
+
+// GetTotalSpawnCount returns the number of descendants spawned across all roots
+func (m *Manager) GetTotalSpawnCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.totalSpawns
+}
+
 // GetSubFeatures returns all sub-features of a parent
 func (m *Manager) GetSubFeatures(parentID string) []*RecursiveFeature {
 	feature := m.GetFeature(parentID)
@@ -165,6 +234,36 @@ func (m *Manager) GetTotalTokenUsage(rootID string) *TokenUsage {
 	return root.GetTotalTokenUsage()
 }
 
+// TreeTotals is the spend and activity of a feature and all its descendants
+type TreeTotals struct {
+	Features    int
+	Actions     int
+	TotalTokens int64
+	CostUSD     float64
+}
+
+// GetTreeTotals rolls up token usage, cost and action counts for a feature
+// tree, or returns nil if the feature isn't in memory
+func (m *Manager) GetTreeTotals(rootID string) *TreeTotals {
+	tree := m.GetFeatureTree(rootID)
+	if tree == nil {
+		return nil
+	}
+
+	totals := &TreeTotals{Features: len(tree)}
+	for _, f := range tree {
+		f.mu.RLock()
+		totals.Actions += len(f.Actions)
+		if f.TokenUsage != nil {
+			u := f.TokenUsage.GetSnapshot()
+			totals.TotalTokens += u.TotalTokens
+			totals.CostUSD += u.CostUSD
+		}
+		f.mu.RUnlock()
+	}
+	return totals
+}
+
 // GetAllActions returns all actions from a feature tree
 func (m *Manager) GetAllActions(rootID string) []Action {
 	tree := m.GetFeatureTree(rootID)
@@ -272,6 +371,13 @@ func (m *Manager) ClearFeature(id string) {
 	delete(m.trackers, id)
 }
 
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
 func generateID(input string) string {
 	hash := sha256.Sum256([]byte(input))
 	return fmt.Sprintf("%x", hash[:8])
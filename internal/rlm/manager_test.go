@@ -245,6 +245,54 @@ func TestManagerGetTotalTokenUsage(t *testing.T) {
 	}
 }
 
+func TestGetTreeTotals(t *testing.T) {
+	m := NewManager()
+	root := m.RegisterFeature("root", "Root")
+	root.SetStatus("running")
+
+	child1, _ := m.SpawnSubFeature("root", &SpawnRequest{Title: "Child 1"})
+	child2, _ := m.SpawnSubFeature("root", &SpawnRequest{Title: "Child 2"})
+	child1.TokenUsage.Update(1000, 200, 0, 0, 0.12)
+	child1.AddAction(Action{Type: "tool", Name: "Edit"})
+	child2.TokenUsage.Update(3000, 500, 0, 0, 0.30)
+	child2.AddAction(Action{Type: "tool", Name: "Bash"})
+	child2.AddAction(Action{Type: "tool", Name: "Read"})
+
+	child1.SetStatus("running")
+	grandchild, err := m.SpawnSubFeature(child1.ID, &SpawnRequest{Title: "Grandchild"})
+	if err != nil {
+		t.Fatalf("SpawnSubFeature failed: %v", err)
+	}
+	grandchild.TokenUsage.Update(100, 50, 0, 0, 0.03)
+
+	totals := m.GetTreeTotals("root")
+	if totals == nil {
+		t.Fatal("expected totals for root")
+	}
+	wantCost := 0.12 + 0.30 + 0.03
+	if diff := totals.CostUSD - wantCost; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("expected rolled-up cost %.2f to equal the sum of its children, got %.2f", wantCost, totals.CostUSD)
+	}
+	if totals.Actions != 3 {
+		t.Errorf("expected 3 actions, got %d", totals.Actions)
+	}
+	if totals.TotalTokens != 4850 {
+		t.Errorf("expected 4850 tokens, got %d", totals.TotalTokens)
+	}
+	if totals.Features != 4 {
+		t.Errorf("expected 4 features in the tree, got %d", totals.Features)
+	}
+
+	child1Totals := m.GetTreeTotals(child1.ID)
+	if diff := child1Totals.CostUSD - 0.15; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("expected child 1's subtree cost 0.15, got %.2f", child1Totals.CostUSD)
+	}
+
+	if m.GetTreeTotals("missing") != nil {
+		t.Error("expected nil totals for an unknown feature")
+	}
+}
+
 func TestGetAllActions(t *testing.T) {
 	m := NewManager()
 	root := m.RegisterFeature("root", "Root")
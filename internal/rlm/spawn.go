@@ -22,6 +22,11 @@ func NewSpawnHandler(mgr *Manager, m *manifest.Manifest) *SpawnHandler {
 	}
 }
 
+// SetManifest sets the manifest that spawned sub-features are persisted to
+func (h *SpawnHandler) SetManifest(m *manifest.Manifest) {
+	h.manifest = m
+}
+
 // ProcessLine processes output and returns a spawn request if detected
 func (h *SpawnHandler) ProcessLine(featureID string, line string) (*SpawnRequest, error) {
 	if h.manager == nil {
@@ -52,6 +57,7 @@ func (h *SpawnHandler) SpawnChild(parentID string, req *SpawnRequest) (*Recursiv
 	if parent == nil {
 		return nil, ErrFeatureNotFound
 	}
+	req.ParentID = parentID
 
 	parentShort := parentID
 	if len(parentShort) > 8 {
@@ -85,6 +91,7 @@ func (h *SpawnHandler) SpawnChild(parentID string, req *SpawnRequest) (*Recursiv
 			ParentID:      parentID,
 			Depth:         child.Depth,
 			ContextBudget: int64(child.ContextBudget),
+			Group:         child.Group,
 		}
 
 		if err := h.manifest.AddSubFeature(parentID, mf); err != nil {
@@ -164,7 +171,12 @@ func (h *SpawnHandler) CompleteFeature(featureID string, status string, summary
 		return ""
 	}
 
+	resultContext := h.manager.GenerateSpawnResultContext(result)
+
 	feature := h.manager.GetFeature(featureID)
+	if feature != nil {
+		feature.SetResultContext(resultContext)
+	}
 	if feature != nil && !feature.IsRoot() {
 		idShort := featureID
 		if len(idShort) > 8 {
@@ -176,7 +188,7 @@ func (h *SpawnHandler) CompleteFeature(featureID string, status string, summary
 			"tokens", feature.TokenUsage.TotalTokens)
 	}
 
-	return h.manager.GenerateSpawnResultContext(result)
+	return resultContext
 }
 
 // GetFeature returns a feature by ID
@@ -187,6 +199,14 @@ func (h *SpawnHandler) GetFeature(id string) *RecursiveFeature {
 	return h.manager.GetFeature(id)
 }
 
+// SetMaxSpawns configures the spawn caps per root feature and overall
+func (h *SpawnHandler) SetMaxSpawns(perRoot, total int) {
+	if h.manager == nil {
+		return
+	}
+	h.manager.SetMaxSpawns(perRoot, total)
+}
+
 // GetManager returns the underlying RLM manager
 func (h *SpawnHandler) GetManager() *Manager {
 	return h.manager
@@ -236,6 +256,15 @@ func (h *SpawnHandler) BuildChildPrompt(req *SpawnRequest, parentContext string)
 		prompt += "\n"
 	}
 
+	if len(req.ExpectedOutputs) > 0 {
+		prompt += "## Expected Outputs\n"
+		prompt += "The parent feature relies on these. Create them at exactly these paths:\n"
+		for _, output := range req.ExpectedOutputs {
+			prompt += fmt.Sprintf("- %s\n", output)
+		}
+		prompt += "\n"
+	}
+
 	prompt += "## Instructions\n"
 	prompt += "Complete the tasks listed above. When finished, ensure all tests pass.\n\n"
 
@@ -244,9 +273,36 @@ func (h *SpawnHandler) BuildChildPrompt(req *SpawnRequest, parentContext string)
 		prompt += parentContext + "\n"
 	}
 
+	if req.ShareSiblings {
+		if siblings := h.siblingResults(req.ParentID); len(siblings) > 0 {
+			prompt += "\n## Results from Completed Siblings\n"
+			for _, sibling := range siblings {
+				prompt += sibling + "\n"
+			}
+		}
+	}
+
 	return prompt
 }
 
+// siblingResults returns the result contexts of the parent's completed children
+func (h *SpawnHandler) siblingResults(parentID string) []string {
+	if h.manager == nil || parentID == "" {
+		return nil
+	}
+
+	var results []string
+	for _, sibling := range h.manager.GetSubFeatures(parentID) {
+		if sibling.GetStatus() != "completed" {
+			continue
+		}
+		if ctx := sibling.GetResultContext(); ctx != "" {
+			results = append(results, ctx)
+		}
+	}
+	return results
+}
+
 // BuildChildPromptWithBudget creates a budget-aware prompt for a child feature
 // It uses the context package to extract and summarize parent context within budget
 func (h *SpawnHandler) BuildChildPromptWithBudget(req *SpawnRequest, parentContext string, childBudget int64) string {
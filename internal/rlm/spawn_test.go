@@ -2,8 +2,11 @@ package rlm
 
 import (
 	"encoding/json"
+	"errors"
 	"strings"
 	"testing"
+
+	"github.com/vx/ralph-go/internal/manifest"
 )
 
 func TestNewSpawnHandler(t *testing.T) {
@@ -155,6 +158,55 @@ func TestSpawnHandlerSpawnChild(t *testing.T) {
 	}
 }
 
+func TestSpawnHandlerSpawnChildInheritsGroup(t *testing.T) {
+	m := manifest.New("test.md", "Test Project")
+	m.Features = []manifest.ManifestFeature{
+		{ID: "01", Title: "Parent", Group: "Auth"},
+	}
+	mgr := NewManager()
+	handler := NewSpawnHandler(mgr, m)
+	handler.RegisterRootFeature("01", "Parent").SetStatus("running")
+
+	inherited, err := handler.SpawnChild("01", &SpawnRequest{Title: "Login form"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := m.GetFeature(inherited.ID).Group; got != "Auth" {
+		t.Errorf("expected child to inherit group 'Auth', got %q", got)
+	}
+
+	overridden, err := handler.SpawnChild("01", &SpawnRequest{Title: "Audit log", Group: "Compliance"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := m.GetFeature(overridden.ID).Group; got != "Compliance" {
+		t.Errorf("expected the spawn request's group 'Compliance', got %q", got)
+	}
+}
+
+func TestSpawnHandlerSpawnChildRecordsGroupWithoutManifest(t *testing.T) {
+	handler := NewSpawnHandler(NewManager(), nil)
+	root := handler.RegisterRootFeature("01", "Parent")
+	root.Group = "Auth"
+	root.SetStatus("running")
+
+	inherited, err := handler.SpawnChild("01", &SpawnRequest{Title: "Login form"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inherited.Group != "Auth" {
+		t.Errorf("expected child to inherit group 'Auth', got %q", inherited.Group)
+	}
+
+	overridden, err := handler.SpawnChild("01", &SpawnRequest{Title: "Audit log", Group: "Compliance"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if overridden.Group != "Compliance" {
+		t.Errorf("expected the spawn request's group 'Compliance', got %q", overridden.Group)
+	}
+}
+
 func TestSpawnHandlerSpawnChildDepthLimit(t *testing.T) {
 	mgr := NewManagerWithConfig(2, 100000)
 	handler := NewSpawnHandler(mgr, nil)
@@ -180,6 +232,72 @@ func TestSpawnHandlerSpawnChildDepthLimit(t *testing.T) {
 	}
 }
 
+func TestSpawnHandlerSpawnChildPerRootLimit(t *testing.T) {
+	mgr := NewManager()
+	handler := NewSpawnHandler(mgr, nil)
+	handler.SetMaxSpawns(2, 0)
+
+	parent := handler.RegisterRootFeature("01", "Parent")
+	parent.SetStatus("running")
+
+	child1, err := handler.SpawnChild("01", &SpawnRequest{Title: "Child 1"})
+	if err != nil {
+		t.Fatalf("unexpected error spawning child 1: %v", err)
+	}
+	child1.SetStatus("running")
+
+	// Grandchildren count against the same root
+	if _, err := handler.SpawnChild(child1.ID, &SpawnRequest{Title: "Grandchild"}); err != nil {
+		t.Fatalf("unexpected error spawning grandchild: %v", err)
+	}
+
+	_, err = handler.SpawnChild("01", &SpawnRequest{Title: "Child 2"})
+	if !errors.Is(err, ErrMaxSpawnsExceeded) {
+		t.Fatalf("expected ErrMaxSpawnsExceeded, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "limit of 2") {
+		t.Errorf("expected error to mention the limit, got %q", err.Error())
+	}
+	if got := mgr.GetSpawnCount("01"); got != 2 {
+		t.Errorf("expected spawn count 2, got %d", got)
+	}
+	if len(parent.GetSubFeatures()) != 1 {
+		t.Errorf("expected rejected spawn not to be added, got %d sub-features", len(parent.GetSubFeatures()))
+	}
+
+	// Other roots are unaffected by the per-root cap
+	other := handler.RegisterRootFeature("02", "Other")
+	other.SetStatus("running")
+	if _, err := handler.SpawnChild("02", &SpawnRequest{Title: "Child 1"}); err != nil {
+		t.Errorf("unexpected error spawning under other root: %v", err)
+	}
+}
+
+func TestSpawnHandlerSpawnChildTotalLimit(t *testing.T) {
+	mgr := NewManager()
+	handler := NewSpawnHandler(mgr, nil)
+	handler.SetMaxSpawns(0, 2)
+
+	for _, id := range []string{"01", "02", "03"} {
+		handler.RegisterRootFeature(id, "Root "+id).SetStatus("running")
+	}
+
+	if _, err := handler.SpawnChild("01", &SpawnRequest{Title: "Child"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := handler.SpawnChild("02", &SpawnRequest{Title: "Child"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err := handler.SpawnChild("03", &SpawnRequest{Title: "Child"})
+	if !errors.Is(err, ErrMaxSpawnsExceeded) {
+		t.Fatalf("expected ErrMaxSpawnsExceeded, got %v", err)
+	}
+	if got := mgr.GetTotalSpawnCount(); got != 2 {
+		t.Errorf("expected total spawn count 2, got %d", got)
+	}
+}
+
 func TestSpawnHandlerSpawnChildParentNotRunning(t *testing.T) {
 	mgr := NewManager()
 	handler := NewSpawnHandler(mgr, nil)
@@ -321,6 +439,57 @@ func TestSpawnHandlerBuildChildPromptMinimal(t *testing.T) {
 	}
 }
 
+func TestSpawnHandlerBuildChildPromptSharesSiblings(t *testing.T) {
+	handler := NewSpawnHandler(NewManager(), nil)
+	handler.RegisterRootFeature("01", "Parent")
+	handler.SetFeatureRunning("01")
+
+	childA, err := handler.SpawnChild("01", &SpawnRequest{Title: "Child A"})
+	if err != nil {
+		t.Fatalf("failed to spawn child A: %v", err)
+	}
+	handler.CompleteFeature(childA.ID, "completed", "Created internal/api/handler.go")
+
+	reqB := &SpawnRequest{Title: "Child B", ShareSiblings: true}
+	if _, err := handler.SpawnChild("01", reqB); err != nil {
+		t.Fatalf("failed to spawn child B: %v", err)
+	}
+
+	prompt := handler.BuildChildPrompt(reqB, "")
+	if !strings.Contains(prompt, "## Results from Completed Siblings") {
+		t.Error("prompt missing sibling results section")
+	}
+	if !strings.Contains(prompt, "Created internal/api/handler.go") {
+		t.Errorf("prompt missing child A's summary:\n%s", prompt)
+	}
+
+	reqB.ShareSiblings = false
+	prompt = handler.BuildChildPrompt(reqB, "")
+	if strings.Contains(prompt, "Created internal/api/handler.go") {
+		t.Error("sibling results should only be shared when requested")
+	}
+}
+
+func TestSpawnHandlerExpectedOutputs(t *testing.T) {
+	handler := NewSpawnHandler(NewManager(), nil)
+	handler.RegisterRootFeature("01", "Parent")
+	handler.SetFeatureRunning("01")
+
+	req := &SpawnRequest{Title: "Token parsing", ExpectedOutputs: []string{"internal/auth/token.go"}}
+	child, err := handler.SpawnChild("01", req)
+	if err != nil {
+		t.Fatalf("failed to spawn child: %v", err)
+	}
+	if got := child.GetExpectedOutputs(); len(got) != 1 || got[0] != "internal/auth/token.go" {
+		t.Errorf("expected the child to keep its expected outputs, got %v", got)
+	}
+
+	prompt := handler.BuildChildPrompt(req, "")
+	if !strings.Contains(prompt, "## Expected Outputs") || !strings.Contains(prompt, "- internal/auth/token.go") {
+		t.Errorf("prompt missing expected outputs:\n%s", prompt)
+	}
+}
+
 func TestSpawnHandlerNilManager(t *testing.T) {
 	handler := NewSpawnHandler(nil, nil)
 
@@ -9,6 +9,9 @@ const (
 	DefaultMaxDepth      = 5
 	DefaultContextBudget = 100000
 	MinContextBudget     = 10000 // Minimum budget at any depth
+
+	DefaultMaxSpawnsPerRoot = 20  // Max descendants spawned under a single root feature
+	DefaultMaxTotalSpawns   = 100 // Max descendants spawned across all root features
 )
 
 // IsolationLevel determines how child failures affect parent features
@@ -106,11 +109,20 @@ type RecursiveFeature struct {
 
 	Model         string `json:"model,omitempty"`
 	ExecutionMode string `json:"execution_mode,omitempty"`
+	// Group is the epic or group the feature is filed under, which its
+	// sub-features inherit unless their spawn request sets another
+	Group string `json:"group,omitempty"`
 
 	// Fault isolation fields
 	IsolationLevel IsolationLevel `json:"isolation_level,omitempty"`
 	FailureInfo    *FailureInfo   `json:"failure_info,omitempty"`
 	FailedChildren []string       `json:"failed_children,omitempty"`
+
+	// ResultContext is the result context reported to the parent on completion
+	ResultContext string `json:"result_context,omitempty"`
+
+	// ExpectedOutputs are the files the spawn request asked the child for
+	ExpectedOutputs []string `json:"expected_outputs,omitempty"`
 }
 
 // RecursiveTask represents a task within a recursive feature
@@ -149,6 +161,17 @@ type SpawnRequest struct {
 	Model       string   `json:"model,omitempty"`
 	MaxDepth    int      `json:"max_depth,omitempty"`
 	Description string   `json:"description,omitempty"`
+	// ShareSiblings includes the results of completed siblings in the child prompt
+	ShareSiblings bool `json:"share_siblings,omitempty"`
+	// ExpectedOutputs are the files the parent needs from the child, such as
+	// "internal/auth/token.go"; the child's summary reports on them first
+	ExpectedOutputs []string `json:"expected_outputs,omitempty"`
+	// Group files the child under an epic or group other than its parent's.
+	// It is recorded on the child's RecursiveFeature, and in manifest mode on
+	// its manifest entry too.
+	Group string `json:"group,omitempty"`
+	// ParentID is set by SpawnChild so the prompt builder can find siblings
+	ParentID string `json:"-"`
 }
 
 // SpawnResult contains the outcome of a spawned sub-feature
@@ -204,6 +227,7 @@ func (f *RecursiveFeature) NewChildFeature(id, title string) (*RecursiveFeature,
 		SubFeatures:   make([]*RecursiveFeature, 0),
 		Model:         f.Model,
 		ExecutionMode: f.ExecutionMode,
+		Group:         f.Group,
 	}
 
 	return child, nil
@@ -298,6 +322,29 @@ func (f *RecursiveFeature) GetStatus() string {
 	return f.Status
 }
 
+// SetResultContext records the result context generated on completion
+func (f *RecursiveFeature) SetResultContext(context string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ResultContext = context
+}
+
+// GetResultContext returns the result context recorded on completion
+func (f *RecursiveFeature) GetResultContext() string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.ResultContext
+}
+
+// GetExpectedOutputs returns the files the parent asked the feature for
+func (f *RecursiveFeature) GetExpectedOutputs() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	outputs := make([]string, len(f.ExpectedOutputs))
+	copy(outputs, f.ExpectedOutputs)
+	return outputs
+}
+
 // GetContextBudget returns the context budget for this feature
 func (f *RecursiveFeature) GetContextBudget() int64 {
 	f.mu.RLock()
@@ -0,0 +1,68 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+)
+
+// reservedArgs are claude flags ralph sets itself; extra args may not override them
+var reservedArgs = map[string]bool{
+	"-p":                             true,
+	"--print":                        true,
+	"--output-format":                true,
+	"--input-format":                 true,
+	"--verbose":                      true,
+	"--dangerously-skip-permissions": true,
+	"--model":                        true,
+	"--resume":                       true,
+}
+
+// ValidateExtraArgs returns an error if any extra arg conflicts with the
+// flags ralph passes to claude
+func ValidateExtraArgs(args []string) error {
+	for _, arg := range args {
+		flag := arg
+		if i := strings.Index(flag, "="); i >= 0 {
+			flag = flag[:i]
+		}
+		if reservedArgs[flag] {
+			return fmt.Errorf("extra claude arg %s conflicts with a flag set by ralph", flag)
+		}
+	}
+	return nil
+}
+
+// SetExtraArgs sets additional flags passed to every claude instance, inserted
+// before the prompt. Args that conflict with ralph's own flags are rejected.
+func (m *Manager) SetExtraArgs(args []string) error {
+	if err := ValidateExtraArgs(args); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.extraArgs = append([]string(nil), args...)
+	return nil
+}
+
+// buildArgs constructs the claude command line for a prompt, resuming
+// resumeSessionID when it is set
+func (m *Manager) buildArgs(model string, prompt string, resumeSessionID string) []string {
+	args := []string{
+		"--dangerously-skip-permissions",
+		"--verbose",
+		"--output-format", "stream-json",
+	}
+
+	if model != "" && model != "sonnet" {
+		args = append(args, "--model", model)
+	}
+
+	if resumeSessionID != "" {
+		args = append(args, "--resume", resumeSessionID)
+	}
+
+	args = append(args, m.extraArgs...)
+	args = append(args, "-p", prompt)
+	return args
+}
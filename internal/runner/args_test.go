@@ -0,0 +1,81 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildArgsIncludesExtraArgsBeforePrompt(t *testing.T) {
+	mgr := NewManager("/tmp")
+	if err := mgr.SetExtraArgs([]string{"--mcp-config", "mcp.json", "--add-dir", "../shared"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	args := mgr.buildArgs("opus", "do the thing", "")
+
+	expected := []string{
+		"--dangerously-skip-permissions",
+		"--verbose",
+		"--output-format", "stream-json",
+		"--model", "opus",
+		"--mcp-config", "mcp.json",
+		"--add-dir", "../shared",
+		"-p", "do the thing",
+	}
+	if len(args) != len(expected) {
+		t.Fatalf("expected %d args, got %d: %v", len(expected), len(args), args)
+	}
+	for i := range expected {
+		if args[i] != expected[i] {
+			t.Errorf("arg %d: expected %q, got %q", i, expected[i], args[i])
+		}
+	}
+}
+
+func TestBuildArgsWithoutExtraArgs(t *testing.T) {
+	mgr := NewManager("/tmp")
+
+	args := mgr.buildArgs("sonnet", "prompt", "")
+
+	if len(args) != 6 {
+		t.Fatalf("expected 6 args, got %d: %v", len(args), args)
+	}
+	if args[4] != "-p" || args[5] != "prompt" {
+		t.Errorf("expected prompt to be last, got %v", args)
+	}
+}
+
+func TestBuildArgsResumesSession(t *testing.T) {
+	mgr := NewManager("/tmp")
+
+	args := mgr.buildArgs("sonnet", "prompt", "sess-123")
+
+	joined := strings.Join(args, " ")
+	if !strings.Contains(joined, "--resume sess-123") {
+		t.Errorf("expected --resume sess-123 in args, got %v", args)
+	}
+	if args[len(args)-2] != "-p" || args[len(args)-1] != "prompt" {
+		t.Errorf("expected prompt to be last, got %v", args)
+	}
+}
+
+func TestSetExtraArgsRejectsReservedFlags(t *testing.T) {
+	tests := [][]string{
+		{"--output-format", "json"},
+		{"--output-format=json"},
+		{"--model", "haiku"},
+		{"-p", "other prompt"},
+		{"--add-dir", "x", "--verbose"},
+		{"--resume", "abc"},
+	}
+
+	for _, args := range tests {
+		mgr := NewManager("/tmp")
+		if err := mgr.SetExtraArgs(args); err == nil {
+			t.Errorf("expected error for %v", args)
+		}
+		if len(mgr.extraArgs) != 0 {
+			t.Errorf("expected rejected args not to be set, got %v", mgr.extraArgs)
+		}
+	}
+}
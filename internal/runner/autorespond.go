@@ -0,0 +1,105 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/vx/ralph-go/internal/logger"
+)
+
+// AutoResponse answers an approval prompt that a tool prints even with
+// --dangerously-skip-permissions: a line of output matching Pattern gets
+// Response, followed by a newline, written to the process's stdin
+type AutoResponse struct {
+	Pattern  *regexp.Regexp
+	Response string
+}
+
+// ParseAutoResponse parses "PATTERN=RESPONSE", splitting at the last "=" so
+// the pattern may contain one. PATTERN is a regular expression.
+func ParseAutoResponse(spec string) (AutoResponse, error) {
+	i := strings.LastIndex(spec, "=")
+	if i <= 0 {
+		return AutoResponse{}, fmt.Errorf("invalid auto-response %q: must be PATTERN=RESPONSE", spec)
+	}
+	pattern, err := regexp.Compile(spec[:i])
+	if err != nil {
+		return AutoResponse{}, fmt.Errorf("invalid auto-response pattern %q: %w", spec[:i], err)
+	}
+	return AutoResponse{Pattern: pattern, Response: spec[i+1:]}, nil
+}
+
+// SetAutoResponses sets the prompts answered on every instance's stdin. With
+// none, instances get no stdin pipe.
+func (m *Manager) SetAutoResponses(responses []AutoResponse) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.autoResponses = append([]AutoResponse(nil), responses...)
+}
+
+// stdinPiper is implemented by executors that can give the process a stdin
+// pipe. Like Pipes, StdinPipe must be called before Start.
+type stdinPiper interface {
+	StdinPipe() (io.WriteCloser, error)
+}
+
+func (e *cmdExecutor) StdinPipe() (io.WriteCloser, error) {
+	return e.cmd.StdinPipe()
+}
+
+// errStdinClosed is returned for a prompt matched after stdin was closed
+var errStdinClosed = errors.New("stdin already closed")
+
+// autoResponder writes the response to the first auto-response whose
+// pattern matches an output line, for every match while the process runs.
+// stdin is closed once claude reports its result, or its output ends, so a
+// process reading stdin to EOF isn't left waiting on ralph for more.
+type autoResponder struct {
+	mu        sync.Mutex
+	stdin     io.WriteCloser
+	responses []AutoResponse
+	closed    bool
+}
+
+// respond answers line if it matches a pattern, returning the pattern that
+// matched
+func (r *autoResponder) respond(line string) (string, error) {
+	for _, resp := range r.responses {
+		if !resp.Pattern.MatchString(line) {
+			continue
+		}
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if r.closed {
+			return resp.Pattern.String(), errStdinClosed
+		}
+		_, err := io.WriteString(r.stdin, resp.Response+"\n")
+		return resp.Pattern.String(), err
+	}
+	return "", nil
+}
+
+// close closes stdin, once
+func (r *autoResponder) close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	return r.stdin.Close()
+}
+
+// closeStdin closes the instance's stdin if it has an auto-responder
+func (inst *Instance) closeStdin() {
+	if inst.responder == nil {
+		return
+	}
+	if err := inst.responder.close(); err != nil {
+		logger.Warn("runner", "Failed to close stdin", "featureID", inst.FeatureID, "error", err)
+	}
+}
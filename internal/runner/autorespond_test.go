@@ -0,0 +1,129 @@
+package runner
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestParseAutoResponse(t *testing.T) {
+	resp, err := ParseAutoResponse(`Overwrite \S+\? \[y/N\]=y`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Response != "y" || !resp.Pattern.MatchString("Overwrite go.sum? [y/N]") {
+		t.Errorf("unexpected auto-response %q -> %q", resp.Pattern, resp.Response)
+	}
+
+	if resp, err := ParseAutoResponse("key=value=yes"); err != nil || resp.Pattern.String() != "key=value" || resp.Response != "yes" {
+		t.Errorf("expected a split at the last =, got %q -> %q (%v)", resp.Pattern, resp.Response, err)
+	}
+	for _, spec := range []string{"no separator", "=y", "([=y"} {
+		if _, err := ParseAutoResponse(spec); err == nil {
+			t.Errorf("expected an error for %q", spec)
+		}
+	}
+}
+
+func TestAutoResponderUnblocksPrompt(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	// Stands in for a tool that stops to ask for approval and waits on stdin
+	script := `echo '{"type":"system","subtype":"init","session_id":"sess-1"}'
+echo 'Allow this command to run? [y/N]'
+read answer
+echo "{\"type\":\"assistant\",\"message\":{\"content\":\"approved: $answer\"}}"
+echo '{"type":"result","subtype":"success","result":"done"}'
+`
+	m := NewManager(t.TempDir())
+	m.SetExecutorFactory(func(ctx context.Context, dir string, args []string) Executor {
+		cmd := exec.CommandContext(ctx, "sh", "-c", script)
+		cmd.Dir = dir
+		return &cmdExecutor{cmd: cmd}
+	})
+	resp, err := ParseAutoResponse(`Allow this command to run\? \[y/N\]=y`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.SetAutoResponses([]AutoResponse{resp})
+
+	inst, err := m.StartInstance("01", "sonnet", "Run the migration")
+	if err != nil {
+		t.Fatalf("StartInstance failed: %v", err)
+	}
+	waitForDone(t, inst)
+
+	if got := inst.GetStatus(); got != "completed" {
+		t.Errorf("expected status completed, got %q (error %q)", got, inst.GetError())
+	}
+	if output := inst.GetOutput(); !strings.Contains(output, "approved: y") {
+		t.Errorf("expected the prompt to be answered with y, got output:\n%s", output)
+	}
+}
+
+func TestAutoResponderAnswersRepeatedPrompt(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	// The same prompt comes up twice and has to be answered both times
+	script := `echo 'Continue? [y/N]'
+read first
+echo 'Continue? [y/N]'
+read second
+echo "{\"type\":\"assistant\",\"message\":{\"content\":\"answers: $first $second\"}}"
+echo '{"type":"result","subtype":"success","result":"done"}'
+`
+	m := NewManager(t.TempDir())
+	m.SetExecutorFactory(func(ctx context.Context, dir string, args []string) Executor {
+		return &cmdExecutor{cmd: exec.CommandContext(ctx, "sh", "-c", script)}
+	})
+	resp, err := ParseAutoResponse(`Continue\? \[y/N\]=y`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.SetAutoResponses([]AutoResponse{resp})
+
+	inst, err := m.StartInstance("01", "sonnet", "Run the migration")
+	if err != nil {
+		t.Fatalf("StartInstance failed: %v", err)
+	}
+	waitForDone(t, inst)
+
+	if got := inst.GetStatus(); got != "completed" {
+		t.Errorf("expected status completed, got %q (error %q)", got, inst.GetError())
+	}
+	if output := inst.GetOutput(); !strings.Contains(output, "answers: y y") {
+		t.Errorf("expected both prompts to be answered with y, got output:\n%s", output)
+	}
+}
+
+func TestAutoResponderClosesStdinOnResult(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	// Reports its result, then reads stdin to EOF before exiting
+	script := `echo '{"type":"result","subtype":"success","result":"done"}'
+cat >/dev/null
+`
+	m := NewManager(t.TempDir())
+	m.SetExecutorFactory(func(ctx context.Context, dir string, args []string) Executor {
+		return &cmdExecutor{cmd: exec.CommandContext(ctx, "sh", "-c", script)}
+	})
+	resp, err := ParseAutoResponse(`Never asked\?=y`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.SetAutoResponses([]AutoResponse{resp})
+
+	inst, err := m.StartInstance("01", "sonnet", "Run the migration")
+	if err != nil {
+		t.Fatalf("StartInstance failed: %v", err)
+	}
+	waitForDone(t, inst)
+
+	if got := inst.GetStatus(); got != "completed" {
+		t.Errorf("expected status completed once stdin closed, got %q (error %q)", got, inst.GetError())
+	}
+}
@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ErrCheckoutBusy is returned when a feature's Base can't be checked out
+// because other features are running in the same work dir
+var ErrCheckoutBusy = errors.New("other features are running in the work dir")
+
+// workDirInstances counts the instances running in each work dir across
+// every manager, since headless parallel features each have a manager of
+// their own. A checkout would switch the tree under all of them.
+var (
+	workDirMu        sync.Mutex
+	workDirInstances = make(map[string]int)
+)
+
+// gitCheckout checks out ref in workDir. It is a variable so tests can run
+// without a git repository.
+var gitCheckout = func(workDir, ref string) error {
+	cmd := exec.Command("git", "checkout", ref)
+	cmd.Dir = workDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git checkout %s failed: %s", ref, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// claimWorkDir counts an instance as running in workDir, first checking out
+// ref if it isn't empty. The checkout is refused with ErrCheckoutBusy while
+// any other instance runs there.
+func claimWorkDir(workDir, ref string) error {
+	key := filepath.Clean(workDir)
+	workDirMu.Lock()
+	defer workDirMu.Unlock()
+	if ref != "" {
+		if n := workDirInstances[key]; n > 0 {
+			return fmt.Errorf("can't check out %s: %w (%d)", ref, ErrCheckoutBusy, n)
+		}
+		if err := gitCheckout(workDir, ref); err != nil {
+			return err
+		}
+	}
+	workDirInstances[key]++
+	return nil
+}
+
+// releaseWorkDir stops counting an instance claimed with claimWorkDir
+func releaseWorkDir(workDir string) {
+	key := filepath.Clean(workDir)
+	workDirMu.Lock()
+	defer workDirMu.Unlock()
+	if workDirInstances[key]--; workDirInstances[key] <= 0 {
+		delete(workDirInstances, key)
+	}
+}
+
+// SetCheckoutBase enables checking out a feature's Base commit or tag in the
+// work dir before its instance starts. The feature fails to start, rather
+// than switching the tree under them, while other features are running.
+func (m *Manager) SetCheckoutBase(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.checkoutBase = enabled
+}
@@ -0,0 +1,92 @@
+package runner
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func stubGitCheckout(t *testing.T, err error) *[]string {
+	t.Helper()
+	var refs []string
+	orig := gitCheckout
+	gitCheckout = func(workDir, ref string) error {
+		refs = append(refs, ref)
+		return err
+	}
+	t.Cleanup(func() { gitCheckout = orig })
+	return &refs
+}
+
+func TestStartInstanceChecksOutBase(t *testing.T) {
+	// An empty PATH makes the claude start fail after the checkout
+	t.Setenv("PATH", t.TempDir())
+
+	t.Run("checks out when enabled", func(t *testing.T) {
+		refs := stubGitCheckout(t, nil)
+		mgr := NewManager(t.TempDir())
+		mgr.SetCheckoutBase(true)
+
+		mgr.StartInstanceWithOptions("01", "sonnet", "prompt", StartInstanceOptions{Base: "v1.2.0"})
+
+		if len(*refs) != 1 || (*refs)[0] != "v1.2.0" {
+			t.Errorf("expected checkout of v1.2.0, got %v", *refs)
+		}
+	})
+
+	t.Run("skips checkout when disabled", func(t *testing.T) {
+		refs := stubGitCheckout(t, nil)
+		mgr := NewManager(t.TempDir())
+
+		mgr.StartInstanceWithOptions("01", "sonnet", "prompt", StartInstanceOptions{Base: "v1.2.0"})
+
+		if len(*refs) != 0 {
+			t.Errorf("expected no checkout, got %v", *refs)
+		}
+	})
+
+	t.Run("fails the start when checkout fails", func(t *testing.T) {
+		stubGitCheckout(t, errors.New("git checkout v9 failed: unknown revision"))
+		mgr := NewManager(t.TempDir())
+		mgr.SetCheckoutBase(true)
+
+		_, err := mgr.StartInstanceWithOptions("01", "sonnet", "prompt", StartInstanceOptions{Base: "v9"})
+		if err == nil || err.Error() != "git checkout v9 failed: unknown revision" {
+			t.Errorf("expected checkout error, got %v", err)
+		}
+	})
+	t.Run("refuses while another feature runs in the work dir", func(t *testing.T) {
+		refs := stubGitCheckout(t, nil)
+		running := newFakeManager(t, &fakeExecutor{block: true})
+		inst, err := running.StartInstance("01", "sonnet", "prompt")
+		if err != nil {
+			t.Fatalf("StartInstance: %v", err)
+		}
+
+		// A second manager on the same work dir, as headless parallel
+		// features have
+		mgr := NewManager(running.workDir)
+		mgr.SetCheckoutBase(true)
+		_, err = mgr.StartInstanceWithOptions("02", "sonnet", "prompt", StartInstanceOptions{Base: "v1.2.0"})
+		if !errors.Is(err, ErrCheckoutBusy) {
+			t.Errorf("expected ErrCheckoutBusy, got %v", err)
+		}
+		if len(*refs) != 0 {
+			t.Errorf("expected no checkout while 01 runs, got %v", *refs)
+		}
+
+		running.StopInstance("01")
+		waitForDone(t, inst)
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			mgr.StartInstanceWithOptions("02", "sonnet", "prompt", StartInstanceOptions{Base: "v1.2.0"})
+			if len(*refs) == 1 || time.Now().After(deadline) {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if len(*refs) != 1 {
+			t.Errorf("expected checkout once 01 stopped, got %v", *refs)
+		}
+	})
+}
@@ -34,6 +34,12 @@ type ChildExecutor struct {
 	// Tracks skipped children per parent
 	skippedChildren map[string][]string
 
+	// Tracks parent → max concurrently running children (0 = no limit)
+	childLimits map[string]int
+
+	// Tracks parent → spawned children waiting for a running one to finish
+	queuedChildren map[string][]*QueuedChild
+
 	// Callback when child completes
 	onChildComplete func(parentID string, result *rlm.SpawnResult)
 
@@ -52,7 +58,117 @@ func NewChildExecutor(manager *Manager, spawnHandler *rlm.SpawnHandler) *ChildEx
 		pausedParents:   make(map[string]bool),
 		failedChildren:  make(map[string][]*rlm.ChildFailureResult),
 		skippedChildren: make(map[string][]string),
+		childLimits:     make(map[string]int),
+		queuedChildren:  make(map[string][]*QueuedChild),
+	}
+}
+
+// QueuedChild is a spawned child waiting for its parent's concurrency limit
+type QueuedChild struct {
+	ParentID string
+	Child    *rlm.RecursiveFeature
+	Prompt   string
+}
+
+// SetChildLimit caps how many of a parent's children run at once, from its
+// Max-Children-Concurrent:. A limit of 0 removes the cap.
+func (ce *ChildExecutor) SetChildLimit(parentID string, limit int) {
+	ce.mu.Lock()
+	defer ce.mu.Unlock()
+	if limit <= 0 {
+		delete(ce.childLimits, parentID)
+		return
+	}
+	ce.childLimits[parentID] = limit
+}
+
+// GetChildLimit returns the cap on a parent's running children, or 0
+func (ce *ChildExecutor) GetChildLimit(parentID string) int {
+	ce.mu.RLock()
+	defer ce.mu.RUnlock()
+	return ce.childLimits[parentID]
+}
+
+// hasChildSlotUnlocked reports whether another of parentID's children may
+// start under its limit
+func (ce *ChildExecutor) hasChildSlotUnlocked(parentID string) bool {
+	limit := ce.childLimits[parentID]
+	return limit <= 0 || len(ce.runningChildren[parentID]) < limit
+}
+
+// AdmitChild tracks a spawned child as running if its parent's limit allows
+// and returns true, so the caller starts it. Otherwise the child is queued
+// until ReleaseChild frees a slot.
+func (ce *ChildExecutor) AdmitChild(parentID string, child *rlm.RecursiveFeature, prompt string) bool {
+	ce.mu.Lock()
+	defer ce.mu.Unlock()
+
+	if !ce.hasChildSlotUnlocked(parentID) {
+		ce.queuedChildren[parentID] = append(ce.queuedChildren[parentID], &QueuedChild{
+			ParentID: parentID,
+			Child:    child,
+			Prompt:   prompt,
+		})
+		childShort := child.ID
+		if len(childShort) > 8 {
+			childShort = childShort[:8]
+		}
+		parentShort := parentID
+		if len(parentShort) > 8 {
+			parentShort = parentShort[:8]
+		}
+		logger.Info("runner", "Child queued at parent's limit",
+			"childID", childShort,
+			"parentID", parentShort,
+			"limit", ce.childLimits[parentID])
+		return false
+	}
+
+	ce.runningChildren[parentID] = append(ce.runningChildren[parentID], child.ID)
+	ce.childToParent[child.ID] = parentID
+	return true
+}
+
+// ReleaseChild stops tracking a child that finished or failed to start. If
+// that frees a slot for a queued sibling, the sibling is tracked as running
+// and returned for the caller to start.
+func (ce *ChildExecutor) ReleaseChild(childID string) *QueuedChild {
+	ce.mu.Lock()
+	defer ce.mu.Unlock()
+
+	parentID, ok := ce.childToParent[childID]
+	if !ok {
+		return nil
+	}
+	children := ce.runningChildren[parentID]
+	for i, id := range children {
+		if id == childID {
+			ce.runningChildren[parentID] = append(children[:i], children[i+1:]...)
+			break
+		}
+	}
+	delete(ce.childToParent, childID)
+
+	queued := ce.queuedChildren[parentID]
+	if len(queued) == 0 || !ce.hasChildSlotUnlocked(parentID) {
+		return nil
 	}
+	next := queued[0]
+	ce.queuedChildren[parentID] = queued[1:]
+	ce.runningChildren[parentID] = append(ce.runningChildren[parentID], next.Child.ID)
+	ce.childToParent[next.Child.ID] = parentID
+	return next
+}
+
+// GetQueuedChildren returns the IDs of a parent's children waiting to start
+func (ce *ChildExecutor) GetQueuedChildren(parentID string) []string {
+	ce.mu.RLock()
+	defer ce.mu.RUnlock()
+	var ids []string
+	for _, q := range ce.queuedChildren[parentID] {
+		ids = append(ids, q.Child.ID)
+	}
+	return ids
 }
 
 // SetOnChildComplete sets the callback for when a child completes
@@ -100,7 +216,16 @@ func (ce *ChildExecutor) StartPendingChildren(parentID string) (int, error) {
 	ce.mu.Unlock()
 
 	started := 0
-	for _, req := range pending {
+	for i, req := range pending {
+		ce.mu.Lock()
+		if !ce.hasChildSlotUnlocked(parentID) {
+			// Leave the rest pending until running children finish
+			ce.pendingChildren[parentID] = append(append([]*rlm.SpawnRequest{}, pending[i:]...), ce.pendingChildren[parentID]...)
+			ce.mu.Unlock()
+			break
+		}
+		ce.mu.Unlock()
+
 		child, err := ce.startChild(parentID, req)
 		if err != nil {
 			parentShort := parentID
@@ -434,6 +559,8 @@ func (ce *ChildExecutor) ClearParent(parentID string) {
 	delete(ce.pausedParents, parentID)
 	delete(ce.failedChildren, parentID)
 	delete(ce.skippedChildren, parentID)
+	delete(ce.childLimits, parentID)
+	delete(ce.queuedChildren, parentID)
 
 	// Clear result contexts
 	resultContexts.Lock()
@@ -832,3 +832,43 @@ func TestChildExecutorRetryChildNoParent(t *testing.T) {
 		t.Error("expected error for unknown child")
 	}
 }
+
+func TestChildExecutorQueuesChildrenBeyondLimit(t *testing.T) {
+	mgr := NewManager("/tmp")
+	rlmMgr := rlm.NewManager()
+	spawnHandler := rlm.NewSpawnHandler(rlmMgr, nil)
+	ce := NewChildExecutor(mgr, spawnHandler)
+
+	parentID := "parent-limit"
+	ce.SetChildLimit(parentID, 2)
+
+	children := []*rlm.RecursiveFeature{{ID: "child-1"}, {ID: "child-2"}, {ID: "child-3"}}
+	for i, child := range children {
+		admitted := ce.AdmitChild(parentID, child, "prompt")
+		if want := i < 2; admitted != want {
+			t.Errorf("child %d: expected admitted=%v, got %v", i+1, want, admitted)
+		}
+	}
+	if queued := ce.GetQueuedChildren(parentID); len(queued) != 1 || queued[0] != "child-3" {
+		t.Fatalf("expected child-3 to be queued, got %v", queued)
+	}
+	if running := ce.GetRunningChildren(parentID); len(running) != 2 {
+		t.Errorf("expected 2 running children, got %v", running)
+	}
+
+	next := ce.ReleaseChild("child-1")
+	if next == nil || next.Child.ID != "child-3" || next.ParentID != parentID || next.Prompt != "prompt" {
+		t.Fatalf("expected child-3 to start once child-1 finished, got %+v", next)
+	}
+	if queued := ce.GetQueuedChildren(parentID); len(queued) != 0 {
+		t.Errorf("expected the queue to be empty, got %v", queued)
+	}
+	if next := ce.ReleaseChild("child-2"); next != nil {
+		t.Errorf("expected nothing left to start, got %+v", next)
+	}
+
+	ce.SetChildLimit(parentID, 0)
+	if !ce.AdmitChild(parentID, &rlm.RecursiveFeature{ID: "child-4"}, "") || !ce.AdmitChild(parentID, &rlm.RecursiveFeature{ID: "child-5"}, "") {
+		t.Error("expected children to start freely without a limit")
+	}
+}
@@ -0,0 +1,77 @@
+package runner
+
+import "strings"
+
+// Lengths, in bytes, that output lines are clipped to for display unless
+// ClipLimits say otherwise
+const (
+	DefaultAssistantClip  = 200
+	DefaultToolResultClip = 500
+)
+
+// ClipLimits are the lengths assistant text and tool results are clipped to
+// for display. 0 clips at the default; negative keeps the full content.
+type ClipLimits struct {
+	Assistant  int
+	ToolResult int
+}
+
+// limit returns the length lines of lineType are clipped to, or 0 for none
+func (l ClipLimits) limit(lineType string) int {
+	limit, fallback := 0, 0
+	switch lineType {
+	case "assistant":
+		limit, fallback = l.Assistant, DefaultAssistantClip
+	case "tool_result":
+		limit, fallback = l.ToolResult, DefaultToolResultClip
+	default:
+		return 0
+	}
+	switch {
+	case limit < 0:
+		return 0
+	case limit == 0:
+		return fallback
+	}
+	return limit
+}
+
+// SetClipLimits sets how much of each output line instances started from
+// now on keep for display. The full line stays available from FullContent.
+func (m *Manager) SetClipLimits(limits ClipLimits) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clipLimits = limits
+}
+
+// clipContent shortens long assistant text and tool results for display,
+// keeping the full content for FullContent
+func clipContent(outputLine *OutputLine, limits ClipLimits) {
+	limit := limits.limit(outputLine.Type)
+	if limit > 0 && len(outputLine.Content) > limit {
+		outputLine.full = outputLine.Content
+		outputLine.Content = outputLine.Content[:limit] + "..."
+	}
+}
+
+// FullContent returns the line's content without clipping
+func FullContent(line OutputLine) string {
+	if line.full != "" {
+		return line.full
+	}
+	return line.Content
+}
+
+// GetFullOutput is GetOutput with every line's full, unclipped content
+func (inst *Instance) GetFullOutput() string {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+
+	var sb strings.Builder
+	for _, line := range inst.output {
+		line.Content = FullContent(line)
+		sb.WriteString(FormatOutputLine(line))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,58 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClipLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		limits   ClipLimits
+		lineType string
+		want     int
+	}{
+		{"assistant default", ClipLimits{}, "assistant", DefaultAssistantClip},
+		{"tool result default", ClipLimits{}, "tool_result", DefaultToolResultClip},
+		{"assistant configured", ClipLimits{Assistant: 50}, "assistant", 50},
+		{"tool result configured", ClipLimits{ToolResult: 1000}, "tool_result", 1000},
+		{"clipping off", ClipLimits{Assistant: -1, ToolResult: -1}, "assistant", 0},
+		{"other types never clipped", ClipLimits{Assistant: 10, ToolResult: 10}, "system", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.limits.limit(tt.lineType); got != tt.want {
+				t.Errorf("limit(%q) = %d, want %d", tt.lineType, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadOutputClipsToConfiguredLimits(t *testing.T) {
+	inst := newTestInstance(nil)
+	inst.clipLimits = ClipLimits{ToolResult: 10}
+
+	result := strings.Repeat("x", 40)
+	inst.readOutput(strings.NewReader(`{"type":"tool_result","result":"`+result+`"}`+"\n"), "stdout")
+
+	lines := inst.GetOutputLines()
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 output line, got %d", len(lines))
+	}
+	if want := strings.Repeat("x", 10) + "..."; lines[0].Content != want {
+		t.Errorf("expected content clipped to 10, got %q", lines[0].Content)
+	}
+	if got := FullContent(lines[0]); got != result {
+		t.Errorf("expected full content from the raw line, got %q", got)
+	}
+	if full := inst.GetFullOutput(); !strings.Contains(full, "tool_result: "+result+"\n") {
+		t.Errorf("expected full output to show the whole result, got %q", full)
+	}
+}
+
+func TestFullContentWithoutRaw(t *testing.T) {
+	line := OutputLine{Type: "ralph", Subtype: "verify_failed", Content: "verification failed"}
+	if got := FullContent(line); got != "verification failed" {
+		t.Errorf("expected content of a line without raw output, got %q", got)
+	}
+}
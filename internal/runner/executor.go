@@ -0,0 +1,136 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"time"
+
+	"github.com/vx/ralph-go/internal/logger"
+)
+
+// outputWaitDelay is how long a claude process that has exited, or was
+// killed, may leave its output open before it is closed under whatever
+// still holds it, such as a background process claude started
+const outputWaitDelay = 5 * time.Second
+
+// Executor runs the claude process behind an instance. Pipes is called once
+// before Start; Wait blocks until the process exits and returns an error with
+// an ExitCode() int method, like *exec.ExitError, when it exits non-zero.
+type Executor interface {
+	Pipes() (stdout io.Reader, stderr io.Reader, err error)
+	Start() error
+	Wait() error
+}
+
+// ExecutorFactory creates the executor that runs claude with args in dir,
+// killing it when ctx is cancelled
+type ExecutorFactory func(ctx context.Context, dir string, args []string) Executor
+
+// SetExecutorFactory replaces how instances run claude, so tests can replay
+// canned stream-json instead. nil restores the default of running the claude
+// binary.
+func (m *Manager) SetExecutorFactory(factory ExecutorFactory) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.newExecutor = factory
+}
+
+// cmdExecutor runs claude as a child process. The process is waited on as
+// soon as it starts, and its output closed once it exits, so readers reach
+// EOF even when a process it started holds on to stdout.
+type cmdExecutor struct {
+	cmd     *exec.Cmd
+	outputs []*io.PipeWriter
+	done    chan struct{}
+	err     error
+}
+
+func newCmdExecutor(ctx context.Context, dir string, args []string) Executor {
+	cmd := exec.CommandContext(ctx, "claude", args...)
+	cmd.Dir = dir
+	return &cmdExecutor{cmd: cmd}
+}
+
+func (e *cmdExecutor) Pipes() (io.Reader, io.Reader, error) {
+	stdout, stdoutW := io.Pipe()
+	stderr, stderrW := io.Pipe()
+	e.cmd.Stdout = stdoutW
+	e.cmd.Stderr = stderrW
+	e.outputs = []*io.PipeWriter{stdoutW, stderrW}
+	return stdout, stderr, nil
+}
+
+func (e *cmdExecutor) Start() error {
+	if e.cmd.WaitDelay == 0 {
+		e.cmd.WaitDelay = outputWaitDelay
+	}
+	if err := e.cmd.Start(); err != nil {
+		e.closeOutputs()
+		return err
+	}
+	e.done = make(chan struct{})
+	go func() {
+		e.err = e.cmd.Wait()
+		e.closeOutputs()
+		close(e.done)
+	}()
+	return nil
+}
+
+// errOutputHeld is returned by Wait when the process exited cleanly but
+// something still held its output open past outputWaitDelay, so the output
+// was closed under it and anything written after that was lost
+var errOutputHeld = errors.New("output still held open after claude exited")
+
+// Wait returns once the process has exited and its output is closed. A
+// process that exited cleanly but left its output held open past
+// outputWaitDelay returns errOutputHeld.
+func (e *cmdExecutor) Wait() error {
+	if e.done == nil {
+		return errors.New("claude was not started")
+	}
+	<-e.done
+	if errors.Is(e.err, exec.ErrWaitDelay) {
+		return errOutputHeld
+	}
+	return e.err
+}
+
+// reportHeldOutput notes, in the log and the output, that claude's output
+// was closed under a process that still held it, so whatever that process
+// wrote afterwards is missing
+func (inst *Instance) reportHeldOutput() {
+	logger.Warn("runner", "Closed output held open after claude exited; later output is lost",
+		"featureID", inst.FeatureID,
+		"waitDelay", outputWaitDelay)
+	inst.emitOutput(OutputLine{
+		Timestamp: time.Now(),
+		Type:      "ralph",
+		Subtype:   "output_held",
+		Content:   "claude exited but a process it started still held its output open, so it was closed; any output after that is lost",
+	})
+}
+
+func (e *cmdExecutor) closeOutputs() {
+	for _, w := range e.outputs {
+		w.Close()
+	}
+}
+
+// Pid returns the process ID once started
+func (e *cmdExecutor) Pid() int {
+	if e.cmd.Process == nil {
+		return 0
+	}
+	return e.cmd.Process.Pid
+}
+
+// executorPid returns the process ID of executors that have one, or 0
+func executorPid(e Executor) int {
+	if p, ok := e.(interface{ Pid() int }); ok {
+		return p.Pid()
+	}
+	return 0
+}
@@ -0,0 +1,372 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeExecutor replays canned output in place of a claude process. Wait
+// returns once both streams have been read to EOF, or once the context is
+// cancelled while blocking.
+type fakeExecutor struct {
+	ctx      context.Context
+	dir      string
+	args     []string
+	stdout   string
+	stderr   string
+	block    bool  // Keep stdout open until the context is cancelled
+	startErr error // Returned by Start
+	waitErr  error // Returned by Wait after the output is read
+
+	drained sync.WaitGroup
+	piped   bool
+	started bool
+}
+
+func (f *fakeExecutor) Pipes() (io.Reader, io.Reader, error) {
+	f.piped = true
+	f.drained.Add(2)
+	var stdout io.Reader = strings.NewReader(f.stdout)
+	if f.block {
+		pr, pw := io.Pipe()
+		go func() {
+			io.WriteString(pw, f.stdout)
+			<-f.ctx.Done()
+			pw.Close()
+		}()
+		stdout = pr
+	}
+	return &eofReader{r: stdout, done: f.drained.Done}, &eofReader{r: strings.NewReader(f.stderr), done: f.drained.Done}, nil
+}
+
+func (f *fakeExecutor) Start() error {
+	if f.startErr != nil {
+		return f.startErr
+	}
+	f.started = true
+	return nil
+}
+
+func (f *fakeExecutor) Wait() error {
+	if f.piped {
+		f.drained.Wait()
+	}
+	if f.ctx != nil && f.ctx.Err() != nil {
+		return fakeExitError{code: -1}
+	}
+	return f.waitErr
+}
+
+// eofReader calls done the first time the wrapped reader reports EOF
+type eofReader struct {
+	r    io.Reader
+	done func()
+	once sync.Once
+}
+
+func (e *eofReader) Read(p []byte) (int, error) {
+	n, err := e.r.Read(p)
+	if err != nil {
+		e.once.Do(e.done)
+	}
+	return n, err
+}
+
+type fakeExitError struct {
+	code int
+}
+
+func (e fakeExitError) Error() string { return fmt.Sprintf("exit status %d", e.code) }
+func (e fakeExitError) ExitCode() int { return e.code }
+
+// newFakeManager returns a manager whose instances run fake, recording the
+// context, directory and arguments each was created with
+func newFakeManager(t *testing.T, fake *fakeExecutor) *Manager {
+	t.Helper()
+	m := NewManager(t.TempDir())
+	m.SetExecutorFactory(func(ctx context.Context, dir string, args []string) Executor {
+		fake.ctx = ctx
+		fake.dir = dir
+		fake.args = args
+		return fake
+	})
+	return m
+}
+
+// waitForDone drains the instance's output channel until it is closed
+func waitForDone(t *testing.T, inst *Instance) []OutputLine {
+	t.Helper()
+	var lines []OutputLine
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case line, ok := <-inst.OutputChannel():
+			if !ok {
+				return lines
+			}
+			lines = append(lines, line)
+		case <-timeout:
+			t.Fatal("instance did not finish")
+		}
+	}
+}
+
+func TestExecutorLifecycleCompleted(t *testing.T) {
+	fake := &fakeExecutor{stdout: `{"type":"system","subtype":"init","session_id":"sess-1"}
+{"type":"assistant","message":{"content":"Adding the handler"}}
+{"type":"tool_use","tool":"Write","tool_input":{"file_path":"handler.go","content":"package main\nfunc main() {}\n"}}
+{"type":"result","subtype":"success","result":"done"}
+`}
+	m := newFakeManager(t, fake)
+
+	inst, err := m.StartInstance("01", "opus", "Build the handler")
+	if err != nil {
+		t.Fatalf("StartInstance failed: %v", err)
+	}
+	if !fake.started {
+		t.Fatal("expected the executor to be started")
+	}
+	if fake.dir != m.workDir {
+		t.Errorf("expected dir %q, got %q", m.workDir, fake.dir)
+	}
+	if !strings.Contains(strings.Join(fake.args, " "), "--model opus") {
+		t.Errorf("expected --model opus in args, got %v", fake.args)
+	}
+	if m.GetInstance("01") != inst {
+		t.Error("expected the instance to be registered")
+	}
+
+	lines := waitForDone(t, inst)
+
+	if len(lines) != 4 {
+		t.Errorf("expected 4 output lines, got %d", len(lines))
+	}
+	if got := inst.GetStatus(); got != "completed" {
+		t.Errorf("expected status completed, got %q (error %q)", got, inst.GetError())
+	}
+	if inst.ExitCode != 0 {
+		t.Errorf("expected exit code 0, got %d", inst.ExitCode)
+	}
+	if got := inst.GetSessionID(); got != "sess-1" {
+		t.Errorf("expected session ID sess-1, got %q", got)
+	}
+	if got := inst.GetActionCount(); got != 1 {
+		t.Errorf("expected 1 action, got %d", got)
+	}
+	if added, _ := inst.GetLineChanges(); added != 2 {
+		t.Errorf("expected 2 lines added, got %d", added)
+	}
+	if inst.CompletedAt == nil {
+		t.Error("expected CompletedAt to be set")
+	}
+}
+
+func TestExecutorLifecycleResultStats(t *testing.T) {
+	tests := []struct {
+		name      string
+		result    string
+		wantDur   time.Duration
+		wantTurns int
+	}{
+		{
+			name:      "num_turns",
+			result:    `{"type":"result","subtype":"success","result":"done","duration_ms":123456,"num_turns":14}`,
+			wantDur:   123456 * time.Millisecond,
+			wantTurns: 14,
+		},
+		{
+			name:      "num_messages",
+			result:    `{"type":"result","subtype":"success","result":"done","duration_ms":2500,"num_messages":3}`,
+			wantDur:   2500 * time.Millisecond,
+			wantTurns: 3,
+		},
+		{
+			name:   "neither",
+			result: `{"type":"result","subtype":"success","result":"done"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeExecutor{stdout: `{"type":"assistant","message":{"content":"Working"}}` + "\n" + tt.result + "\n"}
+			m := newFakeManager(t, fake)
+
+			inst, err := m.StartInstance("01", "opus", "Build the handler")
+			if err != nil {
+				t.Fatalf("StartInstance failed: %v", err)
+			}
+			waitForDone(t, inst)
+
+			duration, turns := inst.GetResultStats()
+			if duration != tt.wantDur {
+				t.Errorf("expected duration %v, got %v", tt.wantDur, duration)
+			}
+			if turns != tt.wantTurns {
+				t.Errorf("expected %d turns, got %d", tt.wantTurns, turns)
+			}
+		})
+	}
+}
+
+func TestExecutorLifecycleNonZeroExit(t *testing.T) {
+	fake := &fakeExecutor{
+		stdout:  `{"type":"assistant","message":{"content":"Trying"}}` + "\n",
+		stderr:  "fatal: something broke\n",
+		waitErr: fakeExitError{code: 2},
+	}
+	m := newFakeManager(t, fake)
+
+	inst, err := m.StartInstance("01", "sonnet", "Build it")
+	if err != nil {
+		t.Fatalf("StartInstance failed: %v", err)
+	}
+	waitForDone(t, inst)
+
+	if got := inst.GetStatus(); got != "failed" {
+		t.Errorf("expected status failed, got %q", got)
+	}
+	if inst.ExitCode != 2 {
+		t.Errorf("expected exit code 2, got %d", inst.ExitCode)
+	}
+	if got := inst.GetError(); got != "exit status 2" {
+		t.Errorf("expected error %q, got %q", "exit status 2", got)
+	}
+}
+
+func TestExecutorLifecycleTestFailures(t *testing.T) {
+	fake := &fakeExecutor{stdout: `{"type":"assistant","message":{"content":"2 failed"}}` + "\n"}
+	m := newFakeManager(t, fake)
+
+	inst, err := m.StartInstance("01", "sonnet", "Build it")
+	if err != nil {
+		t.Fatalf("StartInstance failed: %v", err)
+	}
+	waitForDone(t, inst)
+
+	if got := inst.GetStatus(); got != "failed" {
+		t.Errorf("expected status failed, got %q", got)
+	}
+	if got := inst.GetError(); got != "2 tests failed" {
+		t.Errorf("expected error %q, got %q", "2 tests failed", got)
+	}
+}
+
+func TestExecutorLifecycleStop(t *testing.T) {
+	fake := &fakeExecutor{
+		stdout: `{"type":"assistant","message":{"content":"Working"}}` + "\n",
+		block:  true,
+	}
+	m := newFakeManager(t, fake)
+
+	inst, err := m.StartInstance("01", "sonnet", "Build it")
+	if err != nil {
+		t.Fatalf("StartInstance failed: %v", err)
+	}
+	if got := inst.GetStatus(); got != "running" {
+		t.Errorf("expected status running, got %q", got)
+	}
+
+	m.StopInstance("01")
+	waitForDone(t, inst)
+
+	if got := inst.GetStatus(); got != "failed" {
+		t.Errorf("expected status failed after stop, got %q", got)
+	}
+	if inst.ExitCode != -1 {
+		t.Errorf("expected exit code -1, got %d", inst.ExitCode)
+	}
+}
+
+func TestExecutorStartError(t *testing.T) {
+	fake := &fakeExecutor{startErr: errors.New("executable file not found")}
+	m := newFakeManager(t, fake)
+
+	_, err := m.StartInstance("01", "sonnet", "Build it")
+	if err == nil || !strings.Contains(err.Error(), "executable file not found") {
+		t.Fatalf("expected start error, got %v", err)
+	}
+	if m.GetInstance("01") != nil {
+		t.Error("expected no instance to be registered")
+	}
+	if fake.ctx.Err() == nil {
+		t.Error("expected the context to be cancelled")
+	}
+}
+
+func TestCmdExecutorDoesNotWaitOnHeldOutput(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	// The background sleep inherits stdout and holds it open after sh exits
+	script := `sleep 3 &
+echo '{"type":"result","subtype":"success","result":"done"}'
+`
+	m := NewManager(t.TempDir())
+	m.SetExecutorFactory(func(ctx context.Context, dir string, args []string) Executor {
+		cmd := exec.CommandContext(ctx, "sh", "-c", script)
+		cmd.WaitDelay = 100 * time.Millisecond
+		return &cmdExecutor{cmd: cmd}
+	})
+
+	inst, err := m.StartInstance("01", "sonnet", "prompt")
+	if err != nil {
+		t.Fatalf("StartInstance failed: %v", err)
+	}
+	lines := waitForDone(t, inst)
+
+	if got := inst.GetStatus(); got != StatusCompletedWithWarnings {
+		t.Errorf("expected status %s, got %q (error %q)", StatusCompletedWithWarnings, got, inst.GetError())
+	}
+	var reported bool
+	for _, line := range lines {
+		reported = reported || line.Subtype == "output_held"
+	}
+	if !reported {
+		t.Error("expected the cut-off output to be reported")
+	}
+}
+
+// closingExecutor closes its stdout when Wait is called, as exec.Cmd does
+// with StdoutPipe, so whatever hasn't been read by then is lost
+type closingExecutor struct {
+	lines  int
+	stdout *io.PipeReader
+}
+
+func (e *closingExecutor) Pipes() (io.Reader, io.Reader, error) {
+	pr, pw := io.Pipe()
+	e.stdout = pr
+	go func() {
+		for i := 1; i <= e.lines; i++ {
+			fmt.Fprintf(pw, `{"type":"assistant","message":{"content":"line %d"}}`+"\n", i)
+		}
+		pw.Close()
+	}()
+	return pr, strings.NewReader(""), nil
+}
+func (e *closingExecutor) Start() error { return nil }
+func (e *closingExecutor) Wait() error  { return e.stdout.Close() }
+
+func TestInstanceReadsOutputToEOFBeforeWait(t *testing.T) {
+	m := NewManager(t.TempDir())
+	m.SetExecutorFactory(func(ctx context.Context, dir string, args []string) Executor {
+		return &closingExecutor{lines: 200}
+	})
+
+	inst, err := m.StartInstance("01", "sonnet", "prompt")
+	if err != nil {
+		t.Fatalf("StartInstance failed: %v", err)
+	}
+	waitForDone(t, inst)
+
+	if output := inst.GetOutput(); !strings.Contains(output, "line 200") {
+		t.Error("expected every line to be read before waiting on the executor")
+	}
+}
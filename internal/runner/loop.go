@@ -0,0 +1,132 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/vx/ralph-go/internal/logger"
+)
+
+// FailureToolLoop classifies an instance stopped because it kept repeating
+// the same tool call
+const FailureToolLoop = "tool_loop"
+
+const (
+	// DefaultLoopThreshold is how many identical tool calls within the loop
+	// window count as a loop
+	DefaultLoopThreshold = 10
+	// DefaultLoopWindow is how many of the most recent tool calls are checked
+	// for repeats
+	DefaultLoopWindow = 20
+)
+
+// loopDetector counts identical tool calls among the most recent ones
+type loopDetector struct {
+	threshold int
+	window    int
+	recent    []string // Signatures of the last window tool calls, oldest first
+	counts    map[string]int
+}
+
+func newLoopDetector(threshold, window int) *loopDetector {
+	if threshold <= 0 {
+		return nil
+	}
+	if window < threshold {
+		window = threshold
+	}
+	return &loopDetector{
+		threshold: threshold,
+		window:    window,
+		counts:    make(map[string]int),
+	}
+}
+
+// toolSignature identifies a tool call by its tool and input, ignoring
+// formatting differences in the input JSON
+func toolSignature(tool string, input json.RawMessage) string {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, input); err != nil {
+		return tool + " " + string(input)
+	}
+	return tool + " " + compact.String()
+}
+
+// record adds a tool call and reports whether its signature has now been
+// seen threshold times within the window. It reports true only once per
+// crossing, so a loop that keeps going isn't flagged on every call.
+func (d *loopDetector) record(signature string) bool {
+	d.recent = append(d.recent, signature)
+	d.counts[signature]++
+	if len(d.recent) > d.window {
+		oldest := d.recent[0]
+		d.recent = d.recent[1:]
+		if d.counts[oldest]--; d.counts[oldest] == 0 {
+			delete(d.counts, oldest)
+		}
+	}
+	return d.counts[signature] == d.threshold
+}
+
+// detectToolLoop records a tool call and flags the instance when the same
+// call repeats beyond the loop threshold. The instance is failed and
+// cancelled only when the manager was configured to cancel loops.
+func (inst *Instance) detectToolLoop(tool string, input json.RawMessage) {
+	inst.mu.Lock()
+	if inst.loops == nil || inst.ToolLoop != "" {
+		inst.mu.Unlock()
+		return
+	}
+	if !inst.loops.record(toolSignature(tool, input)) {
+		inst.mu.Unlock()
+		return
+	}
+
+	target := tool
+	if len(input) > 0 {
+		target = fmt.Sprintf("%s %s", tool, input)
+	}
+	if len(target) > 200 {
+		target = target[:200] + "..."
+	}
+	inst.ToolLoop = fmt.Sprintf("%s repeated %d times in the last %d tool calls",
+		target, inst.loops.threshold, len(inst.loops.recent))
+	cancel := inst.cancelOnLoop && inst.FailureClass == ""
+	if cancel {
+		inst.Status = "failed"
+		inst.FailureClass = FailureToolLoop
+		inst.Error = "Tool loop: " + inst.ToolLoop
+	}
+	featureID := inst.FeatureID
+	message := inst.ToolLoop
+	inst.mu.Unlock()
+
+	if len(featureID) > 8 {
+		featureID = featureID[:8]
+	}
+	logger.Warn("runner", "Instance is repeating a tool call",
+		"featureID", featureID,
+		"loop", message,
+		"cancel", cancel)
+
+	inst.emitOutput(OutputLine{
+		Timestamp: time.Now(),
+		Type:      "ralph",
+		Subtype:   FailureToolLoop,
+		Content:   message,
+	})
+
+	if cancel {
+		inst.Stop()
+	}
+}
+
+// GetToolLoop describes the repeated tool call that was flagged as a loop,
+// or returns "" if none was
+func (inst *Instance) GetToolLoop() string {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	return inst.ToolLoop
+}
@@ -0,0 +1,97 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+)
+
+func repeatedToolUse(command string, n int) string {
+	line := `{"type":"tool_use","tool":"Bash","tool_input":{"command":"` + command + `"}}` + "\n"
+	return strings.Repeat(line, n)
+}
+
+func TestReadOutputFlagsToolLoopAtThreshold(t *testing.T) {
+	inst := newTestInstance(nil)
+	inst.loops = newLoopDetector(5, 10)
+
+	inst.readOutput(strings.NewReader(repeatedToolUse("go test ./...", 4)), "stdout")
+	if loop := inst.GetToolLoop(); loop != "" {
+		t.Fatalf("expected no loop below the threshold, got %q", loop)
+	}
+
+	inst.readOutput(strings.NewReader(repeatedToolUse("go test ./...", 1)), "stdout")
+	loop := inst.GetToolLoop()
+	if !strings.Contains(loop, "go test ./...") || !strings.Contains(loop, "repeated 5 times") {
+		t.Errorf("expected loop to describe the repeated command, got %q", loop)
+	}
+	if inst.GetStatus() != "running" {
+		t.Errorf("expected instance to keep running when not cancelling loops, got %s", inst.GetStatus())
+	}
+
+	lines := inst.GetOutputLines()
+	last := lines[len(lines)-1]
+	if last.Type != "ralph" || last.Subtype != FailureToolLoop {
+		t.Errorf("expected a tool loop line after the repeated call, got %s:%s", last.Type, last.Subtype)
+	}
+}
+
+func TestReadOutputCancelsToolLoop(t *testing.T) {
+	cancelled := false
+	inst := newTestInstance(func() { cancelled = true })
+	inst.loops = newLoopDetector(3, 3)
+	inst.cancelOnLoop = true
+
+	inst.readOutput(strings.NewReader(repeatedToolUse("ls", 3)), "stdout")
+
+	if !cancelled {
+		t.Error("expected instance to be cancelled")
+	}
+	if inst.GetStatus() != "failed" {
+		t.Errorf("expected status failed, got %s", inst.GetStatus())
+	}
+	if inst.GetFailureClass() != FailureToolLoop {
+		t.Errorf("expected failure class %s, got %s", FailureToolLoop, inst.GetFailureClass())
+	}
+	if !strings.HasPrefix(inst.GetError(), "Tool loop: ") {
+		t.Errorf("expected tool loop error, got %q", inst.GetError())
+	}
+}
+
+func TestReadOutputIgnoresVariedToolCalls(t *testing.T) {
+	inst := newTestInstance(nil)
+	inst.loops = newLoopDetector(3, 4)
+
+	// a and b repeat three times, but never three times within four calls
+	var output string
+	for _, command := range []string{"a", "a", "b", "c", "d", "a", "b", "c", "a", "b"} {
+		output += repeatedToolUse(command, 1)
+	}
+	inst.readOutput(strings.NewReader(output), "stdout")
+
+	if loop := inst.GetToolLoop(); loop != "" {
+		t.Errorf("expected no loop, got %q", loop)
+	}
+}
+
+func TestLoopDetectorDisabled(t *testing.T) {
+	if newLoopDetector(0, 20) != nil {
+		t.Error("expected a zero threshold to disable loop detection")
+	}
+
+	inst := newTestInstance(nil)
+	inst.readOutput(strings.NewReader(repeatedToolUse("ls", 50)), "stdout")
+	if loop := inst.GetToolLoop(); loop != "" {
+		t.Errorf("expected no loop detection without a detector, got %q", loop)
+	}
+}
+
+func TestToolSignatureIgnoresFormatting(t *testing.T) {
+	a := toolSignature("Bash", []byte(`{"command": "ls"}`))
+	b := toolSignature("Bash", []byte(`{"command":"ls"}`))
+	if a != b {
+		t.Errorf("expected equal signatures, got %q and %q", a, b)
+	}
+	if toolSignature("Read", []byte(`{"command":"ls"}`)) == b {
+		t.Error("expected different tools to have different signatures")
+	}
+}
@@ -0,0 +1,65 @@
+package runner
+
+import (
+	"fmt"
+
+	"github.com/vx/ralph-go/internal/logger"
+)
+
+// FailureMalformedOutput classifies an instance whose stdout was mostly not
+// stream-json, so its progress, usage and results could not be read
+const FailureMalformedOutput = "malformed_output"
+
+const (
+	// malformedMinLines is how many stdout lines must be seen before the
+	// output format is judged, so a single stray line doesn't fail a feature
+	malformedMinLines = 5
+	// malformedRatio is the fraction of non-JSON stdout lines at which the
+	// output is treated as malformed
+	malformedRatio = 0.5
+)
+
+const malformedRemediation = "claude does not appear to be emitting stream-json. " +
+	"Check that the claude on PATH supports --output-format stream-json (claude --version) " +
+	"and that no wrapper script or shell profile prints to stdout"
+
+// recordStdoutLineLocked counts a stdout line for output format detection.
+// Callers hold inst.mu.
+func (inst *Instance) recordStdoutLineLocked(isJSON bool) {
+	inst.stdoutLines++
+	if !isJSON {
+		inst.malformedLines++
+	}
+}
+
+// outputMalformedLocked reports whether enough stdout lines failed to parse
+// as JSON to suspect an output format mismatch. Callers hold inst.mu.
+func (inst *Instance) outputMalformedLocked() bool {
+	if inst.stdoutLines < malformedMinLines {
+		return false
+	}
+	return float64(inst.malformedLines) >= float64(inst.stdoutLines)*malformedRatio
+}
+
+// classifyMalformedOutputLocked fails the instance with FailureMalformedOutput
+// when its stdout wasn't stream-json, replacing whatever error the garbled
+// output produced. Callers hold inst.mu.
+func (inst *Instance) classifyMalformedOutputLocked() {
+	if inst.FailureClass != "" || !inst.outputMalformedLocked() {
+		return
+	}
+
+	inst.Status = "failed"
+	inst.FailureClass = FailureMalformedOutput
+	inst.Error = fmt.Sprintf("Malformed output: %d of %d stdout lines were not JSON. %s",
+		inst.malformedLines, inst.stdoutLines, malformedRemediation)
+
+	featureID := inst.FeatureID
+	if len(featureID) > 8 {
+		featureID = featureID[:8]
+	}
+	logger.Error("runner", "Instance output is not stream-json",
+		"featureID", featureID,
+		"malformedLines", inst.malformedLines,
+		"stdoutLines", inst.stdoutLines)
+}
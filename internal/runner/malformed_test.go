@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMalformedOutputFailsWithDiagnostic(t *testing.T) {
+	inst := newTestInstance(nil)
+
+	output := `Usage: claude [options] [command] [prompt]
+error: unknown option '--output-format'
+  -p, --print   Print response and exit
+  -h, --help    Display help for command
+{"type":"system","subtype":"init","session_id":"abc"}
+Segmentation fault (core dumped)
+`
+	finishWithOutput(t, inst, output)
+
+	if got := inst.GetStatus(); got != "failed" {
+		t.Errorf("expected status failed, got %s", got)
+	}
+	if got := inst.GetFailureClass(); got != FailureMalformedOutput {
+		t.Errorf("expected failure class %q, got %q", FailureMalformedOutput, got)
+	}
+	errMsg := inst.GetError()
+	if !strings.Contains(errMsg, "5 of 6 stdout lines were not JSON") {
+		t.Errorf("expected line counts in error, got %q", errMsg)
+	}
+	if !strings.Contains(errMsg, "stream-json") {
+		t.Errorf("expected output format hint in error, got %q", errMsg)
+	}
+}
+
+func TestOccasionalNonJSONLinesAreTolerated(t *testing.T) {
+	inst := newTestInstance(nil)
+
+	output := `{"type":"system","subtype":"init","session_id":"abc"}
+{"type":"assistant","message":{"content":"Working on it"}}
+warning: partial line from a crashed hook
+{"type":"assistant","message":{"content":"Done"}}
+{"type":"result","subtype":"success","result":"done"}
+`
+	finishWithOutput(t, inst, output)
+
+	if got := inst.GetFailureClass(); got != "" {
+		t.Errorf("expected no failure class, got %q", got)
+	}
+	if got := inst.GetStatus(); got != "completed" {
+		t.Errorf("expected status completed, got %s", got)
+	}
+}
+
+func TestStderrDoesNotCountAsMalformed(t *testing.T) {
+	inst := newTestInstance(nil)
+	inst.readOutput(strings.NewReader(strings.Repeat("npm WARN deprecated package\n", 10)), "stderr")
+	finishWithOutput(t, inst, `{"type":"result","subtype":"success","result":"done"}`+"\n")
+
+	if got := inst.GetFailureClass(); got != "" {
+		t.Errorf("expected stderr noise to be ignored, got failure class %q", got)
+	}
+}
+
+func TestShortOutputIsNotJudged(t *testing.T) {
+	inst := newTestInstance(nil)
+	finishWithOutput(t, inst, "claude: command produced no JSON\n")
+
+	if got := inst.GetFailureClass(); got != "" {
+		t.Errorf("expected too few lines to judge, got failure class %q", got)
+	}
+}
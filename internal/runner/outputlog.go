@@ -0,0 +1,105 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// OutputLogDir is the directory, relative to the work dir, where each
+// feature's raw stream-json output is kept
+const OutputLogDir = ".ralph/logs"
+
+// OutputLogPath returns the path of the raw output log for a feature
+func OutputLogPath(workDir, featureID string) string {
+	return filepath.Join(workDir, OutputLogDir, featureID+".jsonl")
+}
+
+// openOutputLog creates the output log for a feature, replacing the log of
+// any earlier attempt
+func openOutputLog(workDir, featureID string) (*os.File, error) {
+	path := OutputLogPath(workDir, featureID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output log directory: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create output log: %w", err)
+	}
+	return f, nil
+}
+
+// writeOutputLogLocked appends a raw output line to the instance's log.
+// Callers hold inst.mu.
+func (inst *Instance) writeOutputLogLocked(line string) {
+	if inst.outputLog == nil {
+		return
+	}
+	if _, err := inst.outputLog.WriteString(line + "\n"); err != nil {
+		inst.outputLog.Close()
+		inst.outputLog = nil
+	}
+}
+
+// closeOutputLogLocked closes the instance's log. Callers hold inst.mu.
+func (inst *Instance) closeOutputLogLocked() {
+	if inst.outputLog != nil {
+		inst.outputLog.Close()
+		inst.outputLog = nil
+	}
+}
+
+// FormatLogLine formats one raw line of an output log the way the inspect
+// view shows it, clipped to limits. Lines that aren't stream-json are shown
+// as stderr.
+func FormatLogLine(line string, limits ClipLimits) string {
+	outputLine, _ := parseOutputLine(line, "stderr", time.Time{})
+	clipContent(&outputLine, limits)
+	return FormatOutputLine(outputLine)
+}
+
+// LogOptions are how FormatLog reads an output log
+type LogOptions struct {
+	// ScanBufferSize is the longest line read, in bytes; longer lines are
+	// skipped with a note (0 = DefaultScanBufferSize)
+	ScanBufferSize int
+	// Clip is how much of each line is shown, as set by --max-line-length
+	Clip ClipLimits
+}
+
+// FormatLog writes every line of an output log to w, formatted with
+// FormatLogLine. A line too long to read is skipped with a note in its
+// place, as the runner does, and the rest of the log is still written.
+func FormatLog(r io.Reader, w io.Writer, opts LogOptions) error {
+	lr := newLineReader(r, opts.ScanBufferSize)
+	for {
+		raw, length, err := lr.next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		var formatted string
+		switch {
+		case errors.Is(err, errLineTooLong):
+			formatted = FormatOutputLine(OutputLine{
+				Type:    "ralph",
+				Subtype: "line_too_long",
+				Content: fmt.Sprintf("skipped a %d byte line, longer than the %d byte scan buffer", length, lr.size),
+			})
+		case err != nil:
+			return err
+		default:
+			line := strings.TrimSpace(raw)
+			if line == "" {
+				continue
+			}
+			formatted = FormatLogLine(line, opts.Clip)
+		}
+		if _, err := fmt.Fprintln(w, formatted); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package runner
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestFormatLogReadsWrittenLog(t *testing.T) {
+	workDir := t.TempDir()
+	f, err := openOutputLog(workDir, "03")
+	if err != nil {
+		t.Fatalf("openOutputLog: %v", err)
+	}
+	inst := newTestInstance(nil)
+	inst.outputLog = f
+
+	output := `{"type":"system","subtype":"init","session_id":"abc"}
+{"type":"assistant","message":{"content":[{"type":"text","text":"Adding the login form"}]}}
+{"type":"tool_use","tool":"Write","tool_input":{"file_path":"login.go"}}
+{"type":"tool_result","result":"permission denied","is_error":true}
+not json at all
+{"type":"result","subtype":"success","result":"done"}
+`
+	finishWithOutput(t, inst, output)
+
+	logFile, err := os.Open(OutputLogPath(workDir, "03"))
+	if err != nil {
+		t.Fatalf("expected output log to be written: %v", err)
+	}
+	defer logFile.Close()
+
+	var sb strings.Builder
+	if err := FormatLog(logFile, &sb, LogOptions{}); err != nil {
+		t.Fatalf("FormatLog: %v", err)
+	}
+
+	want := `system:init: 
+assistant: Adding the login form
+tool_use[Write]: [Tool: Write]
+tool_result:error: permission denied
+stderr: not json at all
+result:success: [Completed successfully]
+`
+	if sb.String() != want {
+		t.Errorf("unexpected formatted log:\n%s\nwant:\n%s", sb.String(), want)
+	}
+}
+
+func TestFormatLogSkipsLongLines(t *testing.T) {
+	log := `{"type":"assistant","message":{"content":[{"type":"text","text":"before"}]}}
+{"type":"tool_result","result":"` + strings.Repeat("x", 200) + `"}
+{"type":"assistant","message":{"content":[{"type":"text","text":"after"}]}}
+`
+	var sb strings.Builder
+	if err := FormatLog(strings.NewReader(log), &sb, LogOptions{ScanBufferSize: 128}); err != nil {
+		t.Fatalf("FormatLog: %v", err)
+	}
+
+	want := `assistant: before
+ralph:line_too_long: skipped a 235 byte line, longer than the 128 byte scan buffer
+assistant: after
+`
+	if sb.String() != want {
+		t.Errorf("unexpected formatted log:\n%s\nwant:\n%s", sb.String(), want)
+	}
+}
+
+func TestFormatLogClipsToLimits(t *testing.T) {
+	result := strings.Repeat("x", 40)
+	log := `{"type":"tool_result","result":"` + result + `"}` + "\n"
+
+	var sb strings.Builder
+	if err := FormatLog(strings.NewReader(log), &sb, LogOptions{Clip: ClipLimits{ToolResult: 10}}); err != nil {
+		t.Fatalf("FormatLog: %v", err)
+	}
+	if want := "tool_result: " + strings.Repeat("x", 10) + "...\n"; sb.String() != want {
+		t.Errorf("expected the result clipped to 10, got %q", sb.String())
+	}
+
+	sb.Reset()
+	if err := FormatLog(strings.NewReader(log), &sb, LogOptions{Clip: ClipLimits{ToolResult: -1}}); err != nil {
+		t.Fatalf("FormatLog: %v", err)
+	}
+	if want := "tool_result: " + result + "\n"; sb.String() != want {
+		t.Errorf("expected the full result with clipping off, got %q", sb.String())
+	}
+}
+
+func TestOpenOutputLogReplacesPreviousAttempt(t *testing.T) {
+	workDir := t.TempDir()
+	for _, line := range []string{"first attempt", "second attempt"} {
+		f, err := openOutputLog(workDir, "01")
+		if err != nil {
+			t.Fatalf("openOutputLog: %v", err)
+		}
+		f.WriteString(line + "\n")
+		f.Close()
+	}
+
+	content, err := os.ReadFile(OutputLogPath(workDir, "01"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(content) != "second attempt\n" {
+		t.Errorf("expected only the latest attempt, got %q", string(content))
+	}
+}
+
+func TestFormatLogLineClipsLongContent(t *testing.T) {
+	line := `{"type":"tool_result","result":"` + strings.Repeat("x", 600) + `"}`
+	got := FormatLogLine(line, ClipLimits{})
+	if want := "tool_result: " + strings.Repeat("x", 500) + "..."; got != want {
+		t.Errorf("expected clipped tool result, got %d chars", len(got))
+	}
+}
@@ -0,0 +1,73 @@
+package runner
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/vx/ralph-go/internal/logger"
+)
+
+// FailurePermissionRequired classifies an instance stopped because claude
+// asked for a permission approval that can't be given non-interactively
+const FailurePermissionRequired = "permission_required"
+
+// permissionPatterns match claude asking for tool approval. They are only
+// checked against stderr, plain output and error results, since the model's
+// own text may discuss permissions freely.
+var permissionPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)requested permissions? to .+ but you haven'?t granted it`),
+	regexp.MustCompile(`(?i)waiting for (user )?(approval|permission)`),
+	regexp.MustCompile(`(?i)(do you want to|allow|approve) .+\?\s*\(?(y/n|yes/no)\)?`),
+	regexp.MustCompile(`(?i)permission prompt`),
+}
+
+const permissionRemediation = "ralph runs claude non-interactively, so approval prompts can't be answered. " +
+	"Check that --dangerously-skip-permissions is honoured in this environment, or allow the tool in .claude/settings.json"
+
+// isPermissionRequest reports whether a line is claude asking for approval
+func isPermissionRequest(content string) bool {
+	for _, pattern := range permissionPatterns {
+		if pattern.MatchString(content) {
+			return true
+		}
+	}
+	return false
+}
+
+// detectPermissionRequest fails and cancels the instance when claude asks for
+// approval, rather than leaving it waiting for input that never comes
+func (inst *Instance) detectPermissionRequest(content string) {
+	if !isPermissionRequest(content) {
+		return
+	}
+
+	inst.mu.Lock()
+	if inst.FailureClass != "" {
+		inst.mu.Unlock()
+		return
+	}
+	if len(content) > 200 {
+		content = content[:200] + "..."
+	}
+	inst.Status = "failed"
+	inst.FailureClass = FailurePermissionRequired
+	inst.Error = fmt.Sprintf("Permission required: %s. %s", content, permissionRemediation)
+	featureID := inst.FeatureID
+	inst.mu.Unlock()
+
+	if len(featureID) > 8 {
+		featureID = featureID[:8]
+	}
+	logger.Error("runner", "Instance is waiting for permission approval, cancelling",
+		"featureID", featureID,
+		"line", content)
+
+	inst.Stop()
+}
+
+// GetFailureClass returns the classified failure reason, if any
+func (inst *Instance) GetFailureClass() string {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	return inst.FailureClass
+}
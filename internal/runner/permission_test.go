@@ -0,0 +1,74 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vx/ralph-go/internal/usage"
+)
+
+func newTestInstance(cancel func()) *Instance {
+	return &Instance{
+		FeatureID:   "feature-1",
+		Status:      "running",
+		cancel:      cancel,
+		outputCh:    make(chan OutputLine, 100),
+		TestResults: &TestResults{},
+		Usage:       usage.New(),
+	}
+}
+
+func TestReadOutputDetectsPermissionRequest(t *testing.T) {
+	cancelled := false
+	inst := newTestInstance(func() { cancelled = true })
+
+	output := `{"type":"assistant","message":{"content":"Writing the config file"}}
+Claude requested permissions to write to /app/config.yaml, but you haven't granted it yet.
+`
+	inst.readOutput(strings.NewReader(output), "stderr")
+
+	if !cancelled {
+		t.Error("expected instance to be cancelled")
+	}
+	if inst.GetStatus() != "failed" {
+		t.Errorf("expected status failed, got %q", inst.GetStatus())
+	}
+	if inst.GetFailureClass() != FailurePermissionRequired {
+		t.Errorf("expected failure class %q, got %q", FailurePermissionRequired, inst.GetFailureClass())
+	}
+	if !strings.Contains(inst.GetError(), ".claude/settings.json") {
+		t.Errorf("expected remediation in error, got %q", inst.GetError())
+	}
+}
+
+func TestReadOutputIgnoresPermissionTalkFromModel(t *testing.T) {
+	cancelled := false
+	inst := newTestInstance(func() { cancelled = true })
+
+	output := `{"type":"assistant","message":{"content":"Do you want to allow writes? (y/n) is what the CLI would ask"}}
+`
+	inst.readOutput(strings.NewReader(output), "stdout")
+
+	if cancelled || inst.GetFailureClass() != "" {
+		t.Error("expected assistant text not to trigger permission detection")
+	}
+}
+
+func TestIsPermissionRequest(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{"Claude requested permissions to use Bash, but you haven't granted it yet.", true},
+		{"Waiting for user approval...", true},
+		{"Do you want to proceed with this edit? (y/n)", true},
+		{"open /etc/shadow: permission denied", false},
+		{"ok  \tgithub.com/example/pkg\t0.01s", false},
+	}
+
+	for _, tt := range tests {
+		if got := isPermissionRequest(tt.line); got != tt.want {
+			t.Errorf("isPermissionRequest(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
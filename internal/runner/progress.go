@@ -0,0 +1,146 @@
+package runner
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/vx/ralph-go/internal/actions"
+)
+
+// checkedTaskRegex matches a completed markdown checkbox echoed in output
+var checkedTaskRegex = regexp.MustCompile(`(?m)^\s*[-*]\s+\[[xX]\]\s+(.+)$`)
+
+// keywordSplitRegex splits task descriptions and file names into words
+var keywordSplitRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// minKeywordLen ignores short words ("add", "the", "to") when matching
+// file names against task descriptions
+const minKeywordLen = 4
+
+// taskProgress tracks which of a feature's tasks appear to be done
+type taskProgress struct {
+	tasks    []string
+	keywords [][]string
+	done     []bool
+}
+
+func newTaskProgress(tasks []string) *taskProgress {
+	p := &taskProgress{
+		tasks:    tasks,
+		keywords: make([][]string, len(tasks)),
+		done:     make([]bool, len(tasks)),
+	}
+	for i, task := range tasks {
+		p.keywords[i] = taskKeywords(task)
+	}
+	return p
+}
+
+// taskKeywords returns the significant lowercase words of a task description
+func taskKeywords(task string) []string {
+	var keywords []string
+	for _, word := range keywordSplitRegex.Split(strings.ToLower(task), -1) {
+		if len(word) >= minKeywordLen {
+			keywords = append(keywords, word)
+		}
+	}
+	return keywords
+}
+
+// markCheckboxes marks tasks echoed back as "- [x] <task>"
+func (p *taskProgress) markCheckboxes(content string) {
+	for _, match := range checkedTaskRegex.FindAllStringSubmatch(content, -1) {
+		checked := strings.ToLower(strings.TrimSpace(match[1]))
+		for i, task := range p.tasks {
+			if !p.done[i] && strings.EqualFold(strings.TrimSpace(task), checked) {
+				p.done[i] = true
+				break
+			}
+		}
+	}
+}
+
+// markFile marks the first open task with a keyword in the written file's
+// name, e.g. "Add user handler" for a write to user_handler.go
+func (p *taskProgress) markFile(path string) {
+	base := strings.ToLower(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	words := make(map[string]bool)
+	for _, word := range keywordSplitRegex.Split(base, -1) {
+		words[word] = true
+	}
+
+	for i, keywords := range p.keywords {
+		if p.done[i] {
+			continue
+		}
+		for _, keyword := range keywords {
+			if words[keyword] {
+				p.done[i] = true
+				return
+			}
+		}
+	}
+}
+
+// percent returns the share of tasks done, 0-100
+func (p *taskProgress) percent() int {
+	if len(p.tasks) == 0 {
+		return 0
+	}
+	done := 0
+	for _, d := range p.done {
+		if d {
+			done++
+		}
+	}
+	return done * 100 / len(p.tasks)
+}
+
+// SetTasks sets the task descriptions used to estimate progress
+func (inst *Instance) SetTasks(tasks []string) {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+	inst.progress = newTaskProgress(tasks)
+}
+
+// detectTaskCompletion updates task progress from checkboxes in output
+func (inst *Instance) detectTaskCompletion(content string) {
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+	if inst.progress != nil {
+		inst.progress.markCheckboxes(content)
+	}
+}
+
+// detectTaskFromAction updates task progress from a write or edit action
+func (inst *Instance) detectTaskFromAction(action actions.Action) {
+	if action.Type != actions.ActionWrite && action.Type != actions.ActionEdit {
+		return
+	}
+	path := action.Path
+	if path == "" {
+		path = action.Target
+	}
+
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+	if inst.progress != nil {
+		inst.progress.markFile(path)
+	}
+}
+
+// GetProgressPercent returns the approximate share of the feature's tasks
+// completed so far, inferred from output. It returns 100 once the instance
+// completes and -1 when no tasks are known.
+func (inst *Instance) GetProgressPercent() int {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	if inst.progress == nil || len(inst.progress.tasks) == 0 {
+		return -1
+	}
+	if IsCompleted(inst.Status) {
+		return 100
+	}
+	return inst.progress.percent()
+}
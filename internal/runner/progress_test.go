@@ -0,0 +1,74 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vx/ralph-go/internal/actions"
+)
+
+func TestTaskProgressPercent(t *testing.T) {
+	tasks := []string{
+		"Add user handler",
+		"Create database schema",
+		"Write tests",
+		"Update README",
+	}
+
+	tests := []struct {
+		name     string
+		files    []string
+		output   string
+		expected int
+	}{
+		{"nothing done", nil, "", 0},
+		{"file matches a task keyword", []string{"/app/internal/user_handler.go"}, "", 25},
+		{"checkbox echoed in output", nil, "Progress:\n- [x] Write tests\n- [ ] Update README", 25},
+		{"checkbox and file combine", []string{"schema.sql"}, "- [x] Add user handler", 50},
+		{"unrelated file counts nothing", []string{"main.go"}, "", 0},
+		{"same task is counted once", []string{"user.go", "handler.go"}, "- [x] Add user handler", 25},
+		{"all tasks done", []string{"handler.go", "schema.sql", "readme.md"}, "- [x] Write tests", 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTaskProgress(tasks)
+			for _, f := range tt.files {
+				p.markFile(f)
+			}
+			p.markCheckboxes(tt.output)
+			if got := p.percent(); got != tt.expected {
+				t.Errorf("expected %d%%, got %d%%", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGetProgressPercentFromOutput(t *testing.T) {
+	inst := newTestInstance(func() {})
+	if inst.GetProgressPercent() != -1 {
+		t.Errorf("expected -1 without tasks, got %d", inst.GetProgressPercent())
+	}
+
+	inst.SetTasks([]string{"Add user handler", "Write tests"})
+
+	output := `{"type":"tool_use","tool":"Write","tool_input":{"file_path":"/app/user_handler.go","content":"package app"}}
+{"type":"assistant","message":{"content":"- [x] Write tests"}}
+`
+	inst.readOutput(strings.NewReader(output), "stdout")
+
+	if got := inst.GetProgressPercent(); got != 100 {
+		t.Errorf("expected 100%% after both tasks detected, got %d%%", got)
+	}
+
+	inst.SetTasks([]string{"Add user handler", "Write tests"})
+	inst.detectTaskFromAction(actions.Action{Type: actions.ActionRead, Path: "/app/user_handler.go"})
+	if got := inst.GetProgressPercent(); got != 0 {
+		t.Errorf("expected reads not to count toward progress, got %d%%", got)
+	}
+
+	inst.SetStatus("completed")
+	if got := inst.GetProgressPercent(); got != 100 {
+		t.Errorf("expected 100%% once completed, got %d%%", got)
+	}
+}
@@ -0,0 +1,27 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// PromptLogDir is the directory, relative to the work dir, where prompts are logged
+const PromptLogDir = ".ralph/prompts"
+
+// PromptLogPath returns the path of the prompt log for a feature attempt
+func PromptLogPath(workDir, featureID string, attempt int) string {
+	return filepath.Join(workDir, PromptLogDir, fmt.Sprintf("%s-attempt%d.md", featureID, attempt))
+}
+
+// writePromptLog writes the full prompt sent for a feature attempt
+func writePromptLog(workDir, featureID string, attempt int, prompt string) (string, error) {
+	path := PromptLogPath(workDir, featureID, attempt)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create prompt log directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(prompt), 0644); err != nil {
+		return "", fmt.Errorf("failed to write prompt log: %w", err)
+	}
+	return path, nil
+}
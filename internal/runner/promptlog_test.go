@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStartInstanceLogsPromptWhenEnabled(t *testing.T) {
+	// An empty PATH makes the claude start fail after the prompt is logged
+	t.Setenv("PATH", t.TempDir())
+	workDir := t.TempDir()
+
+	mgr := NewManager(workDir)
+	mgr.SetPromptLogging(true)
+
+	prompt := "## Feature 01\n\nBuild the thing."
+	mgr.StartInstanceWithOptions("01", "sonnet", prompt, StartInstanceOptions{Attempt: 2})
+
+	content, err := os.ReadFile(filepath.Join(workDir, ".ralph", "prompts", "01-attempt2.md"))
+	if err != nil {
+		t.Fatalf("expected prompt log to be written: %v", err)
+	}
+	if string(content) != prompt {
+		t.Errorf("expected prompt %q, got %q", prompt, string(content))
+	}
+}
+
+func TestStartInstanceCountsAttemptsForPromptLog(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	workDir := t.TempDir()
+
+	mgr := NewManager(workDir)
+	mgr.SetPromptLogging(true)
+
+	mgr.StartInstance("01", "sonnet", "first")
+	mgr.StartInstance("01", "sonnet", "second")
+
+	for attempt, want := range map[int]string{1: "first", 2: "second"} {
+		content, err := os.ReadFile(PromptLogPath(workDir, "01", attempt))
+		if err != nil {
+			t.Fatalf("expected prompt log for attempt %d: %v", attempt, err)
+		}
+		if string(content) != want {
+			t.Errorf("attempt %d: expected %q, got %q", attempt, want, string(content))
+		}
+	}
+}
+
+func TestStartInstanceDoesNotLogPromptWhenDisabled(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	workDir := t.TempDir()
+
+	mgr := NewManager(workDir)
+	mgr.StartInstance("01", "sonnet", "prompt")
+
+	if _, err := os.Stat(filepath.Join(workDir, ".ralph", "prompts")); !os.IsNotExist(err) {
+		t.Error("expected no prompt log directory when prompt logging is disabled")
+	}
+}
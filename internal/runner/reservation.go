@@ -0,0 +1,137 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/vx/ralph-go/internal/usage"
+)
+
+const (
+	// reservationMultiplier scales a prompt's input estimate to a ceiling for
+	// the whole feature, which re-reads files and tool results many times
+	reservationMultiplier = 20
+	// minReservationTokens is the smallest ceiling held for a feature
+	minReservationTokens = 50_000
+	// reservationOutputRatio assumes one output token per this many input
+	// tokens when pricing a reservation
+	reservationOutputRatio = 10
+)
+
+// ErrBudgetReserved is returned when starting an instance would commit more
+// than the global budget, counting what running instances have reserved
+var ErrBudgetReserved = errors.New("global budget is fully reserved by running features")
+
+// budgetReservation is the cost ceiling held against the global budget while
+// an instance runs
+type budgetReservation struct {
+	tokens int64
+	usd    float64
+}
+
+// estimateReservation returns the ceiling to reserve for a feature: its own
+// budget when set, otherwise a multiple of the prompt's input estimate
+func estimateReservation(prompt, model string, budgetTokens int64, budgetUSD float64) budgetReservation {
+	tokens := usage.EstimateTokens(prompt) * reservationMultiplier
+	if tokens < minReservationTokens {
+		tokens = minReservationTokens
+	}
+	r := budgetReservation{
+		tokens: tokens,
+		usd:    usage.EstimateCost(tokens, tokens/reservationOutputRatio, 0, 0, model),
+	}
+	if budgetTokens > 0 {
+		r.tokens = budgetTokens
+	}
+	if budgetUSD > 0 {
+		r.usd = budgetUSD
+	}
+	return r
+}
+
+// committedBudgetLocked returns the budget committed so far: actual usage of
+// every instance, raised to its reservation while it is still running. The
+// second result reports whether any instance is running. Callers hold m.mu.
+func (m *Manager) committedBudgetLocked() (committed budgetReservation, running bool) {
+	for _, inst := range m.instances {
+		u := inst.GetUsage()
+		tokens := u.TotalTokens
+		cost := inst.GetEstimatedCost()
+
+		if status := inst.GetStatus(); status == "running" || status == "starting" {
+			running = true
+			inst.mu.RLock()
+			reserved := inst.reservation
+			inst.mu.RUnlock()
+			if reserved.tokens > tokens {
+				tokens = reserved.tokens
+			}
+			if reserved.usd > cost {
+				cost = reserved.usd
+			}
+		}
+
+		committed.tokens += tokens
+		committed.usd += cost
+	}
+	return committed, running
+}
+
+// checkReservationLocked returns ErrBudgetReserved if holding r on top of the
+// committed budget would exceed the global limit. A feature may always start
+// when nothing else is running, so a ceiling larger than the whole budget
+// doesn't block every feature; CheckGlobalBudget still stops it once actual
+// usage runs out. Callers hold m.mu.
+func (m *Manager) checkReservationLocked(r budgetReservation) error {
+	if m.globalBudgetTokens == 0 && m.globalBudgetUSD == 0 {
+		return nil
+	}
+	if m.budgetAcknowledged {
+		return nil
+	}
+
+	committed, running := m.committedBudgetLocked()
+	if !running {
+		return nil
+	}
+
+	if m.globalBudgetTokens > 0 {
+		if committed.tokens+r.tokens > m.globalBudgetTokens {
+			return fmt.Errorf("%w: %s committed + %s reserved would exceed %s",
+				ErrBudgetReserved, usage.FormatTokens(committed.tokens), usage.FormatTokens(r.tokens),
+				usage.FormatTokens(m.globalBudgetTokens))
+		}
+		return nil
+	}
+
+	if committed.usd+r.usd > m.globalBudgetUSD {
+		return fmt.Errorf("%w: $%.2f committed + $%.2f reserved would exceed $%.2f",
+			ErrBudgetReserved, committed.usd, r.usd, m.globalBudgetUSD)
+	}
+	return nil
+}
+
+// GetReservedBudget returns the budget held by running instances beyond what
+// they have used so far
+func (m *Manager) GetReservedBudget() (tokens int64, usd float64) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, inst := range m.instances {
+		if status := inst.GetStatus(); status != "running" && status != "starting" {
+			continue
+		}
+		u := inst.GetUsage()
+		cost := inst.GetEstimatedCost()
+		inst.mu.RLock()
+		reserved := inst.reservation
+		inst.mu.RUnlock()
+		if reserved.tokens > u.TotalTokens {
+			tokens += reserved.tokens - u.TotalTokens
+		}
+		if reserved.usd > cost {
+			usd += reserved.usd - cost
+		}
+	}
+	return tokens, usd
+}
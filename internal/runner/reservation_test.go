@@ -0,0 +1,139 @@
+package runner
+
+import (
+	"errors"
+	"testing"
+)
+
+// addRunning registers a running instance holding the given reservation
+func addRunning(m *Manager, featureID string, r budgetReservation) *Instance {
+	inst := newTestInstance(func() {})
+	inst.FeatureID = featureID
+	inst.Model = "sonnet"
+	inst.reservation = r
+	m.instances[featureID] = inst
+	return inst
+}
+
+func TestEstimateReservation(t *testing.T) {
+	r := estimateReservation("short prompt", "sonnet", 0, 0)
+	if r.tokens != minReservationTokens {
+		t.Errorf("expected minimum reservation %d, got %d", minReservationTokens, r.tokens)
+	}
+	if r.usd <= 0 {
+		t.Error("expected a positive USD reservation")
+	}
+
+	long := make([]byte, 40_000)
+	if r := estimateReservation(string(long), "sonnet", 0, 0); r.tokens != 10_000*reservationMultiplier {
+		t.Errorf("expected reservation scaled from prompt, got %d", r.tokens)
+	}
+
+	if r := estimateReservation("short prompt", "sonnet", 80_000, 2.5); r.tokens != 80_000 || r.usd != 2.5 {
+		t.Errorf("expected the feature budget as ceiling, got %+v", r)
+	}
+}
+
+func TestReservationPreventsOverCommitment(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	m := NewManager(t.TempDir())
+	m.SetGlobalBudget(200_000, 0)
+
+	addRunning(m, "01", budgetReservation{tokens: 100_000})
+	addRunning(m, "02", budgetReservation{tokens: 60_000})
+
+	_, err := m.StartInstanceWithOptions("03", "sonnet", "prompt", StartInstanceOptions{BudgetTokens: 50_000})
+	if !errors.Is(err, ErrBudgetReserved) {
+		t.Fatalf("expected ErrBudgetReserved, got %v", err)
+	}
+	if m.GetInstance("03") != nil {
+		t.Error("refused feature should not be registered")
+	}
+
+	// A smaller ceiling fits in what remains, so the start proceeds (and
+	// fails only because claude isn't on PATH)
+	_, err = m.StartInstanceWithOptions("03", "sonnet", "prompt", StartInstanceOptions{BudgetTokens: 40_000})
+	if err == nil || errors.Is(err, ErrBudgetReserved) {
+		t.Errorf("expected the reservation to fit, got %v", err)
+	}
+
+	tokens, _ := m.GetReservedBudget()
+	if tokens != 160_000 {
+		t.Errorf("expected 160k reserved by running instances, got %d", tokens)
+	}
+}
+
+func TestReservationCountsUsageBeyondCeiling(t *testing.T) {
+	m := NewManager(t.TempDir())
+	m.SetGlobalBudget(100_000, 0)
+
+	inst := addRunning(m, "01", budgetReservation{tokens: 20_000})
+	inst.Usage.InputTokens = 70_000
+	inst.Usage.TotalTokens = 70_000
+
+	m.mu.Lock()
+	err := m.checkReservationLocked(budgetReservation{tokens: 40_000})
+	m.mu.Unlock()
+	if !errors.Is(err, ErrBudgetReserved) {
+		t.Errorf("expected actual usage above the reservation to count, got %v", err)
+	}
+}
+
+func TestReservationReleasedWhenInstanceFinishes(t *testing.T) {
+	m := NewManager(t.TempDir())
+	m.SetGlobalBudget(0, 10)
+
+	first := addRunning(m, "01", budgetReservation{usd: 8})
+	addRunning(m, "02", budgetReservation{usd: 1})
+
+	m.mu.Lock()
+	err := m.checkReservationLocked(budgetReservation{usd: 5})
+	m.mu.Unlock()
+	if !errors.Is(err, ErrBudgetReserved) {
+		t.Fatalf("expected USD reservation to be refused, got %v", err)
+	}
+
+	first.mu.Lock()
+	first.Status = "completed"
+	first.mu.Unlock()
+
+	m.mu.Lock()
+	err = m.checkReservationLocked(budgetReservation{usd: 5})
+	m.mu.Unlock()
+	if err != nil {
+		t.Errorf("expected reservation to be released once the instance finished, got %v", err)
+	}
+}
+
+func TestReservationAllowsFirstFeatureAndAcknowledgedBudget(t *testing.T) {
+	m := NewManager(t.TempDir())
+	m.SetGlobalBudget(10_000, 0)
+
+	m.mu.Lock()
+	err := m.checkReservationLocked(budgetReservation{tokens: 50_000})
+	m.mu.Unlock()
+	if err != nil {
+		t.Errorf("expected a feature to start when nothing else is running, got %v", err)
+	}
+
+	addRunning(m, "01", budgetReservation{tokens: 50_000})
+	m.AcknowledgeBudget()
+	m.mu.Lock()
+	err = m.checkReservationLocked(budgetReservation{tokens: 50_000})
+	m.mu.Unlock()
+	if err != nil {
+		t.Errorf("expected no reservation check after the budget was acknowledged, got %v", err)
+	}
+}
+
+func TestReservationWithoutGlobalBudget(t *testing.T) {
+	m := NewManager(t.TempDir())
+	addRunning(m, "01", budgetReservation{tokens: 1_000_000})
+
+	m.mu.Lock()
+	err := m.checkReservationLocked(budgetReservation{tokens: 1_000_000})
+	m.mu.Unlock()
+	if err != nil {
+		t.Errorf("expected no limit without a global budget, got %v", err)
+	}
+}
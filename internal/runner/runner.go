@@ -1,12 +1,12 @@
 package runner
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"os/exec"
+	"os"
 	"regexp"
 	"strings"
 	"sync"
@@ -34,12 +34,14 @@ type Instance struct {
 	StartedAt           time.Time
 	CompletedAt         *time.Time
 	ExitCode            int
-	cmd                 *exec.Cmd
+	executor            Executor
 	cancel              context.CancelFunc
+	ctx                 context.Context // Cancelled by Stop
 	output              []OutputLine
 	outputCh            chan OutputLine
 	TestResults         *TestResults
 	Error               string
+	FailureClass        string // Set when a failure is classified, e.g. FailurePermissionRequired
 	Actions             []actions.Action
 	Usage               *usage.TokenUsage
 	BudgetTokens        int64
@@ -49,6 +51,27 @@ type Instance struct {
 	SpawnCallback       SpawnCallback
 	ModelChangeCallback ModelChangeCallback
 	autoSelector        *automodel.Selector
+	progress            *taskProgress
+	reservation         budgetReservation // Held against the global budget while running
+	SessionID           string            // Claude session ID, reported in the stream
+	ToolErrors          int               // Tool calls that returned an error
+	warningThreshold    int               // Tool errors plus skipped tests that mark a warning
+	outputLog           *os.File          // Raw stream-json log, see OutputLogPath
+	ToolLoop            string            // Repeated tool call flagged as a loop
+	loops               *loopDetector     // nil when loop detection is disabled
+	cancelOnLoop        bool              // Fail and cancel the instance when a loop is flagged
+	responder           *autoResponder    // Answers approval prompts on stdin; nil when off
+	verifyCommand       string            // Run after a clean exit to confirm success; "" when off
+	workDir             string            // Where claude and the verify command run
+	clipLimits          ClipLimits        // How much of each output line is kept for display
+	scanBufferSize      int               // Longest output line read (0 = DefaultScanBufferSize)
+	stdoutLines         int               // Non-empty stdout lines read
+	malformedLines      int               // Stdout lines that weren't JSON
+	LinesAdded          int               // Lines added by Edit and Write calls
+	LinesRemoved        int               // Lines removed by Edit calls
+	CLIDuration         time.Duration     // Run time claude reported in its result message
+	LastOutputAt        time.Time         // When claude last wrote a line of output
+	NumTurns            int               // Turns claude reported in its result message
 }
 
 type OutputLine struct {
@@ -58,6 +81,7 @@ type OutputLine struct {
 	Content   string
 	Tool      string
 	Raw       json.RawMessage
+	full      string // Content before clipContent shortened it; "" if it wasn't
 }
 
 type TestResults struct {
@@ -70,17 +94,19 @@ type TestResults struct {
 
 // Claude Code stream-json message types
 type StreamMessage struct {
-	Type      string          `json:"type"`
-	Subtype   string          `json:"subtype,omitempty"`
-	CostUSD   float64         `json:"cost_usd,omitempty"`
-	Duration  float64         `json:"duration_ms,omitempty"`
-	Message   json.RawMessage `json:"message,omitempty"`
-	Content   string          `json:"content,omitempty"`
-	Tool      string          `json:"tool,omitempty"`
-	ToolInput json.RawMessage `json:"tool_input,omitempty"`
-	Result    string          `json:"result,omitempty"`
-	IsError   bool            `json:"is_error,omitempty"`
-	SessionID string          `json:"session_id,omitempty"`
+	Type        string          `json:"type"`
+	Subtype     string          `json:"subtype,omitempty"`
+	CostUSD     float64         `json:"cost_usd,omitempty"`
+	Duration    float64         `json:"duration_ms,omitempty"`
+	NumTurns    int             `json:"num_turns,omitempty"`
+	NumMessages int             `json:"num_messages,omitempty"` // Older CLIs' name for num_turns
+	Message     json.RawMessage `json:"message,omitempty"`
+	Content     string          `json:"content,omitempty"`
+	Tool        string          `json:"tool,omitempty"`
+	ToolInput   json.RawMessage `json:"tool_input,omitempty"`
+	Result      string          `json:"result,omitempty"`
+	IsError     bool            `json:"is_error,omitempty"`
+	SessionID   string          `json:"session_id,omitempty"`
 }
 
 // Nested message content structures - Claude Code uses varying formats
@@ -150,15 +176,26 @@ func extractTextContent(raw json.RawMessage) string {
 
 type Config struct {
 	MaxRetries    int
-	RetryDelay    time.Duration
 	MaxConcurrent int
+	// WarningThreshold is the number of tool errors plus skipped tests at
+	// which a successful run is marked completed_with_warnings (0 = never)
+	WarningThreshold int
+	// LoopThreshold is the number of identical tool calls within the last
+	// LoopWindow calls that flags a tool loop (0 = never)
+	LoopThreshold int
+	LoopWindow    int
+	// CancelOnLoop fails and stops an instance with FailureToolLoop when a
+	// loop is flagged, instead of only reporting it
+	CancelOnLoop bool
 }
 
 func DefaultConfig() Config {
 	return Config{
-		MaxRetries:    3,
-		RetryDelay:    5 * time.Second,
-		MaxConcurrent: 3,
+		MaxRetries:       3,
+		MaxConcurrent:    3,
+		WarningThreshold: DefaultWarningThreshold,
+		LoopThreshold:    DefaultLoopThreshold,
+		LoopWindow:       DefaultLoopWindow,
 	}
 }
 
@@ -170,9 +207,20 @@ type Manager struct {
 	globalBudgetTokens  int64
 	globalBudgetUSD     float64
 	budgetAcknowledged  bool
+	priorTokens         int64 // Usage from outside instances counted toward the global budget
+	priorUSD            float64
 	spawnCallback       SpawnCallback
 	modelChangeCallback ModelChangeCallback
 	autoModelManager    *automodel.Manager
+	logPrompts          bool
+	checkoutBase        bool
+	promptAttempts      map[string]int
+	extraArgs           []string
+	newExecutor         ExecutorFactory // nil = newCmdExecutor
+	autoResponses       []AutoResponse
+	verifyCommand       string
+	clipLimits          ClipLimits
+	scanBufferSize      int
 }
 
 func NewManager(workDir string) *Manager {
@@ -181,6 +229,7 @@ func NewManager(workDir string) *Manager {
 		workDir:          workDir,
 		config:           DefaultConfig(),
 		autoModelManager: automodel.NewManager(),
+		promptAttempts:   make(map[string]int),
 	}
 }
 
@@ -190,6 +239,7 @@ func NewManagerWithConfig(workDir string, config Config) *Manager {
 		workDir:          workDir,
 		config:           config,
 		autoModelManager: automodel.NewManager(),
+		promptAttempts:   make(map[string]int),
 	}
 }
 
@@ -199,6 +249,13 @@ func (m *Manager) SetConfig(config Config) {
 	m.config = config
 }
 
+// GetConfig returns the manager's current config
+func (m *Manager) GetConfig() Config {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.config
+}
+
 // SetSpawnCallback sets the global spawn callback for all instances
 func (m *Manager) SetSpawnCallback(callback SpawnCallback) {
 	m.mu.Lock()
@@ -213,10 +270,35 @@ func (m *Manager) SetModelChangeCallback(callback ModelChangeCallback) {
 	m.modelChangeCallback = callback
 }
 
+// SetPromptLogging enables writing the full prompt of every attempt to
+// .ralph/prompts/<featureID>-attempt<N>.md
+func (m *Manager) SetPromptLogging(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logPrompts = enabled
+}
+
 // StartInstanceOptions contains optional parameters for starting an instance
 type StartInstanceOptions struct {
 	IsLeafTask bool
 	TaskCount  int
+	// Tasks are the feature's task descriptions, used to estimate progress
+	Tasks []string
+	// Base is the commit or tag checked out first when SetCheckoutBase is on
+	Base string
+	// Attempt is the attempt number used when logging the prompt. If zero,
+	// the manager counts starts of the feature itself.
+	Attempt int
+	// BudgetTokens and BudgetUSD are the feature's own budget, reserved
+	// against the global budget in place of the default estimate
+	BudgetTokens int64
+	BudgetUSD    float64
+	// ResumeSessionID continues an earlier claude session with --resume
+	// instead of starting a new one
+	ResumeSessionID string
+	// OwnConcurrencyLimit skips the manager's MaxConcurrent check, for a
+	// child whose parent caps its children with Max-Children-Concurrent:
+	OwnConcurrencyLimit bool
 }
 
 func (m *Manager) StartInstance(featureID string, model string, prompt string) (*Instance, error) {
@@ -233,7 +315,7 @@ func (m *Manager) StartInstanceWithOptions(featureID string, model string, promp
 		}
 	}
 
-	if m.config.MaxConcurrent > 0 {
+	if m.config.MaxConcurrent > 0 && !opts.OwnConcurrencyLimit {
 		running := 0
 		for _, inst := range m.instances {
 			if inst.GetStatus() == "running" {
@@ -245,6 +327,17 @@ func (m *Manager) StartInstanceWithOptions(featureID string, model string, promp
 		}
 	}
 
+	displayID := featureID
+	if len(displayID) > 8 {
+		displayID = displayID[:8]
+	}
+
+	reservation := estimateReservation(prompt, model, opts.BudgetTokens, opts.BudgetUSD)
+	if err := m.checkReservationLocked(reservation); err != nil {
+		logger.Warn("runner", "Not starting instance, budget reserved", "featureID", displayID, "error", err)
+		return nil, err
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	isAutoModel := automodel.IsAutoMode(model)
@@ -265,33 +358,34 @@ func (m *Manager) StartInstanceWithOptions(featureID string, model string, promp
 		Status:              "starting",
 		StartedAt:           time.Now(),
 		cancel:              cancel,
+		ctx:                 ctx,
 		outputCh:            make(chan OutputLine, 100),
 		TestResults:         &TestResults{},
 		Usage:               usage.New(),
 		SpawnCallback:       m.spawnCallback,
 		ModelChangeCallback: m.modelChangeCallback,
 		autoSelector:        selector,
+		reservation:         reservation,
+		warningThreshold:    m.config.WarningThreshold,
+		loops:               newLoopDetector(m.config.LoopThreshold, m.config.LoopWindow),
+		cancelOnLoop:        m.config.CancelOnLoop,
+		verifyCommand:       m.verifyCommand,
+		workDir:             m.workDir,
+		clipLimits:          m.clipLimits,
+		scanBufferSize:      m.scanBufferSize,
 	}
-
-	args := []string{
-		"--dangerously-skip-permissions",
-		"--verbose",
-		"--output-format", "stream-json",
+	if len(opts.Tasks) > 0 {
+		inst.progress = newTaskProgress(opts.Tasks)
 	}
 
-	if actualModel != "" && actualModel != "sonnet" {
-		args = append(args, "--model", actualModel)
-	}
+	args := m.buildArgs(actualModel, prompt, opts.ResumeSessionID)
 
-	args = append(args, "-p", prompt)
-
-	inst.cmd = exec.CommandContext(ctx, "claude", args...)
-	inst.cmd.Dir = m.workDir
-
-	displayID := featureID
-	if len(displayID) > 8 {
-		displayID = displayID[:8]
+	newExecutor := m.newExecutor
+	if newExecutor == nil {
+		newExecutor = newCmdExecutor
 	}
+	inst.executor = newExecutor(ctx, m.workDir, args)
+
 	logModel := actualModel
 	if isAutoModel {
 		logModel = fmt.Sprintf("auto->%s", actualModel)
@@ -300,63 +394,126 @@ func (m *Manager) StartInstanceWithOptions(featureID string, model string, promp
 		"featureID", displayID,
 		"model", logModel,
 		"workDir", m.workDir,
-		"promptLen", len(prompt))
+		"promptLen", len(prompt),
+		"resume", opts.ResumeSessionID)
 	logger.Debug("runner", "Full command args", "args", strings.Join(args[:len(args)-1], " ")+" -p <prompt>")
 
-	stdout, err := inst.cmd.StdoutPipe()
+	attempt := opts.Attempt
+	if attempt <= 0 {
+		attempt = m.promptAttempts[featureID] + 1
+	}
+	m.promptAttempts[featureID] = attempt
+	if m.logPrompts {
+		if path, err := writePromptLog(m.workDir, featureID, attempt, prompt); err != nil {
+			logger.Warn("runner", "Failed to log prompt", "featureID", displayID, "error", err)
+		} else {
+			logger.Debug("runner", "Prompt logged", "featureID", displayID, "attempt", attempt, "path", path)
+		}
+	}
+
+	stdout, stderr, err := inst.executor.Pipes()
 	if err != nil {
 		cancel()
-		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+		return nil, fmt.Errorf("failed to create output pipes: %w", err)
 	}
 
-	stderr, err := inst.cmd.StderrPipe()
-	if err != nil {
+	if len(m.autoResponses) > 0 {
+		if piper, ok := inst.executor.(stdinPiper); ok {
+			stdin, err := piper.StdinPipe()
+			if err != nil {
+				cancel()
+				return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+			}
+			inst.responder = &autoResponder{stdin: stdin, responses: m.autoResponses}
+		} else {
+			logger.Warn("runner", "Executor has no stdin; auto-responses are off", "featureID", displayID)
+		}
+	}
+
+	var base string
+	if m.checkoutBase {
+		base = opts.Base
+	}
+	if err := claimWorkDir(m.workDir, base); err != nil {
 		cancel()
-		return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
+		logger.Error("runner", "Failed to check out base", "featureID", displayID, "base", base, "error", err)
+		return nil, err
+	}
+	if base != "" {
+		logger.Info("runner", "Checked out base", "featureID", displayID, "base", base)
 	}
 
-	if err := inst.cmd.Start(); err != nil {
+	if err := inst.executor.Start(); err != nil {
 		cancel()
+		releaseWorkDir(m.workDir)
 		logger.Error("runner", "Failed to start claude", "featureID", displayID, "error", err)
 		return nil, fmt.Errorf("failed to start claude: %w", err)
 	}
 
-	logger.Info("runner", "Claude process started", "featureID", displayID, "pid", inst.cmd.Process.Pid)
+	logger.Info("runner", "Claude process started", "featureID", displayID, "pid", executorPid(inst.executor))
+
+	if f, err := openOutputLog(m.workDir, featureID); err != nil {
+		logger.Warn("runner", "Failed to open output log", "featureID", displayID, "error", err)
+	} else {
+		inst.outputLog = f
+	}
 
 	inst.Status = "running"
 	m.instances[featureID] = inst
 
-	go inst.readOutput(stdout, "stdout")
-	go inst.readOutput(stderr, "stderr")
-	go inst.waitForCompletion()
+	// Both streams are read to EOF before waiting on the executor, so none
+	// of the output is lost
+	var readers sync.WaitGroup
+	readers.Add(2)
+	go func() {
+		defer readers.Done()
+		inst.readOutput(stdout, "stdout")
+	}()
+	go func() {
+		defer readers.Done()
+		inst.readOutput(stderr, "stderr")
+	}()
+	go func() {
+		readers.Wait()
+		inst.closeStdin()
+		inst.waitForCompletion()
+		releaseWorkDir(m.workDir)
+	}()
 
 	return inst, nil
 }
 
 func (inst *Instance) readOutput(r io.Reader, source string) {
-	scanner := bufio.NewScanner(r)
-	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	lines := newLineReader(r, inst.scanBufferSize)
 
 	featureShort := inst.FeatureID
 	if len(featureShort) > 8 {
 		featureShort = featureShort[:8]
 	}
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "" {
+	for {
+		line, length, err := lines.next()
+		if errors.Is(err, errLineTooLong) {
+			inst.skipLongLine(source, length, lines.size)
 			continue
 		}
-
-		outputLine := OutputLine{
-			Timestamp: time.Now(),
-			Raw:       json.RawMessage(line),
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				logger.Warn("runner", "Stopped reading output", "featureID", featureShort, "source", source, "error", err)
+			}
+			return
+		}
+		if line == "" {
+			continue
 		}
 
-		var msg StreamMessage
-		if err := json.Unmarshal([]byte(line), &msg); err == nil {
-			outputLine.Type = msg.Type
-			outputLine.Subtype = msg.Subtype
+		outputLine, msg := parseOutputLine(line, source, time.Now())
+		if msg != nil {
+			if msg.SessionID != "" {
+				inst.mu.Lock()
+				inst.SessionID = msg.SessionID
+				inst.mu.Unlock()
+			}
 
 			// Parse token usage from stream-json
 			inst.Usage.ParseLine(line)
@@ -377,29 +534,18 @@ func (inst *Instance) readOutput(r io.Reader, source string) {
 
 			switch msg.Type {
 			case "assistant":
-				outputLine.Content = extractTextContent(msg.Message)
-				if outputLine.Content == "" {
-					outputLine.Content = msg.Content
-				}
 				inst.detectTestResults(outputLine.Content)
-				if len(outputLine.Content) > 200 {
-					outputLine.Content = outputLine.Content[:200] + "..."
-				}
+				inst.detectTaskCompletion(outputLine.Content)
 			case "user":
-				outputLine.Content = extractTextContent(msg.Message)
-				if outputLine.Content == "" {
-					outputLine.Content = msg.Content
-				}
-			case "system":
-				outputLine.Content = msg.Content
-				outputLine.Subtype = msg.Subtype
+				inst.addToolErrors(toolErrorCount(msg.Message))
 			case "tool_use":
-				outputLine.Tool = msg.Tool
-				outputLine.Content = fmt.Sprintf("[Tool: %s]", msg.Tool)
 				if action := actions.ExtractAction(msg.Tool, msg.ToolInput, outputLine.Timestamp); action != nil {
 					inst.mu.Lock()
 					inst.Actions = append(inst.Actions, *action)
+					inst.LinesAdded += action.LinesAdded
+					inst.LinesRemoved += action.LinesRemoved
 					inst.mu.Unlock()
+					inst.detectTaskFromAction(*action)
 				}
 				// Check for spawn request
 				if msg.Tool == "ralph_spawn_feature" {
@@ -411,44 +557,128 @@ func (inst *Instance) readOutput(r io.Reader, source string) {
 					}
 				}
 			case "tool_result":
-				outputLine.Content = msg.Result
 				if msg.IsError {
-					outputLine.Subtype = "error"
+					inst.addToolErrors(1)
 				}
 				inst.detectTestResults(msg.Result)
-				if len(outputLine.Content) > 500 {
-					outputLine.Content = outputLine.Content[:500] + "..."
-				}
-			case "result":
-				outputLine.Subtype = msg.Subtype
-				if msg.Subtype == "success" {
-					outputLine.Content = "[Completed successfully]"
-				} else if msg.Subtype == "error" {
-					outputLine.Content = fmt.Sprintf("[Error: %s]", msg.Result)
+				if msg.IsError {
+					inst.detectPermissionRequest(msg.Result)
 				}
 			case "error":
-				outputLine.Content = msg.Result
 				inst.mu.Lock()
 				inst.Error = msg.Result
 				inst.mu.Unlock()
-			default:
-				outputLine.Content = line
+				inst.detectPermissionRequest(msg.Result)
+			case "result":
+				inst.recordResultStats(msg)
 			}
 		} else {
-			outputLine.Type = source
-			outputLine.Content = line
 			inst.detectTestResults(line)
+			inst.detectPermissionRequest(line)
 		}
+		clipContent(&outputLine, inst.clipLimits)
 
 		inst.mu.Lock()
-		inst.output = append(inst.output, outputLine)
+		inst.LastOutputAt = outputLine.Timestamp
+		if source == "stdout" {
+			inst.recordStdoutLineLocked(msg != nil)
+		}
+		inst.writeOutputLogLocked(line)
 		inst.mu.Unlock()
+		inst.emitOutput(outputLine)
+
+		if inst.responder != nil {
+			if pattern, err := inst.responder.respond(line); err != nil {
+				logger.Warn("runner", "Failed to write auto-response", "featureID", featureShort, "pattern", pattern, "error", err)
+			} else if pattern != "" {
+				logger.Info("runner", "Auto-responded to prompt", "featureID", featureShort, "pattern", pattern)
+			}
+			if msg != nil && msg.Type == "result" {
+				inst.closeStdin()
+			}
+		}
+
+		if msg != nil && msg.Type == "tool_use" {
+			inst.detectToolLoop(msg.Tool, msg.ToolInput)
+		}
+	}
+}
 
-		select {
-		case inst.outputCh <- outputLine:
-		default:
+// emitOutput records an output line and passes it to whoever is listening
+func (inst *Instance) emitOutput(outputLine OutputLine) {
+	inst.mu.Lock()
+	inst.output = append(inst.output, outputLine)
+	inst.mu.Unlock()
+
+	select {
+	case inst.outputCh <- outputLine:
+	default:
+	}
+}
+
+// parseOutputLine converts a line of claude output into its display form,
+// with the full content. The message is nil when the line isn't stream-json,
+// in which case it is shown as coming from source.
+func parseOutputLine(line, source string, ts time.Time) (OutputLine, *StreamMessage) {
+	outputLine := OutputLine{
+		Timestamp: ts,
+		Raw:       json.RawMessage(line),
+	}
+
+	var msg StreamMessage
+	if err := json.Unmarshal([]byte(line), &msg); err != nil {
+		outputLine.Type = source
+		outputLine.Content = line
+		return outputLine, nil
+	}
+
+	outputLine.Type = msg.Type
+	outputLine.Subtype = msg.Subtype
+
+	switch msg.Type {
+	case "assistant", "user":
+		outputLine.Content = extractTextContent(msg.Message)
+		if outputLine.Content == "" {
+			outputLine.Content = msg.Content
+		}
+	case "system":
+		outputLine.Content = msg.Content
+	case "tool_use":
+		outputLine.Tool = msg.Tool
+		outputLine.Content = fmt.Sprintf("[Tool: %s]", msg.Tool)
+	case "tool_result":
+		outputLine.Content = msg.Result
+		if msg.IsError {
+			outputLine.Subtype = "error"
 		}
+	case "result":
+		if msg.Subtype == "success" {
+			outputLine.Content = "[Completed successfully]"
+		} else if msg.Subtype == "error" {
+			outputLine.Content = fmt.Sprintf("[Error: %s]", msg.Result)
+		}
+	case "error":
+		outputLine.Content = msg.Result
+	default:
+		outputLine.Content = line
+	}
+	return outputLine, &msg
+}
+
+// FormatOutputLine renders an output line the way the inspect view shows it.
+// The timestamp is left out when it isn't known.
+func FormatOutputLine(line OutputLine) string {
+	prefix := line.Type
+	if line.Subtype != "" {
+		prefix = fmt.Sprintf("%s:%s", line.Type, line.Subtype)
+	}
+	if line.Tool != "" {
+		prefix = fmt.Sprintf("%s[%s]", line.Type, line.Tool)
 	}
+	if line.Timestamp.IsZero() {
+		return fmt.Sprintf("%s: %s", prefix, line.Content)
+	}
+	return fmt.Sprintf("[%s] %s: %s", line.Timestamp.Format("15:04:05"), prefix, line.Content)
 }
 
 var testPatterns = []*regexp.Regexp{
@@ -494,6 +724,8 @@ func (inst *Instance) detectTestResults(content string) {
 		}
 	}
 
+	inst.detectSkippedTests(content)
+
 	if strings.Contains(content, "ok  \t") || strings.Contains(content, "PASS") {
 		inst.TestResults.Output += content + "\n"
 	}
@@ -510,16 +742,24 @@ func (inst *Instance) waitForCompletion() {
 		featureShort = featureShort[:8]
 	}
 
-	err := inst.cmd.Wait()
+	err := inst.executor.Wait()
+	outputHeld := errors.Is(err, errOutputHeld)
+	if outputHeld {
+		err = nil
+		inst.reportHeldOutput()
+	}
+	verifyFailure := inst.verify(err)
 	inst.mu.Lock()
 	defer inst.mu.Unlock()
 
 	now := time.Now()
 	inst.CompletedAt = &now
 	duration := now.Sub(inst.StartedAt)
+	inst.classifyMalformedOutputLocked()
 
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
+		var exitErr interface{ ExitCode() int }
+		if errors.As(err, &exitErr) {
 			inst.ExitCode = exitErr.ExitCode()
 		}
 		inst.Status = "failed"
@@ -533,7 +773,10 @@ func (inst *Instance) waitForCompletion() {
 			"duration", duration.Round(time.Second))
 	} else {
 		inst.ExitCode = 0
-		if inst.TestResults.Failed > 0 {
+		if inst.FailureClass != "" {
+			// Already failed and cancelled while reading output
+			inst.Status = "failed"
+		} else if inst.TestResults.Failed > 0 {
 			inst.Status = "failed"
 			inst.Error = fmt.Sprintf("%d tests failed", inst.TestResults.Failed)
 			logger.Warn("runner", "Instance completed with test failures",
@@ -541,6 +784,22 @@ func (inst *Instance) waitForCompletion() {
 				"passed", inst.TestResults.Passed,
 				"failed", inst.TestResults.Failed,
 				"duration", duration.Round(time.Second))
+		} else if verifyFailure != "" {
+			// Claude reported success but the verify command disagrees
+			inst.Status = "failed"
+			inst.Error = verifyFailure
+			logger.Warn("runner", "Instance failed verification",
+				"featureID", featureShort,
+				"command", inst.verifyCommand,
+				"duration", duration.Round(time.Second))
+		} else if inst.hasWarningsLocked() || outputHeld {
+			inst.Status = StatusCompletedWithWarnings
+			logger.Warn("runner", "Instance completed with warnings",
+				"featureID", featureShort,
+				"toolErrors", inst.ToolErrors,
+				"skipped", inst.TestResults.Skipped,
+				"outputHeld", outputHeld,
+				"duration", duration.Round(time.Second))
 		} else {
 			inst.Status = "completed"
 			logger.Info("runner", "Instance completed successfully",
@@ -549,6 +808,7 @@ func (inst *Instance) waitForCompletion() {
 				"duration", duration.Round(time.Second))
 		}
 	}
+	inst.closeOutputLogLocked()
 	close(inst.outputCh)
 }
 
@@ -647,6 +907,69 @@ func (inst *Instance) GetCurrentModel() string {
 	return inst.Model
 }
 
+// ExplainModel returns why auto model selection started the instance on its
+// model, or false if the instance doesn't use auto model selection
+func (inst *Instance) ExplainModel() (automodel.Explanation, bool) {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	if inst.autoSelector == nil {
+		return automodel.Explanation{}, false
+	}
+	return inst.autoSelector.Explain(), true
+}
+
+// IdleFor returns how long a running instance has gone without output as of
+// now, counting from when it started until its first line. It is 0 once the
+// instance has stopped running.
+func (inst *Instance) IdleFor(now time.Time) time.Duration {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	if inst.Status != "running" {
+		return 0
+	}
+	last := inst.LastOutputAt
+	if last.Before(inst.StartedAt) {
+		last = inst.StartedAt
+	}
+	if idle := now.Sub(last); idle > 0 {
+		return idle
+	}
+	return 0
+}
+
+// recordResultStats keeps the duration and turn count claude reports in its
+// final result message
+func (inst *Instance) recordResultStats(msg *StreamMessage) {
+	turns := msg.NumTurns
+	if turns == 0 {
+		turns = msg.NumMessages
+	}
+
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+	if msg.Duration > 0 {
+		inst.CLIDuration = time.Duration(msg.Duration * float64(time.Millisecond))
+	}
+	if turns > 0 {
+		inst.NumTurns = turns
+	}
+}
+
+// GetResultStats returns the run time and turn count claude reported when it
+// finished, or zeros before its result message
+func (inst *Instance) GetResultStats() (time.Duration, int) {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	return inst.CLIDuration, inst.NumTurns
+}
+
+// GetSessionID returns the claude session ID, or "" if none has been reported
+func (inst *Instance) GetSessionID() string {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	return inst.SessionID
+}
+
 func (inst *Instance) GetError() string {
 	inst.mu.RLock()
 	defer inst.mu.RUnlock()
@@ -668,18 +991,8 @@ func (inst *Instance) GetOutput() string {
 
 	var sb strings.Builder
 	for _, line := range inst.output {
-		prefix := line.Type
-		if line.Subtype != "" {
-			prefix = fmt.Sprintf("%s:%s", line.Type, line.Subtype)
-		}
-		if line.Tool != "" {
-			prefix = fmt.Sprintf("%s[%s]", line.Type, line.Tool)
-		}
-		sb.WriteString(fmt.Sprintf("[%s] %s: %s\n",
-			line.Timestamp.Format("15:04:05"),
-			prefix,
-			line.Content,
-		))
+		sb.WriteString(FormatOutputLine(line))
+		sb.WriteString("\n")
 	}
 	return sb.String()
 }
@@ -700,6 +1013,21 @@ func (inst *Instance) GetActions() []actions.Action {
 	return result
 }
 
+// GetLineChanges returns the lines added and removed by Edit and Write calls
+// so far
+func (inst *Instance) GetLineChanges() (added, removed int) {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	return inst.LinesAdded, inst.LinesRemoved
+}
+
+// GetActionCount returns the number of tool calls recorded so far
+func (inst *Instance) GetActionCount() int {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	return len(inst.Actions)
+}
+
 func (inst *Instance) GetActionSummary() actions.ActionSummary {
 	inst.mu.RLock()
 	defer inst.mu.RUnlock()
@@ -711,6 +1039,8 @@ func (inst *Instance) GetActionSummary() actions.ActionSummary {
 			summary.Files++
 		case actions.ActionBash:
 			summary.Commands++
+		case actions.ActionTestRun:
+			summary.TestRuns++
 		case actions.ActionTask, actions.ActionAgent:
 			summary.Agents++
 		case actions.ActionRead:
@@ -972,41 +1302,62 @@ func (m *Manager) HasGlobalBudget() bool {
 }
 
 // CheckGlobalBudget checks total usage against global budget
-// Returns: percent used, at threshold (>=90%), over budget
 func (m *Manager) CheckGlobalBudget() (percent float64, atThreshold bool, overBudget bool) {
+	tokens, usd, spentTokens, spentUSD := m.globalBudgetUsage()
+	return CheckBudget(tokens, usd, spentTokens, spentUSD)
+}
+
+// globalBudgetUsage returns the global budget and what counts against it:
+// every instance's usage plus prior usage
+func (m *Manager) globalBudgetUsage() (tokens int64, usd float64, spentTokens int64, spentUSD float64) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	if m.globalBudgetTokens == 0 && m.globalBudgetUSD == 0 {
-		return 0, false, false
-	}
-
-	total := usage.New()
+	spentTokens, spentUSD = m.priorTokens, m.priorUSD
 	for _, inst := range m.instances {
 		instUsage := inst.GetUsage()
-		total.Add(&instUsage)
+		spentTokens += instUsage.TotalTokens
+		spentUSD += inst.GetEstimatedCost()
 	}
-	snapshot := total.Snapshot()
+	return m.globalBudgetTokens, m.globalBudgetUSD, spentTokens, spentUSD
+}
 
-	if m.globalBudgetTokens > 0 {
-		percent = float64(snapshot.TotalTokens) / float64(m.globalBudgetTokens) * 100
-		atThreshold = percent >= 90
-		overBudget = snapshot.TotalTokens >= m.globalBudgetTokens
-		return
+// CheckBudget compares spending with a budget of tokens, or failing that
+// USD, where 0 means no limit. The threshold is reached at 90%.
+func CheckBudget(tokens int64, usd float64, spentTokens int64, spentUSD float64) (percent float64, atThreshold bool, overBudget bool) {
+	if tokens > 0 {
+		percent = float64(spentTokens) / float64(tokens) * 100
+		return percent, percent >= 90, spentTokens >= tokens
 	}
+	if usd > 0 {
+		percent = spentUSD / usd * 100
+		return percent, percent >= 90, spentUSD >= usd
+	}
+	return 0, false, false
+}
 
-	if m.globalBudgetUSD > 0 {
-		var totalCost float64
-		for _, inst := range m.instances {
-			totalCost += inst.GetEstimatedCost()
-		}
-		percent = totalCost / m.globalBudgetUSD * 100
-		atThreshold = percent >= 90
-		overBudget = totalCost >= m.globalBudgetUSD
-		return
+// FormatBudgetStatus describes spending against a budget, in USD if it sets
+// one and tokens otherwise, e.g. "$4.50/$10.00 (45%)". It is "" with no
+// budget.
+func FormatBudgetStatus(tokens int64, usd float64, spentTokens int64, spentUSD float64) string {
+	if usd > 0 {
+		return fmt.Sprintf("$%.2f/$%.2f (%.0f%%)", spentUSD, usd, spentUSD/usd*100)
+	}
+	if tokens > 0 {
+		percent := float64(spentTokens) / float64(tokens) * 100
+		return fmt.Sprintf("%s/%s (%.0f%%)", usage.FormatTokens(spentTokens), usage.FormatTokens(tokens), percent)
 	}
+	return ""
+}
 
-	return 0, false, false
+// AddPriorUsage counts usage from outside the manager's instances, such as
+// features an earlier manager ran in the same headless run, toward the
+// global budget
+func (m *Manager) AddPriorUsage(tokens int64, usd float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.priorTokens += tokens
+	m.priorUSD += usd
 }
 
 // AcknowledgeBudget marks the budget as acknowledged (user chose to continue)
@@ -1032,28 +1383,5 @@ func (m *Manager) ResetBudgetAcknowledgement() {
 
 // GetGlobalBudgetStatus returns a formatted budget status string
 func (m *Manager) GetGlobalBudgetStatus() string {
-	m.mu.RLock()
-	tokens := m.globalBudgetTokens
-	usd := m.globalBudgetUSD
-	m.mu.RUnlock()
-
-	if tokens == 0 && usd == 0 {
-		return ""
-	}
-
-	total := m.GetTotalUsage()
-	snapshot := total
-
-	if usd > 0 {
-		totalCost := m.GetTotalCost()
-		percent := totalCost / usd * 100
-		return fmt.Sprintf("$%.2f/$%.2f (%.0f%%)", totalCost, usd, percent)
-	}
-
-	if tokens > 0 {
-		percent := float64(snapshot.TotalTokens) / float64(tokens) * 100
-		return fmt.Sprintf("%s/%s (%.0f%%)", usage.FormatTokens(snapshot.TotalTokens), usage.FormatTokens(tokens), percent)
-	}
-
-	return ""
+	return FormatBudgetStatus(m.globalBudgetUsage())
 }
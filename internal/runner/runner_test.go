@@ -0,0 +1,145 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadOutputCapturesSessionID(t *testing.T) {
+	inst := newTestInstance(nil)
+
+	output := `{"type":"system","subtype":"init","session_id":"9f2c1a7e-session"}
+{"type":"assistant","message":{"content":"Working on it"},"session_id":"9f2c1a7e-session"}
+{"type":"result","subtype":"success","result":"done"}
+`
+	inst.readOutput(strings.NewReader(output), "stdout")
+
+	if got := inst.GetSessionID(); got != "9f2c1a7e-session" {
+		t.Errorf("expected session ID 9f2c1a7e-session, got %q", got)
+	}
+}
+
+func TestReadOutputWithoutSessionID(t *testing.T) {
+	inst := newTestInstance(nil)
+
+	inst.readOutput(strings.NewReader(`{"type":"assistant","message":{"content":"hi"}}`+"\n"), "stdout")
+
+	if got := inst.GetSessionID(); got != "" {
+		t.Errorf("expected no session ID, got %q", got)
+	}
+}
+
+// finishWithOutput feeds output to the instance as if from a claude process
+// that exits 0
+func finishWithOutput(t *testing.T, inst *Instance, output string) {
+	t.Helper()
+	inst.executor = &fakeExecutor{}
+	inst.readOutput(strings.NewReader(output), "stdout")
+	inst.waitForCompletion()
+}
+
+func TestCompletedWithWarningsOnToolErrors(t *testing.T) {
+	inst := newTestInstance(nil)
+	inst.warningThreshold = 3
+
+	output := `{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"command not found","is_error":true}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t2","content":"no such file","is_error":true},{"type":"tool_result","tool_use_id":"t3","content":"ok"}]}}
+{"type":"tool_result","result":"permission denied reading cache","is_error":true}
+{"type":"result","subtype":"success","result":"done"}
+`
+	finishWithOutput(t, inst, output)
+
+	if got := inst.GetStatus(); got != StatusCompletedWithWarnings {
+		t.Errorf("expected status %s, got %s", StatusCompletedWithWarnings, got)
+	}
+	if toolErrors, _ := inst.GetWarnings(); toolErrors != 3 {
+		t.Errorf("expected 3 tool errors, got %d", toolErrors)
+	}
+	if !IsCompleted(inst.GetStatus()) {
+		t.Error("expected completed_with_warnings to count as completed")
+	}
+}
+
+func TestCompletedWithWarningsOnSkippedTests(t *testing.T) {
+	inst := newTestInstance(nil)
+	inst.warningThreshold = 2
+
+	output := `{"type":"assistant","message":{"content":"Tests: 12 passed, 4 skipped"}}
+`
+	finishWithOutput(t, inst, output)
+
+	if got := inst.GetStatus(); got != StatusCompletedWithWarnings {
+		t.Errorf("expected status %s, got %s", StatusCompletedWithWarnings, got)
+	}
+	if _, skipped := inst.GetWarnings(); skipped != 4 {
+		t.Errorf("expected 4 skipped tests, got %d", skipped)
+	}
+}
+
+func TestCompletedBelowWarningThreshold(t *testing.T) {
+	inst := newTestInstance(nil)
+	inst.warningThreshold = 3
+
+	output := `{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"t1","content":"oops","is_error":true}]}}
+{"type":"result","subtype":"success","result":"done"}
+`
+	finishWithOutput(t, inst, output)
+
+	if got := inst.GetStatus(); got != "completed" {
+		t.Errorf("expected clean completion below the threshold, got %s", got)
+	}
+}
+
+func TestWarningThresholdZeroDisablesWarnings(t *testing.T) {
+	inst := newTestInstance(nil)
+
+	output := `{"type":"tool_result","result":"failed","is_error":true}
+`
+	finishWithOutput(t, inst, output)
+
+	if got := inst.GetStatus(); got != "completed" {
+		t.Errorf("expected completed with threshold 0, got %s", got)
+	}
+}
+
+func TestLineChangesAccumulateFromActions(t *testing.T) {
+	inst := newTestInstance(nil)
+
+	output := `{"type":"tool_use","tool":"Write","tool_input":{"file_path":"/app/login.go","content":"package app\n\nfunc Login() {}\n"}}
+{"type":"tool_use","tool":"Edit","tool_input":{"file_path":"/app/login.go","old_string":"func Login() {}","new_string":"func Login() error {\n\treturn nil\n}"}}
+{"type":"tool_use","tool":"Edit","tool_input":{"file_path":"/app/main.go","old_string":"a\nb\nc\nd","new_string":"a\nd"}}
+{"type":"tool_use","tool":"Read","tool_input":{"file_path":"/app/main.go"}}
+{"type":"result","subtype":"success","result":"done"}
+`
+	finishWithOutput(t, inst, output)
+
+	added, removed := inst.GetLineChanges()
+	if added != 6 || removed != 3 {
+		t.Errorf("expected +6/-3, got +%d/-%d", added, removed)
+	}
+}
+
+func TestIdleForTracksLastOutput(t *testing.T) {
+	inst := newTestInstance(func() {})
+	inst.StartedAt = time.Now().Add(-5 * time.Minute)
+
+	now := inst.StartedAt.Add(3 * time.Minute)
+	if got := inst.IdleFor(now); got != 3*time.Minute {
+		t.Errorf("expected idle since start before any output, got %v", got)
+	}
+
+	inst.readOutput(strings.NewReader(`{"type":"assistant","message":{"content":"Working"}}`+"\n"), "stdout")
+	last := inst.LastOutputAt
+	if last.IsZero() {
+		t.Fatal("expected LastOutputAt to be set by output")
+	}
+	if got := inst.IdleFor(last.Add(90 * time.Second)); got != 90*time.Second {
+		t.Errorf("expected idle since the last line, got %v", got)
+	}
+
+	inst.Status = "completed"
+	if got := inst.IdleFor(last.Add(time.Hour)); got != 0 {
+		t.Errorf("expected a finished instance not to be idle, got %v", got)
+	}
+}
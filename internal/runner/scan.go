@@ -0,0 +1,87 @@
+package runner
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/vx/ralph-go/internal/logger"
+)
+
+// DefaultScanBufferSize is the longest output line, in bytes, read unless
+// the manager sets another size
+const DefaultScanBufferSize = 1024 * 1024
+
+// minScanBufferSize is the smallest buffer bufio will allocate
+const minScanBufferSize = 16
+
+// errLineTooLong reports a line longer than the scan buffer, which is skipped
+var errLineTooLong = errors.New("line exceeds the scan buffer")
+
+// SetScanBufferSize sets the longest output line, in bytes, that instances
+// started from now on read. Longer lines, such as a very large tool result,
+// are skipped with a warning and reading carries on. 0 restores
+// DefaultScanBufferSize.
+func (m *Manager) SetScanBufferSize(size int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.scanBufferSize = size
+}
+
+// lineReader reads output a line at a time, skipping lines longer than its
+// buffer instead of stopping at them as bufio.Scanner does
+type lineReader struct {
+	r    *bufio.Reader
+	size int
+}
+
+func newLineReader(r io.Reader, size int) *lineReader {
+	if size <= 0 {
+		size = DefaultScanBufferSize
+	}
+	size = max(size, minScanBufferSize)
+	return &lineReader{r: bufio.NewReaderSize(r, size), size: size}
+}
+
+// next returns the next line without its line ending. A line too long for
+// the buffer is consumed and reported as errLineTooLong with its length.
+// The last line may lack a newline; io.EOF follows it.
+func (lr *lineReader) next() (line string, length int, err error) {
+	data, err := lr.r.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		length = len(data)
+		for errors.Is(err, bufio.ErrBufferFull) {
+			data, err = lr.r.ReadSlice('\n')
+			length += len(data)
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", length, err
+		}
+		return "", length, errLineTooLong
+	}
+	if err != nil && (len(data) == 0 || !errors.Is(err, io.EOF)) {
+		return "", 0, err
+	}
+	line = strings.TrimSuffix(string(data), "\n")
+	line = strings.TrimSuffix(line, "\r")
+	return line, len(data), nil
+}
+
+// skipLongLine warns that a line of length bytes from source was too long to
+// read and has been skipped
+func (inst *Instance) skipLongLine(source string, length, limit int) {
+	logger.Warn("runner", "Skipped output line longer than the scan buffer",
+		"featureID", inst.FeatureID,
+		"source", source,
+		"bytes", length,
+		"limit", limit)
+	inst.emitOutput(OutputLine{
+		Timestamp: time.Now(),
+		Type:      "ralph",
+		Subtype:   "line_too_long",
+		Content:   fmt.Sprintf("skipped a %d byte line from %s, longer than the %d byte scan buffer", length, source, limit),
+	})
+}
@@ -0,0 +1,44 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadOutputSkipsLinesLongerThanScanBuffer(t *testing.T) {
+	inst := newTestInstance(nil)
+	inst.scanBufferSize = 64
+
+	huge := `{"type":"tool_result","result":"` + strings.Repeat("x", 200) + `"}`
+	output := `{"type":"assistant","message":{"content":"before"}}` + "\n" +
+		huge + "\n" +
+		`{"type":"assistant","message":{"content":"after"}}` + "\r\n" +
+		`{"type":"result","subtype":"success","result":"done"}`
+	inst.readOutput(strings.NewReader(output), "stdout")
+
+	lines := inst.GetOutputLines()
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 output lines, got %d: %+v", len(lines), lines)
+	}
+	if lines[0].Content != "before" || lines[2].Content != "after" {
+		t.Errorf("expected the lines around the long one to be read, got %q and %q", lines[0].Content, lines[2].Content)
+	}
+	if lines[1].Type != "ralph" || lines[1].Subtype != "line_too_long" {
+		t.Errorf("expected a line_too_long notice, got %s/%s", lines[1].Type, lines[1].Subtype)
+	}
+	if !strings.Contains(lines[1].Content, "64 byte scan buffer") {
+		t.Errorf("expected the notice to name the limit, got %q", lines[1].Content)
+	}
+	if lines[3].Type != "result" {
+		t.Errorf("expected the final line without a newline to be read, got %s", lines[3].Type)
+	}
+}
+
+func TestLineReaderDefaultSize(t *testing.T) {
+	if got := newLineReader(strings.NewReader(""), 0).size; got != DefaultScanBufferSize {
+		t.Errorf("expected the default size, got %d", got)
+	}
+	if got := newLineReader(strings.NewReader(""), 1).size; got != minScanBufferSize {
+		t.Errorf("expected the size raised to the minimum, got %d", got)
+	}
+}
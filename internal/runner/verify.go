@@ -0,0 +1,90 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// verifyOutputLimit is how much of the tail of a failed verify command's
+// output is kept in the instance's error
+const verifyOutputLimit = 500
+
+// verifyTimeout is how long a verify command may run before it is killed
+// and the instance fails verification
+var verifyTimeout = 10 * time.Minute
+
+// verifyMu runs verify commands one at a time, so two features finishing
+// together don't run the project's tests over each other
+var verifyMu sync.Mutex
+
+// SetVerifyCommand sets a shell command, such as the project's test command,
+// run once in the work directory after an instance exits cleanly. If it
+// fails, the instance is marked failed even though claude reported success.
+// Empty turns verification off.
+//
+// Verification isn't isolated: it runs in the shared work directory while
+// other features may still be editing it, so a feature can fail on another
+// feature's half-written changes. Verify commands themselves run one at a
+// time, are killed when the instance is stopped, and time out after 10
+// minutes.
+func (m *Manager) SetVerifyCommand(command string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.verifyCommand = strings.TrimSpace(command)
+}
+
+// verify runs the verify command after claude exits with waitErr, unless
+// verification is off or the instance has already failed. It returns the
+// reason the instance fails verification, or "" if it passes.
+func (inst *Instance) verify(waitErr error) string {
+	inst.mu.RLock()
+	command := inst.verifyCommand
+	failed := inst.FailureClass != "" || inst.TestResults.Failed > 0
+	inst.mu.RUnlock()
+	if command == "" || waitErr != nil || failed {
+		return ""
+	}
+
+	ctx := inst.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, verifyTimeout)
+	defer cancel()
+
+	verifyMu.Lock()
+	defer verifyMu.Unlock()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Dir = inst.workDir
+	// Don't wait on processes the command left holding its output
+	cmd.WaitDelay = time.Second
+	out, err := cmd.CombinedOutput()
+	if err == nil {
+		return ""
+	}
+
+	reason := fmt.Sprintf("verification failed: %s: %v", command, err)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		reason = fmt.Sprintf("verification timed out after %s: %s", verifyTimeout, command)
+	}
+	tail := strings.TrimSpace(string(out))
+	if len(tail) > verifyOutputLimit {
+		tail = "..." + tail[len(tail)-verifyOutputLimit:]
+	}
+	if tail != "" {
+		reason += "\n" + tail
+	}
+	inst.emitOutput(OutputLine{
+		Timestamp: time.Now(),
+		Type:      "ralph",
+		Subtype:   "verify_failed",
+		Content:   reason,
+	})
+	return reason
+}
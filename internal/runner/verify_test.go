@@ -0,0 +1,78 @@
+package runner
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifyCommandFailsCompletedInstance(t *testing.T) {
+	fake := &fakeExecutor{stdout: `{"type":"result","subtype":"success","result":"done"}
+`}
+	m := newFakeManager(t, fake)
+	m.SetVerifyCommand("echo 'FAIL: TestLogin'; exit 1")
+
+	inst, err := m.StartInstance("01", "sonnet", "Build login")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := waitForDone(t, inst)
+
+	if got := inst.GetStatus(); got != "failed" {
+		t.Fatalf("expected verification to fail the instance, got %q", got)
+	}
+	if !strings.Contains(inst.Error, "verification failed") || !strings.Contains(inst.Error, "FAIL: TestLogin") {
+		t.Errorf("expected the verify output in the error, got %q", inst.Error)
+	}
+	reported := false
+	for _, line := range lines {
+		if line.Type == "ralph" && line.Subtype == "verify_failed" {
+			reported = true
+		}
+	}
+	if !reported {
+		t.Error("expected the verification failure in the instance output")
+	}
+}
+
+func TestVerifyCommandPasses(t *testing.T) {
+	fake := &fakeExecutor{stdout: `{"type":"result","subtype":"success","result":"done"}
+`}
+	m := newFakeManager(t, fake)
+	m.SetVerifyCommand("true")
+
+	inst, err := m.StartInstance("01", "sonnet", "Build login")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	waitForDone(t, inst)
+
+	if got := inst.GetStatus(); got != "completed" {
+		t.Errorf("expected completed, got %q", got)
+	}
+}
+
+func TestVerifyCommandTimesOut(t *testing.T) {
+	orig := verifyTimeout
+	verifyTimeout = 100 * time.Millisecond
+	defer func() { verifyTimeout = orig }()
+
+	fake := &fakeExecutor{stdout: `{"type":"result","subtype":"success","result":"done"}
+`}
+	m := newFakeManager(t, fake)
+	m.SetVerifyCommand("sleep 10")
+
+	inst, err := m.StartInstance("01", "sonnet", "Build login")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	start := time.Now()
+	waitForDone(t, inst)
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the verify command to be killed, took %s", elapsed)
+	}
+	if got := inst.GetStatus(); got != "failed" || !strings.Contains(inst.Error, "timed out") {
+		t.Errorf("expected a verification timeout, got %q: %q", got, inst.Error)
+	}
+}
@@ -0,0 +1,95 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// StatusCompletedWithWarnings is the status of an instance that exited
+// cleanly but reported at least its warning threshold of tool errors and
+// skipped tests
+const StatusCompletedWithWarnings = "completed_with_warnings"
+
+// DefaultWarningThreshold is the number of tool errors plus skipped tests at
+// which a successful run is marked StatusCompletedWithWarnings
+const DefaultWarningThreshold = 5
+
+// IsCompleted reports whether status is a successful completion, with or
+// without warnings
+func IsCompleted(status string) bool {
+	return status == "completed" || status == StatusCompletedWithWarnings
+}
+
+var skipPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)(\d+)\s+skip(?:ped|s)?\b`),
+	regexp.MustCompile(`(?i)SKIP:\s*(\d+)`),
+}
+
+var goSkipPattern = regexp.MustCompile(`---\s*SKIP:`)
+
+// detectSkippedTests records skipped tests reported in content. Counts are
+// kept at their highest reported value since summaries repeat across runs.
+// Callers hold inst.mu.
+func (inst *Instance) detectSkippedTests(content string) {
+	for _, pattern := range skipPatterns {
+		if matches := pattern.FindStringSubmatch(content); matches != nil {
+			var n int
+			fmt.Sscanf(matches[1], "%d", &n)
+			if n > inst.TestResults.Skipped {
+				inst.TestResults.Skipped = n
+			}
+		}
+	}
+	inst.TestResults.Skipped += len(goSkipPattern.FindAllStringIndex(content, -1))
+}
+
+// toolErrorCount returns how many tool results in a message's content blocks
+// report an error
+func toolErrorCount(raw json.RawMessage) int {
+	if len(raw) == 0 {
+		return 0
+	}
+	var msg struct {
+		Content []struct {
+			Type    string `json:"type"`
+			IsError bool   `json:"is_error"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return 0
+	}
+	count := 0
+	for _, block := range msg.Content {
+		if block.Type == "tool_result" && block.IsError {
+			count++
+		}
+	}
+	return count
+}
+
+// addToolErrors counts failed tool calls toward the instance's warnings
+func (inst *Instance) addToolErrors(n int) {
+	if n == 0 {
+		return
+	}
+	inst.mu.Lock()
+	inst.ToolErrors += n
+	inst.mu.Unlock()
+}
+
+// GetWarnings returns the tool errors and skipped tests seen so far
+func (inst *Instance) GetWarnings() (toolErrors, skippedTests int) {
+	inst.mu.RLock()
+	defer inst.mu.RUnlock()
+	return inst.ToolErrors, inst.TestResults.Skipped
+}
+
+// hasWarningsLocked reports whether the warnings seen reach the instance's
+// threshold. A threshold of 0 disables the check. Callers hold inst.mu.
+func (inst *Instance) hasWarningsLocked() bool {
+	if inst.warningThreshold <= 0 {
+		return false
+	}
+	return inst.ToolErrors+inst.TestResults.Skipped >= inst.warningThreshold
+}
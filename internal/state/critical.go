@@ -0,0 +1,123 @@
+package state
+
+import (
+	"time"
+
+	"github.com/vx/ralph-go/internal/manifest"
+)
+
+// CriticalPath is the longest chain of dependencies through a manifest
+type CriticalPath struct {
+	Features []string      // Feature IDs along the path, dependencies first
+	Duration time.Duration // Projected time to run the whole path
+}
+
+// CriticalPath returns the chain of dependent features in m that takes the
+// longest to run. Each completed feature counts the time it actually took;
+// every other feature is projected at the average of the completed ones. With
+// nothing completed yet, the longest chain by feature count is returned.
+// Disabled and spawned sub-features aren't part of the graph, and
+// dependencies on unknown features are ignored. A dependency cycle is
+// returned as an error.
+func (p *Progress) CriticalPath(m *manifest.Manifest) (CriticalPath, error) {
+	order, err := m.GetTopologicalOrder()
+	if err != nil {
+		return CriticalPath{}, err
+	}
+
+	features := make(map[string]manifest.ManifestFeature)
+	for _, f := range m.GetRootFeatures() {
+		if !f.Disabled {
+			features[f.ID] = f
+		}
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	average := p.averageCompletedDurationLocked()
+
+	// Longest path ending at each feature, built in dependency order
+	type pathEnd struct {
+		duration time.Duration
+		length   int
+		prev     string
+	}
+	ends := make(map[string]pathEnd, len(features))
+	var last string
+	for _, id := range order {
+		f, ok := features[id]
+		if !ok {
+			continue
+		}
+		end := pathEnd{}
+		for _, depID := range f.DependsOn {
+			dep, ok := ends[depID]
+			if ok && longerPath(dep.duration, dep.length, end.duration, end.length) {
+				end = pathEnd{duration: dep.duration, length: dep.length, prev: depID}
+			}
+		}
+		end.duration += p.projectedDurationLocked(id, average)
+		end.length++
+		ends[id] = end
+
+		if best, ok := ends[last]; !ok || longerPath(end.duration, end.length, best.duration, best.length) {
+			last = id
+		}
+	}
+
+	var path CriticalPath
+	if last == "" {
+		return path, nil
+	}
+	path.Duration = ends[last].duration
+	for id := last; id != ""; id = ends[id].prev {
+		path.Features = append([]string{id}, path.Features...)
+	}
+	return path, nil
+}
+
+// longerPath reports whether a path of duration d and length n is longer
+// than one of bestD and bestN, preferring more features when durations tie
+func longerPath(d time.Duration, n int, bestD time.Duration, bestN int) bool {
+	if d != bestD {
+		return d > bestD
+	}
+	return n > bestN
+}
+
+// averageCompletedDurationLocked returns the average time completed features
+// took, or 0 if none has finished. Caller must hold p.mu.
+func (p *Progress) averageCompletedDurationLocked() time.Duration {
+	var total time.Duration
+	count := 0
+	for _, f := range p.Features {
+		if d, ok := completedDuration(f); ok {
+			total += d
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return total / time.Duration(count)
+}
+
+// projectedDurationLocked returns how long the feature took if it has
+// completed, or average otherwise. Caller must hold p.mu.
+func (p *Progress) projectedDurationLocked(id string, average time.Duration) time.Duration {
+	if f, ok := p.Features[id]; ok {
+		if d, ok := completedDuration(f); ok {
+			return d
+		}
+	}
+	return average
+}
+
+// completedDuration returns how long a successfully completed feature ran
+func completedDuration(f *FeatureState) (time.Duration, bool) {
+	if !isCompleted(f.Status) || f.StartedAt == nil || f.CompletedAt == nil {
+		return 0, false
+	}
+	return f.CompletedAt.Sub(*f.StartedAt), true
+}
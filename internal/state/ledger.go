@@ -0,0 +1,139 @@
+package state
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// AttemptUsage is the token usage and estimated cost of one attempt at a
+// feature
+type AttemptUsage struct {
+	Attempt      int       `json:"attempt"`
+	Model        string    `json:"model,omitempty"`
+	InputTokens  int64     `json:"input_tokens,omitempty"`
+	OutputTokens int64     `json:"output_tokens,omitempty"`
+	CacheRead    int64     `json:"cache_read,omitempty"`
+	CacheWrite   int64     `json:"cache_write,omitempty"`
+	Cost         float64   `json:"cost,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
+// LedgerEntry is one row of the cost ledger: an attempt at a feature
+type LedgerEntry struct {
+	FeatureID string
+	Title     string
+	AttemptUsage
+}
+
+// ledgerHeader is the first row of the cost ledger CSV
+var ledgerHeader = []string{
+	"feature", "title", "attempt", "model",
+	"input_tokens", "output_tokens", "cache_read_tokens", "cache_write_tokens",
+	"cost_usd", "timestamp",
+}
+
+// RecordAttemptUsage adds the usage of the feature's current attempt to its
+// ledger, stamped with the time it finished
+func (p *Progress) RecordAttemptUsage(id, model string, inputTokens, outputTokens, cacheRead, cacheWrite int64, cost float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dirty = true
+
+	f, ok := p.Features[id]
+	if !ok {
+		return
+	}
+	f.AttemptUsage = append(f.AttemptUsage, AttemptUsage{
+		Attempt:      f.Attempts,
+		Model:        model,
+		InputTokens:  inputTokens,
+		OutputTokens: outputTokens,
+		CacheRead:    cacheRead,
+		CacheWrite:   cacheWrite,
+		Cost:         cost,
+		Timestamp:    time.Now(),
+	})
+	p.UpdatedAt = time.Now()
+}
+
+// Ledger returns a row per recorded attempt, oldest first. A feature with
+// usage but no per-attempt records, such as one from an older progress.json,
+// gets a single row for its latest attempt from its totals.
+func (p *Progress) Ledger() []LedgerEntry {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var entries []LedgerEntry
+	for id, f := range p.Features {
+		if len(f.AttemptUsage) > 0 {
+			for _, attempt := range f.AttemptUsage {
+				entries = append(entries, LedgerEntry{FeatureID: id, Title: f.Title, AttemptUsage: attempt})
+			}
+			continue
+		}
+		if f.InputTokens+f.OutputTokens+f.CacheRead+f.CacheWrite == 0 && f.EstimatedCost == 0 {
+			continue
+		}
+		entry := LedgerEntry{FeatureID: id, Title: f.Title, AttemptUsage: AttemptUsage{
+			Attempt:      f.Attempts,
+			Model:        f.CurrentModel,
+			InputTokens:  f.InputTokens,
+			OutputTokens: f.OutputTokens,
+			CacheRead:    f.CacheRead,
+			CacheWrite:   f.CacheWrite,
+			Cost:         f.EstimatedCost,
+		}}
+		if f.CompletedAt != nil {
+			entry.Timestamp = *f.CompletedAt
+		} else if f.StartedAt != nil {
+			entry.Timestamp = *f.StartedAt
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].Timestamp.Equal(entries[j].Timestamp) {
+			return entries[i].Timestamp.Before(entries[j].Timestamp)
+		}
+		if entries[i].FeatureID != entries[j].FeatureID {
+			return entries[i].FeatureID < entries[j].FeatureID
+		}
+		return entries[i].Attempt < entries[j].Attempt
+	})
+	return entries
+}
+
+// WriteLedgerCSV writes the ledger as CSV with a header row. Costs are in
+// USD and timestamps in RFC 3339.
+func WriteLedgerCSV(w io.Writer, entries []LedgerEntry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(ledgerHeader); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		timestamp := ""
+		if !e.Timestamp.IsZero() {
+			timestamp = e.Timestamp.Format(time.RFC3339)
+		}
+		record := []string{
+			e.FeatureID,
+			e.Title,
+			strconv.Itoa(e.Attempt),
+			e.Model,
+			strconv.FormatInt(e.InputTokens, 10),
+			strconv.FormatInt(e.OutputTokens, 10),
+			strconv.FormatInt(e.CacheRead, 10),
+			strconv.FormatInt(e.CacheWrite, 10),
+			strconv.FormatFloat(e.Cost, 'f', 4, 64),
+			timestamp,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
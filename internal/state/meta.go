@@ -0,0 +1,78 @@
+package state
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ParseRunMeta parses "key=value", splitting at the first "=" so the value
+// may contain one
+func ParseRunMeta(spec string) (key, value string, err error) {
+	key, value, ok := strings.Cut(spec, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return "", "", fmt.Errorf("invalid run metadata %q: must be key=value", spec)
+	}
+	return key, value, nil
+}
+
+// SetRunMeta records a key/value pair describing the run, such as the git
+// SHA or ticket it belongs to. An empty value removes the key.
+func (p *Progress) SetRunMeta(key, value string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dirty = true
+	p.setRunMetaUnlocked(key, value)
+	p.UpdatedAt = time.Now()
+}
+
+// ReplaceRunMeta records meta as the run's metadata, dropping keys an
+// earlier run set
+func (p *Progress) ReplaceRunMeta(meta map[string]string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dirty = true
+	p.RunMeta = nil
+	for key, value := range meta {
+		p.setRunMetaUnlocked(key, value)
+	}
+	p.UpdatedAt = time.Now()
+}
+
+func (p *Progress) setRunMetaUnlocked(key, value string) {
+	if value == "" {
+		delete(p.RunMeta, key)
+		return
+	}
+	if p.RunMeta == nil {
+		p.RunMeta = make(map[string]string)
+	}
+	p.RunMeta[key] = value
+}
+
+// GetRunMeta returns a copy of the run's metadata
+func (p *Progress) GetRunMeta() map[string]string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	meta := make(map[string]string, len(p.RunMeta))
+	for key, value := range p.RunMeta {
+		meta[key] = value
+	}
+	return meta
+}
+
+// FormatRunMeta renders metadata as "key=value" pairs sorted by key
+func FormatRunMeta(meta map[string]string) string {
+	keys := make([]string, 0, len(meta))
+	for key := range meta {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + "=" + meta[key]
+	}
+	return strings.Join(pairs, " ")
+}
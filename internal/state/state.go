@@ -5,11 +5,18 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 )
 
+// saveRetryDelay is how long Save waits before retrying a failed write
+const saveRetryDelay = 100 * time.Millisecond
+
+// writeFile is os.WriteFile, replaceable in tests to simulate write failures
+var writeFile = os.WriteFile
+
 type Progress struct {
 	mu          sync.RWMutex
 	path        string
@@ -20,8 +27,29 @@ type Progress struct {
 	Features    map[string]*FeatureState `json:"features"`
 	GlobalState map[string]interface{}   `json:"global_state"`
 	Config      ProgressConfig           `json:"config"`
+	// Concurrency is the number of running features after each change,
+	// see ConcurrencyStats
+	Concurrency     []ConcurrencySample `json:"concurrency,omitempty"`
+	PeakConcurrency int                 `json:"peak_concurrency,omitempty"`
+	// RunMeta tags the run with key/value pairs from --meta, such as the git
+	// SHA or ticket it belongs to
+	RunMeta map[string]string `json:"run_meta,omitempty"`
+
+	stale       bool   // PRD changed since progress was recorded
+	pendingHash string // Hash of the changed PRD, adopted by AcceptPRDHash
+	dirty       bool   // Changed since it was loaded or last saved
+}
+
+// ConcurrencySample is the number of features running from Timestamp on
+type ConcurrencySample struct {
+	Timestamp time.Time `json:"timestamp"`
+	Running   int       `json:"running"`
 }
 
+// maxConcurrencySamples bounds the timeline kept in progress.json; the
+// oldest samples are dropped first, PeakConcurrency is kept regardless
+const maxConcurrencySamples = 1000
+
 type ProgressConfig struct {
 	MaxRetries    int `json:"max_retries"`
 	MaxConcurrent int `json:"max_concurrent"`
@@ -36,6 +64,7 @@ type FeatureState struct {
 	Attempts       int                   `json:"attempts"`
 	MaxRetries     int                   `json:"max_retries"`
 	LastError      string                `json:"last_error,omitempty"`
+	ErrorHistory   []AttemptError        `json:"error_history,omitempty"`
 	Tasks          map[string]*TaskState `json:"tasks"`
 	TestResults    *TestResultState      `json:"test_results,omitempty"`
 	ParentID       string                `json:"parent_id,omitempty"`
@@ -51,12 +80,30 @@ type FeatureState struct {
 	MaxAdjustments int                   `json:"max_adjustments,omitempty"`
 	OriginalModel  string                `json:"original_model,omitempty"`
 	Simplified     bool                  `json:"simplified,omitempty"`
+	// SuccessfulAdjustment is the adjustment that preceded the completing attempt
+	SuccessfulAdjustment *AdjustmentState `json:"successful_adjustment,omitempty"`
 	// Token and cost tracking
 	InputTokens   int64   `json:"input_tokens,omitempty"`
 	OutputTokens  int64   `json:"output_tokens,omitempty"`
 	CacheRead     int64   `json:"cache_read,omitempty"`
 	CacheWrite    int64   `json:"cache_write,omitempty"`
 	EstimatedCost float64 `json:"estimated_cost,omitempty"`
+	// AttemptUsage is the usage and cost of each attempt, for the cost
+	// ledger. Like the totals above, it survives a reset.
+	AttemptUsage []AttemptUsage `json:"attempt_usage,omitempty"`
+	// SessionID is the claude session of the latest attempt, used to resume it
+	SessionID string `json:"session_id,omitempty"`
+	// LinesAdded and LinesRemoved are the latest attempt's Edit and Write
+	// line counts
+	LinesAdded   int `json:"lines_added,omitempty"`
+	LinesRemoved int `json:"lines_removed,omitempty"`
+	// CLIDurationMs and NumTurns are what claude reported in the latest
+	// attempt's result message
+	CLIDurationMs int64 `json:"cli_duration_ms,omitempty"`
+	NumTurns      int   `json:"num_turns,omitempty"`
+	// MaxChildrenConcurrent caps the feature's concurrently running children,
+	// overriding its PRD's Max-Children-Concurrent: (0 = not set)
+	MaxChildrenConcurrent int `json:"max_children_concurrent,omitempty"`
 }
 
 type AdjustmentState struct {
@@ -69,12 +116,21 @@ type AdjustmentState struct {
 	AttemptNum int       `json:"attempt_num"`
 }
 
+// AttemptError records how one attempt of a feature failed
+type AttemptError struct {
+	Attempt     int       `json:"attempt"`
+	Error       string    `json:"error"`
+	TestsFailed int       `json:"tests_failed,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
 type ModelSwitchState struct {
 	Timestamp time.Time `json:"timestamp"`
 	FromModel string    `json:"from_model"`
 	ToModel   string    `json:"to_model"`
 	Reason    string    `json:"reason"`
 	Details   string    `json:"details,omitempty"`
+	Evidence  string    `json:"evidence,omitempty"` // What triggered the switch
 }
 
 type TaskState struct {
@@ -165,13 +221,26 @@ func (p *Progress) Save() error {
 		return fmt.Errorf("failed to marshal progress: %w", err)
 	}
 
-	if err := os.WriteFile(p.path, data, 0644); err != nil {
-		return fmt.Errorf("failed to write progress file: %w", err)
+	// Retry once so a transient failure doesn't lose progress
+	if err := writeFile(p.path, data, 0644); err != nil {
+		time.Sleep(saveRetryDelay)
+		if err := writeFile(p.path, data, 0644); err != nil {
+			return fmt.Errorf("failed to write progress file: %w", err)
+		}
 	}
 
+	p.dirty = false
 	return nil
 }
 
+// IsDirty reports whether progress has changed since it was loaded or last
+// saved successfully
+func (p *Progress) IsDirty() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.dirty
+}
+
 func (p *Progress) SetPath(prdPath string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -200,6 +269,7 @@ func (p *Progress) GetFeature(id string) *FeatureState {
 func (p *Progress) InitFeature(id string, title string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.dirty = true
 
 	if p.Features[id] == nil {
 		p.Features[id] = &FeatureState{
@@ -215,6 +285,7 @@ func (p *Progress) InitFeature(id string, title string) {
 func (p *Progress) UpdateFeature(id string, status string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.dirty = true
 
 	if p.Features[id] == nil {
 		p.Features[id] = &FeatureState{
@@ -234,19 +305,22 @@ func (p *Progress) UpdateFeature(id string, status string) {
 			p.Features[id].StartedAt = &now
 		}
 		p.Features[id].Attempts++
-	case "completed":
+	case "completed", "completed_with_warnings":
 		p.Features[id].CompletedAt = &now
 		p.Features[id].LastError = ""
+		p.Features[id].SuccessfulAdjustment = precedingAdjustment(p.Features[id])
 	case "failed":
 		// Don't clear error on failure
 	}
 
+	p.sampleConcurrencyLocked(now)
 	p.UpdatedAt = now
 }
 
 func (p *Progress) SetFeatureError(id string, err string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.dirty = true
 
 	if p.Features[id] == nil {
 		p.Features[id] = &FeatureState{
@@ -254,14 +328,42 @@ func (p *Progress) SetFeatureError(id string, err string) {
 			Tasks: make(map[string]*TaskState),
 		}
 	}
-	p.Features[id].LastError = err
-	p.Features[id].Status = "failed"
-	p.UpdatedAt = time.Now()
+	f := p.Features[id]
+	f.LastError = err
+	f.Status = "failed"
+
+	record := AttemptError{
+		Attempt:   f.Attempts,
+		Error:     err,
+		Timestamp: time.Now(),
+	}
+	if f.TestResults != nil {
+		record.TestsFailed = f.TestResults.Failed
+	}
+	f.ErrorHistory = append(f.ErrorHistory, record)
+	p.UpdatedAt = record.Timestamp
+	p.sampleConcurrencyLocked(p.UpdatedAt)
+}
+
+// GetErrorHistory returns a copy of the feature's per-attempt errors, oldest
+// first
+func (p *Progress) GetErrorHistory(id string) []AttemptError {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	f := p.Features[id]
+	if f == nil || len(f.ErrorHistory) == 0 {
+		return nil
+	}
+	history := make([]AttemptError, len(f.ErrorHistory))
+	copy(history, f.ErrorHistory)
+	return history
 }
 
 func (p *Progress) SetTestResults(id string, passed, failed, skipped int, output string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.dirty = true
 
 	if p.Features[id] == nil {
 		return
@@ -306,6 +408,7 @@ func (p *Progress) GetAttempts(id string) int {
 func (p *Progress) ResetFeature(id string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.dirty = true
 
 	if p.Features[id] != nil {
 		p.Features[id].Status = "pending"
@@ -313,14 +416,22 @@ func (p *Progress) ResetFeature(id string) {
 		p.Features[id].CompletedAt = nil
 		p.Features[id].Attempts = 0
 		p.Features[id].LastError = ""
+		p.Features[id].ErrorHistory = nil
 		p.Features[id].TestResults = nil
+		p.Features[id].SessionID = ""
+		p.Features[id].LinesAdded = 0
+		p.Features[id].LinesRemoved = 0
+		p.Features[id].CLIDurationMs = 0
+		p.Features[id].NumTurns = 0
 	}
 	p.UpdatedAt = time.Now()
+	p.sampleConcurrencyLocked(p.UpdatedAt)
 }
 
 func (p *Progress) ResetAll() {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.dirty = true
 
 	for _, f := range p.Features {
 		f.Status = "pending"
@@ -328,48 +439,151 @@ func (p *Progress) ResetAll() {
 		f.CompletedAt = nil
 		f.Attempts = 0
 		f.LastError = ""
+		f.ErrorHistory = nil
 		f.TestResults = nil
-	}
+		f.SessionID = ""
+		f.LinesAdded = 0
+		f.LinesRemoved = 0
+		f.CLIDurationMs = 0
+		f.NumTurns = 0
+	}
+	p.Concurrency = nil
+	p.PeakConcurrency = 0
 	p.UpdatedAt = time.Now()
 }
 
-func (p *Progress) GetPendingFeatures() []string {
+// MarkStale compares the current PRD hash with the one progress was recorded
+// against and flags the progress as stale if the PRD has changed. Progress
+// with no recorded hash, or where no feature has started yet, adopts the new
+// hash instead. Returns whether the progress is stale.
+func (p *Progress) MarkStale(prdHash string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if prdHash == "" || p.PRDHash == prdHash {
+		p.stale = false
+		p.pendingHash = ""
+		return false
+	}
+
+	if p.PRDHash == "" || !p.hasStartedFeaturesUnlocked() {
+		p.PRDHash = prdHash
+		p.dirty = true
+		p.stale = false
+		p.pendingHash = ""
+		return false
+	}
+
+	p.stale = true
+	p.pendingHash = prdHash
+	return true
+}
+
+// IsStale returns true if the PRD changed since progress was recorded
+func (p *Progress) IsStale() bool {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
+	return p.stale
+}
 
-	var pending []string
-	for id, feature := range p.Features {
-		if feature.Status == "pending" || feature.Status == "" {
-			pending = append(pending, id)
+// AcceptPRDHash records the changed PRD hash as current, clearing the stale flag
+func (p *Progress) AcceptPRDHash() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.pendingHash != "" && p.pendingHash != p.PRDHash {
+		p.PRDHash = p.pendingHash
+		p.dirty = true
+	}
+	p.stale = false
+	p.pendingHash = ""
+}
+
+func (p *Progress) hasStartedFeaturesUnlocked() bool {
+	for _, f := range p.Features {
+		if f.Status != "" && f.Status != "pending" {
+			return true
 		}
 	}
-	return pending
+	return false
 }
 
-func (p *Progress) GetRunningFeatures() []string {
+// FeaturesByStatus returns the feature IDs grouped by status, each group
+// sorted. Features without a status are grouped as "pending".
+func (p *Progress) FeaturesByStatus() map[string][]string {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
-	var running []string
+	groups := make(map[string][]string)
 	for id, feature := range p.Features {
-		if feature.Status == "running" {
-			running = append(running, id)
+		status := feature.Status
+		if status == "" {
+			status = "pending"
 		}
+		groups[status] = append(groups[status], id)
+	}
+	for _, ids := range groups {
+		sort.Strings(ids)
 	}
-	return running
+	return groups
 }
 
-func (p *Progress) GetFailedFeatures() []string {
-	p.mu.RLock()
-	defer p.mu.RUnlock()
+func (p *Progress) GetPendingFeatures() []string {
+	return p.FeaturesByStatus()["pending"]
+}
+
+func (p *Progress) GetRunningFeatures() []string {
+	return p.FeaturesByStatus()["running"]
+}
+
+// MarkInterrupted flags features recorded as running that have no live
+// instance, as left behind by a crashed run, with status "interrupted". It
+// returns the flagged IDs in sorted order.
+func (p *Progress) MarkInterrupted(isLive func(id string) bool) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	var failed []string
+	var interrupted []string
 	for id, feature := range p.Features {
-		if feature.Status == "failed" {
-			failed = append(failed, id)
+		if feature.Status == "running" && (isLive == nil || !isLive(id)) {
+			feature.Status = "interrupted"
+			interrupted = append(interrupted, id)
+		}
+	}
+	if len(interrupted) > 0 {
+		sort.Strings(interrupted)
+		p.dirty = true
+		p.UpdatedAt = time.Now()
+		p.sampleConcurrencyLocked(p.UpdatedAt)
+	}
+	return interrupted
+}
+
+// ResetInterrupted returns interrupted features to pending so they run
+// again, keeping their attempt count and error history. It returns the reset
+// IDs in sorted order.
+func (p *Progress) ResetInterrupted() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dirty = true
+
+	var reset []string
+	for id, feature := range p.Features {
+		if feature.Status == "interrupted" {
+			feature.Status = "pending"
+			feature.StartedAt = nil
+			reset = append(reset, id)
 		}
 	}
-	return failed
+	if len(reset) > 0 {
+		sort.Strings(reset)
+		p.UpdatedAt = time.Now()
+	}
+	return reset
+}
+
+func (p *Progress) GetFailedFeatures() []string {
+	return p.FeaturesByStatus()["failed"]
 }
 
 func (p *Progress) GetRetryableFeatures() []string {
@@ -400,13 +614,19 @@ func (p *Progress) AllCompleted() bool {
 	}
 
 	for _, feature := range p.Features {
-		if feature.Status != "completed" {
+		if !isCompleted(feature.Status) {
 			return false
 		}
 	}
 	return true
 }
 
+// isCompleted reports whether status is a successful completion, including
+// one with warnings
+func isCompleted(status string) bool {
+	return status == "completed" || status == "completed_with_warnings"
+}
+
 func (p *Progress) HasFailures() bool {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
@@ -419,19 +639,23 @@ func (p *Progress) HasFailures() bool {
 	return false
 }
 
-func (p *Progress) GetSummary() (total, completed, running, failed, pending int) {
+// GetSummary counts features by status. Skipped features count towards the
+// total but are neither done nor pending.
+func (p *Progress) GetSummary() (total, completed, running, failed, pending, skipped int) {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
 	total = len(p.Features)
 	for _, feature := range p.Features {
 		switch feature.Status {
-		case "completed":
+		case "completed", "completed_with_warnings":
 			completed++
 		case "running":
 			running++
 		case "failed":
 			failed++
+		case "skipped":
+			skipped++
 		default:
 			pending++
 		}
@@ -442,6 +666,7 @@ func (p *Progress) GetSummary() (total, completed, running, failed, pending int)
 func (p *Progress) SetConfig(maxRetries, maxConcurrent int) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.dirty = true
 	p.Config.MaxRetries = maxRetries
 	p.Config.MaxConcurrent = maxConcurrent
 }
@@ -449,6 +674,7 @@ func (p *Progress) SetConfig(maxRetries, maxConcurrent int) {
 func (p *Progress) SetFeatureParent(id string, parentID string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.dirty = true
 
 	if p.Features[id] == nil {
 		p.Features[id] = &FeatureState{
@@ -490,6 +716,7 @@ func (p *Progress) GetChildFeatures(parentID string) []string {
 func (p *Progress) SetCurrentModel(id string, model string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.dirty = true
 
 	if p.Features[id] == nil {
 		p.Features[id] = &FeatureState{
@@ -512,8 +739,15 @@ func (p *Progress) GetCurrentModel(id string) string {
 }
 
 func (p *Progress) AddModelSwitch(id string, fromModel, toModel, reason, details string) {
+	p.AddModelSwitchWithEvidence(id, fromModel, toModel, reason, details, "")
+}
+
+// AddModelSwitchWithEvidence records a model switch along with the output
+// that triggered it, such as "2 consecutive Bash errors"
+func (p *Progress) AddModelSwitchWithEvidence(id string, fromModel, toModel, reason, details, evidence string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.dirty = true
 
 	if p.Features[id] == nil {
 		p.Features[id] = &FeatureState{
@@ -528,6 +762,7 @@ func (p *Progress) AddModelSwitch(id string, fromModel, toModel, reason, details
 		ToModel:   toModel,
 		Reason:    reason,
 		Details:   details,
+		Evidence:  evidence,
 	}
 	p.Features[id].ModelSwitches = append(p.Features[id].ModelSwitches, sw)
 	p.Features[id].CurrentModel = toModel
@@ -550,6 +785,7 @@ func (p *Progress) GetModelSwitches(id string) []ModelSwitchState {
 func (p *Progress) SetIsolationLevel(id string, level string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.dirty = true
 
 	if p.Features[id] == nil {
 		p.Features[id] = &FeatureState{
@@ -572,10 +808,40 @@ func (p *Progress) GetIsolationLevel(id string) string {
 	return ""
 }
 
+// SetMaxChildrenConcurrent sets how many of a feature's children may run at
+// once
+func (p *Progress) SetMaxChildrenConcurrent(id string, limit int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dirty = true
+
+	if p.Features[id] == nil {
+		p.Features[id] = &FeatureState{
+			ID:    id,
+			Tasks: make(map[string]*TaskState),
+		}
+	}
+	p.Features[id].MaxChildrenConcurrent = limit
+	p.UpdatedAt = time.Now()
+}
+
+// GetMaxChildrenConcurrent returns how many of a feature's children may run
+// at once, or 0 if it has no limit of its own
+func (p *Progress) GetMaxChildrenConcurrent(id string) int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if f := p.Features[id]; f != nil {
+		return f.MaxChildrenConcurrent
+	}
+	return 0
+}
+
 // SetFailureReason sets the failure reason for a feature
 func (p *Progress) SetFailureReason(id string, reason string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.dirty = true
 
 	if p.Features[id] == nil {
 		p.Features[id] = &FeatureState{
@@ -602,6 +868,7 @@ func (p *Progress) GetFailureReason(id string) string {
 func (p *Progress) AddFailedChild(parentID, childID string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.dirty = true
 
 	if p.Features[parentID] == nil {
 		p.Features[parentID] = &FeatureState{
@@ -646,6 +913,7 @@ func (p *Progress) HasFailedChildren(id string) bool {
 func (p *Progress) SkipFeature(id string, reason string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.dirty = true
 
 	if p.Features[id] == nil {
 		p.Features[id] = &FeatureState{
@@ -658,7 +926,8 @@ func (p *Progress) SkipFeature(id string, reason string) {
 	p.Features[id].SkipReason = reason
 	now := time.Now()
 	p.Features[id].CompletedAt = &now
-	p.UpdatedAt = time.Now()
+	p.UpdatedAt = now
+	p.sampleConcurrencyLocked(now)
 }
 
 // IsSkipped returns true if a feature was skipped
@@ -687,6 +956,7 @@ func (p *Progress) GetSkipReason(id string) string {
 func (p *Progress) ClearFailure(id string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.dirty = true
 
 	if f := p.Features[id]; f != nil {
 		f.LastError = ""
@@ -720,6 +990,7 @@ func (p *Progress) CanChildRetry(childID, parentID string) bool {
 func (p *Progress) AddAdjustment(id string, adj AdjustmentState) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.dirty = true
 
 	if p.Features[id] == nil {
 		p.Features[id] = &FeatureState{
@@ -761,6 +1032,7 @@ func (p *Progress) GetAdjustmentCount(id string) int {
 func (p *Progress) SetMaxAdjustments(id string, max int) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.dirty = true
 
 	if p.Features[id] == nil {
 		p.Features[id] = &FeatureState{
@@ -805,6 +1077,7 @@ func (p *Progress) CanAdjust(id string) bool {
 func (p *Progress) SetOriginalModel(id, model string) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.dirty = true
 
 	if p.Features[id] == nil {
 		p.Features[id] = &FeatureState{
@@ -833,6 +1106,7 @@ func (p *Progress) GetOriginalModel(id string) string {
 func (p *Progress) SetSimplified(id string, simplified bool) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.dirty = true
 
 	if p.Features[id] == nil {
 		p.Features[id] = &FeatureState{
@@ -867,6 +1141,46 @@ func (p *Progress) LastAdjustment(id string) *AdjustmentState {
 	return nil
 }
 
+// SuccessfulAdjustment returns the adjustment that preceded the attempt which
+// completed the feature, or nil if it completed without any adjustment
+func (p *Progress) SuccessfulAdjustment(id string) *AdjustmentState {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if f := p.Features[id]; f != nil && f.SuccessfulAdjustment != nil {
+		adj := *f.SuccessfulAdjustment
+		return &adj
+	}
+	return nil
+}
+
+// GetSuccessfulAdjustmentCounts returns how often each adjustment type led to success
+func (p *Progress) GetSuccessfulAdjustmentCounts() map[string]int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, f := range p.Features {
+		if isCompleted(f.Status) && f.SuccessfulAdjustment != nil {
+			counts[f.SuccessfulAdjustment.Type]++
+		}
+	}
+	return counts
+}
+
+// precedingAdjustment returns a copy of the last adjustment made before the
+// current attempt, or nil if the current attempt ran unadjusted
+func precedingAdjustment(f *FeatureState) *AdjustmentState {
+	if len(f.Adjustments) == 0 {
+		return nil
+	}
+	adj := f.Adjustments[len(f.Adjustments)-1]
+	if adj.AttemptNum >= f.Attempts {
+		return nil
+	}
+	return &adj
+}
+
 // HasModelEscalation returns true if model was escalated during retries
 func (p *Progress) HasModelEscalation(id string) bool {
 	p.mu.RLock()
@@ -910,6 +1224,7 @@ func (p *Progress) GetAdjustmentSummary(id string) string {
 func (p *Progress) SetFeatureUsage(id string, inputTokens, outputTokens, cacheRead, cacheWrite int64, cost float64) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	p.dirty = true
 
 	if f, ok := p.Features[id]; ok {
 		f.InputTokens = inputTokens
@@ -920,6 +1235,119 @@ func (p *Progress) SetFeatureUsage(id string, inputTokens, outputTokens, cacheRe
 	}
 }
 
+// SetSessionID records the claude session of a feature's latest attempt
+func (p *Progress) SetSessionID(id, sessionID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dirty = true
+
+	if f, ok := p.Features[id]; ok {
+		f.SessionID = sessionID
+	}
+}
+
+// GetSessionID returns the claude session of a feature's latest attempt, or
+// "" if none was recorded
+func (p *Progress) GetSessionID(id string) string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if f, ok := p.Features[id]; ok {
+		return f.SessionID
+	}
+	return ""
+}
+
+// SetLineChanges records the lines a feature's latest attempt added and
+// removed
+func (p *Progress) SetLineChanges(id string, added, removed int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dirty = true
+
+	if f, ok := p.Features[id]; ok {
+		f.LinesAdded = added
+		f.LinesRemoved = removed
+	}
+}
+
+// GetLineChanges returns the lines a feature's latest attempt added and
+// removed
+func (p *Progress) GetLineChanges(id string) (added, removed int) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if f, ok := p.Features[id]; ok {
+		return f.LinesAdded, f.LinesRemoved
+	}
+	return 0, 0
+}
+
+// SetResultStats records the run time and turn count claude reported for a
+// feature's latest attempt
+func (p *Progress) SetResultStats(id string, duration time.Duration, turns int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dirty = true
+
+	if f, ok := p.Features[id]; ok {
+		f.CLIDurationMs = duration.Milliseconds()
+		f.NumTurns = turns
+	}
+}
+
+// GetResultStats returns the run time and turn count claude reported for a
+// feature's latest attempt
+func (p *Progress) GetResultStats(id string) (time.Duration, int) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if f, ok := p.Features[id]; ok {
+		return time.Duration(f.CLIDurationMs) * time.Millisecond, f.NumTurns
+	}
+	return 0, 0
+}
+
+// SetTaskCompleted checks a task of a feature off, or back on, by the task's
+// ID. The override is kept across resets so retries don't redo work that is
+// known to be done.
+func (p *Progress) SetTaskCompleted(featureID, taskID string, completed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dirty = true
+
+	if p.Features[featureID] == nil {
+		p.Features[featureID] = &FeatureState{
+			ID:     featureID,
+			Status: "pending",
+			Tasks:  make(map[string]*TaskState),
+		}
+	}
+	f := p.Features[featureID]
+	if f.Tasks == nil {
+		f.Tasks = make(map[string]*TaskState)
+	}
+	f.Tasks[taskID] = &TaskState{ID: taskID, Completed: completed}
+	p.UpdatedAt = time.Now()
+}
+
+// GetTaskStates returns whether each task recorded for a feature is
+// completed, keyed by task ID, or nil if none are recorded
+func (p *Progress) GetTaskStates(featureID string) map[string]bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	f, ok := p.Features[featureID]
+	if !ok || len(f.Tasks) == 0 {
+		return nil
+	}
+	states := make(map[string]bool, len(f.Tasks))
+	for id, task := range f.Tasks {
+		states[id] = task.Completed
+	}
+	return states
+}
+
 // GetTotalTokens returns aggregated token counts across all features
 func (p *Progress) GetTotalTokens() (input, output, cacheRead, cacheWrite int64) {
 	p.mu.RLock()
@@ -946,6 +1374,86 @@ func (p *Progress) GetTotalCost() float64 {
 	return total
 }
 
+// CostByModel returns the estimated cost of all features, summed per model.
+// A feature that switched models mid-run has its cost split between them in
+// proportion to how long each model was in use. Cost with no known model is
+// attributed to "unknown".
+func (p *Progress) CostByModel() map[string]float64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	now := time.Now()
+	costs := make(map[string]float64)
+	for _, f := range p.Features {
+		if f.EstimatedCost == 0 {
+			continue
+		}
+		for model, share := range modelTimeShares(f, now) {
+			costs[model] += f.EstimatedCost * share
+		}
+	}
+	return costs
+}
+
+// modelTimeShares returns the fraction of f's run time spent on each model,
+// replaying its model switches between StartedAt and CompletedAt (or now)
+func modelTimeShares(f *FeatureState, now time.Time) map[string]float64 {
+	final := f.CurrentModel
+	if final == "" {
+		final = f.OriginalModel
+	}
+	if final == "" {
+		final = "unknown"
+	}
+	if len(f.ModelSwitches) == 0 || f.StartedAt == nil {
+		return map[string]float64{final: 1}
+	}
+
+	start := *f.StartedAt
+	end := now
+	if f.CompletedAt != nil {
+		end = *f.CompletedAt
+	}
+
+	current := f.ModelSwitches[0].FromModel
+	if current == "" {
+		current = f.ModelSwitches[0].ToModel
+	}
+	durations := make(map[string]time.Duration)
+	segmentStart := start
+	for _, sw := range f.ModelSwitches {
+		at := sw.Timestamp
+		if at.Before(segmentStart) {
+			at = segmentStart
+		}
+		if at.After(end) {
+			at = end
+		}
+		durations[current] += at.Sub(segmentStart)
+		segmentStart = at
+		if sw.ToModel != "" {
+			current = sw.ToModel
+		}
+	}
+	durations[current] += end.Sub(segmentStart)
+
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	if total <= 0 {
+		return map[string]float64{current: 1}
+	}
+
+	shares := make(map[string]float64, len(durations))
+	for model, d := range durations {
+		if d > 0 {
+			shares[model] = float64(d) / float64(total)
+		}
+	}
+	return shares
+}
+
 // GetTotalElapsed returns total time spent on completed features
 func (p *Progress) GetTotalElapsed() time.Duration {
 	p.mu.RLock()
@@ -975,3 +1483,69 @@ func (p *Progress) GetFeatureElapsed(id string) time.Duration {
 	}
 	return time.Since(*f.StartedAt)
 }
+
+// ConcurrencyStats summarises how many features ran at once
+type ConcurrencyStats struct {
+	Peak     int                 // Most features running at the same time
+	PeakAt   time.Time           // When Peak was first reached (zero if not in the timeline)
+	Average  float64             // Running count averaged over the timeline's duration
+	Timeline []ConcurrencySample // Running count after each change, oldest first
+}
+
+// sampleConcurrencyLocked records the current running count if it differs
+// from the last sample. Caller must hold p.mu.
+func (p *Progress) sampleConcurrencyLocked(now time.Time) {
+	running := 0
+	for _, f := range p.Features {
+		if f.Status == "running" {
+			running++
+		}
+	}
+
+	if n := len(p.Concurrency); n > 0 && p.Concurrency[n-1].Running == running {
+		return
+	}
+	if len(p.Concurrency) == 0 && running == 0 {
+		return
+	}
+
+	p.Concurrency = append(p.Concurrency, ConcurrencySample{Timestamp: now, Running: running})
+	if len(p.Concurrency) > maxConcurrencySamples {
+		p.Concurrency = p.Concurrency[len(p.Concurrency)-maxConcurrencySamples:]
+	}
+	if running > p.PeakConcurrency {
+		p.PeakConcurrency = running
+	}
+}
+
+// ConcurrencyStats returns the peak number of features running at once and
+// the timeline of running counts, with the average weighted by how long each
+// count lasted up to the last sample
+func (p *Progress) ConcurrencyStats() ConcurrencyStats {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	stats := ConcurrencyStats{
+		Peak:     p.PeakConcurrency,
+		Timeline: append([]ConcurrencySample(nil), p.Concurrency...),
+	}
+	for _, sample := range p.Concurrency {
+		if sample.Running == stats.Peak {
+			stats.PeakAt = sample.Timestamp
+			break
+		}
+	}
+
+	if n := len(p.Concurrency); n > 1 {
+		total := p.Concurrency[n-1].Timestamp.Sub(p.Concurrency[0].Timestamp)
+		if total > 0 {
+			var weighted float64
+			for i := 0; i < n-1; i++ {
+				d := p.Concurrency[i+1].Timestamp.Sub(p.Concurrency[i].Timestamp)
+				weighted += float64(p.Concurrency[i].Running) * float64(d)
+			}
+			stats.Average = weighted / float64(total)
+		}
+	}
+	return stats
+}
@@ -1,9 +1,17 @@
 package state
 
 import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"math"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
+
+	"github.com/vx/ralph-go/internal/manifest"
 )
 
 func TestNewProgress(t *testing.T) {
@@ -183,14 +191,16 @@ func TestGetSummary(t *testing.T) {
 	p.InitFeature("02", "Running")
 	p.InitFeature("03", "Completed")
 	p.InitFeature("04", "Failed")
+	p.InitFeature("05", "Skipped")
 
 	p.UpdateFeature("02", "running")
 	p.UpdateFeature("03", "completed")
 	p.UpdateFeature("04", "failed")
+	p.SkipFeature("05", "parent failed")
 
-	total, completed, running, failed, pending := p.GetSummary()
-	if total != 4 {
-		t.Errorf("expected total 4, got %d", total)
+	total, completed, running, failed, pending, skipped := p.GetSummary()
+	if total != 5 {
+		t.Errorf("expected total 5, got %d", total)
 	}
 	if completed != 1 {
 		t.Errorf("expected completed 1, got %d", completed)
@@ -204,6 +214,49 @@ func TestGetSummary(t *testing.T) {
 	if pending != 1 {
 		t.Errorf("expected pending 1, got %d", pending)
 	}
+	if skipped != 1 {
+		t.Errorf("expected skipped 1, got %d", skipped)
+	}
+}
+
+func TestFeaturesByStatus(t *testing.T) {
+	p := NewProgress()
+	p.InitFeature("05", "Pending")
+	p.InitFeature("01", "No status")
+	p.Features["01"].Status = ""
+	p.InitFeature("02", "Running")
+	p.InitFeature("06", "Failed again")
+	p.InitFeature("04", "Failed")
+	p.InitFeature("03", "Completed")
+	p.InitFeature("07", "Warnings")
+
+	p.UpdateFeature("02", "running")
+	p.UpdateFeature("06", "failed")
+	p.UpdateFeature("04", "failed")
+	p.UpdateFeature("03", "completed")
+	p.UpdateFeature("07", "completed_with_warnings")
+
+	groups := p.FeaturesByStatus()
+	want := map[string][]string{
+		"pending":                 {"01", "05"},
+		"running":                 {"02"},
+		"failed":                  {"04", "06"},
+		"completed":               {"03"},
+		"completed_with_warnings": {"07"},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("expected %v, got %v", want, groups)
+	}
+
+	if got := p.GetPendingFeatures(); !reflect.DeepEqual(got, groups["pending"]) {
+		t.Errorf("GetPendingFeatures = %v, want %v", got, groups["pending"])
+	}
+	if got := p.GetRunningFeatures(); !reflect.DeepEqual(got, groups["running"]) {
+		t.Errorf("GetRunningFeatures = %v, want %v", got, groups["running"])
+	}
+	if got := p.GetFailedFeatures(); !reflect.DeepEqual(got, groups["failed"]) {
+		t.Errorf("GetFailedFeatures = %v, want %v", got, groups["failed"])
+	}
 }
 
 func TestResetFeature(t *testing.T) {
@@ -243,6 +296,54 @@ func TestResetAll(t *testing.T) {
 	}
 }
 
+func TestMarkInterruptedAfterLoad(t *testing.T) {
+	dir := t.TempDir()
+	prdPath := filepath.Join(dir, "test.md")
+
+	p := NewProgress()
+	p.SetPath(prdPath)
+	p.InitFeature("01", "Crashed")
+	p.InitFeature("02", "Still running")
+	p.InitFeature("03", "Done")
+	p.UpdateFeature("01", "running")
+	p.UpdateFeature("02", "running")
+	p.UpdateFeature("03", "completed")
+	if err := p.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadProgress(prdPath)
+	if err != nil {
+		t.Fatalf("LoadProgress failed: %v", err)
+	}
+
+	interrupted := loaded.MarkInterrupted(func(id string) bool { return id == "02" })
+	if len(interrupted) != 1 || interrupted[0] != "01" {
+		t.Fatalf("expected only 01 to be interrupted, got %v", interrupted)
+	}
+	if got := loaded.GetFeature("01").Status; got != "interrupted" {
+		t.Errorf("expected 01 to be interrupted, got %s", got)
+	}
+	if got := loaded.GetFeature("02").Status; got != "running" {
+		t.Errorf("expected live feature 02 to stay running, got %s", got)
+	}
+	if got := loaded.GetFeature("03").Status; got != "completed" {
+		t.Errorf("expected 03 to stay completed, got %s", got)
+	}
+
+	reset := loaded.ResetInterrupted()
+	if len(reset) != 1 || reset[0] != "01" {
+		t.Fatalf("expected 01 to be reset, got %v", reset)
+	}
+	f := loaded.GetFeature("01")
+	if f.Status != "pending" {
+		t.Errorf("expected 01 to be pending after reset, got %s", f.Status)
+	}
+	if f.Attempts != 1 {
+		t.Errorf("expected reset to keep the attempt count, got %d", f.Attempts)
+	}
+}
+
 func TestSaveAndLoad(t *testing.T) {
 	dir := t.TempDir()
 	prdPath := filepath.Join(dir, "test.md")
@@ -410,6 +511,122 @@ func TestGetModelSwitchesNonexistent(t *testing.T) {
 	}
 }
 
+func TestSaveReportsWriteFailure(t *testing.T) {
+	dir := t.TempDir()
+	// A regular file where a directory is expected makes the write fail
+	blocker := filepath.Join(dir, "blocker")
+	os.WriteFile(blocker, []byte("x"), 0644)
+
+	p := NewProgress()
+	p.SetPathDirect(filepath.Join(blocker, "progress.json"))
+	p.InitFeature("01", "Test Feature")
+
+	if err := p.Save(); err == nil {
+		t.Fatal("expected error when progress file cannot be written")
+	}
+}
+
+func TestProgressDirtyUntilSaved(t *testing.T) {
+	dir := t.TempDir()
+	blocker := filepath.Join(dir, "blocker")
+	os.WriteFile(blocker, []byte("x"), 0644)
+
+	p := NewProgress()
+	if p.IsDirty() {
+		t.Fatal("expected new progress to be clean")
+	}
+
+	p.UpdateFeature("01", "running")
+	if !p.IsDirty() {
+		t.Fatal("expected progress to be dirty after a change")
+	}
+
+	p.SetPathDirect(filepath.Join(blocker, "progress.json"))
+	if err := p.Save(); err == nil {
+		t.Fatal("expected save to fail")
+	}
+	if !p.IsDirty() {
+		t.Error("expected progress to stay dirty after a failed save")
+	}
+
+	p.SetPathDirect(filepath.Join(dir, "progress.json"))
+	if err := p.Save(); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	if p.IsDirty() {
+		t.Error("expected progress to be clean after a successful save")
+	}
+
+	loaded, err := LoadProgressFromPath(filepath.Join(dir, "progress.json"))
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	if loaded.IsDirty() {
+		t.Error("expected loaded progress to be clean")
+	}
+}
+
+func TestProgressCleanAfterStartupChecks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.json")
+	p := NewProgress()
+	p.SetPathDirect(path)
+	p.MarkStale("abc123")
+	p.UpdateFeature("01", "completed")
+	if err := p.Save(); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	loaded, err := LoadProgressFromPath(path)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	if loaded.MarkStale("abc123") {
+		t.Fatal("expected the same hash not to be stale")
+	}
+	if ids := loaded.MarkInterrupted(nil); len(ids) != 0 {
+		t.Fatalf("expected nothing interrupted, got %v", ids)
+	}
+	loaded.AcceptPRDHash()
+	if loaded.IsDirty() {
+		t.Error("expected startup checks that change nothing to leave progress clean")
+	}
+
+	loaded.MarkStale("def456")
+	loaded.AcceptPRDHash()
+	if !loaded.IsDirty() {
+		t.Error("expected accepting a new PRD hash to mark progress dirty")
+	}
+}
+
+func TestSaveRetriesOnce(t *testing.T) {
+	orig := writeFile
+	defer func() { writeFile = orig }()
+
+	calls := 0
+	writeFile = func(name string, data []byte, perm os.FileMode) error {
+		calls++
+		if calls == 1 {
+			return errors.New("transient failure")
+		}
+		return orig(name, data, perm)
+	}
+
+	dir := t.TempDir()
+	p := NewProgress()
+	p.SetPathDirect(filepath.Join(dir, "progress.json"))
+	p.InitFeature("01", "Test Feature")
+
+	if err := p.Save(); err != nil {
+		t.Fatalf("expected retry to succeed, got: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 write attempts, got %d", calls)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "progress.json")); err != nil {
+		t.Errorf("expected progress file to exist after retry: %v", err)
+	}
+}
+
 func TestSaveAndLoadWithModelSwitches(t *testing.T) {
 	dir := t.TempDir()
 	prdPath := filepath.Join(dir, "test.md")
@@ -1006,6 +1223,73 @@ func TestGetAdjustmentSummary(t *testing.T) {
 	}
 }
 
+func TestSuccessfulAdjustmentAfterEscalation(t *testing.T) {
+	p := NewProgress()
+	p.InitFeature("01", "Test Feature")
+
+	// Attempt 1 fails
+	p.UpdateFeature("01", "running")
+	p.SetFeatureError("01", "3 tests failed")
+
+	// Model escalated before attempt 2
+	p.AddAdjustment("01", AdjustmentState{
+		Type:       "model_escalation",
+		Reason:     "test_failures",
+		FromValue:  "sonnet",
+		ToValue:    "opus",
+		AttemptNum: 1,
+	})
+
+	// Attempt 2 completes
+	p.UpdateFeature("01", "running")
+	p.UpdateFeature("01", "completed")
+
+	adj := p.SuccessfulAdjustment("01")
+	if adj == nil {
+		t.Fatal("expected successful adjustment to be attributed")
+	}
+	if adj.Type != "model_escalation" {
+		t.Errorf("expected type 'model_escalation', got %s", adj.Type)
+	}
+	if adj.ToValue != "opus" {
+		t.Errorf("expected ToValue 'opus', got %s", adj.ToValue)
+	}
+
+	counts := p.GetSuccessfulAdjustmentCounts()
+	if counts["model_escalation"] != 1 {
+		t.Errorf("expected 1 successful model_escalation, got %d", counts["model_escalation"])
+	}
+}
+
+func TestSuccessfulAdjustmentNoneWithoutAdjustment(t *testing.T) {
+	p := NewProgress()
+	p.InitFeature("01", "Test Feature")
+
+	p.UpdateFeature("01", "running")
+	p.UpdateFeature("01", "completed")
+
+	if adj := p.SuccessfulAdjustment("01"); adj != nil {
+		t.Errorf("expected nil successful adjustment, got %+v", adj)
+	}
+	if p.SuccessfulAdjustment("nonexistent") != nil {
+		t.Error("expected nil for nonexistent feature")
+	}
+}
+
+func TestSuccessfulAdjustmentIgnoresUnusedAdjustment(t *testing.T) {
+	p := NewProgress()
+	p.InitFeature("01", "Test Feature")
+
+	// Adjustment recorded for the current attempt has not been tried yet
+	p.UpdateFeature("01", "running")
+	p.AddAdjustment("01", AdjustmentState{Type: "task_simplify", AttemptNum: 1})
+	p.UpdateFeature("01", "completed")
+
+	if adj := p.SuccessfulAdjustment("01"); adj != nil {
+		t.Errorf("expected nil successful adjustment, got %+v", adj)
+	}
+}
+
 func TestSaveAndLoadWithAdjustments(t *testing.T) {
 	dir := t.TempDir()
 	prdPath := filepath.Join(dir, "test.md")
@@ -1057,6 +1341,67 @@ func TestSaveAndLoadWithAdjustments(t *testing.T) {
 	}
 }
 
+func TestErrorHistoryAccumulatesAcrossAttempts(t *testing.T) {
+	dir := t.TempDir()
+	prdPath := filepath.Join(dir, "test.md")
+	os.WriteFile(prdPath, []byte("# Test"), 0644)
+
+	p := NewProgress()
+	p.SetPath(prdPath)
+	p.InitFeature("01", "Test Feature")
+
+	p.UpdateFeature("01", "running")
+	p.SetTestResults("01", 3, 2, 0, "--- FAIL: TestA\n--- FAIL: TestB")
+	p.SetFeatureError("01", "tests failed")
+
+	p.UpdateFeature("01", "running")
+	p.SetTestResults("01", 0, 0, 0, "")
+	p.SetFeatureError("01", "undefined: parseConfig")
+
+	history := p.GetErrorHistory("01")
+	if len(history) != 2 {
+		t.Fatalf("expected 2 error records, got %d", len(history))
+	}
+	if history[0].Attempt != 1 || history[0].Error != "tests failed" || history[0].TestsFailed != 2 {
+		t.Errorf("unexpected first record: %+v", history[0])
+	}
+	if history[1].Attempt != 2 || history[1].Error != "undefined: parseConfig" || history[1].TestsFailed != 0 {
+		t.Errorf("unexpected second record: %+v", history[1])
+	}
+	if history[0].Timestamp.IsZero() || history[1].Timestamp.Before(history[0].Timestamp) {
+		t.Error("expected records to be timestamped in order")
+	}
+
+	if err := p.Save(); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+	loaded, err := LoadProgress(prdPath)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+
+	loadedHistory := loaded.GetErrorHistory("01")
+	if len(loadedHistory) != 2 {
+		t.Fatalf("expected 2 error records after load, got %d", len(loadedHistory))
+	}
+	for i := range history {
+		if loadedHistory[i].Attempt != history[i].Attempt ||
+			loadedHistory[i].Error != history[i].Error ||
+			loadedHistory[i].TestsFailed != history[i].TestsFailed ||
+			!loadedHistory[i].Timestamp.Equal(history[i].Timestamp) {
+			t.Errorf("record %d changed across save/load: %+v != %+v", i, loadedHistory[i], history[i])
+		}
+	}
+	if f := loaded.GetFeature("01"); f.LastError != "undefined: parseConfig" {
+		t.Errorf("expected LastError to remain the latest error, got %q", f.LastError)
+	}
+
+	loaded.ResetFeature("01")
+	if len(loaded.GetErrorHistory("01")) != 0 {
+		t.Error("expected ResetFeature to clear the error history")
+	}
+}
+
 func containsStr(s, substr string) bool {
 	for i := 0; i <= len(s)-len(substr); i++ {
 		if s[i:i+len(substr)] == substr {
@@ -1065,3 +1410,553 @@ func containsStr(s, substr string) bool {
 	}
 	return false
 }
+
+func TestMarkStale(t *testing.T) {
+	t.Run("adopts hash when none recorded", func(t *testing.T) {
+		p := NewProgress()
+		if p.MarkStale("abc") {
+			t.Error("expected no stale flag without a recorded hash")
+		}
+		if p.PRDHash != "abc" {
+			t.Errorf("expected hash to be adopted, got %q", p.PRDHash)
+		}
+	})
+
+	t.Run("unchanged hash is not stale", func(t *testing.T) {
+		p := NewProgress()
+		p.PRDHash = "abc"
+		p.UpdateFeature("01", "completed")
+
+		if p.MarkStale("abc") {
+			t.Error("expected unchanged hash not to be stale")
+		}
+		if p.IsStale() {
+			t.Error("expected IsStale to be false")
+		}
+	})
+
+	t.Run("changed hash with progress is stale", func(t *testing.T) {
+		p := NewProgress()
+		p.PRDHash = "abc"
+		p.UpdateFeature("01", "completed")
+
+		if !p.MarkStale("def") {
+			t.Error("expected changed hash to be stale")
+		}
+		if !p.IsStale() {
+			t.Error("expected IsStale to be true")
+		}
+		if p.PRDHash != "abc" {
+			t.Errorf("expected recorded hash to be kept until accepted, got %q", p.PRDHash)
+		}
+
+		p.AcceptPRDHash()
+		if p.IsStale() {
+			t.Error("expected AcceptPRDHash to clear the stale flag")
+		}
+		if p.PRDHash != "def" {
+			t.Errorf("expected new hash after accept, got %q", p.PRDHash)
+		}
+	})
+
+	t.Run("changed hash without started features adopts new hash", func(t *testing.T) {
+		p := NewProgress()
+		p.PRDHash = "abc"
+		p.InitFeature("01", "Feature")
+
+		if p.MarkStale("def") {
+			t.Error("expected no stale flag when no feature has started")
+		}
+		if p.PRDHash != "def" {
+			t.Errorf("expected hash to be adopted, got %q", p.PRDHash)
+		}
+	})
+}
+
+func TestPRDHashPersists(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "progress.json")
+
+	p := NewProgress()
+	p.SetPathDirect(path)
+	p.MarkStale("abc")
+	p.UpdateFeature("01", "completed")
+	if err := p.Save(); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	loaded, err := LoadProgressFromPath(path)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	if loaded.MarkStale("abc") {
+		t.Error("expected reloaded progress with same hash not to be stale")
+	}
+	if !loaded.MarkStale("changed") {
+		t.Error("expected reloaded progress with changed hash to be stale")
+	}
+}
+
+func TestSessionIDPersistsUntilReset(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "progress.json")
+
+	p := NewProgress()
+	p.SetPathDirect(path)
+	p.UpdateFeature("01", "failed")
+	p.SetSessionID("01", "sess-123")
+	if err := p.Save(); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	loaded, err := LoadProgressFromPath(path)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	if got := loaded.GetSessionID("01"); got != "sess-123" {
+		t.Errorf("expected session ID sess-123 after reload, got %q", got)
+	}
+	if got := loaded.GetSessionID("missing"); got != "" {
+		t.Errorf("expected no session ID for unknown feature, got %q", got)
+	}
+
+	loaded.ResetFeature("01")
+	if got := loaded.GetSessionID("01"); got != "" {
+		t.Errorf("expected reset to clear the session ID, got %q", got)
+	}
+}
+
+func TestCostByModel(t *testing.T) {
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) *time.Time {
+		ts := start.Add(time.Duration(minutes) * time.Minute)
+		return &ts
+	}
+
+	p := NewProgress()
+	p.Features["01"] = &FeatureState{ID: "01", CurrentModel: "sonnet", EstimatedCost: 2}
+	p.Features["02"] = &FeatureState{ID: "02", CurrentModel: "haiku", EstimatedCost: 0.5}
+	// Ran 10 minutes on sonnet, then 30 on opus
+	p.Features["03"] = &FeatureState{
+		ID:            "03",
+		CurrentModel:  "opus",
+		EstimatedCost: 4,
+		StartedAt:     at(0),
+		CompletedAt:   at(40),
+		ModelSwitches: []ModelSwitchState{
+			{Timestamp: *at(0), FromModel: "", ToModel: "sonnet", Reason: "initial"},
+			{Timestamp: *at(10), FromModel: "sonnet", ToModel: "opus", Reason: "complexity"},
+		},
+	}
+	p.Features["04"] = &FeatureState{ID: "04", OriginalModel: "sonnet", EstimatedCost: 1}
+	p.Features["05"] = &FeatureState{ID: "05", EstimatedCost: 0.25}
+	p.Features["06"] = &FeatureState{ID: "06", CurrentModel: "opus"}
+
+	got := p.CostByModel()
+	want := map[string]float64{
+		"sonnet":  2 + 1 + 1,
+		"opus":    3,
+		"haiku":   0.5,
+		"unknown": 0.25,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d models, got %v", len(want), got)
+	}
+	for model, cost := range want {
+		if math.Abs(got[model]-cost) > 1e-9 {
+			t.Errorf("%s: expected $%.4f, got $%.4f", model, cost, got[model])
+		}
+	}
+}
+
+func TestLineChangesPersistUntilReset(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "progress.json")
+
+	p := NewProgress()
+	p.SetPathDirect(path)
+	p.UpdateFeature("01", "completed")
+	p.SetLineChanges("01", 120, 15)
+	if err := p.Save(); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	loaded, err := LoadProgressFromPath(path)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	if added, removed := loaded.GetLineChanges("01"); added != 120 || removed != 15 {
+		t.Errorf("expected +120/-15 after reload, got +%d/-%d", added, removed)
+	}
+
+	loaded.ResetFeature("01")
+	if added, removed := loaded.GetLineChanges("01"); added != 0 || removed != 0 {
+		t.Errorf("expected reset to clear line changes, got +%d/-%d", added, removed)
+	}
+}
+
+func TestResultStatsPersistUntilReset(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "progress.json")
+
+	p := NewProgress()
+	p.SetPathDirect(path)
+	p.UpdateFeature("01", "completed")
+	p.SetResultStats("01", 123456*time.Millisecond, 14)
+	if err := p.Save(); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	loaded, err := LoadProgressFromPath(path)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	if duration, turns := loaded.GetResultStats("01"); duration != 123456*time.Millisecond || turns != 14 {
+		t.Errorf("expected 2m3.456s and 14 turns after reload, got %v and %d", duration, turns)
+	}
+
+	loaded.ResetFeature("01")
+	if duration, turns := loaded.GetResultStats("01"); duration != 0 || turns != 0 {
+		t.Errorf("expected reset to clear result stats, got %v and %d", duration, turns)
+	}
+}
+
+func TestConcurrencyStatsPeak(t *testing.T) {
+	p := NewProgress()
+
+	// 01 and 02 overlap, 03 starts after 01 finishes, then 04 joins for a
+	// peak of three
+	steps := []struct {
+		id, status string
+		running    int
+	}{
+		{"01", "running", 1},
+		{"02", "running", 2},
+		{"01", "completed", 1},
+		{"03", "running", 2},
+		{"04", "running", 3},
+		{"02", "failed", 2},
+		{"03", "completed", 1},
+		{"04", "completed_with_warnings", 0},
+	}
+	for _, step := range steps {
+		p.UpdateFeature(step.id, step.status)
+	}
+
+	stats := p.ConcurrencyStats()
+	if stats.Peak != 3 {
+		t.Errorf("expected peak 3, got %d", stats.Peak)
+	}
+	if len(stats.Timeline) != len(steps) {
+		t.Fatalf("expected %d samples, got %d: %+v", len(steps), len(stats.Timeline), stats.Timeline)
+	}
+	for i, step := range steps {
+		if got := stats.Timeline[i].Running; got != step.running {
+			t.Errorf("sample %d: expected %d running, got %d", i, step.running, got)
+		}
+	}
+	if !stats.PeakAt.Equal(stats.Timeline[4].Timestamp) {
+		t.Errorf("expected peak at the fifth sample, got %v", stats.PeakAt)
+	}
+}
+
+func TestConcurrencyStatsSkipsUnchangedCounts(t *testing.T) {
+	p := NewProgress()
+	p.UpdateFeature("01", "pending")
+	p.UpdateFeature("01", "running")
+	p.SetFeatureError("01", "boom")
+	p.UpdateFeature("01", "failed")
+	p.UpdateFeature("02", "failed")
+
+	stats := p.ConcurrencyStats()
+	if len(stats.Timeline) != 2 {
+		t.Fatalf("expected 2 samples, got %+v", stats.Timeline)
+	}
+	if stats.Peak != 1 {
+		t.Errorf("expected peak 1, got %d", stats.Peak)
+	}
+}
+
+func TestConcurrencyStatsSampleFailedAndSkipped(t *testing.T) {
+	p := NewProgress()
+	p.UpdateFeature("01", "running")
+	p.UpdateFeature("02", "running")
+	p.SetFeatureError("01", "boom")
+	p.SkipFeature("02", "dependency failed")
+
+	stats := p.ConcurrencyStats()
+	if n := len(stats.Timeline); n != 4 || stats.Timeline[n-1].Running != 0 {
+		t.Errorf("expected failed and skipped features to stop counting as running, got %+v", stats.Timeline)
+	}
+}
+
+func TestConcurrencyStatsAverage(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	p := NewProgress()
+	p.PeakConcurrency = 3
+	p.Concurrency = []ConcurrencySample{
+		{Timestamp: start, Running: 1},
+		{Timestamp: start.Add(time.Minute), Running: 3},
+		{Timestamp: start.Add(2 * time.Minute), Running: 0},
+	}
+
+	stats := p.ConcurrencyStats()
+	if stats.Average != 2 {
+		t.Errorf("expected average 2, got %v", stats.Average)
+	}
+	if !stats.PeakAt.Equal(start.Add(time.Minute)) {
+		t.Errorf("expected peak at %v, got %v", start.Add(time.Minute), stats.PeakAt)
+	}
+}
+
+func TestConcurrencyPersistsUntilResetAll(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "progress.json")
+
+	p := NewProgress()
+	p.SetPathDirect(path)
+	p.UpdateFeature("01", "running")
+	p.UpdateFeature("02", "running")
+	p.UpdateFeature("01", "completed")
+	if err := p.Save(); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	loaded, err := LoadProgressFromPath(path)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	if stats := loaded.ConcurrencyStats(); stats.Peak != 2 || len(stats.Timeline) != 3 {
+		t.Errorf("expected peak 2 and 3 samples after reload, got %d and %d", stats.Peak, len(stats.Timeline))
+	}
+
+	loaded.ResetAll()
+	if stats := loaded.ConcurrencyStats(); stats.Peak != 0 || len(stats.Timeline) != 0 {
+		t.Errorf("expected reset to clear the timeline, got %+v", stats)
+	}
+}
+
+func TestModelSwitchEvidencePersists(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "progress.json")
+
+	p := NewProgress()
+	p.SetPathDirect(path)
+	p.AddModelSwitchWithEvidence("01", "haiku", "sonnet", "multiple_errors", "2+ tool errors with haiku",
+		`2 consecutive Bash errors; last: "exit status 1"`)
+	if err := p.Save(); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	loaded, err := LoadProgressFromPath(path)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	switches := loaded.GetModelSwitches("01")
+	if len(switches) != 1 {
+		t.Fatalf("expected 1 switch, got %d", len(switches))
+	}
+	if want := `2 consecutive Bash errors; last: "exit status 1"`; switches[0].Evidence != want {
+		t.Errorf("expected evidence %q, got %q", want, switches[0].Evidence)
+	}
+}
+
+func TestWriteLedgerCSV(t *testing.T) {
+	p := NewProgress()
+	p.InitFeature("01", "Login, SSO")
+	p.InitFeature("02", "Docs")
+	p.InitFeature("03", "Untouched")
+
+	p.UpdateFeature("01", "running")
+	p.RecordAttemptUsage("01", "sonnet", 1000, 200, 50, 10, 0.0123)
+	p.UpdateFeature("01", "running")
+	p.RecordAttemptUsage("01", "opus", 3000, 400, 0, 0, 0.075)
+
+	// Usage totals without per-attempt records, as in an older progress.json
+	p.UpdateFeature("02", "running")
+	docs := p.GetFeature("02")
+	docs.CurrentModel = "haiku"
+	docs.InputTokens, docs.OutputTokens = 500, 100
+	docs.EstimatedCost = 0.001
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	login := p.GetFeature("01")
+	login.AttemptUsage[0].Timestamp = base
+	login.AttemptUsage[1].Timestamp = base.Add(10 * time.Minute)
+	docsDone := base.Add(5 * time.Minute)
+	docs.CompletedAt = &docsDone
+
+	var buf bytes.Buffer
+	if err := WriteLedgerCSV(&buf, p.Ledger()); err != nil {
+		t.Fatalf("WriteLedgerCSV failed: %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse CSV: %v", err)
+	}
+
+	want := [][]string{
+		{"feature", "title", "attempt", "model", "input_tokens", "output_tokens", "cache_read_tokens", "cache_write_tokens", "cost_usd", "timestamp"},
+		{"01", "Login, SSO", "1", "sonnet", "1000", "200", "50", "10", "0.0123", "2026-03-01T12:00:00Z"},
+		{"02", "Docs", "1", "haiku", "500", "100", "0", "0", "0.0010", "2026-03-01T12:05:00Z"},
+		{"01", "Login, SSO", "2", "opus", "3000", "400", "0", "0", "0.0750", "2026-03-01T12:10:00Z"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("ledger rows =\n%q\nwant\n%q", rows, want)
+	}
+}
+
+func TestSetTaskCompletedPersists(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "progress.json")
+
+	p := NewProgress()
+	p.SetPathDirect(path)
+	p.SetFeatureError("01", "tests failed")
+	p.SetTaskCompleted("01", "task-a", true)
+	p.SetTaskCompleted("01", "task-b", true)
+	p.SetTaskCompleted("01", "task-b", false)
+	if err := p.Save(); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	loaded, err := LoadProgressFromPath(path)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	states := loaded.GetTaskStates("01")
+	if len(states) != 2 || !states["task-a"] || states["task-b"] {
+		t.Errorf("expected task-a done and task-b not done after reload, got %v", states)
+	}
+	if got := loaded.GetFeature("01").Status; got != "failed" {
+		t.Errorf("expected checking off a task to leave the status alone, got %q", got)
+	}
+
+	loaded.ResetFeature("01")
+	if states := loaded.GetTaskStates("01"); !states["task-a"] {
+		t.Errorf("expected checked off tasks to survive a reset, got %v", states)
+	}
+}
+
+func TestSetTaskCompletedUnknownFeature(t *testing.T) {
+	p := NewProgress()
+	if states := p.GetTaskStates("02"); states != nil {
+		t.Errorf("expected no task states, got %v", states)
+	}
+
+	p.SetTaskCompleted("02", "task-a", true)
+	if states := p.GetTaskStates("02"); !states["task-a"] {
+		t.Errorf("expected the feature to be added with task-a done, got %v", states)
+	}
+	if got := p.GetFeature("02").Status; got != "pending" {
+		t.Errorf("expected a new feature to be pending, got %q", got)
+	}
+}
+
+func TestRunMetaSaveAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "progress.json")
+
+	p := NewProgress()
+	p.SetPathDirect(path)
+	p.SetRunMeta("git_sha", "abc123")
+	p.SetRunMeta("ticket", "PROJ-42")
+	p.SetRunMeta("env", "ci")
+	p.SetRunMeta("env", "")
+	if err := p.Save(); err != nil {
+		t.Fatalf("failed to save: %v", err)
+	}
+
+	loaded, err := LoadProgressFromPath(path)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	want := map[string]string{"git_sha": "abc123", "ticket": "PROJ-42"}
+	if got := loaded.GetRunMeta(); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := FormatRunMeta(loaded.GetRunMeta()); got != "git_sha=abc123 ticket=PROJ-42" {
+		t.Errorf("unexpected formatted metadata %q", got)
+	}
+
+	loaded.GetRunMeta()["git_sha"] = "changed"
+	if loaded.GetRunMeta()["git_sha"] != "abc123" {
+		t.Error("expected GetRunMeta to return a copy")
+	}
+}
+
+func TestParseRunMeta(t *testing.T) {
+	key, value, err := ParseRunMeta("url=https://x.test/?a=b")
+	if err != nil || key != "url" || value != "https://x.test/?a=b" {
+		t.Errorf("expected the value to keep its '=', got %q=%q (%v)", key, value, err)
+	}
+	for _, spec := range []string{"ticket", "=value", ""} {
+		if _, _, err := ParseRunMeta(spec); err == nil {
+			t.Errorf("expected an error for %q", spec)
+		}
+	}
+}
+
+func TestCriticalPath(t *testing.T) {
+	// 01 -> 02 -> 04 -> 05 is the longest chain by count, but 03 took long
+	// enough that 01 -> 03 -> 05 is the critical path
+	m := manifest.New("PRD.md", "Test")
+	m.Features = []manifest.ManifestFeature{
+		{ID: "01", Status: "completed"},
+		{ID: "02", Status: "completed", DependsOn: []string{"01"}},
+		{ID: "03", Status: "completed", DependsOn: []string{"01"}},
+		{ID: "04", Status: "pending", DependsOn: []string{"02"}},
+		{ID: "05", Status: "pending", DependsOn: []string{"03", "04", "missing"}},
+		{ID: "06", Status: "pending", DependsOn: []string{"05"}, Disabled: true},
+	}
+
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	ran := func(id string, minutes int) *FeatureState {
+		started := start
+		completed := start.Add(time.Duration(minutes) * time.Minute)
+		return &FeatureState{ID: id, Status: "completed", StartedAt: &started, CompletedAt: &completed}
+	}
+
+	t.Run("projects pending features at the completed average", func(t *testing.T) {
+		p := NewProgress()
+		p.Features["01"] = ran("01", 10)
+		p.Features["02"] = ran("02", 5)
+		p.Features["03"] = ran("03", 30)
+
+		path, err := p.CriticalPath(m)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := []string{"01", "03", "05"}; !reflect.DeepEqual(path.Features, want) {
+			t.Errorf("expected path %v, got %v", want, path.Features)
+		}
+		// 10 + 30 for the completed features, plus the 15 minute average for 05
+		if want := 55 * time.Minute; path.Duration != want {
+			t.Errorf("expected %s, got %s", want, path.Duration)
+		}
+	})
+
+	t.Run("falls back to the longest chain with nothing completed", func(t *testing.T) {
+		path, err := NewProgress().CriticalPath(m)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := []string{"01", "02", "04", "05"}; !reflect.DeepEqual(path.Features, want) {
+			t.Errorf("expected path %v, got %v", want, path.Features)
+		}
+		if path.Duration != 0 {
+			t.Errorf("expected no projected duration, got %s", path.Duration)
+		}
+	})
+
+	t.Run("reports a dependency cycle", func(t *testing.T) {
+		cyclic := manifest.New("PRD.md", "Test")
+		cyclic.Features = []manifest.ManifestFeature{
+			{ID: "01", DependsOn: []string{"02"}},
+			{ID: "02", DependsOn: []string{"01"}},
+		}
+		if _, err := NewProgress().CriticalPath(cyclic); err == nil {
+			t.Error("expected an error for a dependency cycle")
+		}
+	})
+}
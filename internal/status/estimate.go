@@ -0,0 +1,93 @@
+package status
+
+import (
+	"fmt"
+
+	"github.com/vx/ralph-go/internal/auto"
+	"github.com/vx/ralph-go/internal/automodel"
+	"github.com/vx/ralph-go/internal/manifest"
+	"github.com/vx/ralph-go/internal/usage"
+)
+
+// FeatureEstimate is the projected input cost of a feature's prompt
+type FeatureEstimate struct {
+	ID           string
+	Title        string
+	Model        string
+	PromptTokens int64
+	InputCostUSD float64
+}
+
+// EstimateFeatures projects the prompt input cost of every feature that has
+// not completed yet. Auto-model features are priced at the model they would
+// start with. Spawned sub-features have no spec of their own and are skipped.
+func EstimateFeatures(prdDir string, m *manifest.Manifest) ([]FeatureEstimate, error) {
+	var estimates []FeatureEstimate
+	for _, f := range m.AllFeatures() {
+		if manifest.IsCompleted(f.Status) || !f.IsRootFeature() {
+			continue
+		}
+
+		prompt, err := auto.GetFeaturePrompt(prdDir, &f)
+		if err != nil {
+			return nil, err
+		}
+
+		model := estimateModel(f, prompt)
+		tokens, cost := usage.EstimateInputCost(prompt, model)
+		estimates = append(estimates, FeatureEstimate{
+			ID:           f.ID,
+			Title:        f.Title,
+			Model:        model,
+			PromptTokens: tokens,
+			InputCostUSD: cost,
+		})
+	}
+	return estimates, nil
+}
+
+// estimateModel resolves the model a feature will start on
+func estimateModel(f manifest.ManifestFeature, prompt string) string {
+	if f.Model == "" {
+		return automodel.ModelSonnet
+	}
+	if !automodel.IsAutoMode(f.Model) {
+		return f.Model
+	}
+
+	return automodel.Explain(auto.ModelInputs(prompt)).Model
+}
+
+func printEstimates(estimates []FeatureEstimate) {
+	fmt.Printf("%sEstimated input cost%s %s(prompt only, ~%d chars/token)%s\n",
+		colorBold, colorReset, colorDim, usage.CharsPerToken, colorReset)
+
+	if len(estimates) == 0 {
+		fmt.Println("  Nothing left to run.")
+		return
+	}
+
+	var totalTokens int64
+	var totalCost float64
+	for _, e := range estimates {
+		fmt.Printf("  %s %-30s %-7s %8s tokens  $%.4f\n",
+			e.ID, truncateTitle(e.Title, 30), e.Model, formatTokens(e.PromptTokens), e.InputCostUSD)
+		totalTokens += e.PromptTokens
+		totalCost += e.InputCostUSD
+	}
+	fmt.Printf("  %sTotal: %s tokens  $%.4f%s\n", colorBold, formatTokens(totalTokens), totalCost, colorReset)
+}
+
+func truncateTitle(title string, maxLen int) string {
+	if len(title) <= maxLen {
+		return title
+	}
+	return title[:maxLen-3] + "..."
+}
+
+func formatTokens(tokens int64) string {
+	if tokens >= 1000 {
+		return fmt.Sprintf("%.1fk", float64(tokens)/1000)
+	}
+	return fmt.Sprintf("%d", tokens)
+}
@@ -0,0 +1,79 @@
+package status
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vx/ralph-go/internal/manifest"
+	"github.com/vx/ralph-go/internal/usage"
+)
+
+func TestEstimateFeatures(t *testing.T) {
+	prdDir := t.TempDir()
+
+	write := func(dir, content string) {
+		os.MkdirAll(filepath.Join(prdDir, dir), 0755)
+		os.WriteFile(filepath.Join(prdDir, dir, "feature.md"), []byte(content), 0644)
+	}
+	// 4000 chars ≈ 1000 tokens each
+	write("01-done", strings.Repeat("x", 4000))
+	write("02-opus", strings.Repeat("x", 4000))
+	write("03-default", strings.Repeat("x", 8000))
+	write("04-auto", "## Auto\n\n- [ ] One\n- [ ] Two\n"+strings.Repeat("x", 3971))
+
+	m := manifest.New("", "Test")
+	m.Features = []manifest.ManifestFeature{
+		{ID: "01", Dir: "01-done", Title: "Done", Status: "completed", Model: "opus"},
+		{ID: "02", Dir: "02-opus", Title: "Opus", Status: "pending", Model: "opus"},
+		{ID: "03", Dir: "03-default", Title: "Default", Status: "failed"},
+		{ID: "04", Dir: "04-auto", Title: "Auto", Status: "pending", Model: "auto"},
+	}
+
+	estimates, err := EstimateFeatures(prdDir, m)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(estimates) != 3 {
+		t.Fatalf("expected 3 estimates (completed skipped), got %d", len(estimates))
+	}
+
+	tests := []struct {
+		id     string
+		model  string
+		tokens int64
+		cost   float64
+	}{
+		{"02", "opus", 1000, 1000 * usage.OpusInputPrice / 1_000_000},
+		{"03", "sonnet", 2000, 2000 * usage.SonnetInputPrice / 1_000_000},
+		{"04", "haiku", 1000, 1000 * usage.HaikuInputPrice / 1_000_000},
+	}
+	for i, tt := range tests {
+		e := estimates[i]
+		if e.ID != tt.id {
+			t.Errorf("estimate %d: expected ID %s, got %s", i, tt.id, e.ID)
+		}
+		if e.Model != tt.model {
+			t.Errorf("%s: expected model %s, got %s", tt.id, tt.model, e.Model)
+		}
+		if e.PromptTokens != tt.tokens {
+			t.Errorf("%s: expected %d tokens, got %d", tt.id, tt.tokens, e.PromptTokens)
+		}
+		if math.Abs(e.InputCostUSD-tt.cost) > 0.000001 {
+			t.Errorf("%s: expected cost $%f, got $%f", tt.id, tt.cost, e.InputCostUSD)
+		}
+	}
+}
+
+func TestEstimateFeaturesMissingPrompt(t *testing.T) {
+	m := manifest.New("", "Test")
+	m.Features = []manifest.ManifestFeature{
+		{ID: "01", Dir: "01-missing", Title: "Missing", Status: "pending"},
+	}
+
+	if _, err := EstimateFeatures(t.TempDir(), m); err == nil {
+		t.Error("expected error when feature.md is missing")
+	}
+}
@@ -2,18 +2,25 @@ package status
 
 import (
 	"fmt"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/vx/ralph-go/internal/auto"
 	"github.com/vx/ralph-go/internal/manifest"
+	"github.com/vx/ralph-go/internal/state"
+	"github.com/vx/ralph-go/internal/usage"
 )
 
 const (
-	iconCompleted = "✓"
-	iconRunning   = "●"
-	iconFailed    = "✗"
-	iconPending   = "○"
-	iconBlocked   = "◌"
+	iconCompleted   = "✓"
+	iconRunning     = "●"
+	iconFailed      = "✗"
+	iconPending     = "○"
+	iconBlocked     = "◌"
+	iconInterrupted = "■"
+	iconWarnings    = "⚠"
+	iconDisabled    = "⊖"
 
 	colorReset  = "\033[0m"
 	colorGreen  = "\033[32m"
@@ -24,8 +31,12 @@ const (
 	colorDim    = "\033[2m"
 )
 
-func Run() error {
-	prdDir, err := auto.FindPRDDir()
+// Run prints the status of the PRD in prdDir, or of PRD/ in the current
+// directory if prdDir is empty. With estimate, it also projects the prompt
+// input cost of the features left to run. With tree, features are drawn as a
+// tree of spawned sub-features with their status and cost.
+func Run(prdDir string, estimate, tree bool) error {
+	prdDir, err := auto.ResolvePRDDir(prdDir)
 	if err != nil {
 		return err
 	}
@@ -35,12 +46,88 @@ func Run() error {
 		return err
 	}
 
-	printStatus(m)
+	interrupted := interruptedFeatures(prdDir, m)
+	if tree {
+		printTreeStatus(m, interrupted, featureCosts(prdDir))
+	} else {
+		printStatus(m, interrupted, costByModel(prdDir), checkedOffTasks(prdDir))
+	}
+	if meta := runMeta(prdDir); len(meta) > 0 {
+		fmt.Printf("Run metadata: %s\n\n", state.FormatRunMeta(meta))
+	}
+
+	if estimate {
+		estimates, err := EstimateFeatures(prdDir, m)
+		if err != nil {
+			return err
+		}
+		printEstimates(estimates)
+		fmt.Println()
+	}
 	return nil
 }
 
-func printStatus(m *manifest.Manifest) {
-	total, completed, running, failed, pending, blocked := m.GetSummary()
+// interruptedFeatures returns the features that progress.json and the
+// manifest both record as running. The status command runs no instances of
+// its own, so none of them are live here: they were left behind by a crashed
+// run, unless ralph is running in another terminal.
+func interruptedFeatures(prdDir string, m *manifest.Manifest) map[string]bool {
+	progress, err := state.LoadProgressFromPath(filepath.Join(prdDir, "progress.json"))
+	if err != nil {
+		return nil
+	}
+
+	interrupted := make(map[string]bool)
+	for _, id := range progress.MarkInterrupted(nil) {
+		if f := m.GetFeature(id); f != nil && f.Status == "running" {
+			interrupted[id] = true
+		}
+	}
+	return interrupted
+}
+
+// costByModel returns the spend recorded in progress.json per model, or nil
+// if there is no progress to read
+func costByModel(prdDir string) map[string]float64 {
+	progress, err := state.LoadProgressFromPath(filepath.Join(prdDir, "progress.json"))
+	if err != nil {
+		return nil
+	}
+	return progress.CostByModel()
+}
+
+// runMeta returns the metadata the run was tagged with via --meta, or nil
+// if there is no progress to read
+func runMeta(prdDir string) map[string]string {
+	progress, err := state.LoadProgressFromPath(filepath.Join(prdDir, "progress.json"))
+	if err != nil {
+		return nil
+	}
+	return progress.GetRunMeta()
+}
+
+// checkedOffTasks returns how many tasks of each feature progress.json
+// records as checked off by hand, or nil if there is no progress to read
+func checkedOffTasks(prdDir string) map[string]int {
+	progress, err := state.LoadProgressFromPath(filepath.Join(prdDir, "progress.json"))
+	if err != nil {
+		return nil
+	}
+
+	counts := make(map[string]int)
+	for id := range progress.Features {
+		for _, completed := range progress.GetTaskStates(id) {
+			if completed {
+				counts[id]++
+			}
+		}
+	}
+	return counts
+}
+
+func printStatus(m *manifest.Manifest, interrupted map[string]bool, costs map[string]float64, checkedOff map[string]int) {
+	summary := m.GetDetailedSummary()
+	summary.Running -= len(interrupted)
 
 	fmt.Println()
 	fmt.Printf("%s%s%s\n", colorBold, m.Title, colorReset)
@@ -49,15 +136,45 @@ func printStatus(m *manifest.Manifest) {
 
 	features := m.AllFeatures()
 	for _, f := range features {
-		printFeature(m, &f)
+		if interrupted[f.ID] {
+			f.Status = "interrupted"
+		}
+		printFeature(m, &f, checkedOff[f.ID])
+	}
+
+	fmt.Println()
+	printSummary(summary, len(interrupted))
+	printGroups(m.GroupSummaries())
+	if len(costs) > 0 {
+		fmt.Printf("Cost by model: %s\n", formatCostByModel(costs))
+	}
+	if len(interrupted) > 0 {
+		fmt.Printf("%sInterrupted features were running when ralph last exited; open ralph to reset them.%s\n", colorGray, colorReset)
 	}
+	fmt.Println()
+}
+
+// printTreeStatus prints the title, the feature tree and the summary line
+func printTreeStatus(m *manifest.Manifest, interrupted map[string]bool, costs map[string]float64) {
+	summary := m.GetDetailedSummary()
+	summary.Running -= len(interrupted)
 
 	fmt.Println()
-	printSummary(total, completed, running, failed, pending, blocked)
+	fmt.Printf("%s%s%s\n", colorBold, m.Title, colorReset)
+	fmt.Println(strings.Repeat("─", len(m.Title)))
+	fmt.Println()
+	fmt.Print(renderTree(m, interrupted, costs))
+	fmt.Println()
+	printSummary(summary, len(interrupted))
 	fmt.Println()
 }
 
-func printFeature(m *manifest.Manifest, f *manifest.ManifestFeature) {
+func printFeature(m *manifest.Manifest, f *manifest.ManifestFeature, checkedOff int) {
+	if f.Disabled {
+		fmt.Printf("  %s%s %s %s (disabled)%s\n", colorDim, iconDisabled, f.ID, f.Title, colorReset)
+		return
+	}
+
 	icon, color := getStatusIcon(f.Status, m.IsDependencySatisfied(f.ID))
 
 	deps := formatDeps(f.DependsOn)
@@ -68,6 +185,14 @@ func printFeature(m *manifest.Manifest, f *manifest.ManifestFeature) {
 
 	fmt.Printf("  %s%s%s %s %s%s\n", color, icon, colorReset, f.ID, f.Title, depsStr)
 
+	if checkedOff > 0 && f.Status != "completed" && f.Status != manifest.StatusCompletedWithWarnings {
+		tasks := "1 task"
+		if checkedOff > 1 {
+			tasks = fmt.Sprintf("%d tasks", checkedOff)
+		}
+		fmt.Printf("      %s↳ %s checked off by hand%s\n", colorGray, tasks, colorReset)
+	}
+
 	if f.Status == "pending" && !m.IsDependencySatisfied(f.ID) {
 		pending := m.GetPendingDependencies(f.ID)
 		if len(pending) > 0 {
@@ -81,10 +206,16 @@ func getStatusIcon(status string, depsSatisfied bool) (string, string) {
 	switch status {
 	case "completed":
 		return iconCompleted, colorGreen
+	case manifest.StatusCompletedWithWarnings:
+		return iconWarnings, colorYellow
 	case "running":
 		return iconRunning, colorYellow
 	case "failed":
 		return iconFailed, colorRed
+	case "interrupted":
+		return iconInterrupted, colorYellow
+	case manifest.StatusSkipped:
+		return iconDisabled, colorGray
 	case "pending":
 		if depsSatisfied {
 			return iconPending, colorGray
@@ -114,25 +245,36 @@ func getPendingDepTitles(m *manifest.Manifest, pendingIDs []string) []string {
 	return titles
 }
 
-func printSummary(total, completed, running, failed, pending, blocked int) {
+// printSummary prints the count of features in each status, leaving out
+// statuses no feature has
+func printSummary(s manifest.Summary, interrupted int) {
 	fmt.Printf("Summary: ")
 
 	parts := []string{}
 
-	if completed > 0 {
-		parts = append(parts, fmt.Sprintf("%s%d completed%s", colorGreen, completed, colorReset))
+	if s.Completed > 0 {
+		parts = append(parts, fmt.Sprintf("%s%d completed%s", colorGreen, s.Completed, colorReset))
+	}
+	if s.Running > 0 {
+		parts = append(parts, fmt.Sprintf("%s%d running%s", colorYellow, s.Running, colorReset))
 	}
-	if running > 0 {
-		parts = append(parts, fmt.Sprintf("%s%d running%s", colorYellow, running, colorReset))
+	if interrupted > 0 {
+		parts = append(parts, fmt.Sprintf("%s%d interrupted%s", colorYellow, interrupted, colorReset))
 	}
-	if failed > 0 {
-		parts = append(parts, fmt.Sprintf("%s%d failed%s", colorRed, failed, colorReset))
+	if s.Failed > 0 {
+		parts = append(parts, fmt.Sprintf("%s%d failed%s", colorRed, s.Failed, colorReset))
 	}
-	if pending > 0 {
-		parts = append(parts, fmt.Sprintf("%d pending", pending))
+	if s.OptionalFailed > 0 {
+		parts = append(parts, fmt.Sprintf("%s%d optional failed%s", colorYellow, s.OptionalFailed, colorReset))
 	}
-	if blocked > 0 {
-		parts = append(parts, fmt.Sprintf("%d blocked", blocked))
+	if s.Skipped > 0 {
+		parts = append(parts, fmt.Sprintf("%s%d skipped%s", colorGray, s.Skipped, colorReset))
+	}
+	if s.Pending > 0 {
+		parts = append(parts, fmt.Sprintf("%d pending", s.Pending))
+	}
+	if s.Blocked > 0 {
+		parts = append(parts, fmt.Sprintf("%d blocked", s.Blocked))
 	}
 
 	if len(parts) == 0 {
@@ -141,5 +283,55 @@ func printSummary(total, completed, running, failed, pending, blocked int) {
 		fmt.Printf("%s", strings.Join(parts, ", "))
 	}
 
-	fmt.Printf(" (%d total)\n", total)
+	fmt.Printf(" (%d total)\n", s.Total)
+}
+
+// formatCostByModel lists each model's cost, most expensive first
+func formatCostByModel(costs map[string]float64) string {
+	models := make([]string, 0, len(costs))
+	for model := range costs {
+		models = append(models, model)
+	}
+	sort.Slice(models, func(i, j int) bool {
+		if costs[models[i]] != costs[models[j]] {
+			return costs[models[i]] > costs[models[j]]
+		}
+		return models[i] < models[j]
+	})
+
+	parts := make([]string, 0, len(models))
+	for _, model := range models {
+		parts = append(parts, fmt.Sprintf("%s %s", model, usage.FormatCost(costs[model])))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// printGroups prints a status rollup line for each epic or group
+func printGroups(groups []manifest.GroupSummary) {
+	if len(groups) == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Println("Groups:")
+	for _, g := range groups {
+		fmt.Printf("  %s: %s\n", g.Name, formatGroupSummary(g))
+	}
+}
+
+func formatGroupSummary(g manifest.GroupSummary) string {
+	parts := []string{fmt.Sprintf("%d/%d completed", g.Completed, g.Total)}
+	if g.Running > 0 {
+		parts = append(parts, fmt.Sprintf("%d running", g.Running))
+	}
+	if g.Failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d failed", g.Failed))
+	}
+	if g.Pending > 0 {
+		parts = append(parts, fmt.Sprintf("%d pending", g.Pending))
+	}
+	if g.Blocked > 0 {
+		parts = append(parts, fmt.Sprintf("%d blocked", g.Blocked))
+	}
+	return strings.Join(parts, ", ")
 }
@@ -1,11 +1,18 @@
 package status
 
 import (
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/vx/ralph-go/internal/manifest"
+	"github.com/vx/ralph-go/internal/state"
+	"github.com/vx/ralph-go/internal/usage"
 )
 
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
 func TestGetStatusIcon(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -210,3 +217,89 @@ func TestColorsAreDefined(t *testing.T) {
 		t.Error("colorGray should not be empty")
 	}
 }
+
+func TestInterruptedFeatures(t *testing.T) {
+	dir := t.TempDir()
+
+	m := manifest.New("test.md", "Test PRD")
+	m.Features = append(m.Features,
+		manifest.ManifestFeature{ID: "01", Title: "Crashed", Status: "running"},
+		manifest.ManifestFeature{ID: "02", Title: "Done", Status: "completed"},
+	)
+
+	if got := interruptedFeatures(dir, m); len(got) != 0 {
+		t.Errorf("expected no interrupted features without progress.json, got %v", got)
+	}
+
+	p := state.NewProgress()
+	p.SetPathDirect(filepath.Join(dir, "progress.json"))
+	p.InitFeature("01", "Crashed")
+	p.InitFeature("02", "Done")
+	p.UpdateFeature("01", "running")
+	p.UpdateFeature("02", "running") // manifest says completed, so not interrupted
+	if err := p.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	got := interruptedFeatures(dir, m)
+	if len(got) != 1 || !got["01"] {
+		t.Errorf("expected only 01 to be interrupted, got %v", got)
+	}
+}
+
+func TestCheckedOffTasks(t *testing.T) {
+	dir := t.TempDir()
+	if got := checkedOffTasks(dir); got != nil {
+		t.Errorf("expected nil without progress.json, got %v", got)
+	}
+
+	p := state.NewProgress()
+	p.SetPathDirect(filepath.Join(dir, "progress.json"))
+	p.SetTaskCompleted("01", "task-a", true)
+	p.SetTaskCompleted("01", "task-b", true)
+	p.SetTaskCompleted("02", "task-c", false)
+	if err := p.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	got := checkedOffTasks(dir)
+	if got["01"] != 2 || got["02"] != 0 {
+		t.Errorf("expected 2 tasks checked off for 01 and none for 02, got %v", got)
+	}
+}
+
+func TestFormatCostByModel(t *testing.T) {
+	got := formatCostByModel(map[string]float64{
+		"sonnet": 1.5,
+		"opus":   4.25,
+		"haiku":  1.5,
+	})
+	want := "opus $4.25, haiku $1.50, sonnet $1.50"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestRenderTree(t *testing.T) {
+	m := manifest.New("", "Test")
+	m.Features = []manifest.ManifestFeature{
+		{ID: "01", Title: "Auth", Status: "running", Children: []string{"01-01", "01-02"}},
+		{ID: "01-01", Title: "Login", Status: "completed", ParentID: "01", Depth: 1, Children: []string{"01-01-01"}},
+		{ID: "01-01-01", Title: "Tokens", Status: "failed", ParentID: "01-01", Depth: 2},
+		{ID: "01-02", Title: "Signup", Status: "pending", ParentID: "01", Depth: 1},
+		{ID: "02", Title: "Billing", Status: "pending", Usage: &usage.TokenUsage{CostUSD: 0.5}},
+	}
+	costs := map[string]float64{"01": 1.25, "01-01": 0.4}
+
+	got := ansiPattern.ReplaceAllString(renderTree(m, nil, costs), "")
+	want := strings.Join([]string{
+		"  ● 01 Auth (running) $1.25",
+		"  ├── ✓ 01-01 Login (completed) $0.40",
+		"  │   └── ✗ 01-01-01 Tokens (failed)",
+		"  └── ○ 01-02 Signup (pending)",
+		"  ○ 02 Billing (pending) $0.50",
+	}, "\n") + "\n"
+	if got != want {
+		t.Errorf("unexpected tree:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,99 @@
+package status
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/vx/ralph-go/internal/manifest"
+	"github.com/vx/ralph-go/internal/state"
+	"github.com/vx/ralph-go/internal/usage"
+)
+
+// featureCosts returns the spend recorded in progress.json per feature, or
+// nil if there is no progress to read
+func featureCosts(prdDir string) map[string]float64 {
+	progress, err := state.LoadProgressFromPath(filepath.Join(prdDir, "progress.json"))
+	if err != nil {
+		return nil
+	}
+
+	costs := make(map[string]float64)
+	for id, f := range progress.Features {
+		if f.EstimatedCost > 0 {
+			costs[id] = f.EstimatedCost
+		}
+	}
+	return costs
+}
+
+// renderTree draws every feature as an ASCII tree, spawned sub-features
+// nested under their parent the way the TUI shows them. Each node carries its
+// status icon and, when known, its cost: from costs, falling back to the
+// usage recorded in the manifest.
+func renderTree(m *manifest.Manifest, interrupted map[string]bool, costs map[string]float64) string {
+	features := m.AllFeatures()
+	known := make(map[string]bool, len(features))
+	for _, f := range features {
+		known[f.ID] = true
+	}
+
+	children := make(map[string][]manifest.ManifestFeature)
+	var roots []manifest.ManifestFeature
+	for _, f := range features {
+		if f.ParentID != "" && known[f.ParentID] {
+			children[f.ParentID] = append(children[f.ParentID], f)
+		} else {
+			roots = append(roots, f)
+		}
+	}
+
+	var sb strings.Builder
+	var render func(f manifest.ManifestFeature, indent string, branch string)
+	render = func(f manifest.ManifestFeature, indent string, branch string) {
+		sb.WriteString("  " + indent + branch + treeNode(m, f, interrupted, costs) + "\n")
+
+		childIndent := indent
+		switch branch {
+		case "├── ":
+			childIndent += "│   "
+		case "└── ":
+			childIndent += "    "
+		}
+		kids := children[f.ID]
+		for i, child := range kids {
+			childBranch := "├── "
+			if i == len(kids)-1 {
+				childBranch = "└── "
+			}
+			render(child, childIndent, childBranch)
+		}
+	}
+	for _, root := range roots {
+		render(root, "", "")
+	}
+	return sb.String()
+}
+
+// treeNode formats one feature of the tree: icon, ID, title, status and cost
+func treeNode(m *manifest.Manifest, f manifest.ManifestFeature, interrupted map[string]bool, costs map[string]float64) string {
+	if f.Disabled {
+		return fmt.Sprintf("%s%s %s %s (disabled)%s", colorDim, iconDisabled, f.ID, f.Title, colorReset)
+	}
+
+	status := f.Status
+	if interrupted[f.ID] {
+		status = "interrupted"
+	}
+	icon, color := getStatusIcon(status, m.IsDependencySatisfied(f.ID))
+
+	node := fmt.Sprintf("%s%s%s %s %s %s(%s)%s", color, icon, colorReset, f.ID, f.Title, colorDim, status, colorReset)
+	cost := costs[f.ID]
+	if cost == 0 && f.Usage != nil {
+		cost = f.Usage.CostUSD
+	}
+	if cost > 0 {
+		node += " " + usage.FormatCost(cost)
+	}
+	return node
+}
@@ -4,11 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/vx/ralph-go/internal/actions"
+	"github.com/vx/ralph-go/internal/usage"
 )
 
 const (
@@ -31,6 +33,17 @@ type ChildResult struct {
 	Actions      []ActionEntry  `json:"actions,omitempty"`
 	Duration     time.Duration  `json:"duration_ms,omitempty"`
 	TokensUsed   int64          `json:"tokens_used,omitempty"`
+
+	// ExpectedOutputs are the files the spawn request asked the child for
+	ExpectedOutputs []string `json:"expected_outputs,omitempty"`
+}
+
+// ExpectedOutput is a file the parent asked the child for and how the child
+// changed it
+type ExpectedOutput struct {
+	Path      string `json:"path"`
+	Operation string `json:"operation,omitempty"` // Empty if the child didn't touch it
+	Found     string `json:"found,omitempty"`     // The changed file it matched
 }
 
 // FileChange represents a file modification
@@ -132,29 +145,66 @@ func (r *ChildResult) SetDuration(d time.Duration) {
 	r.Duration = d
 }
 
-// ExtractFromActions populates file changes from action list
+// SetExpectedOutputs sets the files the parent asked the child for, which the
+// summary reports on ahead of everything else
+func (r *ChildResult) SetExpectedOutputs(outputs []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ExpectedOutputs = outputs
+}
+
+// expectedOutputs matches each expected output against the changed files. A
+// relative path matches a changed file with the same path or ending in it,
+// since actions usually record absolute paths.
+func (r *ChildResult) expectedOutputs() []ExpectedOutput {
+	result := make([]ExpectedOutput, 0, len(r.ExpectedOutputs))
+	for _, expected := range r.ExpectedOutputs {
+		out := ExpectedOutput{Path: expected}
+		want := strings.TrimPrefix(strings.TrimSpace(expected), "./")
+		for _, f := range r.sortedFiles() {
+			if f.Path == want || strings.HasSuffix(f.Path, "/"+want) {
+				out.Operation = f.Operation
+				out.Found = f.Path
+				break
+			}
+		}
+		result = append(result, out)
+	}
+	return result
+}
+
+// ExtractFromActions populates file changes from action list. Files are
+// recorded by their full path when the action carries one, so the parent
+// knows exactly which files the child touched.
 func (r *ChildResult) ExtractFromActions(acts []actions.Action) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	seen := make(map[string]bool)
+	for _, f := range r.FilesChanged {
+		seen[f.Path] = true
+	}
+
 	for _, act := range acts {
+		var operation string
 		switch act.Type {
 		case actions.ActionWrite:
-			if !seen[act.Target] {
-				r.FilesChanged = append(r.FilesChanged, FileChange{
-					Path:      act.Target,
-					Operation: "created",
-				})
-				seen[act.Target] = true
-			}
+			operation = "created"
 		case actions.ActionEdit:
-			if !seen[act.Target] {
+			operation = "modified"
+		}
+
+		if operation != "" {
+			path := act.Path
+			if path == "" {
+				path = act.Target
+			}
+			if path != "" && !seen[path] {
 				r.FilesChanged = append(r.FilesChanged, FileChange{
-					Path:      act.Target,
-					Operation: "modified",
+					Path:      path,
+					Operation: operation,
 				})
-				seen[act.Target] = true
+				seen[path] = true
 			}
 		}
 
@@ -165,7 +215,30 @@ func (r *ChildResult) ExtractFromActions(acts []actions.Action) {
 	}
 }
 
-// GenerateSummary creates a formatted summary within the given token budget
+// sortedFiles returns the changed files ordered by path, so summaries don't
+// depend on the order the child happened to touch them
+func (r *ChildResult) sortedFiles() []FileChange {
+	files := make([]FileChange, len(r.FilesChanged))
+	copy(files, r.FilesChanged)
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	return files
+}
+
+// sortedFailures returns the test failures in sorted order
+func (r *ChildResult) sortedFailures() []string {
+	failures := make([]string, len(r.TestResults.Failures))
+	copy(failures, r.TestResults.Failures)
+	sort.Strings(failures)
+	return failures
+}
+
+// GenerateSummary creates a formatted summary within the given token budget.
+// Sections always appear in the same order and files and test failures are
+// sorted, so identical results produce byte-identical output. Key actions
+// keep their chronological order. Repeated lines in the error and failures
+// are collapsed so they don't use up the budget.
 func (r *ChildResult) GenerateSummary(maxTokens int64) *Summary {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -183,7 +256,22 @@ func (r *ChildResult) GenerateSummary(maxTokens int64) *Summary {
 
 	// Error if present
 	if r.Error != "" {
-		sb.WriteString(fmt.Sprintf("**Error:** %s\n", r.Error))
+		sb.WriteString(fmt.Sprintf("**Error:** %s\n", dedupeText(r.Error)))
+	}
+
+	// Expected outputs first: they are what the parent spawned the child for
+	if len(r.ExpectedOutputs) > 0 {
+		sb.WriteString("\n### Expected Outputs\n")
+		for _, out := range r.expectedOutputs() {
+			switch {
+			case out.Operation == "":
+				sb.WriteString(fmt.Sprintf("- **%s**: not produced\n", out.Path))
+			case out.Found != out.Path:
+				sb.WriteString(fmt.Sprintf("- **%s**: %s (%s)\n", out.Path, out.Operation, out.Found))
+			default:
+				sb.WriteString(fmt.Sprintf("- **%s**: %s\n", out.Path, out.Operation))
+			}
+		}
 	}
 
 	// Test results
@@ -196,7 +284,7 @@ func (r *ChildResult) GenerateSummary(maxTokens int64) *Summary {
 		}
 		if len(r.TestResults.Failures) > 0 {
 			sb.WriteString("\n**Failures:**\n")
-			for _, f := range r.TestResults.Failures {
+			for _, f := range dedupeLines(r.sortedFailures(), strings.TrimSpace) {
 				sb.WriteString(fmt.Sprintf("- %s\n", f))
 			}
 		}
@@ -205,7 +293,7 @@ func (r *ChildResult) GenerateSummary(maxTokens int64) *Summary {
 	// Files changed
 	if len(r.FilesChanged) > 0 {
 		sb.WriteString("\n### Files Changed\n")
-		for _, f := range r.FilesChanged {
+		for _, f := range r.sortedFiles() {
 			sb.WriteString(fmt.Sprintf("- %s: %s\n", f.Operation, f.Path))
 		}
 	}
@@ -271,7 +359,11 @@ func (r *ChildResult) formatForInjection(summaryText string) string {
 	}
 
 	if r.Error != "" {
-		data["sub_feature_completed"].(map[string]interface{})["error"] = r.Error
+		data["sub_feature_completed"].(map[string]interface{})["error"] = dedupeText(r.Error)
+	}
+
+	if len(r.ExpectedOutputs) > 0 {
+		data["sub_feature_completed"].(map[string]interface{})["expected_outputs"] = r.expectedOutputs()
 	}
 
 	if r.TestResults != nil {
@@ -284,7 +376,7 @@ func (r *ChildResult) formatForInjection(summaryText string) string {
 
 	if len(r.FilesChanged) > 0 {
 		files := make([]string, len(r.FilesChanged))
-		for i, f := range r.FilesChanged {
+		for i, f := range r.sortedFiles() {
 			files[i] = f.Path
 		}
 		data["sub_feature_completed"].(map[string]interface{})["files_changed"] = files
@@ -387,26 +479,57 @@ func (r *ChildResult) BuildHaikuPrompt() string {
 	return sb.String()
 }
 
-// estimateTokens provides a rough token count (chars / 4 is a reasonable estimate)
+// truncationNotice is appended to a summary cut down to its token budget
+const truncationNotice = "\n\n[Summary truncated to fit context budget]"
+
+// estimateTokens approximates the token count of text with a BPE-like
+// tokenizer, so summaries can be trimmed close to their budget
 func estimateTokens(text string) int {
-	return len(text) / 4
+	return int(usage.CountTokens(text))
 }
 
-// truncateToTokenBudget truncates text to fit within token budget
+// truncateToTokenBudget truncates text so that, with the truncation notice,
+// it counts at most maxTokens tokens
 func truncateToTokenBudget(text string, maxTokens int64) string {
-	maxChars := int(maxTokens * 4)
-	if len(text) <= maxChars {
+	if usage.CountTokens(text) <= maxTokens {
 		return text
 	}
 
-	// Find a good truncation point (end of a line)
-	truncated := text[:maxChars]
-	lastNewline := strings.LastIndex(truncated, "\n")
-	if lastNewline > maxChars/2 {
+	budget := maxTokens - usage.CountTokens(truncationNotice)
+	if budget <= 0 {
+		// Too tight for the notice; keep as much of the text as fits
+		return prefixWithinTokens(text, maxTokens)
+	}
+
+	// Prefer to end on a line boundary when that keeps most of the text
+	truncated := prefixWithinTokens(text, budget)
+	if lastNewline := strings.LastIndex(truncated, "\n"); lastNewline > len(truncated)/2 {
 		truncated = truncated[:lastNewline]
 	}
 
-	return truncated + "\n\n[Summary truncated to fit context budget]"
+	return truncated + truncationNotice
+}
+
+// prefixWithinTokens returns the longest prefix of text, cut on a rune
+// boundary, that counts at most maxTokens tokens
+func prefixWithinTokens(text string, maxTokens int64) string {
+	cuts := make([]int, 0, len(text)+1)
+	for i := range text {
+		cuts = append(cuts, i)
+	}
+	cuts = append(cuts, len(text))
+
+	// cuts[lo] always fits, since the empty prefix counts no tokens
+	lo, hi := 0, len(cuts)-1
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if usage.CountTokens(text[:cuts[mid]]) <= maxTokens {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return text[:cuts[lo]]
 }
 
 // truncateString truncates a string with ellipsis
@@ -419,6 +542,58 @@ func truncateString(s string, maxLen int) string {
 	return s[:maxLen-3] + "..."
 }
 
+var (
+	hexPattern    = regexp.MustCompile(`0x[0-9a-fA-F]+`)
+	numberPattern = regexp.MustCompile(`\b\d+\b`)
+)
+
+// lineKey normalises a line for deduplication, so lines that differ only in
+// surrounding whitespace, addresses or standalone numbers, such as goroutine
+// IDs and line numbers in a stack trace, count as repeats
+func lineKey(line string) string {
+	key := strings.Join(strings.Fields(line), " ")
+	key = hexPattern.ReplaceAllString(key, "0x?")
+	return numberPattern.ReplaceAllString(key, "#")
+}
+
+// dedupeLines collapses lines with the same key into their first
+// occurrence, marked "(repeated N×)". Blank lines are kept as they are.
+func dedupeLines(lines []string, key func(string) string) []string {
+	counts := make(map[string]int)
+	for _, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			counts[key(line)]++
+		}
+	}
+
+	result := make([]string, 0, len(lines))
+	seen := make(map[string]bool)
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			result = append(result, line)
+			continue
+		}
+		k := key(line)
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		if n := counts[k]; n > 1 {
+			line = fmt.Sprintf("%s (repeated %d×)", line, n)
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
+// dedupeText collapses repeated and near-identical lines of text
+func dedupeText(text string) string {
+	if !strings.Contains(text, "\n") {
+		return text
+	}
+	return strings.Join(dedupeLines(strings.Split(text, "\n"), lineKey), "\n")
+}
+
 // Summarizer handles result summarization with optional AI assistance
 type Summarizer struct {
 	mu sync.RWMutex
@@ -485,7 +660,9 @@ func (s *Summarizer) Summarize(result *ChildResult) (*Summary, error) {
 	return result.GenerateSummary(maxTokens), nil
 }
 
-// ExtractTestFailures parses test output for failure details
+// ExtractTestFailures parses test output for failure details. A failure
+// reported more than once, as when a suite is re-run, is listed once with
+// its count.
 func ExtractTestFailures(output string) []string {
 	var failures []string
 
@@ -516,5 +693,10 @@ func ExtractTestFailures(output string) []string {
 		}
 	}
 
-	return failures
+	if len(failures) == 0 {
+		return nil
+	}
+	// Failures are only collapsed when identical: parametrised tests differ
+	// in just a number
+	return dedupeLines(failures, strings.TrimSpace)
 }
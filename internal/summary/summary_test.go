@@ -1,6 +1,8 @@
 package summary
 
 import (
+	"encoding/json"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -117,6 +119,40 @@ func TestChildResultExtractFromActions(t *testing.T) {
 	}
 }
 
+func TestChildResultExtractFromActionsFullPaths(t *testing.T) {
+	r := NewChildResult("child-1", "Child", "completed")
+
+	now := time.Now()
+	acts := []*actions.Action{
+		actions.ExtractAction("Write", json.RawMessage(`{"file_path":"/home/user/project/internal/api/handler.go"}`), now),
+		actions.ExtractAction("Write", json.RawMessage(`{"file_path":"/home/user/project/internal/api/handler_test.go"}`), now),
+		actions.ExtractAction("Edit", json.RawMessage(`{"file_path":"/home/user/project/internal/api/handler.go"}`), now),
+	}
+	var list []actions.Action
+	for _, act := range acts {
+		list = append(list, *act)
+	}
+
+	r.ExtractFromActions(list)
+
+	if len(r.FilesChanged) != 2 {
+		t.Fatalf("Expected 2 files changed, got %d", len(r.FilesChanged))
+	}
+
+	summary := r.GenerateSummary(5000)
+	for _, path := range []string{
+		"/home/user/project/internal/api/handler.go",
+		"/home/user/project/internal/api/handler_test.go",
+	} {
+		if !strings.Contains(summary.Raw, "created: "+path) {
+			t.Errorf("Summary should list %s as created, got:\n%s", path, summary.Raw)
+		}
+		if !strings.Contains(summary.Formatted, path) {
+			t.Errorf("Formatted summary should include %s", path)
+		}
+	}
+}
+
 func TestGenerateSummaryBasic(t *testing.T) {
 	r := NewChildResult("test-123", "Test Feature", "completed")
 	r.SetTestResults(5, 0, 0, "")
@@ -167,6 +203,40 @@ func TestGenerateSummaryWithError(t *testing.T) {
 	}
 }
 
+func TestGenerateSummaryCollapsesRepeatedErrorLines(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 12; i++ {
+		lines = append(lines, fmt.Sprintf("panic: nil map write in goroutine %d", i))
+		lines = append(lines, fmt.Sprintf("\tmain.go:%d +0x%x", 40+i, 0x1d+i))
+	}
+	r := NewChildResult("test", "Test", "failed")
+	r.SetError("build failed\n" + strings.Join(lines, "\n"))
+
+	summary := r.GenerateSummary(5000)
+
+	if got := strings.Count(summary.Raw, "panic: nil map write"); got != 1 {
+		t.Errorf("expected the repeated panic once, got %d times:\n%s", got, summary.Raw)
+	}
+	if !strings.Contains(summary.Raw, "panic: nil map write in goroutine 1 (repeated 12×)") {
+		t.Errorf("expected the first panic marked as repeated, got:\n%s", summary.Raw)
+	}
+	if !strings.Contains(summary.Raw, "main.go:41 +0x1e (repeated 12×)") {
+		t.Errorf("expected near-identical trace lines collapsed, got:\n%s", summary.Raw)
+	}
+	if strings.Contains(summary.Formatted, "goroutine 2") {
+		t.Error("expected the injected error to be collapsed too")
+	}
+}
+
+func TestDedupeLinesKeepsDistinctLines(t *testing.T) {
+	lines := []string{"first", "", "second", "first", "", "first"}
+	want := []string{"first (repeated 3×)", "", "second", ""}
+	got := dedupeLines(lines, strings.TrimSpace)
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("dedupeLines = %q, want %q", got, want)
+	}
+}
+
 func TestGenerateSummaryTruncation(t *testing.T) {
 	r := NewChildResult("test", "Test", "completed")
 
@@ -203,6 +273,133 @@ func TestGenerateSummaryFormatted(t *testing.T) {
 	}
 }
 
+func goldenChildResult(files [][2]string, failures []string) *ChildResult {
+	r := NewChildResult("feat-golden", "Golden Feature", "failed")
+	r.SetError("1 test failed")
+	r.SetTestResults(3, 2, 1, "")
+	r.TestResults.Failures = failures
+	for _, f := range files {
+		r.AddFileChange(f[0], f[1])
+	}
+	r.AddAction("write", "handler.go", "")
+	r.AddAction("bash", "go test ./...", "")
+	r.SetTokensUsed(4200)
+	r.SetDuration(90 * time.Second)
+	return r
+}
+
+const goldenSummaryRaw = `## Sub-Feature: Golden Feature
+
+**Status:** failed
+**Error:** 1 test failed
+
+### Test Results
+- Passed: 3
+- Failed: 2
+- Skipped: 1
+
+**Failures:**
+- TestCreate
+- TestDelete
+
+### Files Changed
+- modified: /app/api/handler.go
+- created: /app/api/handler_test.go
+- created: /app/store/store.go
+
+### Key Actions
+- write: handler.go
+- bash: go test ./...
+
+### Stats
+- Tokens: 4200
+- Duration: 1m30s
+`
+
+const goldenSummaryFormatted = `{
+  "sub_feature_completed": {
+    "error": "1 test failed",
+    "files_changed": [
+      "/app/api/handler.go",
+      "/app/api/handler_test.go",
+      "/app/store/store.go"
+    ],
+    "id": "feat-golden",
+    "status": "failed",
+    "summary": ` + "%s" + `,
+    "tests": {
+      "failed": 2,
+      "passed": 3,
+      "total": 6
+    },
+    "title": "Golden Feature",
+    "tokens_used": 4200
+  }
+}`
+
+func TestGenerateSummaryGolden(t *testing.T) {
+	files := [][2]string{
+		{"/app/store/store.go", "created"},
+		{"/app/api/handler.go", "modified"},
+		{"/app/api/handler_test.go", "created"},
+	}
+	r := goldenChildResult(files, []string{"TestDelete", "TestCreate"})
+
+	summary := r.GenerateSummary(5000)
+
+	if summary.Raw != goldenSummaryRaw {
+		t.Errorf("raw summary does not match golden output\ngot:\n%s\nwant:\n%s", summary.Raw, goldenSummaryRaw)
+	}
+	rawJSON, _ := json.Marshal(goldenSummaryRaw)
+	wantFormatted := fmt.Sprintf(goldenSummaryFormatted, rawJSON)
+	if summary.Formatted != wantFormatted {
+		t.Errorf("formatted summary does not match golden output\ngot:\n%s\nwant:\n%s", summary.Formatted, wantFormatted)
+	}
+
+	// The same result with files and failures recorded in a different
+	// order produces byte-identical output
+	reversed := [][2]string{files[2], files[1], files[0]}
+	other := goldenChildResult(reversed, []string{"TestCreate", "TestDelete"}).GenerateSummary(5000)
+	if other.Raw != summary.Raw || other.Formatted != summary.Formatted {
+		t.Error("expected summary to be independent of file and failure ordering")
+	}
+}
+
+func TestGenerateSummaryHighlightsExpectedOutputs(t *testing.T) {
+	r := NewChildResult("child-1", "Token parsing", "completed")
+	r.SetExpectedOutputs([]string{"internal/auth/token.go", "docs/auth.md"})
+	r.ExtractFromActions([]actions.Action{
+		{Type: actions.ActionEdit, Target: ".../app/README.md", Path: "/app/README.md"},
+		{Type: actions.ActionWrite, Target: ".../auth/token.go", Path: "/app/internal/auth/token.go"},
+	})
+
+	summary := r.GenerateSummary(5000)
+
+	expected := strings.Index(summary.Raw, "### Expected Outputs")
+	files := strings.Index(summary.Raw, "### Files Changed")
+	if expected < 0 || expected > files {
+		t.Fatalf("expected the expected outputs ahead of the files changed:\n%s", summary.Raw)
+	}
+	if !strings.Contains(summary.Raw, "- **internal/auth/token.go**: created (/app/internal/auth/token.go)") {
+		t.Errorf("expected the produced file to be highlighted:\n%s", summary.Raw)
+	}
+	if !strings.Contains(summary.Raw, "- **docs/auth.md**: not produced") {
+		t.Errorf("expected the missing file to be flagged:\n%s", summary.Raw)
+	}
+	if !strings.Contains(summary.Formatted, `"found": "/app/internal/auth/token.go"`) {
+		t.Errorf("expected the expected outputs in the injected JSON:\n%s", summary.Formatted)
+	}
+
+	// A tight budget cuts the actions, not the outputs the parent asked for
+	for i := 0; i < 40; i++ {
+		r.AddAction("bash", fmt.Sprintf("go test ./internal/auth/... -run TestCase%d", i), "")
+	}
+	tight := r.GenerateSummary(60)
+	if !tight.Truncated || !strings.Contains(tight.Raw, "internal/auth/token.go**: created") {
+		t.Errorf("expected the expected outputs to survive truncation:\n%s", tight.Raw)
+	}
+}
+
 func TestCompact(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -354,6 +551,16 @@ func TestExtractTestFailures(t *testing.T) {
 			output:   "PASS\nok  test 0.001s",
 			expected: nil,
 		},
+		{
+			name:     "repeated failures collapse",
+			output:   strings.Repeat("--- FAIL: TestFlaky\n", 3) + "--- FAIL: TestOther",
+			expected: []string{"TestFlaky (repeated 3×)", "TestOther"},
+		},
+		{
+			name:     "parametrised failures stay distinct",
+			output:   "FAILED test_math.py::test_add[1]\nFAILED test_math.py::test_add[2]",
+			expected: []string{"test_math.py::test_add[1]", "test_math.py::test_add[2]"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -379,7 +586,8 @@ func TestEstimateTokens(t *testing.T) {
 		{"", 0},
 		{"test", 1},
 		{"hello world", 2},
-		{strings.Repeat("x", 100), 25},
+		{"internal/summary/summary.go", 7},
+		{strings.Repeat("x", 100), 24},
 	}
 
 	for _, tt := range tests {
@@ -402,6 +610,57 @@ func TestTruncateToTokenBudget(t *testing.T) {
 	}
 }
 
+func TestTruncateToTokenBudgetStaysWithinBudget(t *testing.T) {
+	inputs := map[string]string{
+		"prose":    strings.Repeat("The parent feature should retry the migration once the lock is released. ", 80),
+		"paths":    strings.Repeat("- modified: internal/runner/outputlog_test.go\n", 120),
+		"markdown": strings.Repeat("### Test Results\n**Failures:**\n- TestParseConfig/empty_input (0.01s)\n", 60),
+		"numbers":  strings.Repeat("- Tokens: 1234567, Duration: 12m30s\n", 90),
+		"unicode":  strings.Repeat("résumé ✓ 完了 → done\n", 100),
+		"no lines": strings.Repeat("abcdefghij", 400),
+	}
+
+	for name, text := range inputs {
+		for _, budget := range []int64{5, 20, 50, 100, 250, 500} {
+			truncated := truncateToTokenBudget(text, budget)
+			if got := estimateTokens(truncated); int64(got) > budget {
+				t.Errorf("%s at budget %d: truncated to %d tokens", name, budget, got)
+			}
+			// Trimming should use most of the budget, not overshoot the other way
+			if budget >= 100 && int64(estimateTokens(truncated)) < budget*3/4 {
+				t.Errorf("%s at budget %d: only %d tokens kept", name, budget, estimateTokens(truncated))
+			}
+		}
+	}
+}
+
+func TestGenerateSummaryFitsTokenBudget(t *testing.T) {
+	r := NewChildResult("child-1", "Refactor the runner", "failed")
+	r.SetError(strings.Repeat("exit status 1: go test ./internal/runner failed; ", 20))
+	failures := make([]string, 0, 40)
+	for i := 0; i < 40; i++ {
+		failures = append(failures, fmt.Sprintf("TestInstanceLifecycle/case_%02d", i))
+	}
+	r.TestResults = &TestSummary{Passed: 120, Failed: 40, Failures: failures}
+	for i := 0; i < 60; i++ {
+		r.AddFileChange(fmt.Sprintf("internal/runner/generated_file_%02d.go", i), "modified")
+		r.AddAction("Edit", fmt.Sprintf("internal/runner/generated_file_%02d.go", i), "completed")
+	}
+
+	for _, budget := range []int64{40, 100, 300, 800} {
+		s := r.GenerateSummary(budget)
+		if !s.Truncated {
+			t.Errorf("budget %d: expected summary to be truncated", budget)
+		}
+		if s.TokenCount > int(budget) {
+			t.Errorf("budget %d: summary counts %d tokens", budget, s.TokenCount)
+		}
+		if got := estimateTokens(s.Raw); got != s.TokenCount {
+			t.Errorf("budget %d: TokenCount %d doesn't match summary text (%d)", budget, s.TokenCount, got)
+		}
+	}
+}
+
 func TestTruncateString(t *testing.T) {
 	tests := []struct {
 		s        string
@@ -0,0 +1,167 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vx/ralph-go/internal/live"
+	"github.com/vx/ralph-go/internal/logger"
+	"github.com/vx/ralph-go/internal/manifest"
+	"github.com/vx/ralph-go/internal/runner"
+	"github.com/vx/ralph-go/internal/state"
+)
+
+// attachPollInterval is how often an attached TUI re-reads the headless
+// run's manifest, live status and inspected output log
+const attachPollInterval = time.Second
+
+// attachedReadOnlyMsg is shown when a key that would change the run is
+// pressed while attached
+const attachedReadOnlyMsg = "Read-only: attached to a headless run"
+
+// attachPollMsg carries what a headless run has written to disk since the
+// last poll
+type attachPollMsg struct {
+	manifest  *manifest.Manifest
+	status    *live.Status
+	featureID string // Feature whose output log was read
+	output    string
+	err       error
+}
+
+// RunAttach opens the TUI read-only on the headless run publishing
+// .ralph/live.json in the current directory. It follows the run from the
+// files the run writes and never starts, stops or saves anything itself.
+func RunAttach(prdDir string, opts Options) error {
+	workDir := filepath.Dir(prdDir)
+	if err := logger.Init(workDir); err != nil {
+		return fmt.Errorf("failed to init logger: %w", err)
+	}
+	defer logger.Close()
+
+	logger.Info("tui", "Attaching to headless run", "prdDir", prdDir)
+
+	model := initialModelForAttach(prdDir)
+	model.scroll.Tolerance = opts.FollowTolerance
+	model.attachedLog = runner.LogOptions{ScanBufferSize: opts.ScanBufferSize, Clip: opts.ClipLimits}
+	p := tea.NewProgram(model, tea.WithAltScreen())
+	_, err := p.Run()
+
+	logger.Info("tui", "Ralph detached", "error", err)
+	return err
+}
+
+func initialModelForAttach(prdDir string) Model {
+	m := initialModelForManifest(prdDir)
+	m.attached = true
+	// The headless run doesn't write progress.json, so statuses come from
+	// its manifest instead and nothing is saved
+	m.state = state.NewProgress()
+	return m
+}
+
+// pollAttached reads the run's files after delay, including the output log
+// of the feature being inspected, if any
+func pollAttached(prdDir, featureID string, opts runner.LogOptions, delay time.Duration) tea.Cmd {
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return readAttached(prdDir, featureID, opts)
+	})
+}
+
+// readAttached loads the manifest and live status of the run. Like 'ralph
+// logs', the status and output logs are read from the PRD directory's
+// parent, where 'ralph run' writes them.
+func readAttached(prdDir, featureID string, opts runner.LogOptions) attachPollMsg {
+	workDir := filepath.Dir(prdDir)
+	m, err := manifest.Load(prdDir)
+	if err != nil {
+		return attachPollMsg{err: err}
+	}
+	msg := attachPollMsg{manifest: m, featureID: featureID}
+	if status, err := live.Read(workDir); err == nil {
+		msg.status = status
+	} else if !os.IsNotExist(err) {
+		msg.err = err
+	}
+	if featureID != "" {
+		msg.output = readOutputLog(workDir, featureID, opts)
+	}
+	return msg
+}
+
+// readOutputLog formats a feature's output log for the inspect view
+func readOutputLog(workDir, featureID string, opts runner.LogOptions) string {
+	f, err := os.Open(runner.OutputLogPath(workDir, featureID))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	var sb strings.Builder
+	if err := runner.FormatLog(f, &sb, opts); err != nil {
+		logger.Warn("tui", "Failed to read output log", "featureID", featureID, "error", err)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// applyAttachPoll brings the model up to date with the headless run and
+// schedules the next poll
+func (m Model) applyAttachPoll(msg attachPollMsg) (tea.Model, tea.Cmd) {
+	next := pollAttached(m.prdDir, m.inspecting, m.attachedLog, attachPollInterval)
+	if msg.err != nil {
+		logger.Warn("tui", "Failed to read headless run", "error", msg.err)
+		m.setStatus(fmt.Sprintf("Error reading run: %v", msg.err))
+		return m, next
+	}
+
+	m.manifest = msg.manifest
+	for _, f := range msg.manifest.Features {
+		status := f.Status
+		if status == "" {
+			status = "pending"
+		}
+		fs := m.state.GetFeature(f.ID)
+		if fs == nil {
+			m.state.InitFeature(f.ID, f.Title)
+			fs = m.state.GetFeature(f.ID)
+		}
+		if fs.Status == status {
+			continue
+		}
+		m.state.UpdateFeature(f.ID, status)
+		switch {
+		case status == "running":
+			m.activityLog.AddFeatureStarted(f.ID, f.Title)
+		case status == "failed":
+			m.activityLog.AddFeatureFailed(f.ID, f.Title)
+		case manifest.IsCompleted(status):
+			m.activityLog.AddFeatureCompleted(f.ID, f.Title)
+		}
+	}
+
+	if msg.featureID != "" && msg.featureID == m.inspecting {
+		m.attachedOutput = msg.output
+	}
+
+	if msg.status != nil {
+		m.attachedStatus = msg.status.Describe(time.Now())
+	} else {
+		m.attachedStatus = "No headless run found: waiting for " + live.StatusFile
+	}
+	return m, next
+}
+
+// isAttachedReadOnlyKey reports whether key would change the run, which an
+// attached TUI leaves to the process running it
+func isAttachedReadOnlyKey(key string) bool {
+	switch key {
+	case "s", "S", "r", "R", "x", "X", "m", "e", "ctrl+r", "ctrl+x",
+		"1", "2", "3", "4", "5", "6", "7", "8", "9":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vx/ralph-go/internal/live"
+	"github.com/vx/ralph-go/internal/manifest"
+	"github.com/vx/ralph-go/internal/runner"
+)
+
+// setupAttachedRun writes the files a headless run leaves on disk while
+// feature 02 is running, and changes into its directory
+func setupAttachedRun(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	origDir, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(origDir) })
+	os.Chdir(dir)
+
+	prdDir := filepath.Join(dir, "PRD")
+	os.MkdirAll(prdDir, 0755)
+	mf := manifest.New("PRD.md", "Attach Test")
+	mf.Features = append(mf.Features,
+		manifest.ManifestFeature{ID: "01", Dir: "01-schema", Title: "Schema", Status: "completed"},
+		manifest.ManifestFeature{ID: "02", Dir: "02-auth", Title: "Auth", Status: "running", DependsOn: []string{"01"}},
+	)
+	mf.SetPath(filepath.Join(prdDir, "manifest.json"))
+	if err := mf.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	if err := live.Write("", &live.Status{
+		PID:       4242,
+		PRDDir:    prdDir,
+		StartedAt: now.Add(-time.Minute),
+		UpdatedAt: now,
+		Running:   []live.Feature{{ID: "02", Title: "Auth", StartedAt: now}},
+		Completed: 1,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	logDir := filepath.Join(dir, ".ralph", "logs")
+	os.MkdirAll(logDir, 0755)
+	os.WriteFile(filepath.Join(logDir, "02.jsonl"), []byte(`{"type":"assistant","message":{"content":"Adding login handler"}}`+"\n"), 0644)
+	return prdDir
+}
+
+func TestReadAttachedRunningState(t *testing.T) {
+	prdDir := setupAttachedRun(t)
+
+	msg := readAttached(prdDir, "02", runner.LogOptions{})
+	if msg.err != nil {
+		t.Fatalf("unexpected error: %v", msg.err)
+	}
+	if msg.status == nil || msg.status.PID != 4242 || !msg.status.Active(time.Now()) {
+		t.Fatalf("expected an active status for pid 4242, got %+v", msg.status)
+	}
+	if !strings.Contains(msg.output, "Adding login handler") {
+		t.Errorf("expected the output log to be read, got %q", msg.output)
+	}
+
+	m := initialModelForAttach(prdDir)
+	updated, _ := m.Update(loadManifest(prdDir)())
+	m = updated.(Model)
+	m.inspecting = "02"
+	updated, cmd := m.Update(msg)
+	m = updated.(Model)
+	if cmd == nil {
+		t.Error("expected the next poll to be scheduled")
+	}
+
+	if got := m.getFeatureStatus("01"); got != "completed" {
+		t.Errorf("expected 01 completed, got %q", got)
+	}
+	if got := m.getFeatureStatus("02"); got != "running" {
+		t.Errorf("expected 02 running, got %q", got)
+	}
+	if !strings.Contains(m.attachedStatus, "pid 4242") || !strings.Contains(m.attachedStatus, "1 running") {
+		t.Errorf("unexpected run status %q", m.attachedStatus)
+	}
+	if !strings.Contains(m.attachedOutput, "Adding login handler") {
+		t.Errorf("expected inspected output to be kept, got %q", m.attachedOutput)
+	}
+}
+
+func TestReadAttachedWithoutRun(t *testing.T) {
+	prdDir := setupAttachedRun(t)
+	os.Remove(live.Path(""))
+
+	msg := readAttached(prdDir, "", runner.LogOptions{})
+	if msg.err != nil || msg.status != nil {
+		t.Fatalf("expected no status and no error, got %+v", msg)
+	}
+
+	m := initialModelForAttach(prdDir)
+	updated, _ := m.Update(msg)
+	m = updated.(Model)
+	if !strings.Contains(m.attachedStatus, "No headless run found") {
+		t.Errorf("unexpected run status %q", m.attachedStatus)
+	}
+}
+
+func TestAttachedIsReadOnly(t *testing.T) {
+	prdDir := setupAttachedRun(t)
+
+	m := initialModelForAttach(prdDir)
+	updated, _ := m.Update(loadManifest(prdDir)())
+	m = updated.(Model)
+	updated, _ = m.Update(readAttached(prdDir, "", runner.LogOptions{}))
+	m = updated.(Model)
+	m.taskList.SetItems(m.buildTaskItems())
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	m = updated.(Model)
+	if m.autoMode {
+		t.Error("expected auto mode to stay off while attached")
+	}
+	if m.statusMsg != attachedReadOnlyMsg {
+		t.Errorf("expected read-only status, got %q", m.statusMsg)
+	}
+
+	m.statusMsg = ""
+	for i := 0; i < m.taskList.VisibleCount(); i++ {
+		m.taskList.SetSelected(i)
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+		m = updated.(Model)
+	}
+	if m.statusMsg != attachedReadOnlyMsg {
+		t.Errorf("expected ctrl+x to be read-only, got %q", m.statusMsg)
+	}
+	onDisk, err := manifest.Load(prdDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := onDisk.GetFeature("02").Status; got != "running" {
+		t.Errorf("expected ctrl+x to leave the headless run's manifest alone, got status %q", got)
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	m = updated.(Model)
+	if m.confirmDialog.IsVisible() || cmd == nil {
+		t.Error("expected q to detach without asking to stop instances")
+	}
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -42,20 +43,17 @@ func TestAutoScrollEnabledOnEnterInspectView(t *testing.T) {
 	m.taskList.SetItems([]layout.TaskItem{{ID: "test-feature-1", Title: "Test Feature 1", Status: "pending"}})
 
 	m.currentView = viewMain
-	m.autoScroll = false
+	m.scroll.Follow = false
 
 	newModel, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("enter")})
 	resultModel := newModel.(Model)
 
-	if !resultModel.autoScroll {
+	if !resultModel.scroll.Follow {
 		t.Error("autoScroll should be enabled when entering inspect view")
 	}
 	if resultModel.currentView != viewInspect {
 		t.Error("should switch to inspect view")
 	}
-	if resultModel.scrollOffset != 999999 {
-		t.Errorf("scrollOffset should be set to max (999999), got %d", resultModel.scrollOffset)
-	}
 }
 
 func TestAutoScrollDisabledOnScrollUp(t *testing.T) {
@@ -63,17 +61,17 @@ func TestAutoScrollDisabledOnScrollUp(t *testing.T) {
 	m.prd = mockPRD()
 	m.state = mockState()
 	m.currentView = viewInspect
-	m.autoScroll = true
-	m.scrollOffset = 10
+	m.scroll.Follow = true
+	m.scroll.Offset = 10
 
 	newModel, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
 	resultModel := newModel.(Model)
 
-	if resultModel.autoScroll {
+	if resultModel.scroll.Follow {
 		t.Error("autoScroll should be disabled when scrolling up with 'k'")
 	}
-	if resultModel.scrollOffset != 9 {
-		t.Errorf("scrollOffset should be 9, got %d", resultModel.scrollOffset)
+	if resultModel.scroll.Offset != 9 {
+		t.Errorf("scrollOffset should be 9, got %d", resultModel.scroll.Offset)
 	}
 }
 
@@ -82,17 +80,17 @@ func TestAutoScrollDisabledOnGoToTop(t *testing.T) {
 	m.prd = mockPRD()
 	m.state = mockState()
 	m.currentView = viewInspect
-	m.autoScroll = true
-	m.scrollOffset = 50
+	m.scroll.Follow = true
+	m.scroll.Offset = 50
 
 	newModel, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
 	resultModel := newModel.(Model)
 
-	if resultModel.autoScroll {
+	if resultModel.scroll.Follow {
 		t.Error("autoScroll should be disabled when pressing 'g' (go to top)")
 	}
-	if resultModel.scrollOffset != 0 {
-		t.Errorf("scrollOffset should be 0, got %d", resultModel.scrollOffset)
+	if resultModel.scroll.Offset != 0 {
+		t.Errorf("scrollOffset should be 0, got %d", resultModel.scroll.Offset)
 	}
 }
 
@@ -101,18 +99,15 @@ func TestAutoScrollReEnabledOnGoToEnd(t *testing.T) {
 	m.prd = mockPRD()
 	m.state = mockState()
 	m.currentView = viewInspect
-	m.autoScroll = false
-	m.scrollOffset = 10
+	m.scroll.Follow = false
+	m.scroll.Offset = 10
 
 	newModel, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")})
 	resultModel := newModel.(Model)
 
-	if !resultModel.autoScroll {
+	if !resultModel.scroll.Follow {
 		t.Error("autoScroll should be enabled when pressing 'G' (go to end)")
 	}
-	if resultModel.scrollOffset != 999999 {
-		t.Errorf("scrollOffset should be max (999999), got %d", resultModel.scrollOffset)
-	}
 }
 
 func TestAutoScrollReEnabledOnFollow(t *testing.T) {
@@ -120,18 +115,15 @@ func TestAutoScrollReEnabledOnFollow(t *testing.T) {
 	m.prd = mockPRD()
 	m.state = mockState()
 	m.currentView = viewInspect
-	m.autoScroll = false
-	m.scrollOffset = 10
+	m.scroll.Follow = false
+	m.scroll.Offset = 10
 
 	newModel, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")})
 	resultModel := newModel.(Model)
 
-	if !resultModel.autoScroll {
+	if !resultModel.scroll.Follow {
 		t.Error("autoScroll should be enabled when pressing 'f' (follow)")
 	}
-	if resultModel.scrollOffset != 999999 {
-		t.Errorf("scrollOffset should be max (999999), got %d", resultModel.scrollOffset)
-	}
 }
 
 func TestAutoScrollResetOnExitInspectView(t *testing.T) {
@@ -139,18 +131,18 @@ func TestAutoScrollResetOnExitInspectView(t *testing.T) {
 	m.prd = mockPRD()
 	m.state = mockState()
 	m.currentView = viewInspect
-	m.autoScroll = true
-	m.scrollOffset = 50
+	m.scroll.Follow = true
+	m.scroll.Offset = 50
 	m.inspecting = "test-id"
 
 	newModel, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
 	resultModel := newModel.(Model)
 
-	if resultModel.autoScroll {
+	if resultModel.scroll.Follow {
 		t.Error("autoScroll should be disabled when exiting inspect view")
 	}
-	if resultModel.scrollOffset != 0 {
-		t.Errorf("scrollOffset should be reset to 0, got %d", resultModel.scrollOffset)
+	if resultModel.scroll.Offset != 0 {
+		t.Errorf("scrollOffset should be reset to 0, got %d", resultModel.scroll.Offset)
 	}
 	if resultModel.currentView != viewMain {
 		t.Error("should switch back to main view")
@@ -162,17 +154,17 @@ func TestAutoScrollNotDisabledOnScrollDown(t *testing.T) {
 	m.prd = mockPRD()
 	m.state = mockState()
 	m.currentView = viewInspect
-	m.autoScroll = true
-	m.scrollOffset = 10
+	m.scroll.Follow = true
+	m.scroll.Offset = 10
 
 	newModel, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
 	resultModel := newModel.(Model)
 
-	if !resultModel.autoScroll {
+	if !resultModel.scroll.Follow {
 		t.Error("autoScroll should remain enabled when scrolling down")
 	}
-	if resultModel.scrollOffset != 11 {
-		t.Errorf("scrollOffset should be 11, got %d", resultModel.scrollOffset)
+	if resultModel.scroll.Offset != 11 {
+		t.Errorf("scrollOffset should be 11, got %d", resultModel.scroll.Offset)
 	}
 }
 
@@ -181,16 +173,45 @@ func TestAutoScrollNotDisabledWhenAlreadyAtTopScrollUp(t *testing.T) {
 	m.prd = mockPRD()
 	m.state = mockState()
 	m.currentView = viewInspect
-	m.autoScroll = true
-	m.scrollOffset = 0
+	m.scroll.Follow = true
+	m.scroll.Offset = 0
 
 	newModel, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
 	resultModel := newModel.(Model)
 
-	if !resultModel.autoScroll {
+	if !resultModel.scroll.Follow {
 		t.Error("autoScroll should remain enabled when at top and pressing scroll up")
 	}
-	if resultModel.scrollOffset != 0 {
-		t.Errorf("scrollOffset should stay at 0, got %d", resultModel.scrollOffset)
+	if resultModel.scroll.Offset != 0 {
+		t.Errorf("scrollOffset should stay at 0, got %d", resultModel.scroll.Offset)
+	}
+}
+
+func TestScrollUpWhileFollowingStartsFromRenderedBottom(t *testing.T) {
+	m := initialModel("test.md")
+	m.prd = mockPRD()
+	m.state = mockState()
+	m.currentView = viewInspect
+	m.modal.SetSize(100, 50)
+	m.modal.SetContent(strings.Repeat("output\n", 200))
+	m.modal.SetAutoScroll(true)
+	m.modal.Render("")
+	m.scroll.Bottom(-1)
+
+	newModel, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	resultModel := newModel.(Model)
+
+	maxOffset := m.modal.MaxScrollOffset()
+	if resultModel.scroll.Follow {
+		t.Error("autoScroll should be disabled after scrolling up")
+	}
+	if resultModel.scroll.Offset != maxOffset-1 {
+		t.Errorf("scrollOffset should be one line above the bottom (%d), got %d", maxOffset-1, resultModel.scroll.Offset)
+	}
+
+	newModel, _ = resultModel.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	resultModel = newModel.(Model)
+	if !resultModel.scroll.Follow {
+		t.Error("autoScroll should resume after scrolling back down near the bottom")
 	}
 }
@@ -3,6 +3,7 @@ package tui
 import (
 	"os"
 	"path/filepath"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 
@@ -44,12 +45,31 @@ type instanceOutputMsg struct {
 type instanceDoneMsg struct {
 	featureID string
 	status    string
+	instance  *runner.Instance // The instance that finished, if known
 }
 
 type tickMsg struct{}
 
+// retryDueMsg starts a failed feature's automatic retry once its retry
+// delay has passed
+type retryDueMsg struct {
+	feature parser.Feature
+}
+
 type statusClearMsg struct{}
 
+// idleCheckMsg redraws the task list so idle warnings appear while no
+// output is arriving
+type idleCheckMsg struct{}
+
+// idleCheckInterval is how often the task list is redrawn for idle warnings
+// while features are running
+const idleCheckInterval = 10 * time.Second
+
+func scheduleIdleCheck() tea.Cmd {
+	return tea.Tick(idleCheckInterval, func(time.Time) tea.Msg { return idleCheckMsg{} })
+}
+
 type spawnRequestMsg struct {
 	parentID string
 	request  *rlm.SpawnRequest
@@ -77,6 +97,7 @@ type modelChangedMsg struct {
 	toModel   string
 	reason    string
 	details   string
+	evidence  string
 }
 
 func loadPRD(path string) tea.Cmd {
@@ -122,13 +143,22 @@ func loadStateFromDir(prdDir string) tea.Cmd {
 // manifestToPRD converts a manifest to a synthetic PRD for TUI compatibility
 func manifestToPRD(m *manifest.Manifest, prdDir string) *parser.PRD {
 	prd := &parser.PRD{
-		Title:        m.Title,
-		Context:      "", // Context will be read from feature.md files
-		BudgetTokens: m.BudgetTokens,
-		BudgetUSD:    m.BudgetUSD,
+		Title:            m.Title,
+		Context:          "", // Context will be read from feature.md files
+		BudgetTokens:     m.BudgetTokens,
+		BudgetUSD:        m.BudgetUSD,
+		ClaudeArgs:       m.ClaudeArgs,
+		MaxConcurrent:    m.Concurrent,
+		MaxRetries:       m.Retries,
+		WarningThreshold: m.Warnings,
 	}
 
 	for _, mf := range m.Features {
+		// Spawned sub-features have no feature.md; they are shown under
+		// their parent from the progress tree instead
+		if !mf.IsRootFeature() {
+			continue
+		}
 		// Read feature content from feature.md
 		featurePath := filepath.Join(prdDir, mf.Dir, "feature.md")
 		content, _ := os.ReadFile(featurePath)
@@ -140,6 +170,14 @@ func manifestToPRD(m *manifest.Manifest, prdDir string) *parser.PRD {
 			ExecutionMode: mf.Execution,
 			Model:         mf.Model,
 			DependsOn:     mf.DependsOn,
+			Base:          mf.Base,
+			Group:         mf.Group,
+			Optional:      mf.Optional,
+			OnFailure:     mf.OnFailure,
+			Disabled:      mf.Disabled,
+			Files:         mf.Files,
+
+			MaxChildrenConcurrent: mf.MaxChildrenConcurrent,
 		}
 		prd.Features = append(prd.Features, feature)
 	}
@@ -147,13 +185,18 @@ func manifestToPRD(m *manifest.Manifest, prdDir string) *parser.PRD {
 	return prd
 }
 
-func startFeature(feature parser.Feature, context string, workDir string, mgr *runner.Manager) tea.Cmd {
+func startFeature(feature parser.Feature, context string, workDir string, mgr *runner.Manager, attempt int) tea.Cmd {
 	return func() tea.Msg {
 		progressContent := readProgressMD(workDir)
 		prompt := feature.ToPromptWithProgress(context, progressContent)
 		opts := runner.StartInstanceOptions{
-			IsLeafTask: len(feature.Tasks) <= 2,
-			TaskCount:  len(feature.Tasks),
+			IsLeafTask:   featureTaskCount(feature) <= 2,
+			TaskCount:    featureTaskCount(feature),
+			Tasks:        taskDescriptions(feature),
+			Base:         feature.Base,
+			Attempt:      attempt,
+			BudgetTokens: feature.BudgetTokens,
+			BudgetUSD:    feature.BudgetUSD,
 		}
 		instance, err := mgr.StartInstanceWithOptions(feature.ID, feature.Model, prompt, opts)
 		if err != nil {
@@ -169,13 +212,25 @@ func startFeature(feature parser.Feature, context string, workDir string, mgr *r
 	}
 }
 
-func startFeatureWithBudget(feature parser.Feature, context string, workDir string, mgr *runner.Manager) tea.Cmd {
+func startFeatureWithBudget(feature parser.Feature, context string, workDir string, mgr *runner.Manager, attempt int) tea.Cmd {
+	return resumeFeature(feature, context, workDir, mgr, attempt, "")
+}
+
+// resumeFeature starts a feature like startFeatureWithBudget, continuing the
+// claude session sessionID when it is set
+func resumeFeature(feature parser.Feature, context string, workDir string, mgr *runner.Manager, attempt int, sessionID string) tea.Cmd {
 	return func() tea.Msg {
 		progressContent := readProgressMD(workDir)
 		prompt := feature.ToPromptWithProgress(context, progressContent)
 		opts := runner.StartInstanceOptions{
-			IsLeafTask: len(feature.Tasks) <= 2,
-			TaskCount:  len(feature.Tasks),
+			IsLeafTask:      featureTaskCount(feature) <= 2,
+			TaskCount:       featureTaskCount(feature),
+			Tasks:           taskDescriptions(feature),
+			Base:            feature.Base,
+			Attempt:         attempt,
+			BudgetTokens:    feature.BudgetTokens,
+			BudgetUSD:       feature.BudgetUSD,
+			ResumeSessionID: sessionID,
 		}
 		instance, err := mgr.StartInstanceWithOptions(feature.ID, feature.Model, prompt, opts)
 		if err != nil {
@@ -195,6 +250,32 @@ func startFeatureWithBudget(feature parser.Feature, context string, workDir stri
 	}
 }
 
+// displaySessionID shortens a session ID for status messages
+func displaySessionID(sessionID string) string {
+	if len(sessionID) > 8 {
+		return sessionID[:8]
+	}
+	return sessionID
+}
+
+// featureTaskCount returns how many tasks a feature has. In manifest mode
+// they are only in the description, which holds the feature.md.
+func featureTaskCount(feature parser.Feature) int {
+	if len(feature.Tasks) > 0 {
+		return len(feature.Tasks)
+	}
+	return parser.CountTasks(feature.Description)
+}
+
+// taskDescriptions returns the descriptions of a feature's tasks
+func taskDescriptions(feature parser.Feature) []string {
+	tasks := make([]string, len(feature.Tasks))
+	for i, task := range feature.Tasks {
+		tasks[i] = task.Description
+	}
+	return tasks
+}
+
 func readProgressMD(workDir string) string {
 	path := filepath.Join(workDir, "progress.md")
 	content, err := os.ReadFile(path)
@@ -204,9 +285,11 @@ func readProgressMD(workDir string) string {
 	return string(content)
 }
 
-func startSpawnedChild(parentID string, child *rlm.RecursiveFeature, prompt string, workDir string, mgr *runner.Manager) tea.Cmd {
+// startSpawnedChild starts a child feature. A child whose parent limits its
+// children with Max-Children-Concurrent: isn't held to the global limit.
+func startSpawnedChild(parentID string, child *rlm.RecursiveFeature, prompt string, workDir string, mgr *runner.Manager, ownLimit bool) tea.Cmd {
 	return func() tea.Msg {
-		instance, err := mgr.StartInstance(child.ID, child.Model, prompt)
+		instance, err := mgr.StartInstanceWithOptions(child.ID, child.Model, prompt, runner.StartInstanceOptions{OwnConcurrencyLimit: ownLimit})
 		if err != nil {
 			return spawnStartedMsg{
 				parentID:   parentID,
@@ -239,6 +322,7 @@ func listenForOutput(featureID string, instance *runner.Instance) tea.Cmd {
 			return instanceDoneMsg{
 				featureID: featureID,
 				status:    instance.GetStatus(),
+				instance:  instance,
 			}
 		}
 		return instanceOutputMsg{
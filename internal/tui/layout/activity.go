@@ -2,6 +2,8 @@ package layout
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 	"sync"
 	"time"
 
@@ -10,8 +12,15 @@ import (
 
 const (
 	MaxActivityEntries = 100
+	// MaxAssistantTextLen is how much of an assistant message the activity
+	// feed shows
+	MaxAssistantTextLen = 120
 )
 
+// toolMarkerRegex matches the placeholders the runner puts in assistant text
+// for tool calls
+var toolMarkerRegex = regexp.MustCompile(`\[Tool: [^\]]*\]`)
+
 type ActivityType int
 
 const (
@@ -22,6 +31,7 @@ const (
 	ActivityFeatureStopped
 	ActivityFeatureRetry
 	ActivityOutput
+	ActivityAssistant // Condensed assistant narration
 )
 
 type Activity struct {
@@ -90,6 +100,39 @@ func (a *ActivityLog) AddOutput(featureID, message string) {
 	a.Add(ActivityOutput, message, featureID)
 }
 
+// AddAssistantText adds a condensed line of the assistant's narration: tool
+// markers are dropped, whitespace collapsed and the text clipped to
+// MaxAssistantTextLen. Messages with no text, such as bare tool calls, add
+// nothing. Each feature keeps only its latest narration, replacing the one
+// before, so a chatty feature can't push other activity out of the log.
+func (a *ActivityLog) AddAssistantText(featureID, text string) {
+	text = strings.Join(strings.Fields(toolMarkerRegex.ReplaceAllString(text, " ")), " ")
+	if text == "" {
+		return
+	}
+	if runes := []rune(text); len(runes) > MaxAssistantTextLen {
+		text = string(runes[:MaxAssistantTextLen-3]) + "..."
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	entries := []Activity{{
+		Type:      ActivityAssistant,
+		Timestamp: time.Now(),
+		Message:   "› " + text,
+		FeatureID: featureID,
+	}}
+	for _, entry := range a.entries {
+		if entry.Type != ActivityAssistant || entry.FeatureID != featureID {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) > a.maxEntries {
+		entries = entries[:a.maxEntries]
+	}
+	a.entries = entries
+}
+
 func (a *ActivityLog) GetEntries() []Activity {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -99,6 +142,20 @@ func (a *ActivityLog) GetEntries() []Activity {
 	return result
 }
 
+// GetEntriesForFeature returns only the entries recorded for featureID
+func (a *ActivityLog) GetEntriesForFeature(featureID string) []Activity {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	var result []Activity
+	for _, entry := range a.entries {
+		if entry.FeatureID == featureID {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
 func (a *ActivityLog) Count() int {
 	a.mu.RLock()
 	defer a.mu.RUnlock()
@@ -127,6 +184,8 @@ func (act Activity) Color() lipgloss.TerminalColor {
 		return colorStopped
 	case ActivityPRDLoaded, ActivityOutput:
 		return colorNormal
+	case ActivityAssistant:
+		return colorSubtle
 	default:
 		return colorSubtle
 	}
@@ -158,6 +217,7 @@ type ActivityPane struct {
 	width        int
 	height       int
 	scrollOffset int
+	filterID     string // When set, only entries for this feature are shown
 }
 
 func NewActivityPane(log *ActivityLog) *ActivityPane {
@@ -172,6 +232,29 @@ func (p *ActivityPane) SetSize(width, height int) {
 	p.height = height
 }
 
+// SetFilter shows only entries for featureID; an empty ID shows all entries
+func (p *ActivityPane) SetFilter(featureID string) {
+	p.filterID = featureID
+	p.scrollOffset = 0
+}
+
+// ClearFilter shows entries for all features
+func (p *ActivityPane) ClearFilter() {
+	p.SetFilter("")
+}
+
+// Filter returns the feature ID entries are filtered to, or "" if unfiltered
+func (p *ActivityPane) Filter() string {
+	return p.filterID
+}
+
+func (p *ActivityPane) entries() []Activity {
+	if p.filterID != "" {
+		return p.log.GetEntriesForFeature(p.filterID)
+	}
+	return p.log.GetEntries()
+}
+
 func (p *ActivityPane) ScrollUp() {
 	if p.scrollOffset > 0 {
 		p.scrollOffset--
@@ -179,7 +262,7 @@ func (p *ActivityPane) ScrollUp() {
 }
 
 func (p *ActivityPane) ScrollDown() {
-	entries := p.log.Count()
+	entries := len(p.entries())
 	maxOffset := entries - p.height
 	if maxOffset < 0 {
 		maxOffset = 0
@@ -194,7 +277,7 @@ func (p *ActivityPane) ScrollToTop() {
 }
 
 func (p *ActivityPane) ScrollToBottom() {
-	entries := p.log.Count()
+	entries := len(p.entries())
 	maxOffset := entries - p.height
 	if maxOffset < 0 {
 		maxOffset = 0
@@ -203,9 +286,12 @@ func (p *ActivityPane) ScrollToBottom() {
 }
 
 func (p *ActivityPane) Render() string {
-	entries := p.log.GetEntries()
+	entries := p.entries()
 	if len(entries) == 0 {
 		emptyStyle := lipgloss.NewStyle().Foreground(colorSubtle).Italic(true)
+		if p.filterID != "" {
+			return emptyStyle.Render("No activity for this feature")
+		}
 		return emptyStyle.Render("No activity yet")
 	}
 
@@ -1,6 +1,7 @@
 package layout
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -151,6 +152,7 @@ func TestActivityColor(t *testing.T) {
 		{ActivityFeatureStopped, colorStopped},
 		{ActivityPRDLoaded, colorNormal},
 		{ActivityOutput, colorNormal},
+		{ActivityAssistant, colorSubtle},
 	}
 
 	for _, tt := range tests {
@@ -162,6 +164,59 @@ func TestActivityColor(t *testing.T) {
 	}
 }
 
+func TestActivityLogAddAssistantText(t *testing.T) {
+	log := NewActivityLog()
+
+	log.AddAssistantText("01", "I'll start by reading\n  the config loader. [Tool: Read] Then add tests.")
+	entries := log.GetEntries()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.Type != ActivityAssistant || entry.FeatureID != "01" {
+		t.Errorf("expected an assistant entry for 01, got %+v", entry)
+	}
+	if entry.Message != "› I'll start by reading the config loader. Then add tests." {
+		t.Errorf("expected condensed text without tool markers, got %q", entry.Message)
+	}
+
+	log.AddAssistantText("01", "[Tool: Bash] [Tool: Edit]")
+	log.AddAssistantText("01", "   ")
+	if log.Count() != 1 {
+		t.Errorf("expected messages without text to be skipped, got %d entries", log.Count())
+	}
+
+	log.AddAssistantText("02", strings.Repeat("word ", 100))
+	long := log.GetEntries()[0].Message
+	if got := len([]rune(strings.TrimPrefix(long, "› "))); got != MaxAssistantTextLen {
+		t.Errorf("expected text clipped to %d runes, got %d", MaxAssistantTextLen, got)
+	}
+	if !strings.HasSuffix(long, "...") {
+		t.Errorf("expected clipped text to end with ..., got %q", long)
+	}
+}
+
+func TestActivityLogAssistantTextReplacesFeaturesLast(t *testing.T) {
+	log := NewActivityLog()
+	log.AddFeatureStarted("01", "Auth")
+	log.AddFeatureStarted("02", "Billing")
+	for i := 0; i < MaxActivityEntries*2; i++ {
+		log.AddAssistantText("01", fmt.Sprintf("step %d", i))
+		log.AddAssistantText("02", fmt.Sprintf("step %d", i))
+	}
+
+	if got := log.Count(); got != 4 {
+		t.Fatalf("expected both starts and one narration per feature, got %d entries", got)
+	}
+	narration := log.GetEntriesForFeature("01")
+	if narration[0].Type != ActivityAssistant || narration[0].Message != fmt.Sprintf("› step %d", MaxActivityEntries*2-1) {
+		t.Errorf("expected the feature's latest narration, got %+v", narration[0])
+	}
+	if narration[1].Type != ActivityFeatureStarted {
+		t.Errorf("expected the start to survive the narration, got %+v", narration[1])
+	}
+}
+
 func TestActivityRender(t *testing.T) {
 	act := Activity{
 		Type:      ActivityFeatureStarted,
@@ -302,3 +357,57 @@ func TestActivityLogThreadSafety(t *testing.T) {
 		t.Errorf("Log should respect max entries even with concurrent access")
 	}
 }
+
+func TestActivityLogGetEntriesForFeature(t *testing.T) {
+	log := NewActivityLog()
+	log.AddPRDLoaded("Test PRD")
+	log.AddFeatureStarted("01", "Feature One")
+	log.AddFeatureStarted("02", "Feature Two")
+	log.AddOutput("01", "Output from one")
+	log.AddFeatureCompleted("02", "Feature Two")
+
+	entries := log.GetEntriesForFeature("01")
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries for feature 01, got %d", len(entries))
+	}
+	for _, e := range entries {
+		if e.FeatureID != "01" {
+			t.Errorf("Expected only feature 01 entries, got %s", e.FeatureID)
+		}
+	}
+	if entries[0].Message != "Output from one" {
+		t.Errorf("Expected newest entry first, got %q", entries[0].Message)
+	}
+
+	if len(log.GetEntriesForFeature("03")) != 0 {
+		t.Error("Expected no entries for unknown feature")
+	}
+}
+
+func TestActivityPaneFilter(t *testing.T) {
+	log := NewActivityLog()
+	log.AddFeatureStarted("01", "Feature One")
+	log.AddFeatureStarted("02", "Feature Two")
+	pane := NewActivityPane(log)
+	pane.SetSize(80, 10)
+
+	pane.SetFilter("02")
+	if pane.Filter() != "02" {
+		t.Errorf("Expected filter 02, got %q", pane.Filter())
+	}
+	rendered := pane.Render()
+	if !strings.Contains(rendered, "Feature Two") || strings.Contains(rendered, "Feature One") {
+		t.Errorf("Expected only Feature Two in filtered render, got %q", rendered)
+	}
+
+	pane.SetFilter("03")
+	if !strings.Contains(pane.Render(), "No activity for this feature") {
+		t.Error("Expected empty filter message")
+	}
+
+	pane.ClearFilter()
+	rendered = pane.Render()
+	if !strings.Contains(rendered, "Feature One") || !strings.Contains(rendered, "Feature Two") {
+		t.Error("Expected all entries after clearing filter")
+	}
+}
@@ -0,0 +1,61 @@
+package layout
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// BudgetBarWidth is the number of cells used for the header budget bar
+	BudgetBarWidth = 10
+
+	budgetBarFilled = "█"
+	budgetBarEmpty  = "░"
+
+	budgetWarnPercent  = 70.0
+	budgetAlertPercent = 90.0
+)
+
+// BudgetBarColor returns the bar color for a budget percentage:
+// green below 70%, yellow below 90%, red at or past the 90% threshold
+func BudgetBarColor(percent float64) lipgloss.TerminalColor {
+	switch {
+	case percent >= budgetAlertPercent:
+		return colorFailed
+	case percent >= budgetWarnPercent:
+		return colorRunning
+	default:
+		return colorCompleted
+	}
+}
+
+// RenderBudgetBar renders a colored progress bar for budget usage.
+// Percent is clamped to 0-100 for the fill; width is the number of cells.
+func RenderBudgetBar(percent float64, width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	fillPercent := percent
+	if fillPercent < 0 {
+		fillPercent = 0
+	}
+	if fillPercent > 100 {
+		fillPercent = 100
+	}
+
+	filled := int(fillPercent/100*float64(width) + 0.5)
+	if filled == 0 && fillPercent > 0 {
+		filled = 1
+	}
+	if filled > width {
+		filled = width
+	}
+
+	filledStyle := lipgloss.NewStyle().Foreground(BudgetBarColor(percent))
+	emptyStyle := lipgloss.NewStyle().Foreground(colorDim)
+
+	return filledStyle.Render(strings.Repeat(budgetBarFilled, filled)) +
+		emptyStyle.Render(strings.Repeat(budgetBarEmpty, width-filled))
+}
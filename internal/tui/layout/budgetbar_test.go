@@ -0,0 +1,88 @@
+package layout
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderBudgetBar(t *testing.T) {
+	tests := []struct {
+		name       string
+		percent    float64
+		width      int
+		wantFilled int
+	}{
+		{"empty", 0, 10, 0},
+		{"half", 50, 10, 5},
+		{"rounds to nearest cell", 64, 10, 6},
+		{"small usage shows one cell", 1, 10, 1},
+		{"full", 100, 10, 10},
+		{"over budget clamps", 150, 10, 10},
+		{"negative clamps", -5, 10, 0},
+		{"wider bar", 25, 20, 5},
+		{"narrow bar", 90, 4, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bar := RenderBudgetBar(tt.percent, tt.width)
+			filled := strings.Count(bar, budgetBarFilled)
+			empty := strings.Count(bar, budgetBarEmpty)
+			if filled != tt.wantFilled {
+				t.Errorf("expected %d filled cells, got %d", tt.wantFilled, filled)
+			}
+			if filled+empty != tt.width {
+				t.Errorf("expected %d total cells, got %d", tt.width, filled+empty)
+			}
+		})
+	}
+}
+
+func TestRenderBudgetBarZeroWidth(t *testing.T) {
+	if bar := RenderBudgetBar(50, 0); bar != "" {
+		t.Errorf("expected empty bar for zero width, got %q", bar)
+	}
+}
+
+func TestBudgetBarColor(t *testing.T) {
+	tests := []struct {
+		percent float64
+		want    interface{}
+	}{
+		{0, colorCompleted},
+		{69.9, colorCompleted},
+		{70, colorRunning},
+		{89.9, colorRunning},
+		{90, colorFailed},
+		{120, colorFailed},
+	}
+
+	for _, tt := range tests {
+		if got := BudgetBarColor(tt.percent); got != tt.want {
+			t.Errorf("BudgetBarColor(%.1f) = %v, want %v", tt.percent, got, tt.want)
+		}
+	}
+}
+
+func TestHeaderRenderWithBudgetBar(t *testing.T) {
+	h := NewHeader()
+	h.SetWidth(120)
+
+	data := HeaderData{
+		Version:       "ralph v0.5.1",
+		Title:         "Feature Builder",
+		Total:         5,
+		Completed:     2,
+		BudgetStatus:  "$3.20/$5.00 (64%)",
+		BudgetPercent: 64,
+	}
+
+	result := h.Render(data)
+
+	if !strings.Contains(result, budgetBarFilled) {
+		t.Error("should contain budget bar")
+	}
+	if !strings.Contains(result, "$3.20/$5.00 (64%)") {
+		t.Error("should still contain budget status text")
+	}
+}
@@ -19,6 +19,8 @@ const (
 	ConfirmTypeQuit ConfirmType = iota
 	ConfirmTypeReset
 	ConfirmTypeBudget
+	ConfirmTypeStale
+	ConfirmTypeInterrupted
 )
 
 type ConfirmDialog struct {
@@ -62,6 +64,10 @@ func (c *ConfirmDialog) title() string {
 		return "Reset ALL features?"
 	case ConfirmTypeBudget:
 		return "Budget threshold reached!"
+	case ConfirmTypeStale:
+		return "PRD changed since last run"
+	case ConfirmTypeInterrupted:
+		return "Interrupted features found"
 	default:
 		return "Confirm"
 	}
@@ -75,6 +81,10 @@ func (c *ConfirmDialog) message() string {
 		return "This will stop all instances and\ndelete progress.md"
 	case ConfirmTypeBudget:
 		return "You've used 90% of your budget.\nContinue anyway?"
+	case ConfirmTypeStale:
+		return "Completed features may be out of date.\nReset ALL features?"
+	case ConfirmTypeInterrupted:
+		return "Some features were running when ralph\nlast exited. Reset them to pending?"
 	default:
 		return ""
 	}
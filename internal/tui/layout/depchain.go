@@ -0,0 +1,50 @@
+package layout
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DependencyLine is one upstream dependency listed in the dependency modal
+type DependencyLine struct {
+	ID        string
+	Title     string
+	Status    string
+	Depth     int  // 1 for a direct dependency
+	Missing   bool // Not found in the manifest
+	Satisfied bool // Done, or otherwise no longer blocking
+}
+
+// FormatDependencyChain renders a feature's transitive dependencies as an
+// indented list, each with its status icon, followed by how many aren't done
+func FormatDependencyChain(title string, deps []DependencyLine) string {
+	var sb strings.Builder
+	sb.WriteString(title + "\n\n")
+
+	if len(deps) == 0 {
+		sb.WriteString("No dependencies.\n")
+		return strings.TrimSuffix(sb.String(), "\n")
+	}
+
+	waiting := 0
+	for _, dep := range deps {
+		indent := strings.Repeat("  ", dep.Depth)
+		if dep.Missing {
+			waiting++
+			sb.WriteString(fmt.Sprintf("%s%s %s (not found)\n", indent, statusIcon("failed"), dep.ID))
+			continue
+		}
+		if !dep.Satisfied {
+			waiting++
+		}
+		sb.WriteString(fmt.Sprintf("%s%s %s %s (%s)\n", indent, statusIcon(dep.Status), dep.ID, dep.Title, dep.Status))
+	}
+
+	sb.WriteString("\n")
+	if waiting == 0 {
+		sb.WriteString("All dependencies are done.")
+	} else {
+		sb.WriteString(fmt.Sprintf("Waiting on %d of %d dependencies.", waiting, len(deps)))
+	}
+	return sb.String()
+}
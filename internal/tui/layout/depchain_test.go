@@ -0,0 +1,47 @@
+package layout
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatDependencyChain(t *testing.T) {
+	got := FormatDependencyChain("05 UI", []DependencyLine{
+		{ID: "04", Title: "API", Status: "pending", Depth: 1},
+		{ID: "03", Title: "Auth", Status: "failed", Depth: 2},
+		{ID: "01", Title: "Schema", Status: "completed", Depth: 3, Satisfied: true},
+		{ID: "99", Depth: 1, Missing: true},
+	})
+
+	want := "05 UI\n\n" +
+		"  ○ 04 API (pending)\n" +
+		"    ✗ 03 Auth (failed)\n" +
+		"      ✓ 01 Schema (completed)\n" +
+		"  ✗ 99 (not found)\n\n" +
+		"Waiting on 3 of 4 dependencies."
+	if got != want {
+		t.Errorf("unexpected chain:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFormatDependencyChainNone(t *testing.T) {
+	got := FormatDependencyChain("01 Schema", nil)
+	if !strings.HasSuffix(got, "No dependencies.") {
+		t.Errorf("expected no dependencies message, got %q", got)
+	}
+}
+
+func TestTextModalSetContent(t *testing.T) {
+	h := NewTextModal()
+	h.SetSize(100, 40)
+	h.SetContent("Dependencies", "01 Schema\n\nNo dependencies.")
+	h.Show()
+
+	result := h.Render(strings.Repeat(strings.Repeat("x", 100)+"\n", 40))
+	if !strings.Contains(result, "Dependencies") || !strings.Contains(result, "No dependencies.") {
+		t.Error("expected the modal to render its title and content")
+	}
+	if strings.Contains(result, "Navigation:") {
+		t.Error("expected no help content in a text modal")
+	}
+}
@@ -12,10 +12,15 @@ type FooterData struct {
 	Keybindings string
 	StatusMsg   string
 	StatusColor lipgloss.TerminalColor
+	Unsaved     bool // Progress has changes not yet written to disk
 }
 
+// UnsavedIndicator is shown in the footer while progress has unsaved changes
+const UnsavedIndicator = "● unsaved"
+
 type Footer struct {
-	width int
+	width      int
+	showLegend bool
 }
 
 func NewFooter() *Footer {
@@ -26,7 +31,19 @@ func (f *Footer) SetWidth(width int) {
 	f.width = width
 }
 
+// SetShowLegend toggles the status legend line above the keybindings
+func (f *Footer) SetShowLegend(show bool) {
+	f.showLegend = show
+}
+
+func (f *Footer) ShowLegend() bool {
+	return f.showLegend
+}
+
 func (f *Footer) Height() int {
+	if f.showLegend {
+		return FooterHeight + 1
+	}
 	return FooterHeight
 }
 
@@ -36,14 +53,24 @@ func (f *Footer) Render(data FooterData) string {
 
 	content := keyStyle.Render(data.Keybindings)
 
-	if data.StatusMsg != "" {
+	if data.StatusMsg != "" || data.Unsaved {
 		statusStyle := lipgloss.NewStyle().
 			Foreground(data.StatusColor)
 		status := statusStyle.Render(data.StatusMsg)
+		statusText := data.StatusMsg
+		if data.Unsaved {
+			indicator := lipgloss.NewStyle().Foreground(colorStopped).Render(UnsavedIndicator)
+			if statusText == "" {
+				status, statusText = indicator, UnsavedIndicator
+			} else {
+				status += "  " + indicator
+				statusText += "  " + UnsavedIndicator
+			}
+		}
 
 		contentWidth := f.width - 4
 		keysWidth := lipgloss.Width(data.Keybindings)
-		statusWidth := lipgloss.Width(data.StatusMsg)
+		statusWidth := lipgloss.Width(statusText)
 
 		spaces := contentWidth - keysWidth - statusWidth
 		if spaces < 1 {
@@ -53,6 +80,10 @@ func (f *Footer) Render(data FooterData) string {
 		}
 	}
 
+	if f.showLegend {
+		content = StatusLegend() + "\n" + content
+	}
+
 	boxStyle := lipgloss.NewStyle().
 		Width(f.width).
 		Border(lipgloss.NormalBorder(), true, false, false, false).
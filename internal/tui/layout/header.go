@@ -10,20 +10,22 @@ import (
 const HeaderHeight = 3
 
 type HeaderData struct {
-	Version      string
-	Title        string
-	AutoMode     bool
-	Total        int
-	Completed    int
-	Running      int
-	Failed       int
-	Pending      int
-	TokenUsage   string
-	TotalCost    string
-	ShowCost     bool
-	BudgetStatus string
-	BudgetAlert  bool
-	ElapsedTime  string
+	Version       string
+	Title         string
+	AutoMode      bool
+	Total         int
+	Completed     int
+	Running       int
+	Failed        int
+	Pending       int
+	Skipped       int
+	TokenUsage    string
+	TotalCost     string
+	ShowCost      bool
+	BudgetStatus  string
+	BudgetPercent float64
+	BudgetAlert   bool
+	ElapsedTime   string
 }
 
 type Header struct {
@@ -92,6 +94,9 @@ func (h *Header) buildSummary(data HeaderData) string {
 	if data.Pending > 0 {
 		parts = append(parts, fmt.Sprintf("%d pending", data.Pending))
 	}
+	if data.Skipped > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped", data.Skipped))
+	}
 
 	summary := "[" + strings.Join(parts, ", ") + "]"
 
@@ -101,6 +106,7 @@ func (h *Header) buildSummary(data HeaderData) string {
 		if data.BudgetAlert {
 			budgetStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Bold(true)
 		}
+		summary += " " + RenderBudgetBar(data.BudgetPercent, BudgetBarWidth)
 		summary += " " + budgetStyle.Render(data.BudgetStatus)
 	} else if data.ShowCost && data.TotalCost != "" {
 		summary += " " + data.TotalCost
@@ -143,6 +143,7 @@ func TestBuildSummary(t *testing.T) {
 		Running:    2,
 		Failed:     1,
 		Pending:    2,
+		Skipped:    1,
 		TokenUsage: "1.5k↓ 500↑",
 	}
 
@@ -160,6 +161,9 @@ func TestBuildSummary(t *testing.T) {
 	if !strings.Contains(summary, "2 pending") {
 		t.Error("should contain pending count")
 	}
+	if !strings.Contains(summary, "1 skipped") {
+		t.Error("should contain skipped count")
+	}
 	if !strings.Contains(summary, "1.5k↓ 500↑") {
 		t.Error("should contain token usage")
 	}
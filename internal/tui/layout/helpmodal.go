@@ -10,13 +10,16 @@ const (
 	HelpModalMinWidth  = 60
 	HelpModalMinHeight = 20
 	HelpModalMaxWidth  = 80
-	HelpModalMaxHeight = 80 // Increased to fit expanded help content with model escalation section
+	HelpModalMaxHeight = 90 // Increased to fit expanded help content with model escalation section
 )
 
 var helpContent = `Navigation:
   j/k or ↑/↓    Move selection up/down
   Enter         Inspect selected feature's output
   Space         Toggle expand/collapse (features with children)
+  z/Z           Collapse/expand all features
+  n/N           Jump to next/previous failed or blocked feature
+  Tab           Switch between tasks and activity (narrow terminals)
 
 Actions:
   s             Start selected feature
@@ -24,11 +27,18 @@ Actions:
   r             Retry failed/completed feature
   R             Reset feature (clear attempts)
   x             Stop selected feature
+  Ctrl+x        Stop and reset selected feature to pending
   X             Stop ALL features (exit auto mode)
+  m             Cycle pending feature's model (haiku/sonnet/opus/auto)
+  e             Edit feature notes in $EDITOR (PRD/ directory only)
+  i             Show dependency chain with statuses (PRD/ only)
   Ctrl+r        Reset ALL features (start fresh)
 
 Display:
   c             Toggle cost display (shows $ instead of tokens)
+  f             Filter activity to selected feature (toggle)
+  l             Toggle status legend (✓ ⚠ ● ✗ ○ ◌ ⊘ ⊖)
+  o             Cycle sort: PRD order, status, cost, duration
   a             Toggle action timeline (in inspect view)
 
 Tree View:
@@ -37,15 +47,18 @@ Tree View:
   │   └── ○  Child Feature
   ▶ ○  Collapsed Feature (2/3 done)
 
-  Use Space to toggle expand/collapse.
+  Use Space to toggle expand/collapse, z/Z for all.
   Collapsed parents show aggregated child status.
 
 Inspect View:
-  j/k or ↑/↓    Scroll output
+  j/k or ↑/↓    Scroll output (up pauses auto-scroll, down to the
+                end resumes it)
   g             Go to top (pauses auto-scroll)
   G             Go to end (enables auto-scroll)
   f             Follow output (enables auto-scroll)
   a             Toggle action timeline
+  F             Toggle full, unclipped output lines
+  1-9           Check a task off, or back on, so retries skip it
   s             Start feature
   x             Stop feature
   q/Esc         Close inspect view
@@ -58,7 +71,8 @@ Auto Mode:
   When started with 'S', ralph will:
   - Start features in order
   - Run up to 3 features in parallel
-  - Auto-retry failed features (up to 3 attempts)
+  - Auto-retry failed features (up to 3 attempts) after about 5s,
+    spread at random so they don't all retry at once
   - Stop when all complete or max retries exceeded
 
 Cost Estimation:
@@ -86,7 +100,11 @@ Model Escalation:
   - [H] = Haiku, [S] = Sonnet, [O] = Opus
   - Orange = model was changed during execution`
 
+// HelpModal is a scrollable text box drawn over the dimmed screen: the key
+// help, or any other text given to NewTextModal
 type HelpModal struct {
+	title        string
+	content      string
 	width        int
 	height       int
 	modalWidth   int
@@ -96,9 +114,22 @@ type HelpModal struct {
 }
 
 func NewHelpModal() *HelpModal {
+	return &HelpModal{title: "Help", content: helpContent}
+}
+
+// NewTextModal returns an empty modal; SetContent fills it before Show
+func NewTextModal() *HelpModal {
 	return &HelpModal{}
 }
 
+// SetContent replaces the modal's title and text and resizes it to fit
+func (h *HelpModal) SetContent(title, content string) {
+	h.title = title
+	h.content = content
+	h.scrollOffset = 0
+	h.calculateModalSize()
+}
+
 func (h *HelpModal) SetSize(width, height int) {
 	h.width = width
 	h.height = height
@@ -106,7 +137,7 @@ func (h *HelpModal) SetSize(width, height int) {
 }
 
 func (h *HelpModal) calculateModalSize() {
-	contentLines := strings.Split(helpContent, "\n")
+	contentLines := strings.Split(h.content, "\n")
 	contentHeight := len(contentLines)
 
 	h.modalHeight = contentHeight + 4
@@ -188,7 +219,7 @@ func (h *HelpModal) ScrollToTop() {
 }
 
 func (h *HelpModal) ScrollToBottom() {
-	contentLines := strings.Split(helpContent, "\n")
+	contentLines := strings.Split(h.content, "\n")
 	maxOffset := len(contentLines) - h.ContentHeight()
 	if maxOffset < 0 {
 		maxOffset = 0
@@ -225,7 +256,7 @@ func (h *HelpModal) renderModalBox() string {
 		Width(h.modalWidth-2).
 		Padding(0, 1)
 
-	titleBar := titleBarStyle.Render("Help")
+	titleBar := titleBarStyle.Render(h.title)
 
 	contentLines := h.renderContent()
 
@@ -245,7 +276,7 @@ func (h *HelpModal) renderContent() string {
 	contentWidth := h.ContentWidth()
 	contentHeight := h.ContentHeight()
 
-	lines := strings.Split(helpContent, "\n")
+	lines := strings.Split(h.content, "\n")
 	totalLines := len(lines)
 
 	scrollOffset := h.scrollOffset
@@ -326,6 +357,6 @@ func (h *HelpModal) overlayModal(background, modal string) string {
 }
 
 func (h *HelpModal) NeedsScrolling() bool {
-	lines := strings.Split(helpContent, "\n")
+	lines := strings.Split(h.content, "\n")
 	return len(lines) > h.ContentHeight()
 }
@@ -119,7 +119,7 @@ func TestHelpModal_Toggle(t *testing.T) {
 
 func TestHelpModal_ScrollingWhenNotNeeded(t *testing.T) {
 	h := NewHelpModal()
-	h.SetSize(100, 85) // Larger terminal to fit expanded help content with model escalation section
+	h.SetSize(100, 100) // Larger terminal to fit expanded help content with model escalation section
 	h.Show()
 
 	if h.NeedsScrolling() {
@@ -333,7 +333,7 @@ func TestHelpModal_NeedsScrollingLogic(t *testing.T) {
 	}{
 		{
 			name:           "large terminal - no scrolling",
-			height:         80, // Larger terminal to fit expanded help content
+			height:         100, // Larger terminal to fit expanded help content
 			expectedScroll: false,
 		},
 		{
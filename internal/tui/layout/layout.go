@@ -30,6 +30,14 @@ func (l *Layout) ContentWidth() int {
 	return l.container.ContentWidth()
 }
 
+func (l *Layout) SetShowLegend(show bool) {
+	l.container.Footer().SetShowLegend(show)
+}
+
+func (l *Layout) ShowLegend() bool {
+	return l.container.Footer().ShowLegend()
+}
+
 func (l *Layout) SetPRDTitle(title string) {
 	l.container.TitleBar().SetTitle(title)
 }
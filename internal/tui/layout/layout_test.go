@@ -121,6 +121,53 @@ func TestFooterWithStatus(t *testing.T) {
 	}
 }
 
+func TestStatusLegendCoversAllStatuses(t *testing.T) {
+	legend := StatusLegend()
+
+	for _, status := range []string{"completed", "running", "failed", "pending", "blocked", "skipped"} {
+		entry := statusIcon(status) + " " + status
+		if !strings.Contains(legend, entry) {
+			t.Errorf("Legend should contain %q, got %q", entry, legend)
+		}
+	}
+	if !strings.Contains(legend, statusIcon("completed_with_warnings")+" warnings") {
+		t.Errorf("Legend should show completed_with_warnings as warnings, got %q", legend)
+	}
+	if strings.Contains(legend, "\n") {
+		t.Error("Legend should fit on a single line")
+	}
+}
+
+func TestStatusIconsAreDistinct(t *testing.T) {
+	seen := make(map[string]string)
+	for _, status := range legendStatuses {
+		icon := statusIcon(status)
+		if other, ok := seen[icon]; ok {
+			t.Errorf("Statuses %q and %q share icon %q", other, status, icon)
+		}
+		seen[icon] = status
+	}
+}
+
+func TestFooterLegendToggle(t *testing.T) {
+	f := NewFooter()
+	f.SetWidth(120)
+	data := FooterData{Keybindings: "q: quit"}
+
+	if strings.Contains(f.Render(data), "completed") {
+		t.Error("Legend should be hidden by default")
+	}
+
+	f.SetShowLegend(true)
+	if f.Height() != FooterHeight+1 {
+		t.Errorf("Expected footer height %d with legend, got %d", FooterHeight+1, f.Height())
+	}
+	result := f.Render(data)
+	if !strings.Contains(result, "completed") || !strings.Contains(result, "q: quit") {
+		t.Error("Footer should contain both the legend and keybindings")
+	}
+}
+
 func TestContainerSetSize(t *testing.T) {
 	c := NewContainer()
 	c.SetSize(100, 40)
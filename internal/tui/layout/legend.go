@@ -0,0 +1,30 @@
+package layout
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// legendStatuses are the statuses explained by the legend, in display order
+var legendStatuses = []string{"completed", "completed_with_warnings", "running", "failed", "pending", "blocked", "skipped", "disabled"}
+
+// legendLabels shortens status names that are too long for the legend
+var legendLabels = map[string]string{
+	"completed_with_warnings": "warnings",
+}
+
+// StatusLegend returns a single line mapping each status icon and color to
+// its status name
+func StatusLegend() string {
+	parts := make([]string, 0, len(legendStatuses))
+	for _, status := range legendStatuses {
+		style := lipgloss.NewStyle().Foreground(StatusColor(status))
+		label := status
+		if short, ok := legendLabels[status]; ok {
+			label = short
+		}
+		parts = append(parts, style.Render(statusIcon(status)+" "+label))
+	}
+	return strings.Join(parts, "  ")
+}
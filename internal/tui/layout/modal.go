@@ -1,6 +1,7 @@
 package layout
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -28,13 +29,21 @@ type Modal struct {
 	testSummary       string
 	usageSummary      string
 	adjustmentSummary string
+	skipReason        string
+	notes             string
+	criteria          []string
+	tasks             []ModalTask
+	errorHistory      []string
+	modelSwitches     []string
 	autoScroll        bool
 	showActions       bool
+	showFullContent   bool // Output lines shown unclipped
 	actionTimeline    string
+	maxScrollOffset   int // Largest scroll offset at the last render, -1 before it
 }
 
 func NewModal() *Modal {
-	return &Modal{}
+	return &Modal{maxScrollOffset: -1}
 }
 
 func (m *Modal) SetSize(width, height int) {
@@ -91,8 +100,80 @@ func (m *Modal) SetAdjustmentSummary(summary string) {
 	m.adjustmentSummary = summary
 }
 
+// SetSkipReason sets why a skipped feature was skipped; empty hides the line
+func (m *Modal) SetSkipReason(reason string) {
+	m.skipReason = reason
+}
+
+// SetNotes sets the feature's reviewer notes; empty hides them
+func (m *Modal) SetNotes(notes string) {
+	m.notes = notes
+}
+
+// noteLines returns the notes split into lines, or nil if there are none
+func (m *Modal) noteLines() []string {
+	if m.notes == "" {
+		return nil
+	}
+	return strings.Split(m.notes, "\n")
+}
+
+// SetAcceptanceCriteria sets the feature's acceptance criteria, shown as a
+// checklist; empty hides the section
+func (m *Modal) SetAcceptanceCriteria(criteria []string) {
+	m.criteria = criteria
+}
+
+// ModalTask is one of the feature's tasks, shown with whether it is done
+type ModalTask struct {
+	Description string
+	Completed   bool
+}
+
+// MaxModalTasks is how many tasks the modal lists, one per key 1-9
+const MaxModalTasks = 9
+
+// SetTasks sets the feature's tasks, shown numbered so they can be checked
+// off by number; empty hides the section
+func (m *Modal) SetTasks(tasks []ModalTask) {
+	m.tasks = tasks
+}
+
+// taskLineCount returns how many lines the task section takes, without its
+// heading and trailing blank line
+func (m *Modal) taskLineCount() int {
+	if len(m.tasks) > MaxModalTasks {
+		return MaxModalTasks + 1
+	}
+	return len(m.tasks)
+}
+
+// SetErrorHistory sets one entry per failed attempt, oldest first. Only the
+// first line of each entry is shown.
+func (m *Modal) SetErrorHistory(entries []string) {
+	m.errorHistory = entries
+}
+
+// SetModelSwitches sets the lines explaining each auto model switch; empty
+// hides the section
+func (m *Modal) SetModelSwitches(lines []string) {
+	m.modelSwitches = lines
+}
+
 func (m *Modal) ContentHeight() int {
 	h := m.modalHeight - ModalBorderSize - ModalTitleHeight - (ModalPadding * 2)
+	if m.skipReason != "" {
+		h -= 2
+	}
+	if notes := m.noteLines(); len(notes) > 0 {
+		h -= len(notes) + 2
+	}
+	if len(m.criteria) > 0 {
+		h -= len(m.criteria) + 2
+	}
+	if len(m.tasks) > 0 {
+		h -= m.taskLineCount() + 2
+	}
 	if m.testSummary != "" {
 		h -= 2
 	}
@@ -102,6 +183,12 @@ func (m *Modal) ContentHeight() int {
 	if m.adjustmentSummary != "" {
 		h -= 2
 	}
+	if len(m.errorHistory) > 0 {
+		h -= len(m.errorHistory) + 2
+	}
+	if len(m.modelSwitches) > 0 {
+		h -= len(m.modelSwitches) + 2
+	}
 	if h < 1 {
 		return 1
 	}
@@ -127,10 +214,17 @@ func (m *Modal) SetScrollOffset(offset int) {
 	}
 }
 
+// SetAutoScroll pins the output to the bottom while enabled
 func (m *Modal) SetAutoScroll(enabled bool) {
 	m.autoScroll = enabled
 }
 
+// MaxScrollOffset returns the largest scroll offset at the last render, or
+// -1 if the modal hasn't been rendered yet
+func (m *Modal) MaxScrollOffset() int {
+	return m.maxScrollOffset
+}
+
 func (m *Modal) ScrollDown() {
 	m.scrollOffset++
 }
@@ -167,8 +261,20 @@ func (m *Modal) ShowingActions() bool {
 	return m.showActions
 }
 
+// ToggleFullContent switches the output between clipped lines and their
+// full content
+func (m *Modal) ToggleFullContent() bool {
+	m.showFullContent = !m.showFullContent
+	return m.showFullContent
+}
+
+func (m *Modal) ShowingFullContent() bool {
+	return m.showFullContent
+}
+
 func (m *Modal) ResetView() {
 	m.showActions = false
+	m.showFullContent = false
 	m.scrollOffset = 0
 }
 
@@ -223,6 +329,9 @@ func (m *Modal) renderModalBox() string {
 	} else {
 		titleText += " " + scrollIndicatorStyle.Render("[paused]")
 	}
+	if m.showFullContent && !m.showActions {
+		titleText += " " + viewModeStyle.Render("[FULL]")
+	}
 	titleBar := titleBarStyle.Render(titleText)
 
 	contentLines := m.renderContent()
@@ -249,10 +358,49 @@ func (m *Modal) renderContent() string {
 		if m.actionTimeline == "" {
 			lines = append(lines, "No actions recorded yet.")
 		} else {
-			actionLines := strings.Split(m.actionTimeline, "\n")
-			lines = append(lines, actionLines...)
+			for _, line := range strings.Split(m.actionTimeline, "\n") {
+				lines = append(lines, wrapLine(line, contentWidth)...)
+			}
 		}
 	} else {
+		if m.skipReason != "" {
+			skipStyle := lipgloss.NewStyle().Foreground(StatusColor("skipped"))
+			lines = append(lines, skipStyle.Render(truncateLine("Skipped: "+m.skipReason, contentWidth)))
+			lines = append(lines, "")
+		}
+		if notes := m.noteLines(); len(notes) > 0 {
+			notesStyle := lipgloss.NewStyle().Foreground(colorHighlight)
+			lines = append(lines, notesStyle.Render("Notes:"))
+			for _, note := range notes {
+				lines = append(lines, notesStyle.Render("  "+truncateLine(note, contentWidth-2)))
+			}
+			lines = append(lines, "")
+		}
+		if len(m.criteria) > 0 {
+			criteriaStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("114"))
+			lines = append(lines, criteriaStyle.Render("Acceptance criteria:"))
+			for _, criterion := range m.criteria {
+				lines = append(lines, criteriaStyle.Render("  ☐ "+truncateLine(criterion, contentWidth-4)))
+			}
+			lines = append(lines, "")
+		}
+		if len(m.tasks) > 0 {
+			taskStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("252"))
+			doneStyle := lipgloss.NewStyle().Foreground(StatusColor("completed"))
+			lines = append(lines, taskStyle.Render("Tasks (1-9 to check off):"))
+			for i, task := range m.tasks {
+				if i == MaxModalTasks {
+					lines = append(lines, taskStyle.Render(fmt.Sprintf("  … %d more", len(m.tasks)-MaxModalTasks)))
+					break
+				}
+				if task.Completed {
+					lines = append(lines, doneStyle.Render(fmt.Sprintf("  %d [x] ", i+1)+truncateLine(task.Description, contentWidth-8)))
+				} else {
+					lines = append(lines, taskStyle.Render(fmt.Sprintf("  %d [ ] ", i+1)+truncateLine(task.Description, contentWidth-8)))
+				}
+			}
+			lines = append(lines, "")
+		}
 		if m.testSummary != "" {
 			lines = append(lines, m.testSummary)
 			lines = append(lines, "")
@@ -266,17 +414,39 @@ func (m *Modal) renderContent() string {
 			lines = append(lines, adjStyle.Render("Adjustments: "+m.adjustmentSummary))
 			lines = append(lines, "")
 		}
-		contentLines := strings.Split(m.content, "\n")
-		lines = append(lines, contentLines...)
+		if len(m.errorHistory) > 0 {
+			errStyle := lipgloss.NewStyle().Foreground(StatusColor("failed"))
+			lines = append(lines, errStyle.Render("Error history:"))
+			for _, entry := range m.errorHistory {
+				if i := strings.IndexByte(entry, '\n'); i >= 0 {
+					entry = entry[:i]
+				}
+				lines = append(lines, errStyle.Render("  "+truncateLine(entry, contentWidth-2)))
+			}
+			lines = append(lines, "")
+		}
+		if len(m.modelSwitches) > 0 {
+			switchStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+			lines = append(lines, switchStyle.Render("Model switches:"))
+			for _, line := range m.modelSwitches {
+				lines = append(lines, switchStyle.Render("  "+truncateLine(line, contentWidth-2)))
+			}
+			lines = append(lines, "")
+		}
+		for _, line := range strings.Split(m.content, "\n") {
+			lines = append(lines, wrapLine(line, contentWidth)...)
+		}
 	}
 
 	totalLines := len(lines)
+	maxOffset := totalLines - contentHeight
+	if maxOffset < 0 {
+		maxOffset = 0
+	}
+	m.maxScrollOffset = maxOffset
 	scrollOffset := m.scrollOffset
-	if scrollOffset > totalLines-contentHeight {
-		scrollOffset = totalLines - contentHeight
-		if scrollOffset < 0 {
-			scrollOffset = 0
-		}
+	if scrollOffset > maxOffset || (m.autoScroll && !m.showActions) {
+		scrollOffset = maxOffset
 	}
 
 	endIdx := scrollOffset + contentHeight
@@ -304,6 +474,38 @@ func (m *Modal) renderContent() string {
 	return strings.Join(rendered, "\n")
 }
 
+// wrapLine splits a plain-text line into lines of at most width runes,
+// breaking after the last space that fits and falling back to a hard break
+// inside words longer than width, such as JSON blobs
+func wrapLine(line string, width int) []string {
+	runes := []rune(line)
+	if width < 1 || len(runes) <= width {
+		return []string{line}
+	}
+
+	var wrapped []string
+	for len(runes) > width {
+		brk := -1
+		for i := width; i > 0; i-- {
+			if runes[i] == ' ' {
+				brk = i
+				break
+			}
+		}
+		if brk > 0 {
+			wrapped = append(wrapped, string(runes[:brk]))
+			runes = runes[brk+1:]
+		} else {
+			wrapped = append(wrapped, string(runes[:width]))
+			runes = runes[width:]
+		}
+	}
+	if len(runes) > 0 {
+		wrapped = append(wrapped, string(runes))
+	}
+	return wrapped
+}
+
 func (m *Modal) overlayModal(background, modal string) string {
 	bgLines := strings.Split(background, "\n")
 	modalLines := strings.Split(modal, "\n")
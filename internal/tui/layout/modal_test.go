@@ -125,6 +125,54 @@ func TestModalContentHeightWithTestSummary(t *testing.T) {
 	}
 }
 
+func TestModalAcceptanceCriteria(t *testing.T) {
+	m := NewModal()
+	m.SetSize(100, 50)
+	heightWithout := m.ContentHeight()
+
+	m.SetAcceptanceCriteria([]string{"Login returns a session cookie", "Logout clears it"})
+	if got := m.ContentHeight(); got != heightWithout-4 {
+		t.Errorf("expected the criteria to take 4 lines, got height %d (was %d)", got, heightWithout)
+	}
+
+	content := m.renderContent()
+	for _, want := range []string{"Acceptance criteria:", "☐ Login returns a session cookie", "☐ Logout clears it"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected %q in the content", want)
+		}
+	}
+}
+
+func TestModalTasks(t *testing.T) {
+	m := NewModal()
+	m.SetSize(100, 50)
+	heightWithout := m.ContentHeight()
+
+	m.SetTasks([]ModalTask{{Description: "Add login form"}, {Description: "Store sessions", Completed: true}})
+	if got := m.ContentHeight(); got != heightWithout-4 {
+		t.Errorf("expected the tasks to take 4 lines, got height %d (was %d)", got, heightWithout)
+	}
+
+	content := m.renderContent()
+	for _, want := range []string{"Tasks (1-9 to check off):", "1 [ ] Add login form", "2 [x] Store sessions"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("expected %q in the content", want)
+		}
+	}
+
+	tasks := make([]ModalTask, MaxModalTasks+3)
+	for i := range tasks {
+		tasks[i] = ModalTask{Description: "Task"}
+	}
+	m.SetTasks(tasks)
+	if got := m.ContentHeight(); got != heightWithout-(MaxModalTasks+3) {
+		t.Errorf("expected %d task lines plus a summary, got height %d (was %d)", MaxModalTasks, got, heightWithout)
+	}
+	if !strings.Contains(m.renderContent(), "… 3 more") {
+		t.Error("expected the tasks past the ninth to be summarised")
+	}
+}
+
 func TestModalContentWidth(t *testing.T) {
 	m := NewModal()
 	m.SetSize(100, 50)
@@ -500,3 +548,97 @@ func TestModalRenderWithUsageSummary(t *testing.T) {
 		t.Error("rendered output should contain the usage summary")
 	}
 }
+
+func TestModalErrorHistory(t *testing.T) {
+	m := NewModal()
+	m.SetSize(100, 40)
+	m.SetContent("output")
+	base := m.ContentHeight()
+
+	m.SetErrorHistory([]string{"Attempt 1: tests failed", "Attempt 2: build failed\nmore detail"})
+	if m.ContentHeight() != base-4 {
+		t.Errorf("expected content height %d with two errors, got %d", base-4, m.ContentHeight())
+	}
+
+	result := m.Render("")
+	for _, want := range []string{"Error history:", "Attempt 1: tests failed", "Attempt 2: build failed"} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected modal to contain %q", want)
+		}
+	}
+	if strings.Contains(result, "more detail") {
+		t.Error("expected only the first line of each error")
+	}
+}
+
+func TestModalSkipReason(t *testing.T) {
+	m := NewModal()
+	m.SetSize(100, 40)
+	m.SetContent("output")
+	base := m.ContentHeight()
+
+	m.SetSkipReason("parent 01 failed")
+	if m.ContentHeight() != base-2 {
+		t.Errorf("expected content height %d with a skip reason, got %d", base-2, m.ContentHeight())
+	}
+	if result := m.Render(""); !strings.Contains(result, "Skipped: parent 01 failed") {
+		t.Error("expected modal to show the skip reason")
+	}
+
+	m.SetSkipReason("")
+	if strings.Contains(m.Render(""), "Skipped:") {
+		t.Error("expected no skip line once the reason is cleared")
+	}
+}
+
+func TestWrapLine(t *testing.T) {
+	blob := `{"type":"tool_result","result":"` + strings.Repeat("x", 40) + `"}`
+
+	tests := []struct {
+		name  string
+		line  string
+		width int
+		want  []string
+	}{
+		{"fits", "short line", 20, []string{"short line"}},
+		{"words", "the quick brown fox jumps", 10, []string{"the quick", "brown fox", "jumps"}},
+		{"long word", strings.Repeat("a", 25), 10, []string{strings.Repeat("a", 10), strings.Repeat("a", 10), strings.Repeat("a", 5)}},
+		{"words then long word", "see " + strings.Repeat("b", 12), 8, []string{"see", "bbbbbbbb", "bbbb"}},
+		{"zero width", "unchanged", 0, []string{"unchanged"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wrapLine(tt.line, tt.width)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("wrapLine(%q, %d) = %q, want %q", tt.line, tt.width, got, tt.want)
+			}
+		})
+	}
+
+	for _, width := range []int{10, 25, 40, 80} {
+		got := wrapLine(blob, width)
+		for _, line := range got {
+			if len([]rune(line)) > width {
+				t.Errorf("width %d: line %q is too long", width, line)
+			}
+		}
+		if strings.Join(got, "") != blob {
+			t.Errorf("width %d: wrapping lost characters of the blob", width)
+		}
+	}
+}
+
+func TestModalWrapsLongContentLines(t *testing.T) {
+	m := NewModal()
+	m.SetSize(60, 40)
+	width := m.ContentWidth()
+	m.SetContent(strings.Repeat("z", width*2+5))
+
+	content := m.renderContent()
+	if strings.Contains(content, "...") {
+		t.Error("expected long line to wrap rather than be truncated")
+	}
+	if got := strings.Count(content, "z"); got != width*2+5 {
+		t.Errorf("expected every character to be shown, got %d of %d", got, width*2+5)
+	}
+}
@@ -10,6 +10,9 @@ const (
 	PaneHeaderHeight = 1
 	DividerWidth     = 1
 	MinPaneWidth     = 10
+	// SinglePaneWidth is the width below which the split pane shows only
+	// the focused pane, across the full width
+	SinglePaneWidth = 60
 )
 
 type PaneFocus int
@@ -52,6 +55,22 @@ func (s *SplitPane) Focus() PaneFocus {
 	return s.focus
 }
 
+// ToggleFocus moves focus to the other pane, which in single-pane mode also
+// switches the pane shown
+func (s *SplitPane) ToggleFocus() {
+	if s.focus == FocusLeft {
+		s.focus = FocusRight
+	} else {
+		s.focus = FocusLeft
+	}
+}
+
+// IsSinglePane reports whether the split pane is too narrow for both panes
+// and shows only the focused one
+func (s *SplitPane) IsSinglePane() bool {
+	return s.width < SinglePaneWidth
+}
+
 func (s *SplitPane) Width() int {
 	return s.width
 }
@@ -61,6 +80,9 @@ func (s *SplitPane) Height() int {
 }
 
 func (s *SplitPane) LeftPaneWidth() int {
+	if s.IsSinglePane() {
+		return s.width
+	}
 	availableWidth := s.width - DividerWidth
 	if availableWidth < MinPaneWidth*2 {
 		return s.width / 2
@@ -69,6 +91,9 @@ func (s *SplitPane) LeftPaneWidth() int {
 }
 
 func (s *SplitPane) RightPaneWidth() int {
+	if s.IsSinglePane() {
+		return s.width
+	}
 	availableWidth := s.width - DividerWidth
 	leftWidth := s.LeftPaneWidth()
 	remaining := availableWidth - leftWidth
@@ -91,6 +116,10 @@ func (s *SplitPane) Render(leftContent, rightContent string) string {
 		return ""
 	}
 
+	if s.IsSinglePane() {
+		return s.renderSingle(leftContent, rightContent)
+	}
+
 	leftWidth := s.LeftPaneWidth()
 	rightWidth := s.RightPaneWidth()
 	contentHeight := s.ContentHeight()
@@ -129,6 +158,18 @@ func (s *SplitPane) Render(leftContent, rightContent string) string {
 	return strings.Join(lines, "\n")
 }
 
+// renderSingle renders the focused pane alone, with a hint that tab shows
+// the other one
+func (s *SplitPane) renderSingle(leftContent, rightContent string) string {
+	title, content := s.leftTitle, leftContent
+	if s.focus == FocusRight {
+		title, content = s.rightTitle, rightContent
+	}
+	header := s.renderPaneHeader(title+" (tab ⇄)", s.width, true)
+	body := s.renderPaneContent(content, s.width, s.ContentHeight())
+	return header + "\n" + body
+}
+
 func (s *SplitPane) renderPaneHeader(title string, width int, focused bool) string {
 	var titleColor lipgloss.TerminalColor
 	var underlineColor lipgloss.TerminalColor
@@ -332,3 +332,55 @@ func TestSplitPaneNarrowWidth(t *testing.T) {
 		t.Error("Should render content even with narrow width")
 	}
 }
+
+func TestSplitPaneSinglePaneBreakpoint(t *testing.T) {
+	tests := []struct {
+		width  int
+		single bool
+	}{
+		{30, true},
+		{SinglePaneWidth - 1, true},
+		{SinglePaneWidth, false},
+		{80, false},
+		{200, false},
+	}
+
+	for _, tt := range tests {
+		sp := NewSplitPane()
+		sp.SetSize(tt.width, 10)
+		if got := sp.IsSinglePane(); got != tt.single {
+			t.Errorf("width %d: expected single pane %v, got %v", tt.width, tt.single, got)
+		}
+		if tt.single && (sp.LeftPaneWidth() != tt.width || sp.RightPaneWidth() != tt.width) {
+			t.Errorf("width %d: expected both panes to use the full width, got left=%d right=%d",
+				tt.width, sp.LeftPaneWidth(), sp.RightPaneWidth())
+		}
+	}
+}
+
+func TestSplitPaneSinglePaneToggle(t *testing.T) {
+	sp := NewSplitPane()
+	sp.SetSize(40, 10)
+
+	result := sp.Render("Task 1", "Activity 1")
+	if !strings.Contains(result, "TASKS") || !strings.Contains(result, "Task 1") {
+		t.Error("expected the task list in single-pane mode")
+	}
+	if strings.Contains(result, "ACTIVITY") || strings.Contains(result, "Activity 1") {
+		t.Error("expected the activity pane to be hidden in single-pane mode")
+	}
+
+	sp.ToggleFocus()
+	result = sp.Render("Task 1", "Activity 1")
+	if !strings.Contains(result, "ACTIVITY") || !strings.Contains(result, "Activity 1") {
+		t.Error("expected tab to switch to the activity pane")
+	}
+	if strings.Contains(result, "Task 1") {
+		t.Error("expected the task list to be hidden after switching")
+	}
+
+	sp.ToggleFocus()
+	if sp.Focus() != FocusLeft {
+		t.Error("expected a second toggle to return to the task list")
+	}
+}
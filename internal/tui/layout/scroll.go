@@ -0,0 +1,79 @@
+package layout
+
+// DefaultFollowTolerance is how many lines short of the bottom of the inspect
+// view still count as the bottom when scrolling down
+const DefaultFollowTolerance = 2
+
+// ScrollState is the inspect view's scroll position. While Follow is set the
+// view stays pinned to the newest output; scrolling up turns it off, and
+// scrolling back down to within Tolerance lines of the bottom turns it on
+// again.
+//
+// maxOffset arguments are the largest offset at the current content length,
+// or negative when the view hasn't been rendered yet and it isn't known.
+type ScrollState struct {
+	Offset    int
+	Follow    bool
+	Tolerance int // 0 = DefaultFollowTolerance, negative = only the bottom itself
+}
+
+func (s *ScrollState) tolerance() int {
+	switch {
+	case s.Tolerance > 0:
+		return s.Tolerance
+	case s.Tolerance < 0:
+		return 0
+	}
+	return DefaultFollowTolerance
+}
+
+// Up scrolls up n lines, leaving follow mode. While following, it scrolls
+// from the bottom rather than from a stale offset, so output that arrived
+// since doesn't keep the view moving.
+func (s *ScrollState) Up(n, maxOffset int) {
+	if maxOffset >= 0 && (s.Follow || s.Offset > maxOffset) {
+		s.Offset = maxOffset
+	}
+	if s.Offset == 0 {
+		return
+	}
+	s.Offset -= n
+	if s.Offset < 0 {
+		s.Offset = 0
+	}
+	s.Follow = false
+}
+
+// Down scrolls down n lines, re-entering follow mode once the view is within
+// the tolerance of the bottom
+func (s *ScrollState) Down(n, maxOffset int) {
+	s.Offset += n
+	if maxOffset < 0 {
+		return
+	}
+	if s.Offset > maxOffset {
+		s.Offset = maxOffset
+	}
+	if maxOffset-s.Offset <= s.tolerance() {
+		s.Follow = true
+	}
+}
+
+// Top scrolls to the first line and stops following
+func (s *ScrollState) Top() {
+	s.Offset = 0
+	s.Follow = false
+}
+
+// Bottom scrolls to the newest output and follows it
+func (s *ScrollState) Bottom(maxOffset int) {
+	if maxOffset >= 0 {
+		s.Offset = maxOffset
+	}
+	s.Follow = true
+}
+
+// Reset returns to the top without following, as when leaving the view
+func (s *ScrollState) Reset() {
+	s.Top()
+}
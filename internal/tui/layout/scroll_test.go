@@ -0,0 +1,124 @@
+package layout
+
+import "testing"
+
+func TestScrollStateUpLeavesFollowFromBottom(t *testing.T) {
+	s := ScrollState{Follow: true}
+
+	// Output grew to 100 lines past the view since the last key press
+	s.Up(1, 100)
+	if s.Follow {
+		t.Error("scrolling up should stop following")
+	}
+	if s.Offset != 99 {
+		t.Errorf("expected to scroll up from the bottom to 99, got %d", s.Offset)
+	}
+
+	// A burst of new output must not move a paused view
+	for _, maxOffset := range []int{120, 150, 400} {
+		s.Down(0, maxOffset)
+		if s.Follow || s.Offset != 99 {
+			t.Fatalf("new output moved the paused view: offset %d, follow %v", s.Offset, s.Follow)
+		}
+	}
+}
+
+func TestScrollStateUpAtTopKeepsFollowing(t *testing.T) {
+	s := ScrollState{Follow: true}
+	s.Up(1, 0)
+	if !s.Follow || s.Offset != 0 {
+		t.Errorf("expected output that fits the view to keep following, got offset %d, follow %v", s.Offset, s.Follow)
+	}
+}
+
+func TestScrollStateDownNearBottomResumesFollow(t *testing.T) {
+	s := ScrollState{Offset: 90}
+
+	s.Down(1, 100)
+	if s.Follow {
+		t.Errorf("9 lines from the bottom should not resume following")
+	}
+	for s.Offset < 98 {
+		s.Down(1, 100)
+	}
+	if !s.Follow {
+		t.Errorf("expected follow mode within %d lines of the bottom, offset %d", DefaultFollowTolerance, s.Offset)
+	}
+
+	s.Down(5, 100)
+	if s.Offset != 100 {
+		t.Errorf("expected scrolling past the bottom to stop at 100, got %d", s.Offset)
+	}
+}
+
+func TestScrollStateCustomTolerance(t *testing.T) {
+	s := ScrollState{Offset: 80, Tolerance: 10}
+	s.Down(10, 100)
+	if !s.Follow {
+		t.Error("expected a tolerance of 10 to resume following 10 lines from the bottom")
+	}
+
+	s = ScrollState{Offset: 80, Tolerance: 10}
+	s.Down(9, 100)
+	if s.Follow {
+		t.Error("11 lines from the bottom is outside a tolerance of 10")
+	}
+}
+
+func TestScrollStateNoTolerance(t *testing.T) {
+	s := ScrollState{Offset: 90, Tolerance: -1}
+	s.Down(9, 100)
+	if s.Follow {
+		t.Error("expected no tolerance to keep following off a line short of the bottom")
+	}
+	s.Down(1, 100)
+	if !s.Follow {
+		t.Error("expected following to resume at the bottom itself")
+	}
+}
+
+func TestScrollStateClampsStaleOffset(t *testing.T) {
+	// Paused beyond the end of output that has since been clipped
+	s := ScrollState{Offset: 500}
+	s.Up(1, 40)
+	if s.Offset != 39 {
+		t.Errorf("expected the offset to clamp to the bottom before scrolling, got %d", s.Offset)
+	}
+}
+
+func TestScrollStateTopBottomReset(t *testing.T) {
+	s := ScrollState{Offset: 30}
+	s.Bottom(100)
+	if !s.Follow || s.Offset != 100 {
+		t.Errorf("expected Bottom to follow at 100, got offset %d, follow %v", s.Offset, s.Follow)
+	}
+	s.Top()
+	if s.Follow || s.Offset != 0 {
+		t.Errorf("expected Top to pause at 0, got offset %d, follow %v", s.Offset, s.Follow)
+	}
+	s.Bottom(-1)
+	s.Reset()
+	if s.Follow || s.Offset != 0 {
+		t.Errorf("expected Reset to pause at 0, got offset %d, follow %v", s.Offset, s.Follow)
+	}
+}
+
+func TestModalFollowPinsToBottom(t *testing.T) {
+	m := NewModal()
+	if m.MaxScrollOffset() != -1 {
+		t.Errorf("expected an unknown max offset before rendering, got %d", m.MaxScrollOffset())
+	}
+	m.SetSize(80, 30)
+	lines := make([]string, 100)
+	for i := range lines {
+		lines[i] = "line"
+	}
+	m.SetContent(joinLines(lines))
+	m.SetAutoScroll(true)
+	m.Render("")
+
+	want := 100 - m.ContentHeight()
+	if got := m.MaxScrollOffset(); got != want {
+		t.Errorf("expected max offset %d, got %d", want, got)
+	}
+}
@@ -0,0 +1,105 @@
+package layout
+
+import "sort"
+
+// SortMode controls the order of features in the task list
+type SortMode int
+
+const (
+	SortDefault  SortMode = iota // PRD order
+	SortStatus                   // Status, then PRD order
+	SortCost                     // Status, then most expensive first
+	SortDuration                 // Status, then longest running first
+)
+
+// Next returns the sort mode that follows s, wrapping back to SortDefault
+func (s SortMode) Next() SortMode {
+	return (s + 1) % (SortDuration + 1)
+}
+
+func (s SortMode) String() string {
+	switch s {
+	case SortStatus:
+		return "status"
+	case SortCost:
+		return "status, cost"
+	case SortDuration:
+		return "status, duration"
+	default:
+		return "PRD order"
+	}
+}
+
+// statusSortRank orders statuses for triage: failures first, finished work last
+var statusSortRank = map[string]int{
+	"failed":                  0,
+	"running":                 1,
+	"starting":                1,
+	"stopped":                 2,
+	"interrupted":             2,
+	"pending":                 3,
+	"blocked":                 4,
+	"skipped":                 5,
+	"completed_with_warnings": 6,
+	"completed":               6,
+	"disabled":                7,
+}
+
+func statusRank(status string) int {
+	if rank, ok := statusSortRank[status]; ok {
+		return rank
+	}
+	return len(statusSortRank)
+}
+
+// SortTaskItems returns items ordered by mode. Siblings are sorted among
+// themselves so children stay directly under their parent, and IsLastChild is
+// recomputed for the new order. Ties keep their original order.
+func SortTaskItems(items []TaskItem, mode SortMode) []TaskItem {
+	if mode == SortDefault || len(items) < 2 {
+		return items
+	}
+
+	present := make(map[string]bool, len(items))
+	for _, item := range items {
+		present[item.ID] = true
+	}
+	var roots []TaskItem
+	children := make(map[string][]TaskItem)
+	for _, item := range items {
+		if item.ParentID != "" && present[item.ParentID] {
+			children[item.ParentID] = append(children[item.ParentID], item)
+		} else {
+			roots = append(roots, item)
+		}
+	}
+
+	less := func(a, b TaskItem) bool {
+		if ra, rb := statusRank(a.Status), statusRank(b.Status); ra != rb {
+			return ra < rb
+		}
+		switch mode {
+		case SortCost:
+			return a.CostValue > b.CostValue
+		case SortDuration:
+			return a.Elapsed > b.Elapsed
+		}
+		return false
+	}
+
+	sorted := make([]TaskItem, 0, len(items))
+	var appendSorted func(siblings []TaskItem)
+	appendSorted = func(siblings []TaskItem) {
+		sort.SliceStable(siblings, func(i, j int) bool {
+			return less(siblings[i], siblings[j])
+		})
+		for i, item := range siblings {
+			item.IsLastChild = i == len(siblings)-1
+			sorted = append(sorted, item)
+			appendSorted(children[item.ID])
+		}
+	}
+	appendSorted(roots)
+
+	return sorted
+}
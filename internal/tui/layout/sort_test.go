@@ -0,0 +1,103 @@
+package layout
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func sortTestItems() []TaskItem {
+	return []TaskItem{
+		{ID: "a", Status: "completed", CostValue: 0.50, Elapsed: 5 * time.Minute},
+		{ID: "b", Status: "failed", CostValue: 0.10, Elapsed: 1 * time.Minute, HasChildren: true, Children: []string{"b1", "b2"}},
+		{ID: "b1", ParentID: "b", Depth: 1, Status: "completed", CostValue: 0.05, Elapsed: 30 * time.Second},
+		{ID: "b2", ParentID: "b", Depth: 1, Status: "failed", CostValue: 0.02, Elapsed: 10 * time.Second, IsLastChild: true},
+		{ID: "c", Status: "running", CostValue: 0.30, Elapsed: 2 * time.Minute},
+		{ID: "d", Status: "completed", CostValue: 1.20, Elapsed: 1 * time.Minute},
+		{ID: "e", Status: "pending", IsLastChild: true},
+	}
+}
+
+func sortedIDs(items []TaskItem) []string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	return ids
+}
+
+func TestSortTaskItems(t *testing.T) {
+	tests := []struct {
+		mode SortMode
+		want []string
+	}{
+		{SortDefault, []string{"a", "b", "b1", "b2", "c", "d", "e"}},
+		{SortStatus, []string{"b", "b2", "b1", "c", "e", "a", "d"}},
+		{SortCost, []string{"b", "b2", "b1", "c", "e", "d", "a"}},
+		{SortDuration, []string{"b", "b2", "b1", "c", "e", "a", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			got := SortTaskItems(sortTestItems(), tt.mode)
+			if ids := sortedIDs(got); !reflect.DeepEqual(ids, tt.want) {
+				t.Errorf("SortTaskItems(%s) = %v, want %v", tt.mode, ids, tt.want)
+			}
+
+			for i, item := range got {
+				if item.ParentID == "" {
+					continue
+				}
+				if got[i-1].ID != item.ParentID && got[i-1].ParentID != item.ParentID {
+					t.Errorf("Child %s is not under its parent %s", item.ID, item.ParentID)
+				}
+			}
+		})
+	}
+}
+
+func TestSortTaskItemsRecomputesLastChild(t *testing.T) {
+	got := SortTaskItems(sortTestItems(), SortStatus)
+
+	lastChild := make(map[string]bool)
+	for _, item := range got {
+		lastChild[item.ID] = item.IsLastChild
+	}
+	if lastChild["b2"] || !lastChild["b1"] {
+		t.Errorf("Expected b1 to be the last child after sorting, got b1=%v b2=%v", lastChild["b1"], lastChild["b2"])
+	}
+	if lastChild["e"] || !lastChild["d"] {
+		t.Errorf("Expected d to be the last root after sorting, got d=%v e=%v", lastChild["d"], lastChild["e"])
+	}
+}
+
+func TestSortModeNextWraps(t *testing.T) {
+	mode := SortDefault
+	for i := 0; i < 4; i++ {
+		mode = mode.Next()
+	}
+	if mode != SortDefault {
+		t.Errorf("Expected four cycles to return to SortDefault, got %s", mode)
+	}
+}
+
+func TestTaskListCycleSortKeepsSelection(t *testing.T) {
+	tl := NewTaskList()
+	tl.SetSize(80, 20)
+	tl.SetItems(sortTestItems())
+	tl.SetSelected(0) // "a"
+
+	if mode := tl.CycleSort(); mode != SortStatus {
+		t.Fatalf("Expected SortStatus after one cycle, got %s", mode)
+	}
+	if item := tl.SelectedItem(); item == nil || item.ID != "a" {
+		t.Errorf("Expected 'a' to stay selected after sorting, got %v", item)
+	}
+
+	for tl.SortMode() != SortDefault {
+		tl.CycleSort()
+	}
+	if ids := sortedIDs(tl.visibleItems); !reflect.DeepEqual(ids, sortedIDs(sortTestItems())) {
+		t.Errorf("Expected PRD order to be restored, got %v", ids)
+	}
+}
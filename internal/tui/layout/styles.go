@@ -30,14 +30,16 @@ var (
 
 func StatusColor(status string) lipgloss.TerminalColor {
 	switch status {
-	case "running":
+	case "running", "completed_with_warnings":
 		return colorRunning
 	case "completed":
 		return colorCompleted
 	case "failed":
 		return colorFailed
-	case "stopped":
+	case "stopped", "interrupted":
 		return colorStopped
+	case "skipped", "disabled":
+		return colorDim
 	default:
 		return colorPending
 	}
@@ -3,6 +3,7 @@ package layout
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 )
@@ -13,6 +14,7 @@ type TaskItem struct {
 	Status        string
 	Attempts      int
 	ActionSummary string
+	Progress      string // Approximate task completion while running (e.g. "40%")
 	TokenUsage    string
 	Cost          string
 	BudgetStatus  string
@@ -20,6 +22,12 @@ type TaskItem struct {
 	Model         string // Current model (haiku, sonnet, opus)
 	ModelChanged  bool   // Whether model was escalated/de-escalated
 	ElapsedTime   string // Time taken (running or completed)
+	Idle          string // Warning that a running feature has gone quiet (e.g. "idle 2m")
+
+	// Sort and rollup keys, unformatted
+	CostValue   float64
+	Elapsed     time.Duration
+	ActionCount int
 
 	// Hierarchy fields
 	ParentID     string   // Empty for root features
@@ -36,11 +44,13 @@ type TaskList struct {
 	width        int
 	height       int
 	items        []TaskItem
+	sourceItems  []TaskItem // Items as given, before sorting
 	visibleItems []TaskItem // Filtered list based on expand/collapse
 	selected     int
 	scrollOffset int
 	showCost     bool
 	expandedMap  map[string]bool // Track which items are expanded
+	sortMode     SortMode
 }
 
 func NewTaskList() *TaskList {
@@ -56,7 +66,8 @@ func (t *TaskList) SetSize(width, height int) {
 }
 
 func (t *TaskList) SetItems(items []TaskItem) {
-	t.items = items
+	t.sourceItems = items
+	t.items = SortTaskItems(items, t.sortMode)
 	t.rebuildVisibleItems()
 	t.ensureSelectedVisible()
 }
@@ -158,22 +169,126 @@ func (t *TaskList) IsExpanded(id string) bool {
 	return expanded
 }
 
+// ExpandAll expands every item with children, keeping the selected item selected
 func (t *TaskList) ExpandAll() {
+	t.setAllExpanded(true)
+}
+
+// CollapseAll collapses every item with children. If the selected item becomes
+// hidden, its visible ancestor is selected instead.
+func (t *TaskList) CollapseAll() {
+	t.setAllExpanded(false)
+}
+
+func (t *TaskList) setAllExpanded(expanded bool) {
+	var selectedID string
+	if item := t.SelectedItem(); item != nil {
+		selectedID = item.ID
+	}
+
 	for i := range t.items {
 		if t.items[i].HasChildren {
-			t.expandedMap[t.items[i].ID] = true
+			t.expandedMap[t.items[i].ID] = expanded
 		}
 	}
 	t.rebuildVisibleItems()
+
+	if selectedID != "" {
+		t.selectItemOrAncestor(selectedID)
+	}
 }
 
-func (t *TaskList) CollapseAll() {
-	for i := range t.items {
-		if t.items[i].HasChildren {
-			t.expandedMap[t.items[i].ID] = false
-		}
+// SortMode returns the current sort order of the list
+func (t *TaskList) SortMode() SortMode {
+	return t.sortMode
+}
+
+// CycleSort advances to the next sort mode, keeping the selected item selected
+func (t *TaskList) CycleSort() SortMode {
+	var selectedID string
+	if item := t.SelectedItem(); item != nil {
+		selectedID = item.ID
 	}
+
+	t.sortMode = t.sortMode.Next()
+	t.items = SortTaskItems(t.sourceItems, t.sortMode)
 	t.rebuildVisibleItems()
+
+	if selectedID != "" {
+		t.selectItemOrAncestor(selectedID)
+	}
+	return t.sortMode
+}
+
+// SelectNextWithStatus moves the selection to the next item (or the previous
+// one when forward is false) whose status is one of statuses, wrapping around
+// the list. Collapsed parents are expanded to reveal it. Returns false if no
+// other item matches.
+func (t *TaskList) SelectNextWithStatus(forward bool, statuses ...string) bool {
+	if len(t.items) == 0 {
+		return false
+	}
+	wanted := make(map[string]bool, len(statuses))
+	for _, status := range statuses {
+		wanted[status] = true
+	}
+
+	start := -1
+	if item := t.SelectedItem(); item != nil {
+		for i := range t.items {
+			if t.items[i].ID == item.ID {
+				start = i
+				break
+			}
+		}
+	}
+	if start < 0 && !forward {
+		start = 0
+	}
+
+	// Stepping back by one is stepping forward by n-1 modulo n
+	n := len(t.items)
+	step := 1
+	if !forward {
+		step = n - 1
+	}
+	for i := 1; i <= n; i++ {
+		idx := (start + i*step) % n
+		if idx == start || !wanted[t.items[idx].Status] {
+			continue
+		}
+		for parentID := t.items[idx].ParentID; parentID != ""; {
+			t.expandedMap[parentID] = true
+			parent := t.findItemByID(parentID)
+			if parent == nil {
+				break
+			}
+			parentID = parent.ParentID
+		}
+		t.rebuildVisibleItems()
+		t.selectItemOrAncestor(t.items[idx].ID)
+		return true
+	}
+	return false
+}
+
+// selectItemOrAncestor selects the item with the given ID, or its nearest
+// visible ancestor if it is hidden inside a collapsed parent
+func (t *TaskList) selectItemOrAncestor(id string) {
+	for id != "" {
+		for i, item := range t.visibleItems {
+			if item.ID == id {
+				t.SetSelected(i)
+				return
+			}
+		}
+		item := t.findItemByID(id)
+		if item == nil {
+			break
+		}
+		id = item.ParentID
+	}
+	t.SetSelected(t.selected)
 }
 
 func (t *TaskList) SelectedItem() *TaskItem {
@@ -334,6 +449,9 @@ func (t *TaskList) Render() string {
 	elapsedStyle := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("248"))
 
+	idleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("178"))
+
 	var lines []string
 	for i := startIdx; i < endIdx; i++ {
 		item := t.visibleItems[i]
@@ -362,6 +480,11 @@ func (t *TaskList) Render() string {
 			actionStr = " [" + item.ActionSummary + "]"
 		}
 
+		progressStr := ""
+		if item.Progress != "" {
+			progressStr = " " + item.Progress
+		}
+
 		// Show child summary when collapsed
 		childSummaryStr := ""
 		if item.HasChildren && !item.IsExpanded && item.ChildSummary != "" {
@@ -402,21 +525,31 @@ func (t *TaskList) Render() string {
 			elapsedStr = " " + item.ElapsedTime
 		}
 
+		idleStr := ""
+		if item.Idle != "" {
+			idleStr = " " + item.Idle
+		}
+
 		treePrefixWidth := lipgloss.Width(treePrefix) + lipgloss.Width(expandIndicator)
-		titleMaxLen := maxWidth - 5 - treePrefixWidth - len(attemptStr) - len(actionStr) - lipgloss.Width(childSummaryStr) - len(modelStr) - lipgloss.Width(usageOrCostStr) - len(elapsedStr)
+		titleMaxLen := maxWidth - 5 - treePrefixWidth - len(attemptStr) - len(actionStr) - len(progressStr) - lipgloss.Width(childSummaryStr) - len(modelStr) - lipgloss.Width(usageOrCostStr) - len(elapsedStr) - len(idleStr)
 		displayTitle := t.truncateString(item.Title, titleMaxLen)
+		if item.Status == "disabled" || item.Status == "skipped" {
+			displayTitle = statusStyle(item.Status).Render(displayTitle)
+		}
 
-		line := fmt.Sprintf(" %s%s%s  %s%s%s%s%s%s%s",
+		line := fmt.Sprintf(" %s%s%s  %s%s%s%s%s%s%s%s%s",
 			treeStyle.Render(treePrefix),
 			treeStyle.Render(expandIndicator),
 			statusStyle(item.Status).Render(icon),
 			displayTitle,
 			dimStyle.Render(attemptStr),
 			actionStyle.Render(actionStr),
+			dimStyle.Render(progressStr),
 			childSummaryStyle.Render(childSummaryStr),
 			modelStyleToUse.Render(modelStr),
 			usageOrCostStyle.Render(usageOrCostStr),
-			elapsedStyle.Render(elapsedStr))
+			elapsedStyle.Render(elapsedStr),
+			idleStyle.Render(idleStr))
 
 		if i == t.selected {
 			line = t.padToWidth(line, maxWidth)
@@ -474,7 +607,8 @@ func (t *TaskList) findAncestorAtDepth(item TaskItem, depth int) *TaskItem {
 }
 
 // CalculateChildSummary generates an aggregated status string for a parent feature.
-// Returns a string like "(2/3 done)" showing completed/total child count.
+// Returns a string like "(2/3 done)" showing completed/total child count,
+// followed by the cost and action count of the whole subtree when known.
 func CalculateChildSummary(items []TaskItem, parentID string) string {
 	total := 0
 	completed := 0
@@ -483,7 +617,7 @@ func CalculateChildSummary(items []TaskItem, parentID string) string {
 	for _, item := range items {
 		if item.ParentID == parentID {
 			total++
-			if item.Status == "completed" {
+			if item.Status == "completed" || item.Status == "completed_with_warnings" {
 				completed++
 			} else if item.Status == "running" {
 				running++
@@ -495,10 +629,43 @@ func CalculateChildSummary(items []TaskItem, parentID string) string {
 		return ""
 	}
 
+	parts := []string{fmt.Sprintf("%d/%d done", completed, total)}
 	if running > 0 {
-		return fmt.Sprintf("(%d/%d done, %d running)", completed, total, running)
+		parts = append(parts, fmt.Sprintf("%d running", running))
+	}
+	cost, actions := subtreeTotals(items, parentID)
+	if cost > 0 {
+		parts = append(parts, fmt.Sprintf("$%.2f total", cost))
+	}
+	if actions > 0 {
+		parts = append(parts, fmt.Sprintf("%d actions", actions))
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
+
+// subtreeTotals sums the cost and action count of a feature and all its
+// descendants
+func subtreeTotals(items []TaskItem, rootID string) (cost float64, actions int) {
+	inTree := map[string]bool{rootID: true}
+	// Items are listed parents first, but keep scanning until no new
+	// descendant is found so a sorted list still rolls up fully
+	for changed := true; changed; {
+		changed = false
+		for _, item := range items {
+			if !inTree[item.ID] && inTree[item.ParentID] {
+				inTree[item.ID] = true
+				changed = true
+			}
+		}
+	}
+
+	for _, item := range items {
+		if inTree[item.ID] {
+			cost += item.CostValue
+			actions += item.ActionCount
+		}
 	}
-	return fmt.Sprintf("(%d/%d done)", completed, total)
+	return cost, actions
 }
 
 func (t *TaskList) truncateString(s string, maxLen int) string {
@@ -525,10 +692,18 @@ func statusIcon(status string) string {
 		return "●"
 	case "completed":
 		return "✓"
+	case "completed_with_warnings":
+		return "⚠"
 	case "failed":
 		return "✗"
-	case "stopped":
+	case "stopped", "interrupted":
 		return "■"
+	case "blocked":
+		return "◌"
+	case "skipped":
+		return "⊘"
+	case "disabled":
+		return "⊖"
 	default:
 		return "○"
 	}
@@ -116,6 +116,59 @@ func TestTaskListExpandCollapseAll(t *testing.T) {
 	}
 }
 
+func TestTaskListCollapseExpandAllTransitions(t *testing.T) {
+	tl := NewTaskList()
+	tl.SetSize(80, 20)
+
+	items := []TaskItem{
+		{ID: "01", Title: "Root 1", Status: "pending", Depth: 0, HasChildren: true, Children: []string{"01-01"}, ChildCount: 1},
+		{ID: "01-01", Title: "Child 1.1", Status: "pending", Depth: 1, ParentID: "01", HasChildren: true, Children: []string{"01-01-01"}, ChildCount: 1, IsLastChild: true},
+		{ID: "01-01-01", Title: "Grandchild", Status: "pending", Depth: 2, ParentID: "01-01", IsLastChild: true},
+		{ID: "02", Title: "Root 2", Status: "pending", Depth: 0, HasChildren: true, Children: []string{"02-01"}, ChildCount: 1},
+		{ID: "02-01", Title: "Child 2.1", Status: "pending", Depth: 1, ParentID: "02", IsLastChild: true},
+		{ID: "03", Title: "Leaf", Status: "pending", Depth: 0},
+	}
+	tl.SetItems(items)
+
+	// Select the grandchild, then collapse everything
+	tl.SetSelected(2)
+	tl.CollapseAll()
+
+	if tl.VisibleCount() != 3 {
+		t.Fatalf("Expected 3 visible roots after CollapseAll, got %d", tl.VisibleCount())
+	}
+	for _, id := range []string{"01", "01-01", "02"} {
+		if tl.IsExpanded(id) {
+			t.Errorf("Expected %s to be collapsed", id)
+		}
+	}
+	if item := tl.SelectedItem(); item == nil || item.ID != "01" {
+		t.Errorf("Expected selection to move to visible ancestor 01, got %v", item)
+	}
+
+	// Collapsing again is a no-op
+	tl.CollapseAll()
+	if tl.VisibleCount() != 3 {
+		t.Errorf("Expected CollapseAll to be idempotent, got %d visible", tl.VisibleCount())
+	}
+
+	// Select root 2, then expand everything, including nested parents
+	tl.SetSelected(1)
+	tl.ExpandAll()
+
+	if tl.VisibleCount() != 6 {
+		t.Fatalf("Expected all 6 items visible after ExpandAll, got %d", tl.VisibleCount())
+	}
+	for _, id := range []string{"01", "01-01", "02"} {
+		if !tl.IsExpanded(id) {
+			t.Errorf("Expected %s to be expanded", id)
+		}
+	}
+	if item := tl.SelectedItem(); item == nil || item.ID != "02" {
+		t.Errorf("Expected selection to stay on 02, got %v", item)
+	}
+}
+
 func TestTaskListSelectedItem(t *testing.T) {
 	tl := NewTaskList()
 	tl.SetSize(80, 10)
@@ -242,6 +295,18 @@ func TestCalculateChildSummary(t *testing.T) {
 			parentID: "01",
 			expected: "(1/2 done, 1 running)",
 		},
+		{
+			name: "rolls up subtree cost and actions",
+			items: []TaskItem{
+				{ID: "01", ParentID: "", CostValue: 0.50, ActionCount: 4},
+				{ID: "01-01", ParentID: "01", Status: "completed", CostValue: 0.25, ActionCount: 3},
+				{ID: "01-01-01", ParentID: "01-01", Status: "completed", CostValue: 0.10, ActionCount: 2},
+				{ID: "01-02", ParentID: "01", Status: "running", CostValue: 0.40, ActionCount: 1},
+				{ID: "02", ParentID: "", CostValue: 9.99, ActionCount: 50},
+			},
+			parentID: "01",
+			expected: "(1/2 done, 1 running, $1.25 total, 10 actions)",
+		},
 		{
 			name:     "no children",
 			items:    []TaskItem{{ID: "01", ParentID: ""}},
@@ -260,6 +325,23 @@ func TestCalculateChildSummary(t *testing.T) {
 	}
 }
 
+func TestSubtreeTotalsIgnoresOrder(t *testing.T) {
+	items := []TaskItem{
+		{ID: "01-01-01", ParentID: "01-01", CostValue: 0.10, ActionCount: 2},
+		{ID: "02", ParentID: "", CostValue: 1.00, ActionCount: 5},
+		{ID: "01-01", ParentID: "01", CostValue: 0.20, ActionCount: 3},
+		{ID: "01", ParentID: "", CostValue: 0.30, ActionCount: 1},
+	}
+
+	cost, actions := subtreeTotals(items, "01")
+	if cost < 0.5999 || cost > 0.6001 {
+		t.Errorf("expected the parent's rolled-up cost to be 0.60, got %f", cost)
+	}
+	if actions != 6 {
+		t.Errorf("expected 6 actions, got %d", actions)
+	}
+}
+
 func TestTaskListNavigationWithHierarchy(t *testing.T) {
 	tl := NewTaskList()
 	tl.SetSize(80, 10)
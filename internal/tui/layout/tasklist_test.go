@@ -155,6 +155,7 @@ func TestTaskListRenderStatusIcons(t *testing.T) {
 		{"completed", "✓"},
 		{"failed", "✗"},
 		{"stopped", "■"},
+		{"skipped", "⊘"},
 	}
 
 	for _, tt := range tests {
@@ -271,6 +272,7 @@ func TestStatusIcon(t *testing.T) {
 		{"completed", "✓"},
 		{"failed", "✗"},
 		{"stopped", "■"},
+		{"skipped", "⊘"},
 		{"pending", "○"},
 		{"unknown", "○"},
 		{"", "○"},
@@ -321,6 +323,22 @@ func TestTaskListRenderTokenUsage(t *testing.T) {
 	}
 }
 
+func TestTaskListRenderProgress(t *testing.T) {
+	tl := NewTaskList()
+	tl.SetSize(100, 20)
+
+	items := []TaskItem{
+		{ID: "1", Title: "Feature 1", Status: "running", Progress: "40%"},
+		{ID: "2", Title: "Feature 2", Status: "pending"},
+	}
+	tl.SetItems(items)
+	rendered := tl.Render()
+
+	if !strings.Contains(rendered, "40%") {
+		t.Error("should display progress for running feature")
+	}
+}
+
 func TestTaskListSetShowCost(t *testing.T) {
 	tl := NewTaskList()
 
@@ -403,3 +421,66 @@ func TestTaskListRenderCostWithEmptyCost(t *testing.T) {
 		t.Error("should display cost when present")
 	}
 }
+
+func TestTaskListSelectNextWithStatus(t *testing.T) {
+	tl := NewTaskList()
+	tl.SetSize(80, 20)
+	tl.SetItems([]TaskItem{
+		{ID: "1", Title: "Feature 1", Status: "completed"},
+		{ID: "2", Title: "Feature 2", Status: "failed"},
+		{ID: "3", Title: "Feature 3", Status: "running", HasChildren: true, Children: []string{"3a"}},
+		{ID: "3a", Title: "Child 3a", Status: "blocked", ParentID: "3", Depth: 1, IsLastChild: true},
+		{ID: "4", Title: "Feature 4", Status: "pending"},
+		{ID: "5", Title: "Feature 5", Status: "failed"},
+	})
+	tl.SetSelected(0)
+	tl.CollapseAll()
+
+	wantForward := []string{"2", "3a", "5", "2"}
+	for _, want := range wantForward {
+		if !tl.SelectNextWithStatus(true, "failed", "blocked") {
+			t.Fatalf("expected a match moving forward to %s", want)
+		}
+		if got := tl.SelectedItem().ID; got != want {
+			t.Errorf("expected %s selected, got %s", want, got)
+		}
+	}
+	if !tl.IsExpanded("3") {
+		t.Error("expected collapsed parent to be expanded to reveal the blocked child")
+	}
+
+	wantBackward := []string{"5", "3a", "2"}
+	for _, want := range wantBackward {
+		if !tl.SelectNextWithStatus(false, "failed", "blocked") {
+			t.Fatalf("expected a match moving backward to %s", want)
+		}
+		if got := tl.SelectedItem().ID; got != want {
+			t.Errorf("expected %s selected, got %s", want, got)
+		}
+	}
+
+	// Only failures count when blocked isn't asked for
+	tl.SetSelected(0)
+	if !tl.SelectNextWithStatus(true, "failed") || tl.SelectedItem().ID != "2" {
+		t.Errorf("expected 2 selected, got %s", tl.SelectedItem().ID)
+	}
+	if !tl.SelectNextWithStatus(true, "failed") || tl.SelectedItem().ID != "5" {
+		t.Errorf("expected 5 selected, got %s", tl.SelectedItem().ID)
+	}
+}
+
+func TestTaskListSelectNextWithStatusNoMatch(t *testing.T) {
+	tl := NewTaskList()
+	tl.SetItems([]TaskItem{
+		{ID: "1", Title: "Feature 1", Status: "completed"},
+		{ID: "2", Title: "Feature 2", Status: "failed"},
+	})
+	tl.SetSelected(1)
+
+	if tl.SelectNextWithStatus(true, "failed") {
+		t.Error("expected no other match when the only failure is selected")
+	}
+	if tl.Selected() != 1 {
+		t.Errorf("expected selection unchanged, got %d", tl.Selected())
+	}
+}
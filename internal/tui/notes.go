@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vx/ralph-go/internal/auto"
+	"github.com/vx/ralph-go/internal/logger"
+)
+
+// notesEditedMsg is sent when the editor opened with 'e' exits
+type notesEditedMsg struct {
+	featureID string
+	path      string
+	err       error
+}
+
+// editorProcess returns the command that opens path in the user's editor. It
+// is a variable so tests can run without an editor.
+var editorProcess = func(path string) *exec.Cmd {
+	fields := strings.Fields(auto.EditorCommand())
+	return exec.Command(fields[0], append(fields[1:], path)...)
+}
+
+// editNotes writes a feature's notes to a temporary file and opens it in
+// $EDITOR, suspending the TUI until the editor exits
+func (m *Model) editNotes(id string) tea.Cmd {
+	if !m.manifestMode || m.manifest == nil {
+		m.setStatus("Notes are stored in the manifest; run ralph in a PRD/ directory to edit them")
+		return nil
+	}
+	if m.manifest.GetFeature(id) == nil {
+		return nil
+	}
+
+	f, err := os.CreateTemp("", "ralph-notes-*.md")
+	if err != nil {
+		m.setStatus(fmt.Sprintf("Notes not opened: %v", err))
+		return nil
+	}
+	path := f.Name()
+	_, err = f.WriteString(m.manifest.GetFeatureNotes(id))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		m.setStatus(fmt.Sprintf("Notes not opened: %v", err))
+		return nil
+	}
+
+	return tea.ExecProcess(editorProcess(path), func(err error) tea.Msg {
+		return notesEditedMsg{featureID: id, path: path, err: err}
+	})
+}
+
+// handleNotesEdited saves the notes written in the editor to the manifest
+func (m Model) handleNotesEdited(msg notesEditedMsg) (tea.Model, tea.Cmd) {
+	defer os.Remove(msg.path)
+
+	displayID := msg.featureID
+	if len(displayID) > 8 {
+		displayID = displayID[:8]
+	}
+
+	if msg.err != nil {
+		m.setStatus(fmt.Sprintf("Editor failed, notes unchanged: %v", msg.err))
+		return m, nil
+	}
+	data, err := os.ReadFile(msg.path)
+	if err != nil {
+		m.setStatus(fmt.Sprintf("Notes not saved: %v", err))
+		return m, nil
+	}
+
+	notes := strings.TrimSpace(string(data))
+	if notes == m.manifest.GetFeatureNotes(msg.featureID) {
+		m.setStatus("Notes unchanged")
+		return m, nil
+	}
+	if err := m.manifest.SetFeatureNotes(msg.featureID, notes); err != nil {
+		logger.Error("tui", "Failed to save notes", "featureID", displayID, "error", err)
+		m.setStatus(fmt.Sprintf("Notes not saved: %v", err))
+		return m, nil
+	}
+
+	logger.Info("tui", "Notes updated", "featureID", displayID, "length", len(notes))
+	if notes == "" {
+		m.setStatus(fmt.Sprintf("Notes cleared for %s", displayID))
+	} else {
+		m.setStatus(fmt.Sprintf("Notes saved for %s", displayID))
+	}
+	return m, nil
+}
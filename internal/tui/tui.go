@@ -1,9 +1,11 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -47,12 +49,13 @@ type Model struct {
 	activityPane        *layout.ActivityPane
 	modal               *layout.Modal
 	helpModal           *layout.HelpModal
+	depsModal           *layout.HelpModal // Dependency chain of a feature, opened with i
 	confirmDialog       *layout.ConfirmDialog
+	queuedDialogs       []layout.ConfirmType // Dialogs waiting for the one shown to be answered
 	currentView         view
 	selected            int
 	inspecting          string
-	scrollOffset        int
-	autoScroll          bool
+	scroll              layout.ScrollState
 	showCost            bool
 	width               int
 	height              int
@@ -61,13 +64,27 @@ type Model struct {
 	autoMode            bool
 	statusMsg           string
 	statusExpiry        time.Time
+	saveErr             error
+	staleChecked        bool
 	budgetAlertShown    bool
+	budgetWaiting       string // Feature refused for budget running features reserve, waiting for one to finish
 	pendingFeatureStart *parser.Feature
 	childResults        map[string][]string
+	modelOverrides      map[string]bool   // Features whose model was changed with 'm'
+	retryWaiting        map[string]bool   // Features waiting out their retry delay
+	resumeOnRetry       bool              // 'r' continues the feature's last claude session
+	idleWarning         time.Duration     // See Options.IdleWarning
+	runMeta             map[string]string // See Options.RunMeta
+	idleChecking        bool              // An idleCheckMsg is scheduled
+	attached            bool              // Following a headless run read-only ('ralph attach')
+	attachedOutput      string            // Inspected feature's output log while attached
+	attachedLog         runner.LogOptions // How output logs are read while attached
+	attachedStatus      string            // Headless run's state, shown when no other status is
 	// Manifest mode fields
 	manifestMode bool
 	manifest     *manifest.Manifest
 	prdDir       string
+	specs        map[string]parser.Feature // Each feature's parsed feature.md, see featureSpecs
 }
 
 func initialModel(prdPath string) Model {
@@ -87,20 +104,41 @@ func initialModel(prdPath string) Model {
 		childExecutor: childExec,
 		escalationMgr: escalationMgr,
 		retryStrategy: retryStrat,
-		layout:        layout.New(),
+		layout:        newLayout(),
 		splitPane:     layout.NewSplitPane(),
 		taskList:      layout.NewTaskList(),
 		activityLog:   actLog,
 		activityPane:  layout.NewActivityPane(actLog),
 		modal:         layout.NewModal(),
 		helpModal:     layout.NewHelpModal(),
+		depsModal:     layout.NewTextModal(),
 		confirmDialog: layout.NewConfirmDialog(),
 		currentView:   viewMain,
 		childResults:  make(map[string][]string),
 	}
 }
 
+// newLayout returns the main layout with the status legend shown
+func newLayout() *layout.Layout {
+	l := layout.New()
+	l.SetShowLegend(true)
+	return l
+}
+
+// resizePanes fits the panes to the layout's content area
+func (m Model) resizePanes() {
+	m.splitPane.SetSize(m.layout.ContentWidth(), m.layout.ContentHeight())
+	m.taskList.SetSize(m.splitPane.LeftPaneWidth(), m.splitPane.ContentHeight())
+	m.activityPane.SetSize(m.splitPane.RightPaneWidth(), m.splitPane.ContentHeight())
+}
+
 func (m Model) Init() tea.Cmd {
+	if m.attached {
+		return tea.Batch(
+			loadManifest(m.prdDir),
+			pollAttached(m.prdDir, "", m.attachedLog, 0),
+		)
+	}
 	if m.manifestMode {
 		return tea.Batch(
 			loadManifest(m.prdDir),
@@ -121,11 +159,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		m.layout.SetSize(msg.Width, msg.Height)
-		m.splitPane.SetSize(m.layout.ContentWidth(), m.layout.ContentHeight())
-		m.taskList.SetSize(m.splitPane.LeftPaneWidth(), m.splitPane.ContentHeight())
-		m.activityPane.SetSize(m.splitPane.RightPaneWidth(), m.splitPane.ContentHeight())
+		m.resizePanes()
 		m.modal.SetSize(msg.Width, msg.Height)
 		m.helpModal.SetSize(msg.Width, msg.Height)
+		m.depsModal.SetSize(msg.Width, msg.Height)
 		m.confirmDialog.SetSize(msg.Width, msg.Height)
 		return m, nil
 	case prdLoadedMsg:
@@ -148,6 +185,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.manager.SetGlobalBudget(m.prd.BudgetTokens, m.prd.BudgetUSD)
 			logger.Info("tui", "Global budget set", "tokens", m.prd.BudgetTokens, "usd", m.prd.BudgetUSD)
 		}
+		m.applyClaudeArgs()
+		m.applyRunConfig()
+		m.checkStale()
 		return m, nil
 	case manifestLoadedMsg:
 		if msg.err != nil {
@@ -157,19 +197,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.manifest = msg.manifest
 		m.prd = msg.prd // Synthetic PRD for TUI compatibility
+		m.specs = featureSpecs(m.prd.Features)
+		m.spawnHandler.SetManifest(m.manifest)
 		m.layout.SetPRDTitle(m.prd.Title)
 		m.activityLog.AddPRDLoaded(m.prd.Title)
 		logger.Info("tui", "Manifest loaded", "title", m.prd.Title, "features", len(m.prd.Features))
+		for _, warning := range m.manifest.SchemaWarnings() {
+			logger.Warn("tui", "Manifest warning", "warning", warning)
+			m.activityLog.AddOutput("", "manifest.json: "+warning)
+		}
 		for _, f := range m.prd.Features {
 			if m.state != nil {
 				m.state.InitFeature(f.ID, f.Title)
 			}
 		}
+		m.restoreSpawnedChildren()
 		// Set global budget on manager
 		if m.prd.BudgetTokens > 0 || m.prd.BudgetUSD > 0 {
 			m.manager.SetGlobalBudget(m.prd.BudgetTokens, m.prd.BudgetUSD)
 			logger.Info("tui", "Global budget set", "tokens", m.prd.BudgetTokens, "usd", m.prd.BudgetUSD)
 		}
+		m.applyClaudeArgs()
+		m.applyRunConfig()
+		m.checkStale()
 		return m, nil
 	case stateLoadedMsg:
 		if msg.err != nil {
@@ -181,27 +231,51 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if !m.manifestMode {
 			m.state.SetPath(m.prdPath)
 		}
-		m.manager.SetConfig(runner.Config{
-			MaxRetries:    m.state.Config.MaxRetries,
-			MaxConcurrent: m.state.Config.MaxConcurrent,
-		})
+		m.applyRunConfig()
+		// This run's metadata replaces an earlier run's, even when it has none
+		if !m.attached && (len(m.runMeta) > 0 || len(m.state.GetRunMeta()) > 0) {
+			m.state.ReplaceRunMeta(m.runMeta)
+			m.saveState()
+		}
+		m.checkStale()
+		m.checkInterrupted()
 		return m, nil
 	case instanceStartedMsg:
 		displayID := msg.featureID
 		if len(displayID) > 8 {
 			displayID = displayID[:8]
 		}
+		if msg.err != nil && m.autoMode && errors.Is(msg.err, runner.ErrBudgetReserved) {
+			if m.budgetWaiting != msg.featureID {
+				logger.Info("tui", "Feature waiting for reserved budget", "featureID", displayID, "reason", msg.err)
+			}
+			m.budgetWaiting = msg.featureID
+			title := displayID
+			if feature := m.findFeature(msg.featureID); feature != nil {
+				title = feature.Title
+			}
+			m.setStatus(fmt.Sprintf("%s waits for a running feature to finish: %v", title, msg.err))
+			return m, nil
+		}
 		if msg.err != nil {
 			logger.Error("tui", "Failed to start instance", "featureID", displayID, "error", msg.err)
 			m.setStatus(fmt.Sprintf("Error: %v", msg.err))
 			return m, nil
 		}
+		if m.budgetWaiting == msg.featureID {
+			m.budgetWaiting = ""
+		}
 		logger.Info("tui", "Instance started", "featureID", displayID)
 		feature := m.findFeature(msg.featureID)
 		if feature != nil {
 			m.activityLog.AddFeatureStarted(msg.featureID, feature.Title)
-			m.spawnHandler.RegisterRootFeature(msg.featureID, feature.Title)
+			if rf := m.spawnHandler.RegisterRootFeature(msg.featureID, feature.Title); rf != nil {
+				rf.Group = feature.Group
+			}
 			m.spawnHandler.SetFeatureRunning(msg.featureID)
+			// Kept in progress so the limit holds for children spawned
+			// after a restart, see getParentChildLimit
+			m.state.SetMaxChildrenConcurrent(msg.featureID, feature.MaxChildrenConcurrent)
 		}
 		m.state.UpdateFeature(msg.featureID, "running")
 		// Update manifest status in manifest mode
@@ -212,11 +286,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Track initial model for auto model features
 		if msg.instance != nil && msg.instance.IsAutoModelEnabled() {
 			currentModel := msg.instance.GetCurrentModel()
+			details := initialSelectionDetails
+			if explanation, ok := msg.instance.ExplainModel(); ok {
+				details = explanation.Details()
+			}
 			m.state.SetCurrentModel(msg.featureID, currentModel)
-			m.state.AddModelSwitch(msg.featureID, "", currentModel, "initial", "auto mode initial selection")
+			m.state.AddModelSwitch(msg.featureID, "", currentModel, "initial", details)
 			logger.Info("tui", "Auto model enabled", "featureID", displayID, "model", currentModel)
 		}
-		m.state.Save()
+		m.saveState()
+		if !m.idleChecking && m.idleThreshold() > 0 {
+			m.idleChecking = true
+			return m, tea.Batch(listenForOutput(msg.featureID, msg.instance), scheduleIdleCheck())
+		}
 		return m, listenForOutput(msg.featureID, msg.instance)
 	case modelChangedMsg:
 		displayID := msg.featureID
@@ -227,9 +309,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			"featureID", displayID,
 			"from", msg.fromModel,
 			"to", msg.toModel,
-			"reason", msg.reason)
-		m.state.AddModelSwitch(msg.featureID, msg.fromModel, msg.toModel, msg.reason, msg.details)
-		m.state.Save()
+			"reason", msg.reason,
+			"evidence", msg.evidence)
+		m.state.AddModelSwitchWithEvidence(msg.featureID, msg.fromModel, msg.toModel, msg.reason, msg.details, msg.evidence)
+		m.saveState()
 		m.setStatus(fmt.Sprintf("Model escalated: %s → %s (%s)", msg.fromModel, msg.toModel, msg.reason))
 		return m, nil
 	case instanceOutputMsg:
@@ -238,6 +321,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			var cmds []tea.Cmd
 			cmds = append(cmds, listenForOutput(msg.featureID, inst))
 
+			if msg.line.Type == "assistant" {
+				m.activityLog.AddAssistantText(msg.featureID, msg.line.Content)
+			}
+
+			if msg.line.Type == "ralph" && msg.line.Subtype == runner.FailureToolLoop {
+				m.setStatus(fmt.Sprintf("Feature %s may be looping: %s (x to stop)", msg.featureID, msg.line.Content))
+			}
+
 			// Check for spawn requests
 			if spawnReq, err := m.spawnHandler.ProcessLine(msg.featureID, string(msg.line.Raw)); err == nil && spawnReq != nil {
 				cmds = append(cmds, func() tea.Msg {
@@ -263,6 +354,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 								toModel:   currentModel,
 								reason:    string(lastSwitch.Reason),
 								details:   lastSwitch.Details,
+								evidence:  lastSwitch.Evidence,
 							}
 						})
 					}
@@ -274,15 +366,37 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case spawnRequestMsg:
 		return m.handleSpawnRequest(msg)
+	case notesEditedMsg:
+		return m.handleNotesEdited(msg)
 	case spawnStartedMsg:
 		return m.handleSpawnStarted(msg)
 	case instanceDoneMsg:
-		return m.handleInstanceDone(msg)
+		// An instance cleared while it was stopping, as ctrl+x does, has
+		// already had its feature reset
+		if msg.instance != nil && m.manager.GetInstance(msg.featureID) != msg.instance {
+			return m, nil
+		}
+		// The finished feature's reservation is released, so a feature
+		// waiting for budget may fit now
+		m.budgetWaiting = ""
+		next := m.startQueuedChild(msg.featureID)
+		updated, cmd := m.handleInstanceDone(msg)
+		return updated, tea.Batch(cmd, next)
+	case attachPollMsg:
+		return m.applyAttachPoll(msg)
 	case tickMsg:
 		if m.autoMode {
 			return m.autoStartNext()
 		}
 		return m, nil
+	case retryDueMsg:
+		return m.startDueRetry(msg)
+	case idleCheckMsg:
+		if m.manager.GetRunningCount() == 0 {
+			m.idleChecking = false
+			return m, nil
+		}
+		return m, scheduleIdleCheck()
 	case statusClearMsg:
 		if time.Now().After(m.statusExpiry) {
 			m.statusMsg = ""
@@ -307,6 +421,7 @@ func (m Model) handleInstanceDone(msg instanceDoneMsg) (tea.Model, tea.Cmd) {
 	parentID := m.state.GetFeatureParent(msg.featureID)
 	isChildFeature := parentID != ""
 
+	finalStatus := msg.status
 	inst := m.manager.GetInstance(msg.featureID)
 	if inst != nil {
 		testResults := inst.GetTestResults()
@@ -316,6 +431,14 @@ func (m Model) handleInstanceDone(msg instanceDoneMsg) (tea.Model, tea.Cmd) {
 		u := inst.GetUsage()
 		cost := inst.GetEstimatedCost()
 		m.state.SetFeatureUsage(msg.featureID, u.InputTokens, u.OutputTokens, u.CacheReadTokens, u.CacheWriteTokens, cost)
+		m.state.RecordAttemptUsage(msg.featureID, inst.GetCurrentModel(), u.InputTokens, u.OutputTokens, u.CacheReadTokens, u.CacheWriteTokens, cost)
+		if sessionID := inst.GetSessionID(); sessionID != "" {
+			m.state.SetSessionID(msg.featureID, sessionID)
+		}
+		added, removed := inst.GetLineChanges()
+		m.state.SetLineChanges(msg.featureID, added, removed)
+		cliDuration, turns := inst.GetResultStats()
+		m.state.SetResultStats(msg.featureID, cliDuration, turns)
 
 		if msg.status == "failed" {
 			errMsg := inst.GetError()
@@ -328,16 +451,29 @@ func (m Model) handleInstanceDone(msg instanceDoneMsg) (tea.Model, tea.Cmd) {
 			} else if m.autoMode && m.state.CanRetry(msg.featureID) {
 				// For root features, consider adjustments before retry
 				return m.handleRetryWithAdjustment(msg.featureID, feature, inst, errMsg, displayID)
+			} else if feature != nil {
+				finalStatus = m.applyOnFailure(feature, errMsg)
 			}
 		} else {
 			m.state.UpdateFeature(msg.featureID, msg.status)
-			if msg.status == "completed" {
+			if manifest.IsCompleted(msg.status) {
 				m.activityLog.AddFeatureCompleted(msg.featureID, featureTitle)
+				if msg.status == runner.StatusCompletedWithWarnings {
+					toolErrors, skipped := inst.GetWarnings()
+					m.setStatus(fmt.Sprintf("%s completed with warnings: %d tool errors, %d skipped tests", featureTitle, toolErrors, skipped))
+				}
+				if adj := m.state.SuccessfulAdjustment(msg.featureID); adj != nil {
+					logger.Info("retry", "Adjustment preceded successful attempt",
+						"featureID", displayID,
+						"type", adj.Type,
+						"reason", adj.Reason,
+						"attempt", adj.AttemptNum+1)
+				}
 			}
 		}
 	} else {
 		m.state.UpdateFeature(msg.featureID, msg.status)
-		if msg.status == "completed" {
+		if manifest.IsCompleted(msg.status) {
 			m.activityLog.AddFeatureCompleted(msg.featureID, featureTitle)
 		} else if msg.status == "failed" {
 			m.activityLog.AddFeatureFailed(msg.featureID, featureTitle)
@@ -349,7 +485,7 @@ func (m Model) handleInstanceDone(msg instanceDoneMsg) (tea.Model, tea.Cmd) {
 
 	// Update manifest status in manifest mode
 	if m.manifestMode && m.manifest != nil {
-		_ = m.manifest.UpdateFeatureStatus(msg.featureID, msg.status)
+		_ = m.manifest.UpdateFeatureStatus(msg.featureID, finalStatus)
 		_ = m.manifest.Save()
 	}
 
@@ -373,7 +509,17 @@ func (m Model) handleInstanceDone(msg instanceDoneMsg) (tea.Model, tea.Cmd) {
 		}
 	}
 
-	m.state.Save()
+	m.saveState()
+
+	// Progress now holds the root's outcome, so drop its RLM tree from memory
+	if !isChildFeature && m.spawnHandler != nil && (manifest.IsCompleted(msg.status) || msg.status == "failed") {
+		if rlmFeature := m.spawnHandler.GetFeature(msg.featureID); rlmFeature != nil {
+			rlmFeature.SetStatus(rlmStatus(msg.status))
+			if err := m.spawnHandler.GetManager().Evict(msg.featureID); err == nil {
+				logger.Debug("tui", "Evicted completed feature tree", "featureID", displayID)
+			}
+		}
+	}
 
 	if m.autoMode {
 		if m.state.AllCompleted() {
@@ -387,6 +533,28 @@ func (m Model) handleInstanceDone(msg instanceDoneMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// applyOnFailure carries out a root feature's On-Failure: action once it has
+// no retries left, returning the status to record in the manifest
+func (m *Model) applyOnFailure(feature *parser.Feature, errMsg string) string {
+	switch feature.OnFailure {
+	case parser.OnFailureSkip:
+		reason := "Failed with On-Failure: skip"
+		if errMsg != "" {
+			reason += ": " + errMsg
+		}
+		m.state.SkipFeature(feature.ID, reason)
+		m.setStatus(fmt.Sprintf("%s failed and was skipped", feature.Title))
+		logger.Info("tui", "Feature skipped after failure", "featureID", feature.ID)
+		return manifest.StatusSkipped
+	case parser.OnFailureAbort:
+		m.autoMode = false
+		m.manager.StopAll()
+		m.setStatus(fmt.Sprintf("Run aborted: %s failed (On-Failure: abort)", feature.Title))
+		logger.Warn("tui", "Run aborted after failure", "featureID", feature.ID)
+	}
+	return "failed"
+}
+
 // handleChildFailure processes a child feature failure based on isolation level
 func (m *Model) handleChildFailure(childID, parentID, childTitle, errMsg string) {
 	displayChildID := childID
@@ -488,6 +656,7 @@ func (m Model) handleRetryWithAdjustment(featureID string, feature *parser.Featu
 		HasTimeout:    false,
 		TaskCount:     0,
 		CurrentModel:  currentModel,
+		TestOutput:    testResults.Output,
 	}
 	if feature != nil {
 		failureCtx.TaskCount = len(feature.Tasks)
@@ -543,18 +712,52 @@ func (m Model) handleRetryWithAdjustment(featureID string, feature *parser.Featu
 		if newModel := m.state.GetCurrentModel(featureID); newModel != "" && newModel != feature.Model {
 			adjustedFeature.Model = newModel
 		}
+		// Vary the prompt wording so the retry doesn't repeat the same failure
+		if guidance := retry.PromptAugmentation(attempt+1, failureCtx); guidance != "" {
+			if adjustedFeature.PromptSuffix != "" {
+				adjustedFeature.PromptSuffix += "\n\n"
+			}
+			adjustedFeature.PromptSuffix += guidance
+		}
 
-		m.state.Save()
-		return m, tea.Batch(
-			startFeatureWithBudget(adjustedFeature, m.prd.Context, m.workDir, m.manager),
-			tea.Tick(time.Second, func(t time.Time) tea.Msg { return tickMsg{} }),
-		)
+		m.saveState()
+		return m, m.scheduleRetry(adjustedFeature, decision.Delay)
 	}
 
-	m.state.Save()
+	m.saveState()
 	return m, tea.Tick(time.Second, func(t time.Time) tea.Msg { return tickMsg{} })
 }
 
+// scheduleRetry starts the feature again once delay has passed. The retry
+// strategy jitters the delay, so features that failed together don't all
+// retry against the API at once. Until then autoStartNext leaves it be.
+func (m *Model) scheduleRetry(feature parser.Feature, delay time.Duration) tea.Cmd {
+	if m.retryWaiting == nil {
+		m.retryWaiting = make(map[string]bool)
+	}
+	m.retryWaiting[feature.ID] = true
+	return tea.Batch(
+		tea.Tick(delay, func(time.Time) tea.Msg { return retryDueMsg{feature: feature} }),
+		tea.Tick(time.Second, func(t time.Time) tea.Msg { return tickMsg{} }),
+	)
+}
+
+// startDueRetry starts a retry scheduled by scheduleRetry, unless auto mode
+// was stopped or the feature was started, stopped or reset by hand in the
+// meantime. If other features took every free slot during the delay, the
+// retry keeps waiting until one finishes.
+func (m Model) startDueRetry(msg retryDueMsg) (tea.Model, tea.Cmd) {
+	if !m.retryWaiting[msg.feature.ID] || !m.autoMode || m.getFeatureStatus(msg.feature.ID) == "running" {
+		delete(m.retryWaiting, msg.feature.ID)
+		return m, nil
+	}
+	if !m.manager.CanStartMore() {
+		return m, tea.Tick(time.Second, func(time.Time) tea.Msg { return msg })
+	}
+	delete(m.retryWaiting, msg.feature.ID)
+	return m, startFeatureWithBudget(m.withTaskStates(msg.feature), m.prd.Context, m.workDir, m.manager, m.state.GetAttempts(msg.feature.ID)+1)
+}
+
 // containsBuildError checks if the error message indicates a build/compilation failure
 func containsBuildError(errMsg string) bool {
 	lower := errMsg
@@ -642,6 +845,15 @@ func (m *Model) getParentIsolationLevel(parentID string) rlm.IsolationLevel {
 	return rlm.DefaultIsolationLevel
 }
 
+// rlmStatus maps a runner status to one the RLM tree tracks, which doesn't
+// distinguish completions with warnings
+func rlmStatus(status string) string {
+	if manifest.IsCompleted(status) {
+		return "completed"
+	}
+	return status
+}
+
 // generateChildResultContext creates a context summary for injection into parent
 func (m Model) generateChildResultContext(childID string, status string) string {
 	childFeature := m.spawnHandler.GetFeature(childID)
@@ -650,6 +862,7 @@ func (m Model) generateChildResultContext(childID string, status string) string
 	}
 
 	result := summary.NewChildResult(childID, childFeature.Title, status)
+	result.SetExpectedOutputs(childFeature.GetExpectedOutputs())
 
 	inst := m.manager.GetInstance(childID)
 	if inst != nil {
@@ -686,7 +899,7 @@ func (m Model) generateChildResultContext(childID string, status string) string
 	}
 	summaryResult := result.GenerateSummary(contextBudget)
 
-	contextText := m.spawnHandler.CompleteFeature(childID, status, summaryResult.Raw)
+	contextText := m.spawnHandler.CompleteFeature(childID, rlmStatus(status), summaryResult.Raw)
 	if contextText == "" {
 		return summaryResult.Formatted
 	}
@@ -709,18 +922,70 @@ func (m Model) handleSpawnRequest(msg spawnRequestMsg) (tea.Model, tea.Cmd) {
 		logger.Error("tui", "Failed to spawn child feature",
 			"parentID", parentShort,
 			"error", err.Error())
+		if errors.Is(err, rlm.ErrMaxSpawnsExceeded) {
+			m.activityLog.AddOutput(msg.parentID, fmt.Sprintf("Spawn rejected: %s (%v)", msg.request.Title, err))
+			if parentInst := m.manager.GetInstance(msg.parentID); parentInst != nil {
+				parentInst.AppendOutput(fmt.Sprintf("[Spawn rejected: %s - %v]", msg.request.Title, err))
+			}
+			m.setStatus(fmt.Sprintf("Spawn limit reached for %s", parentShort))
+			return m, nil
+		}
 		m.setStatus(fmt.Sprintf("Spawn failed: %v", err))
 		return m, nil
 	}
 
 	m.state.InitFeature(child.ID, child.Title)
 	m.state.SetFeatureParent(child.ID, msg.parentID)
-	m.state.Save()
+	m.saveState()
 
 	prompt := m.spawnHandler.BuildChildPrompt(msg.request, "")
+	limit := m.getParentChildLimit(msg.parentID)
+	m.childExecutor.SetChildLimit(msg.parentID, limit)
+	if !m.childExecutor.AdmitChild(msg.parentID, child, prompt) {
+		m.activityLog.AddOutput(msg.parentID, fmt.Sprintf("Queued sub-feature: %s (Max-Children-Concurrent: %d)", child.Title, limit))
+		return m, nil
+	}
+
 	m.activityLog.AddFeatureStarted(child.ID, fmt.Sprintf("[sub] %s", child.Title))
+	return m, startSpawnedChild(msg.parentID, child, prompt, m.workDir, m.manager, limit > 0)
+}
 
-	return m, startSpawnedChild(msg.parentID, child, prompt, m.workDir, m.manager)
+// getParentChildLimit returns how many of a parent's children may run at
+// once, or 0 to leave them to the global concurrency limit
+func (m *Model) getParentChildLimit(parentID string) int {
+	if limit := m.state.GetMaxChildrenConcurrent(parentID); limit > 0 {
+		return limit
+	}
+	if feature := m.findFeature(parentID); feature != nil {
+		return feature.MaxChildrenConcurrent
+	}
+	return 0
+}
+
+// startQueuedChild frees the slot childID held under its parent's child
+// limit and starts the next queued sibling, if any
+func (m *Model) startQueuedChild(childID string) tea.Cmd {
+	next := m.childExecutor.ReleaseChild(childID)
+	if next == nil {
+		return nil
+	}
+	m.activityLog.AddFeatureStarted(next.Child.ID, fmt.Sprintf("[sub] %s", next.Child.Title))
+	return startSpawnedChild(next.ParentID, next.Child, next.Prompt, m.workDir, m.manager, true)
+}
+
+// restoreSpawnedChildren re-creates the progress entries of sub-features
+// persisted in the manifest so the task tree survives a restart
+func (m *Model) restoreSpawnedChildren() {
+	if m.state == nil || m.manifest == nil {
+		return
+	}
+	for _, f := range m.manifest.Features {
+		if f.IsRootFeature() {
+			continue
+		}
+		m.state.InitFeature(f.ID, f.Title)
+		m.state.SetFeatureParent(f.ID, f.ParentID)
+	}
 }
 
 func (m Model) handleSpawnStarted(msg spawnStartedMsg) (tea.Model, tea.Cmd) {
@@ -734,7 +999,7 @@ func (m Model) handleSpawnStarted(msg spawnStartedMsg) (tea.Model, tea.Cmd) {
 			"childID", childShort,
 			"error", msg.err.Error())
 		m.setStatus(fmt.Sprintf("Spawn start failed: %v", msg.err))
-		return m, nil
+		return m, m.startQueuedChild(msg.childID)
 	}
 
 	logger.Info("tui", "Spawned child started",
@@ -743,7 +1008,7 @@ func (m Model) handleSpawnStarted(msg spawnStartedMsg) (tea.Model, tea.Cmd) {
 
 	m.spawnHandler.SetFeatureRunning(msg.childID)
 	m.state.UpdateFeature(msg.childID, "running")
-	m.state.Save()
+	m.saveState()
 
 	return m, listenForOutput(msg.childID, msg.instance)
 }
@@ -777,11 +1042,215 @@ func (m *Model) findFeatureOrChild(id string) *parser.Feature {
 	return nil
 }
 
+// nextModel returns the model after current in the haiku, sonnet, opus, auto
+// cycle. Features without a model run on sonnet.
+func nextModel(current string) string {
+	if current == "" {
+		current = "sonnet"
+	}
+	for i, model := range manifest.FeatureModels {
+		if model == current {
+			return manifest.FeatureModels[(i+1)%len(manifest.FeatureModels)]
+		}
+	}
+	return manifest.FeatureModels[0]
+}
+
+// cycleModel moves a pending feature to the next model before it starts,
+// persisting the choice to the manifest in manifest mode
+func (m *Model) cycleModel(id string) {
+	feature := m.findFeature(id)
+	if feature == nil {
+		return
+	}
+	if status := m.getFeatureStatus(id); status != "pending" {
+		m.setStatus("Model can only be changed for pending features")
+		return
+	}
+
+	model := nextModel(feature.Model)
+	if m.manifest != nil {
+		if err := m.manifest.SetFeatureModel(id, model); err != nil {
+			logger.Error("tui", "Failed to save model override", "featureID", id, "error", err)
+			m.setStatus(fmt.Sprintf("Model not changed: %v", err))
+			return
+		}
+	}
+	feature.Model = model
+	if m.modelOverrides == nil {
+		m.modelOverrides = make(map[string]bool)
+	}
+	m.modelOverrides[id] = true
+	m.setStatus(fmt.Sprintf("%s will run on %s", feature.Title, model))
+	logger.Info("tui", "Model overridden", "featureID", id, "model", model)
+}
+
 func (m *Model) setStatus(msg string) {
 	m.statusMsg = msg
 	m.statusExpiry = time.Now().Add(5 * time.Second)
 }
 
+// applyClaudeArgs passes the PRD's Claude-Args to the runner, reporting
+// args that conflict with ralph's own flags instead of using them
+func (m *Model) applyClaudeArgs() {
+	if len(m.prd.ClaudeArgs) == 0 {
+		return
+	}
+	if err := m.manager.SetExtraArgs(m.prd.ClaudeArgs); err != nil {
+		logger.Error("tui", "Ignoring Claude-Args", "error", err)
+		m.setStatus(fmt.Sprintf("Ignoring Claude-Args: %v", err))
+		return
+	}
+	logger.Info("tui", "Extra claude args set", "args", m.prd.ClaudeArgs)
+}
+
+// withTaskStates returns feature with the tasks checked off or on by hand
+// applied, so a retry's prompt doesn't ask for them again
+func (m Model) withTaskStates(feature parser.Feature) parser.Feature {
+	if m.state != nil {
+		feature.ApplyTaskStates(m.state.GetTaskStates(feature.ID))
+	}
+	return feature
+}
+
+// applyRunConfig hands the retry and concurrency limits to the runner. The
+// PRD's Concurrent/Retries override progress.json so a PRD is self-contained;
+// it runs on both loads since either may arrive last.
+func (m *Model) applyRunConfig() {
+	if m.state == nil {
+		return
+	}
+	if m.prd != nil && (m.prd.MaxConcurrent > 0 || m.prd.MaxRetries > 0) {
+		maxRetries := m.state.Config.MaxRetries
+		maxConcurrent := m.state.Config.MaxConcurrent
+		if m.prd.MaxRetries > 0 {
+			maxRetries = m.prd.MaxRetries
+		}
+		if m.prd.MaxConcurrent > 0 {
+			maxConcurrent = m.prd.MaxConcurrent
+		}
+		m.state.SetConfig(maxRetries, maxConcurrent)
+		logger.Info("tui", "Run config set from PRD", "retries", maxRetries, "concurrent", maxConcurrent)
+	}
+	warningThreshold := runner.DefaultWarningThreshold
+	if m.prd != nil && m.prd.WarningThreshold > 0 {
+		warningThreshold = m.prd.WarningThreshold
+	}
+	m.manager.SetConfig(runner.Config{
+		MaxRetries:       m.state.Config.MaxRetries,
+		MaxConcurrent:    m.state.Config.MaxConcurrent,
+		WarningThreshold: warningThreshold,
+		LoopThreshold:    runner.DefaultLoopThreshold,
+		LoopWindow:       runner.DefaultLoopWindow,
+	})
+}
+
+// resetAll stops every instance and resets all feature progress
+func (m *Model) resetAll() {
+	m.autoMode = false
+	m.manager.StopAll()
+	m.state.ResetAll()
+	m.saveState()
+	deleteProgressMD(m.workDir)
+	m.setStatus("Reset all features and cleared progress.md")
+	logger.Info("tui", "Reset all features and deleted progress.md")
+}
+
+// checkStale compares the PRD with the hash progress was recorded against once
+// both have loaded, and offers a reset if the PRD has changed since
+func (m *Model) checkStale() {
+	if m.staleChecked || m.prd == nil || m.state == nil {
+		return
+	}
+	m.staleChecked = true
+
+	if m.state.MarkStale(m.prd.Hash()) {
+		logger.Warn("tui", "PRD changed since progress was recorded")
+		m.setStatus("Warning: PRD changed since last run - progress may be stale")
+		m.showDialog(layout.ConfirmTypeStale)
+	}
+}
+
+// checkInterrupted flags features left running by a crashed session and
+// offers to reset them
+func (m *Model) checkInterrupted() {
+	interrupted := m.state.MarkInterrupted(func(id string) bool {
+		return m.manager.GetInstance(id) != nil
+	})
+	if len(interrupted) == 0 {
+		return
+	}
+
+	logger.Warn("tui", "Found features interrupted by a previous run", "count", len(interrupted))
+	m.setStatus(fmt.Sprintf("%d feature(s) interrupted by a previous run", len(interrupted)))
+	m.showDialog(layout.ConfirmTypeInterrupted)
+}
+
+// showDialog shows a confirm dialog, or queues it behind the one already
+// shown so neither question is lost
+func (m *Model) showDialog(dialogType layout.ConfirmType) {
+	if m.confirmDialog.IsVisible() {
+		m.queuedDialogs = append(m.queuedDialogs, dialogType)
+		return
+	}
+	m.confirmDialog.Show(dialogType)
+}
+
+// showQueuedDialog shows the next queued dialog once the last one is
+// answered. The interrupted dialog is dropped if answering the stale one
+// already reset those features.
+func (m *Model) showQueuedDialog() {
+	for len(m.queuedDialogs) > 0 {
+		dialogType := m.queuedDialogs[0]
+		m.queuedDialogs = m.queuedDialogs[1:]
+		if dialogType == layout.ConfirmTypeInterrupted && len(m.state.FeaturesByStatus()["interrupted"]) == 0 {
+			continue
+		}
+		m.confirmDialog.Show(dialogType)
+		return
+	}
+}
+
+// saveState persists progress, reporting a failure in the status line. The
+// failure stays visible until a later save succeeds.
+func (m *Model) saveState() {
+	if err := m.state.Save(); err != nil {
+		logger.Error("tui", "Failed to save progress", "path", m.state.GetPath(), "error", err)
+		m.saveErr = err
+		m.setStatus(fmt.Sprintf("Warning: progress not saved: %v", err))
+		return
+	}
+	if m.saveErr != nil {
+		logger.Info("tui", "Progress saved after earlier failure", "path", m.state.GetPath())
+		m.saveErr = nil
+	}
+}
+
+// stopAndResetFeature stops a feature's instance and resets it to pending in
+// one step. The instance is cleared straight away, so it finishing as
+// stopped doesn't mark the feature failed.
+func (m *Model) stopAndResetFeature(id, title string) {
+	m.manager.StopInstance(id)
+	m.manager.ClearInstance(id)
+	delete(m.retryWaiting, id)
+	m.state.ResetFeature(id)
+	if m.manifestMode && m.manifest != nil {
+		_ = m.manifest.UpdateFeatureStatus(id, "pending")
+		_ = m.manifest.Save()
+	}
+	m.activityLog.AddFeatureStopped(id, title)
+	m.saveState()
+	if m.saveErr == nil {
+		m.setStatus(fmt.Sprintf("Stopped and reset %s", title))
+	}
+}
+
+// hasUnsavedProgress reports whether progress has changed since it was last
+// saved. A read-only attach never saves, so it never shows as unsaved.
+func (m Model) hasUnsavedProgress() bool {
+	return m.state != nil && !m.attached && m.state.IsDirty()
+}
+
 // getPendingChildResults retrieves and clears any pending child results for a feature
 func (m *Model) getPendingChildResults(parentID string) string {
 	results := m.childResults[parentID]
@@ -823,7 +1292,7 @@ func (m Model) autoStartNext() (tea.Model, tea.Cmd) {
 		}
 	}
 
-	if !m.manager.CanStartMore() {
+	if !m.manager.CanStartMore() || m.budgetWaiting != "" {
 		return m, tea.Tick(time.Second, func(t time.Time) tea.Msg { return tickMsg{} })
 	}
 
@@ -835,7 +1304,7 @@ func (m Model) autoStartNext() (tea.Model, tea.Cmd) {
 			if feature != nil {
 				m.setStatus(fmt.Sprintf("Starting %s...", feature.Title))
 				return m, tea.Batch(
-					startFeatureWithBudget(*feature, m.prd.Context, m.workDir, m.manager),
+					startFeatureWithBudget(m.withTaskStates(*feature), m.prd.Context, m.workDir, m.manager, m.state.GetAttempts(feature.ID)+1),
 					tea.Tick(time.Second, func(t time.Time) tea.Msg { return tickMsg{} }),
 				)
 			}
@@ -843,11 +1312,14 @@ func (m Model) autoStartNext() (tea.Model, tea.Cmd) {
 	} else {
 		// Legacy mode: iterate features without dependency checking
 		for _, feature := range m.prd.Features {
+			if feature.Disabled {
+				continue
+			}
 			fs := m.state.GetFeature(feature.ID)
 			if fs == nil || fs.Status == "pending" || fs.Status == "" {
 				m.setStatus(fmt.Sprintf("Starting %s...", feature.Title))
 				return m, tea.Batch(
-					startFeatureWithBudget(feature, m.prd.Context, m.workDir, m.manager),
+					startFeatureWithBudget(m.withTaskStates(feature), m.prd.Context, m.workDir, m.manager, m.state.GetAttempts(feature.ID)+1),
 					tea.Tick(time.Second, func(t time.Time) tea.Msg { return tickMsg{} }),
 				)
 			}
@@ -856,18 +1328,21 @@ func (m Model) autoStartNext() (tea.Model, tea.Cmd) {
 
 	retryable := m.state.GetRetryableFeatures()
 	for _, id := range retryable {
+		if m.retryWaiting[id] {
+			continue
+		}
 		feature := m.findFeature(id)
 		if feature != nil {
 			m.setStatus(fmt.Sprintf("Retrying %s...", feature.Title))
 			m.manager.ClearInstance(id)
 			return m, tea.Batch(
-				startFeatureWithBudget(*feature, m.prd.Context, m.workDir, m.manager),
+				startFeatureWithBudget(m.withTaskStates(*feature), m.prd.Context, m.workDir, m.manager, m.state.GetAttempts(feature.ID)+1),
 				tea.Tick(time.Second, func(t time.Time) tea.Msg { return tickMsg{} }),
 			)
 		}
 	}
 
-	if m.manager.GetRunningCount() == 0 {
+	if m.manager.GetRunningCount() == 0 && len(m.retryWaiting) == 0 {
 		m.autoMode = false
 		if m.state.HasFailures() {
 			m.setStatus("Stopped: some features failed after max retries")
@@ -888,16 +1363,23 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if dialogType == layout.ConfirmTypeQuit {
 				m.quitting = true
 				m.manager.StopAll()
-				m.state.Save()
+				m.saveState()
 				return m, tea.Quit
 			} else if dialogType == layout.ConfirmTypeReset {
-				m.autoMode = false
-				m.manager.StopAll()
-				m.state.ResetAll()
-				m.state.Save()
-				deleteProgressMD(m.workDir)
-				m.setStatus("Reset all features and cleared progress.md")
-				logger.Info("tui", "Reset all features and deleted progress.md")
+				m.resetAll()
+			} else if dialogType == layout.ConfirmTypeStale {
+				m.state.AcceptPRDHash()
+				m.resetAll()
+			} else if dialogType == layout.ConfirmTypeInterrupted {
+				reset := m.state.ResetInterrupted()
+				if m.manifestMode && m.manifest != nil && len(reset) > 0 {
+					for _, id := range reset {
+						_ = m.manifest.UpdateFeatureStatus(id, "pending")
+					}
+					_ = m.manifest.Save()
+				}
+				m.saveState()
+				m.setStatus(fmt.Sprintf("Reset %d interrupted feature(s) to pending", len(reset)))
 			} else if dialogType == layout.ConfirmTypeBudget {
 				m.manager.AcknowledgeBudget()
 				m.budgetAlertShown = true
@@ -907,9 +1389,11 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				if m.pendingFeatureStart != nil {
 					feature := *m.pendingFeatureStart
 					m.pendingFeatureStart = nil
-					return m, startFeature(feature, m.prd.Context, m.workDir, m.manager)
+					m.showQueuedDialog()
+					return m, startFeature(m.withTaskStates(feature), m.prd.Context, m.workDir, m.manager, m.state.GetAttempts(feature.ID)+1)
 				}
 			}
+			m.showQueuedDialog()
 			return m, nil
 		case "n", "N", "esc":
 			dialogType := m.confirmDialog.Type()
@@ -918,7 +1402,16 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.pendingFeatureStart = nil
 				m.autoMode = false
 				m.setStatus("Stopped at budget limit")
+			} else if dialogType == layout.ConfirmTypeStale {
+				m.state.AcceptPRDHash()
+				m.saveState()
+				m.setStatus("PRD changed - kept existing progress")
+				logger.Info("tui", "Kept progress after PRD change")
+			} else if dialogType == layout.ConfirmTypeInterrupted {
+				m.saveState()
+				m.setStatus("Kept interrupted features - press r to retry")
 			}
+			m.showQueuedDialog()
 			return m, nil
 		}
 		return m, nil
@@ -928,6 +1421,10 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleHelpView(msg)
 	}
 
+	if m.depsModal.IsVisible() {
+		return m.handleDepsView(msg)
+	}
+
 	switch m.currentView {
 	case viewMain:
 		return m.handleMainView(msg)
@@ -938,8 +1435,17 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 }
 
 func (m Model) handleMainView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.attached && isAttachedReadOnlyKey(msg.String()) {
+		m.setStatus(attachedReadOnlyMsg)
+		return m, nil
+	}
 	switch msg.String() {
 	case "q":
+		if m.attached {
+			// Nothing runs in this process, so there's nothing to stop
+			m.quitting = true
+			return m, tea.Quit
+		}
 		m.confirmDialog.Show(layout.ConfirmTypeQuit)
 		return m, nil
 	case "j", "down":
@@ -959,13 +1465,23 @@ func (m Model) handleMainView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.taskList.SetSelected(m.selected)
 			}
 		}
+	case "tab":
+		// Narrow terminals show one pane at a time
+		m.splitPane.ToggleFocus()
+	case "z":
+		// Collapse all features with children
+		m.taskList.CollapseAll()
+		m.selected = m.taskList.Selected()
+	case "Z":
+		// Expand all features with children
+		m.taskList.ExpandAll()
+		m.selected = m.taskList.Selected()
 	case "enter":
 		if m.prd != nil && m.taskList.VisibleCount() > 0 {
 			item := m.taskList.SelectedItem()
 			if item != nil {
 				m.inspecting = item.ID
-				m.scrollOffset = 999999
-				m.autoScroll = true
+				m.scroll.Bottom(-1)
 				m.currentView = viewInspect
 			}
 		}
@@ -983,6 +1499,10 @@ func (m Model) handleMainView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if status == "running" {
 				return m, nil
 			}
+			if feature.Disabled {
+				m.setStatus(fmt.Sprintf("%s is disabled in the PRD", feature.Title))
+				return m, nil
+			}
 			// Check global budget before starting
 			if m.manager.HasGlobalBudget() && !m.manager.IsBudgetAcknowledged() {
 				_, atThreshold, _ := m.manager.CheckGlobalBudget()
@@ -993,11 +1513,12 @@ func (m Model) handleMainView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				}
 			}
 			m.manager.ClearInstance(feature.ID)
-			return m, startFeatureWithBudget(*feature, m.prd.Context, m.workDir, m.manager)
+			return m, startFeatureWithBudget(m.withTaskStates(*feature), m.prd.Context, m.workDir, m.manager, m.state.GetAttempts(feature.ID)+1)
 		}
 	case "S":
 		if m.prd != nil && !m.autoMode {
 			m.autoMode = true
+			m.budgetWaiting = ""
 			m.setStatus("Auto mode enabled - starting features...")
 			return m, tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg { return tickMsg{} })
 		}
@@ -1012,7 +1533,7 @@ func (m Model) handleMainView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 			status := m.getFeatureStatus(item.ID)
-			if status == "failed" || status == "completed" || status == "stopped" {
+			if status == "failed" || manifest.IsCompleted(status) || status == "stopped" || status == "interrupted" {
 				// Check global budget before retrying
 				if m.manager.HasGlobalBudget() && !m.manager.IsBudgetAcknowledged() {
 					_, atThreshold, _ := m.manager.CheckGlobalBudget()
@@ -1023,7 +1544,11 @@ func (m Model) handleMainView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					}
 				}
 				m.manager.ClearInstance(item.ID)
-				return m, startFeatureWithBudget(*feature, m.prd.Context, m.workDir, m.manager)
+				if sessionID := m.state.GetSessionID(item.ID); m.resumeOnRetry && sessionID != "" {
+					m.setStatus(fmt.Sprintf("Resuming session %s", displaySessionID(sessionID)))
+					return m, resumeFeature(m.withTaskStates(*feature), m.prd.Context, m.workDir, m.manager, m.state.GetAttempts(feature.ID)+1, sessionID)
+				}
+				return m, startFeatureWithBudget(m.withTaskStates(*feature), m.prd.Context, m.workDir, m.manager, m.state.GetAttempts(feature.ID)+1)
 			}
 		}
 	case "R":
@@ -1034,7 +1559,7 @@ func (m Model) handleMainView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 			m.state.ResetFeature(item.ID)
 			m.manager.ClearInstance(item.ID)
-			m.state.Save()
+			m.saveState()
 			m.setStatus(fmt.Sprintf("Reset %s", item.Title))
 		}
 	case "x":
@@ -1046,7 +1571,15 @@ func (m Model) handleMainView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.manager.StopInstance(item.ID)
 			m.state.UpdateFeature(item.ID, "stopped")
 			m.activityLog.AddFeatureStopped(item.ID, item.Title)
-			m.state.Save()
+			m.saveState()
+		}
+	case "ctrl+x":
+		if m.prd != nil && m.taskList.VisibleCount() > 0 {
+			item := m.taskList.SelectedItem()
+			if item == nil {
+				return m, nil
+			}
+			m.stopAndResetFeature(item.ID, item.Title)
 		}
 	case "X":
 		m.autoMode = false
@@ -1056,6 +1589,44 @@ func (m Model) handleMainView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.confirmDialog.Show(layout.ConfirmTypeReset)
 	case "?":
 		m.helpModal.Show()
+	case "f":
+		// Toggle filtering the activity pane to the selected feature
+		if m.activityPane.Filter() != "" {
+			m.activityPane.ClearFilter()
+			m.splitPane.SetTitles("TASKS", "ACTIVITY")
+			m.setStatus("Showing activity for all features")
+		} else if item := m.taskList.SelectedItem(); item != nil {
+			m.activityPane.SetFilter(item.ID)
+			m.splitPane.SetTitles("TASKS", "ACTIVITY: "+item.Title)
+			m.setStatus(fmt.Sprintf("Showing activity for %s", item.Title))
+		}
+	case "m":
+		if item := m.taskList.SelectedItem(); item != nil {
+			m.cycleModel(item.ID)
+		}
+	case "e":
+		if item := m.taskList.SelectedItem(); item != nil {
+			return m, m.editNotes(item.ID)
+		}
+	case "i":
+		if item := m.taskList.SelectedItem(); item != nil {
+			m.showDependencyChain(item.ID)
+		}
+	case "n", "N":
+		m.taskList.SetItems(m.buildTaskItems())
+		if m.taskList.SelectNextWithStatus(msg.String() == "n", "failed", "blocked") {
+			m.selected = m.taskList.Selected()
+		} else {
+			m.setStatus("No other failed features")
+		}
+	case "o":
+		m.taskList.SetItems(m.buildTaskItems())
+		mode := m.taskList.CycleSort()
+		m.selected = m.taskList.Selected()
+		m.setStatus(fmt.Sprintf("Sort: %s", mode))
+	case "l":
+		m.layout.SetShowLegend(!m.layout.ShowLegend())
+		m.resizePanes()
 	case "c":
 		m.showCost = !m.showCost
 		m.taskList.SetShowCost(m.showCost)
@@ -1078,33 +1649,45 @@ func (m Model) getFeatureStatus(id string) string {
 	return "pending"
 }
 
+// isBlocked reports whether a pending feature is waiting on dependencies
+// that haven't completed, the way ralph status counts it as blocked
+func (m Model) isBlocked(id string) bool {
+	if !m.manifestMode || m.manifest == nil {
+		return false
+	}
+	f := m.manifest.GetFeature(id)
+	return f != nil && f.IsRootFeature() && !m.manifest.IsDependencySatisfied(id)
+}
+
 func (m Model) handleInspectView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.attached && isAttachedReadOnlyKey(msg.String()) {
+		m.setStatus(attachedReadOnlyMsg)
+		return m, nil
+	}
 	switch msg.String() {
 	case "q", "esc":
 		m.currentView = viewMain
 		m.inspecting = ""
-		m.scrollOffset = 0
-		m.autoScroll = false
+		m.attachedOutput = ""
+		m.scroll.Reset()
 		m.modal.ResetView()
 	case "j", "down":
-		m.scrollOffset++
+		m.scroll.Down(1, m.modal.MaxScrollOffset())
 	case "k", "up":
-		if m.scrollOffset > 0 {
-			m.scrollOffset--
-			m.autoScroll = false
-		}
-	case "G":
-		m.scrollOffset = 999999
-		m.autoScroll = true
+		m.scroll.Up(1, m.modal.MaxScrollOffset())
+	case "G", "f":
+		m.scroll.Bottom(m.modal.MaxScrollOffset())
 	case "g":
-		m.scrollOffset = 0
-		m.autoScroll = false
-	case "f":
-		m.scrollOffset = 999999
-		m.autoScroll = true
+		m.scroll.Top()
 	case "a":
 		m.modal.ToggleActions()
-		m.scrollOffset = 0
+		m.scroll.Offset = 0
+	case "F":
+		if m.modal.ToggleFullContent() {
+			m.setStatus("Showing full output lines")
+		} else {
+			m.setStatus("Showing clipped output lines")
+		}
 	case "s":
 		if m.inspecting != "" {
 			feature := m.findFeature(m.inspecting)
@@ -1112,10 +1695,12 @@ func (m Model) handleInspectView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				status := m.getFeatureStatus(m.inspecting)
 				if status != "running" {
 					m.manager.ClearInstance(m.inspecting)
-					return m, startFeature(*feature, m.prd.Context, m.workDir, m.manager)
+					return m, startFeature(m.withTaskStates(*feature), m.prd.Context, m.workDir, m.manager, m.state.GetAttempts(feature.ID)+1)
 				}
 			}
 		}
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		m.toggleTask(int(msg.String()[0] - '0'))
 	case "x":
 		if m.inspecting != "" {
 			feature := m.findFeature(m.inspecting)
@@ -1124,12 +1709,33 @@ func (m Model) handleInspectView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if feature != nil {
 				m.activityLog.AddFeatureStopped(m.inspecting, feature.Title)
 			}
-			m.state.Save()
+			m.saveState()
 		}
 	}
 	return m, nil
 }
 
+// toggleTask checks the inspected feature's nth task off, or back on, and
+// saves it, so the next attempt's prompt shows it done
+func (m *Model) toggleTask(n int) {
+	feature := m.findFeature(m.inspecting)
+	if feature == nil {
+		return
+	}
+	tasks := m.featureTasks(*feature)
+	if n < 1 || n > len(tasks) {
+		return
+	}
+	task := tasks[n-1]
+	m.state.SetTaskCompleted(feature.ID, task.ID, !task.Completed)
+	if task.Completed {
+		m.setStatus(fmt.Sprintf("Unchecked task %d of %s", n, feature.Title))
+	} else {
+		m.setStatus(fmt.Sprintf("Checked off task %d of %s", n, feature.Title))
+	}
+	m.saveState()
+}
+
 func (m Model) handleHelpView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "q", "esc", "?":
@@ -1146,6 +1752,51 @@ func (m Model) handleHelpView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+func (m Model) handleDepsView(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc", "i":
+		m.depsModal.Hide()
+	case "j", "down":
+		m.depsModal.ScrollDown()
+	case "k", "up":
+		m.depsModal.ScrollUp()
+	case "g":
+		m.depsModal.ScrollToTop()
+	case "G":
+		m.depsModal.ScrollToBottom()
+	}
+	return m, nil
+}
+
+// showDependencyChain opens the modal listing everything the feature depends
+// on, directly or transitively, with each dependency's status
+func (m *Model) showDependencyChain(id string) {
+	if !m.manifestMode || m.manifest == nil {
+		m.setStatus("Dependency chains need a PRD/ directory")
+		return
+	}
+	feature := m.manifest.GetFeature(id)
+	if feature == nil {
+		return
+	}
+
+	var deps []layout.DependencyLine
+	for _, link := range m.manifest.GetDependencyChain(id) {
+		deps = append(deps, layout.DependencyLine{
+			ID:        link.Feature.ID,
+			Title:     link.Feature.Title,
+			Status:    link.Feature.Status,
+			Depth:     link.Depth,
+			Missing:   link.Missing,
+			Satisfied: link.Satisfied,
+		})
+	}
+
+	title := fmt.Sprintf("%s %s", feature.ID, feature.Title)
+	m.depsModal.SetContent("Dependencies", layout.FormatDependencyChain(title, deps))
+	m.depsModal.Show()
+}
+
 func (m Model) View() string {
 	if m.quitting {
 		return "Shutting down...\n"
@@ -1168,9 +1819,9 @@ func (m Model) View() string {
 }
 
 func (m Model) renderMainView() string {
-	var total, completed, running, failed, pending int
+	var total, completed, running, failed, pending, skipped int
 	if m.state != nil {
-		total, completed, running, failed, pending = m.state.GetSummary()
+		total, completed, running, failed, pending, skipped = m.state.GetSummary()
 	}
 
 	// Aggregate tokens: state (completed) + manager (running)
@@ -1208,43 +1859,54 @@ func (m Model) renderMainView() string {
 
 	// Check budget status
 	budgetStatus := ""
+	budgetPercent := 0.0
 	budgetAlert := false
 	if m.manager.HasGlobalBudget() {
 		budgetStatus = m.manager.GetGlobalBudgetStatus()
-		_, atThreshold, _ := m.manager.CheckGlobalBudget()
+		percent, atThreshold, _ := m.manager.CheckGlobalBudget()
+		budgetPercent = percent
 		budgetAlert = atThreshold && !m.manager.IsBudgetAcknowledged()
 	}
 
 	headerData := layout.HeaderData{
-		Version:      layout.AppName + " " + layout.AppVersion,
-		Title:        "Feature Builder",
-		AutoMode:     m.autoMode,
-		Total:        total,
-		Completed:    completed,
-		Running:      running,
-		Failed:       failed,
-		Pending:      pending,
-		TokenUsage:   tokenUsageStr,
-		TotalCost:    totalCostStr,
-		ShowCost:     m.showCost,
-		BudgetStatus: budgetStatus,
-		BudgetAlert:  budgetAlert,
-		ElapsedTime:  elapsedStr,
-	}
-
-	keybindings := "s: start • S: start all • r: retry • R: reset • x: stop • X: stop all • ?: help • q: quit"
+		Version:       layout.AppName + " " + layout.AppVersion,
+		Title:         "Feature Builder",
+		AutoMode:      m.autoMode,
+		Total:         total,
+		Completed:     completed,
+		Running:       running,
+		Failed:        failed,
+		Pending:       pending,
+		Skipped:       skipped,
+		TokenUsage:    tokenUsageStr,
+		TotalCost:     totalCostStr,
+		ShowCost:      m.showCost,
+		BudgetStatus:  budgetStatus,
+		BudgetPercent: budgetPercent,
+		BudgetAlert:   budgetAlert,
+		ElapsedTime:   elapsedStr,
+	}
+
+	keybindings := m.keybindings()
 
 	var statusMsg string
 	var statusColor lipgloss.TerminalColor
 	if m.statusMsg != "" && time.Now().Before(m.statusExpiry) {
 		statusMsg = m.statusMsg
 		statusColor = layout.StatusColor("running")
+	} else if m.saveErr != nil {
+		statusMsg = fmt.Sprintf("Progress not saved: %v", m.saveErr)
+		statusColor = layout.StatusColor("failed")
+	} else if m.attachedStatus != "" {
+		statusMsg = m.attachedStatus
+		statusColor = layout.StatusColor("running")
 	}
 
 	footerData := layout.FooterData{
 		Keybindings: keybindings,
 		StatusMsg:   statusMsg,
 		StatusColor: statusColor,
+		Unsaved:     m.hasUnsavedProgress(),
 	}
 
 	leftContent := m.renderFeatureList()
@@ -1257,6 +1919,10 @@ func (m Model) renderMainView() string {
 		output = m.helpModal.Render(output)
 	}
 
+	if m.depsModal.IsVisible() {
+		output = m.depsModal.Render(output)
+	}
+
 	if m.confirmDialog.IsVisible() {
 		output = m.confirmDialog.Render(output)
 	}
@@ -1293,6 +1959,11 @@ func (m Model) buildTaskItems() []layout.TaskItem {
 		model := ""
 		modelChanged := false
 		elapsedTime := ""
+		idle := ""
+		progress := ""
+		var costValue float64
+		var elapsed time.Duration
+		actionCount := 0
 
 		if m.state != nil {
 			if fs := m.state.GetFeature(id); fs != nil {
@@ -1300,19 +1971,26 @@ func (m Model) buildTaskItems() []layout.TaskItem {
 				attempts = fs.Attempts
 				model = fs.CurrentModel
 				modelChanged = len(fs.ModelSwitches) > 1
+				costValue = fs.EstimatedCost
 			}
-			if elapsed := m.state.GetFeatureElapsed(id); elapsed > 0 {
+			if elapsed = m.state.GetFeatureElapsed(id); elapsed > 0 {
 				elapsedTime = formatDuration(elapsed)
 			}
 		}
 		if inst := m.manager.GetInstance(id); inst != nil {
 			summary := inst.GetActionSummary()
 			actionSummary = summary.String()
+			actionCount = inst.GetActionCount()
+			if pct := inst.GetProgressPercent(); pct >= 0 && status == "running" {
+				progress = fmt.Sprintf("%d%%", pct)
+			}
+			idle = idleLabel(inst.IdleFor(time.Now()), m.idleThreshold())
 			u := inst.GetUsage()
 			tokenUsage = u.Compact()
 			estimatedCost := inst.GetEstimatedCost()
 			if estimatedCost > 0 {
 				cost = usage.FormatCost(estimatedCost)
+				costValue = estimatedCost
 			}
 			if inst.HasBudget() {
 				pct, atThreshold, _ := inst.CheckBudget()
@@ -1333,6 +2011,22 @@ func (m Model) buildTaskItems() []layout.TaskItem {
 			}
 		}
 
+		if status == "pending" {
+			if f := m.findFeature(id); f != nil && f.Disabled {
+				status = "disabled"
+			} else if m.isBlocked(id) {
+				status = "blocked"
+			}
+		}
+
+		// Show the model picked with 'm' until the feature starts
+		if m.modelOverrides[id] && status == "pending" {
+			if f := m.findFeature(id); f != nil {
+				model = f.Model
+				modelChanged = true
+			}
+		}
+
 		children := childrenByParent[id]
 		hasChildren := len(children) > 0
 
@@ -1342,6 +2036,7 @@ func (m Model) buildTaskItems() []layout.TaskItem {
 			Status:        status,
 			Attempts:      attempts,
 			ActionSummary: actionSummary,
+			Progress:      progress,
 			TokenUsage:    tokenUsage,
 			Cost:          cost,
 			BudgetStatus:  budgetStatus,
@@ -1349,6 +2044,10 @@ func (m Model) buildTaskItems() []layout.TaskItem {
 			Model:         model,
 			ModelChanged:  modelChanged,
 			ElapsedTime:   elapsedTime,
+			Idle:          idle,
+			CostValue:     costValue,
+			Elapsed:       elapsed,
+			ActionCount:   actionCount,
 			ParentID:      parentID,
 			Children:      children,
 			Depth:         depth,
@@ -1402,9 +2101,9 @@ func (m Model) renderFeatureList() string {
 }
 
 func (m Model) renderMainViewContent() string {
-	var total, completed, running, failed, pending int
+	var total, completed, running, failed, pending, skipped int
 	if m.state != nil {
-		total, completed, running, failed, pending = m.state.GetSummary()
+		total, completed, running, failed, pending, skipped = m.state.GetSummary()
 	}
 
 	// Aggregate tokens: state (completed) + manager (running)
@@ -1442,43 +2141,54 @@ func (m Model) renderMainViewContent() string {
 
 	// Check budget status
 	budgetStatus := ""
+	budgetPercent := 0.0
 	budgetAlert := false
 	if m.manager.HasGlobalBudget() {
 		budgetStatus = m.manager.GetGlobalBudgetStatus()
-		_, atThreshold, _ := m.manager.CheckGlobalBudget()
+		percent, atThreshold, _ := m.manager.CheckGlobalBudget()
+		budgetPercent = percent
 		budgetAlert = atThreshold && !m.manager.IsBudgetAcknowledged()
 	}
 
 	headerData := layout.HeaderData{
-		Version:      layout.AppName + " " + layout.AppVersion,
-		Title:        "Feature Builder",
-		AutoMode:     m.autoMode,
-		Total:        total,
-		Completed:    completed,
-		Running:      running,
-		Failed:       failed,
-		Pending:      pending,
-		TokenUsage:   tokenUsageStr,
-		TotalCost:    totalCostStr,
-		ShowCost:     m.showCost,
-		BudgetStatus: budgetStatus,
-		BudgetAlert:  budgetAlert,
-		ElapsedTime:  elapsedStr,
-	}
-
-	keybindings := "s: start • S: start all • r: retry • R: reset • x: stop • X: stop all • ?: help • q: quit"
+		Version:       layout.AppName + " " + layout.AppVersion,
+		Title:         "Feature Builder",
+		AutoMode:      m.autoMode,
+		Total:         total,
+		Completed:     completed,
+		Running:       running,
+		Failed:        failed,
+		Pending:       pending,
+		Skipped:       skipped,
+		TokenUsage:    tokenUsageStr,
+		TotalCost:     totalCostStr,
+		ShowCost:      m.showCost,
+		BudgetStatus:  budgetStatus,
+		BudgetPercent: budgetPercent,
+		BudgetAlert:   budgetAlert,
+		ElapsedTime:   elapsedStr,
+	}
+
+	keybindings := m.keybindings()
 
 	var statusMsg string
 	var statusColor lipgloss.TerminalColor
 	if m.statusMsg != "" && time.Now().Before(m.statusExpiry) {
 		statusMsg = m.statusMsg
 		statusColor = layout.StatusColor("running")
+	} else if m.saveErr != nil {
+		statusMsg = fmt.Sprintf("Progress not saved: %v", m.saveErr)
+		statusColor = layout.StatusColor("failed")
+	} else if m.attachedStatus != "" {
+		statusMsg = m.attachedStatus
+		statusColor = layout.StatusColor("running")
 	}
 
 	footerData := layout.FooterData{
 		Keybindings: keybindings,
 		StatusMsg:   statusMsg,
 		StatusColor: statusColor,
+		Unsaved:     m.hasUnsavedProgress(),
 	}
 
 	leftContent := m.renderFeatureList()
@@ -1487,24 +2197,55 @@ func (m Model) renderMainViewContent() string {
 	return m.layout.Render(headerData, footerData, content)
 }
 
+// keybindings returns the key hints shown in the footer
+func (m Model) keybindings() string {
+	if m.attached {
+		return "read-only • enter: inspect • i: dependencies • ?: help • q: detach"
+	}
+	return "s: start • S: start all • r: retry • R: reset • x: stop • X: stop all • ?: help • q: quit"
+}
+
 func (m Model) renderInspectView() string {
 	var featureTitle string
 	var featureStatus string
+	var criteria []string
+	var tasks []layout.ModalTask
 	for _, f := range m.prd.Features {
 		if f.ID == m.inspecting {
 			featureTitle = f.Title
 			featureStatus = m.getFeatureStatus(f.ID)
+			criteria = m.acceptanceCriteria(f)
+			for _, task := range m.featureTasks(f) {
+				tasks = append(tasks, layout.ModalTask{Description: task.Description, Completed: task.Completed})
+			}
 			break
 		}
 	}
 
 	m.modal.SetTitle(featureTitle)
 	m.modal.SetStatus(featureStatus)
+	skipReason := ""
+	if featureStatus == "skipped" {
+		skipReason = m.state.GetSkipReason(m.inspecting)
+		if skipReason == "" {
+			skipReason = "no reason recorded"
+		}
+	}
+	m.modal.SetSkipReason(skipReason)
+	notes := ""
+	if m.manifest != nil {
+		notes = m.manifest.GetFeatureNotes(m.inspecting)
+	}
+	m.modal.SetNotes(notes)
+	m.modal.SetAcceptanceCriteria(criteria)
+	m.modal.SetTasks(tasks)
 
 	var testSummary string
-	var usageSummary string
+	var usageParts []string
 	var output string
 	var actionTimeline string
+	linesAdded, linesRemoved := m.state.GetLineChanges(m.inspecting)
+	cliDuration, turns := m.state.GetResultStats(m.inspecting)
 	if inst := m.manager.GetInstance(m.inspecting); inst != nil {
 		testResults := inst.GetTestResults()
 		if testResults.Total > 0 {
@@ -1517,39 +2258,275 @@ func (m Model) renderInspectView() string {
 		}
 		usage := inst.GetUsage()
 		if !usage.IsEmpty() {
-			usageSummary = lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Render("Tokens: " + usage.Detailed())
+			usageParts = append(usageParts, "Tokens: "+usage.Detailed())
+		}
+		linesAdded, linesRemoved = inst.GetLineChanges()
+		if d, n := inst.GetResultStats(); d > 0 || n > 0 {
+			cliDuration, turns = d, n
+		}
+		if m.modal.ShowingFullContent() {
+			output = inst.GetFullOutput()
+		} else {
+			output = inst.GetOutput()
 		}
-		output = inst.GetOutput()
 		if output == "" {
 			output = "Waiting for output..."
 		}
 		actionTimeline = actions.FormatTimeline(inst.GetActions())
+	} else if m.attached {
+		output = m.attachedOutput
+		if output == "" {
+			output = "No output yet."
+		}
 	} else {
 		output = "No output yet. Press 's' to start this feature."
 	}
 
+	if linesAdded > 0 || linesRemoved > 0 {
+		usageParts = append(usageParts, "Lines: "+actions.FormatLineChanges(linesAdded, linesRemoved))
+	}
+	if stats := formatResultStats(cliDuration, turns); stats != "" {
+		usageParts = append(usageParts, stats)
+	}
+	usageSummary := ""
+	if len(usageParts) > 0 {
+		usageSummary = lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Render(strings.Join(usageParts, "  "))
+	}
+
 	m.modal.SetTestSummary(testSummary)
 	m.modal.SetUsageSummary(usageSummary)
 
 	// Set adjustment summary if any adjustments were made
 	adjustmentSummary := m.state.GetAdjustmentSummary(m.inspecting)
 	m.modal.SetAdjustmentSummary(adjustmentSummary)
+	m.modal.SetErrorHistory(formatErrorHistory(m.state.GetErrorHistory(m.inspecting)))
+	m.modal.SetModelSwitches(formatModelSwitches(m.state.GetModelSwitches(m.inspecting)))
 
 	m.modal.SetContent(output)
 	m.modal.SetActionTimeline(actionTimeline)
-	m.modal.SetScrollOffset(m.scrollOffset)
-	m.modal.SetAutoScroll(m.autoScroll)
+	m.modal.SetScrollOffset(m.scroll.Offset)
+	m.modal.SetAutoScroll(m.scroll.Follow)
 
 	background := m.renderMainViewContent()
 	return m.modal.Render(background)
 }
 
+// featureSpec returns the feature's own section of its description, parsed.
+// In manifest mode the description holds the feature.md, whose tasks and
+// criteria aren't otherwise parsed.
+func featureSpec(feature parser.Feature) (parser.Feature, bool) {
+	if feature.Description == "" {
+		return parser.Feature{}, false
+	}
+	prd, err := parser.ParsePRDContent(feature.Description)
+	if err != nil || len(prd.Features) == 0 {
+		return parser.Feature{}, false
+	}
+	// The feature.md's own section comes after the global context
+	spec := prd.Features[len(prd.Features)-1]
+	for _, f := range prd.Features {
+		if f.Title == feature.Title {
+			spec = f
+			break
+		}
+	}
+	return spec, true
+}
+
+// featureSpecs parses the description of each feature once, on load, rather
+// than on every render of the inspect view
+func featureSpecs(features []parser.Feature) map[string]parser.Feature {
+	specs := make(map[string]parser.Feature)
+	for _, f := range features {
+		if spec, ok := featureSpec(f); ok {
+			specs[f.ID] = spec
+		}
+	}
+	return specs
+}
+
+// featureTasks returns a feature's tasks, checked off as progress records.
+// In manifest mode they are only in the feature.md, parsed on load.
+func (m Model) featureTasks(feature parser.Feature) []parser.Task {
+	if len(feature.Tasks) == 0 {
+		feature.Tasks = m.specs[feature.ID].Tasks
+	}
+	return m.withTaskStates(feature).Tasks
+}
+
+// acceptanceCriteria returns a feature's Acceptance: criteria. In manifest
+// mode they are only in the feature.md, parsed on load.
+func (m Model) acceptanceCriteria(feature parser.Feature) []string {
+	criteria := feature.AcceptanceCriteria
+	if len(criteria) == 0 {
+		criteria = m.specs[feature.ID].AcceptanceCriteria
+	}
+
+	var result []string
+	for _, c := range criteria {
+		if c = strings.TrimSpace(c); c != "" {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// defaultIdleWarning is how long a running feature can go without output
+// before the task list flags it, unless Options.IdleWarning says otherwise
+const defaultIdleWarning = 2 * time.Minute
+
+// idleThreshold returns how long a running feature can go without output
+// before it is flagged as idle, or 0 if it never is
+func (m Model) idleThreshold() time.Duration {
+	switch {
+	case m.idleWarning < 0:
+		return 0
+	case m.idleWarning == 0:
+		return defaultIdleWarning
+	}
+	return m.idleWarning
+}
+
+// idleLabel flags a feature that has gone idle for at least threshold, e.g.
+// "idle 2m", or returns "" if it hasn't or threshold is 0. Idle times are
+// shown in whole minutes once they reach one.
+func idleLabel(idle, threshold time.Duration) string {
+	if threshold <= 0 || idle < threshold {
+		return ""
+	}
+	if idle < time.Minute {
+		return "idle " + formatDuration(idle)
+	}
+	idle = idle.Truncate(time.Minute)
+	if idle >= time.Hour {
+		return fmt.Sprintf("idle %dh%dm", idle/time.Hour, idle%time.Hour/time.Minute)
+	}
+	return fmt.Sprintf("idle %dm", idle/time.Minute)
+}
+
+// formatResultStats describes the run time and turn count claude reported,
+// e.g. "Claude: 2m5s, 14 turns", or "" when it reported neither
+func formatResultStats(duration time.Duration, turns int) string {
+	var parts []string
+	if duration > 0 {
+		parts = append(parts, formatDuration(duration))
+	}
+	switch {
+	case turns == 1:
+		parts = append(parts, "1 turn")
+	case turns > 1:
+		parts = append(parts, fmt.Sprintf("%d turns", turns))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "Claude: " + strings.Join(parts, ", ")
+}
+
+// formatErrorHistory returns one line per failed attempt for the inspect view
+func formatErrorHistory(history []state.AttemptError) []string {
+	entries := make([]string, 0, len(history))
+	for _, record := range history {
+		entry := fmt.Sprintf("Attempt %d", record.Attempt)
+		if record.TestsFailed > 0 {
+			entry += fmt.Sprintf(" (%d tests failed)", record.TestsFailed)
+		}
+		entry += fmt.Sprintf(" at %s: %s", record.Timestamp.Format("15:04:05"), record.Error)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// initialSelectionDetails describes an auto model feature's first model when
+// the selector can't explain it
+const initialSelectionDetails = "auto mode initial selection"
+
+// formatModelSwitches returns a line per auto model switch, each followed by
+// an indented line with its evidence when recorded. The initial pick is
+// included when it was recorded with the selector's inputs.
+func formatModelSwitches(switches []state.ModelSwitchState) []string {
+	var lines []string
+	for _, sw := range switches {
+		if sw.FromModel == "" {
+			// The initial selection, shown when it was recorded with the
+			// selector's inputs
+			if sw.Details != "" && sw.Details != initialSelectionDetails {
+				lines = append(lines, fmt.Sprintf("%s started on %s: %s", sw.Timestamp.Format("15:04:05"), sw.ToModel, sw.Details))
+			}
+			continue
+		}
+		line := fmt.Sprintf("%s %s → %s (%s)", sw.Timestamp.Format("15:04:05"), sw.FromModel, sw.ToModel, sw.Reason)
+		if sw.Details != "" {
+			line += ": " + sw.Details
+		}
+		lines = append(lines, line)
+		if sw.Evidence != "" {
+			lines = append(lines, "  "+sw.Evidence)
+		}
+	}
+	return lines
+}
+
 func deleteProgressMD(workDir string) {
 	path := filepath.Join(workDir, "progress.md")
 	os.Remove(path)
 }
 
-func Run(prdPath string) error {
+// Options configures the TUI session
+type Options struct {
+	// LogPrompts writes the full prompt of every attempt to .ralph/prompts/
+	LogPrompts bool
+	// CheckoutBase checks out a feature's Base revision before it starts
+	CheckoutBase bool
+	// ResumeOnRetry makes 'r' continue a feature's last claude session with
+	// --resume instead of starting cold
+	ResumeOnRetry bool
+	// FollowTolerance is how many lines short of the bottom scrolling down in
+	// the inspect view resumes following output (0 = default, negative = only
+	// at the bottom itself)
+	FollowTolerance int
+	// IdleWarning is how long a running feature can go without output before
+	// the task list flags it as idle (0 = default, negative = never)
+	IdleWarning time.Duration
+	// AutoResponses answer approval prompts a tool prints by writing to
+	// claude's stdin (empty = claude gets no stdin)
+	AutoResponses []runner.AutoResponse
+	// VerifyCommand runs after a feature exits cleanly and fails the feature
+	// if it fails (empty = no verification)
+	VerifyCommand string
+	// RunMeta tags the run with key/value pairs, recorded in progress.json
+	RunMeta map[string]string
+	// ClipLimits are how much of each output line the inspect view shows
+	// until 'F' shows it in full (zero = defaults)
+	ClipLimits runner.ClipLimits
+	// ScanBufferSize is the longest output line read, in bytes; longer lines
+	// are skipped (0 = runner.DefaultScanBufferSize)
+	ScanBufferSize int
+	// MaxSpawnsPerRoot and MaxTotalSpawns cap the sub-features spawned under
+	// one root feature and across the run (0 = rlm defaults, negative = no cap)
+	MaxSpawnsPerRoot int
+	MaxTotalSpawns   int
+}
+
+// applySpawnLimits sets the spawn caps from opts on the spawn handler
+func applySpawnLimits(h *rlm.SpawnHandler, opts Options) {
+	h.SetMaxSpawns(spawnLimit(opts.MaxSpawnsPerRoot, rlm.DefaultMaxSpawnsPerRoot),
+		spawnLimit(opts.MaxTotalSpawns, rlm.DefaultMaxTotalSpawns))
+}
+
+// spawnLimit turns an Options spawn cap into the handler's, where 0 means
+// no cap
+func spawnLimit(n, def int) int {
+	switch {
+	case n == 0:
+		return def
+	case n < 0:
+		return 0
+	}
+	return n
+}
+
+func Run(prdPath string, opts Options) error {
 	workDir := filepath.Dir(prdPath)
 	if err := logger.Init(workDir); err != nil {
 		return fmt.Errorf("failed to init logger: %w", err)
@@ -1558,14 +2535,29 @@ func Run(prdPath string) error {
 
 	logger.Info("tui", "Starting ralph", "prd", prdPath)
 
-	p := tea.NewProgram(initialModel(prdPath), tea.WithAltScreen())
-	_, err := p.Run()
+	model := initialModel(prdPath)
+	model.manager.SetPromptLogging(opts.LogPrompts)
+	model.manager.SetCheckoutBase(opts.CheckoutBase)
+	model.manager.SetAutoResponses(opts.AutoResponses)
+	model.manager.SetVerifyCommand(opts.VerifyCommand)
+	model.manager.SetClipLimits(opts.ClipLimits)
+	model.manager.SetScanBufferSize(opts.ScanBufferSize)
+	applySpawnLimits(model.spawnHandler, opts)
+	model.resumeOnRetry = opts.ResumeOnRetry
+	model.scroll.Tolerance = opts.FollowTolerance
+	model.idleWarning = opts.IdleWarning
+	model.runMeta = opts.RunMeta
+	p := tea.NewProgram(model, tea.WithAltScreen())
+	final, err := p.Run()
+	if err == nil {
+		err = finalSaveError(final)
+	}
 
 	logger.Info("tui", "Ralph exiting", "error", err)
 	return err
 }
 
-func RunWithManifest(prdDir string) error {
+func RunWithManifest(prdDir string, opts Options) error {
 	workDir := filepath.Dir(prdDir)
 	if err := logger.Init(workDir); err != nil {
 		return fmt.Errorf("failed to init logger: %w", err)
@@ -1574,13 +2566,37 @@ func RunWithManifest(prdDir string) error {
 
 	logger.Info("tui", "Starting ralph in manifest mode", "prdDir", prdDir)
 
-	p := tea.NewProgram(initialModelForManifest(prdDir), tea.WithAltScreen())
-	_, err := p.Run()
+	model := initialModelForManifest(prdDir)
+	model.manager.SetPromptLogging(opts.LogPrompts)
+	model.manager.SetCheckoutBase(opts.CheckoutBase)
+	model.manager.SetAutoResponses(opts.AutoResponses)
+	model.manager.SetVerifyCommand(opts.VerifyCommand)
+	model.manager.SetClipLimits(opts.ClipLimits)
+	model.manager.SetScanBufferSize(opts.ScanBufferSize)
+	applySpawnLimits(model.spawnHandler, opts)
+	model.resumeOnRetry = opts.ResumeOnRetry
+	model.scroll.Tolerance = opts.FollowTolerance
+	model.idleWarning = opts.IdleWarning
+	model.runMeta = opts.RunMeta
+	p := tea.NewProgram(model, tea.WithAltScreen())
+	final, err := p.Run()
+	if err == nil {
+		err = finalSaveError(final)
+	}
 
 	logger.Info("tui", "Ralph exiting", "error", err)
 	return err
 }
 
+// finalSaveError reports a progress save failure that was still unresolved
+// when the TUI exited, so it isn't lost with the alt screen
+func finalSaveError(final tea.Model) error {
+	if fm, ok := final.(Model); ok && fm.saveErr != nil {
+		return fmt.Errorf("progress was not saved: %w", fm.saveErr)
+	}
+	return nil
+}
+
 func initialModelForManifest(prdDir string) Model {
 	workDir := filepath.Dir(prdDir)
 	actLog := layout.NewActivityLog()
@@ -1599,13 +2615,14 @@ func initialModelForManifest(prdDir string) Model {
 		childExecutor: childExec,
 		escalationMgr: escalationMgr,
 		retryStrategy: retryStrat,
-		layout:        layout.New(),
+		layout:        newLayout(),
 		splitPane:     layout.NewSplitPane(),
 		taskList:      layout.NewTaskList(),
 		activityLog:   actLog,
 		activityPane:  layout.NewActivityPane(actLog),
 		modal:         layout.NewModal(),
 		helpModal:     layout.NewHelpModal(),
+		depsModal:     layout.NewTextModal(),
 		confirmDialog: layout.NewConfirmDialog(),
 		currentView:   viewMain,
 		childResults:  make(map[string][]string),
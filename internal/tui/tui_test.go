@@ -0,0 +1,1108 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/vx/ralph-go/internal/manifest"
+	"github.com/vx/ralph-go/internal/parser"
+	"github.com/vx/ralph-go/internal/rlm"
+	"github.com/vx/ralph-go/internal/runner"
+	"github.com/vx/ralph-go/internal/state"
+	"github.com/vx/ralph-go/internal/tui/layout"
+)
+
+func TestSaveStateReportsFailure(t *testing.T) {
+	dir := t.TempDir()
+	// A regular file where a directory is expected makes the write fail
+	blocker := filepath.Join(dir, "blocker")
+	os.WriteFile(blocker, []byte("x"), 0644)
+
+	m := initialModel("test.md")
+	m.state = mockState()
+	m.state.SetPathDirect(filepath.Join(blocker, "progress.json"))
+
+	m.saveState()
+
+	if m.saveErr == nil {
+		t.Fatal("expected save error to be recorded")
+	}
+	if !strings.Contains(m.statusMsg, "progress not saved") {
+		t.Errorf("expected status to report save failure, got %q", m.statusMsg)
+	}
+	if err := finalSaveError(m); err == nil {
+		t.Error("expected unresolved save failure to be returned on exit")
+	}
+
+	m.state.SetPathDirect(filepath.Join(dir, "progress.json"))
+	m.saveState()
+
+	if m.saveErr != nil {
+		t.Errorf("expected save error to clear after successful save, got %v", m.saveErr)
+	}
+	if err := finalSaveError(m); err != nil {
+		t.Errorf("expected no exit error after recovery, got %v", err)
+	}
+}
+
+func TestUnsavedProgressIndicator(t *testing.T) {
+	dir := t.TempDir()
+	m := initialModel("test.md")
+	m.state = mockState()
+	m.state.SetPathDirect(filepath.Join(dir, "progress.json"))
+
+	m.state.UpdateFeature("01", "running")
+	if !m.hasUnsavedProgress() {
+		t.Fatal("expected unsaved progress after a change")
+	}
+	footer := layout.NewFooter()
+	footer.SetWidth(120)
+	if out := footer.Render(layout.FooterData{Unsaved: m.hasUnsavedProgress()}); !strings.Contains(out, layout.UnsavedIndicator) {
+		t.Errorf("expected footer to show the unsaved indicator, got %q", out)
+	}
+
+	m.saveState()
+	if m.hasUnsavedProgress() {
+		t.Error("expected no unsaved progress after a successful save")
+	}
+}
+
+func TestCheckStaleOffersResetWhenPRDChanged(t *testing.T) {
+	m := initialModel("test.md")
+	m.prd = mockPRD()
+	m.state = mockState()
+	m.state.PRDHash = "recorded-against-older-prd"
+	m.state.UpdateFeature("test-feature-1", "completed")
+
+	m.checkStale()
+
+	if !m.state.IsStale() {
+		t.Error("expected state to be flagged stale")
+	}
+	if !m.confirmDialog.IsVisible() || m.confirmDialog.Type() != layout.ConfirmTypeStale {
+		t.Error("expected stale confirm dialog to be shown")
+	}
+}
+
+func TestCheckStaleSilentWhenPRDUnchanged(t *testing.T) {
+	m := initialModel("test.md")
+	m.prd = mockPRD()
+	m.state = mockState()
+	m.state.PRDHash = m.prd.Hash()
+	m.state.UpdateFeature("test-feature-1", "completed")
+
+	m.checkStale()
+
+	if m.state.IsStale() {
+		t.Error("expected state not to be stale")
+	}
+	if m.confirmDialog.IsVisible() {
+		t.Error("expected no dialog when PRD is unchanged")
+	}
+}
+
+func TestStateLoadFlagsInterruptedFeatures(t *testing.T) {
+	progress := mockState()
+	progress.UpdateFeature("test-feature-1", "running")
+
+	var model tea.Model = initialModel(filepath.Join(t.TempDir(), "PRD.md"))
+	model, _ = model.Update(stateLoadedMsg{state: progress})
+	m := model.(Model)
+
+	if status := m.state.GetFeature("test-feature-1").Status; status != "interrupted" {
+		t.Errorf("expected running feature without an instance to be interrupted, got %q", status)
+	}
+	if !m.confirmDialog.IsVisible() || m.confirmDialog.Type() != layout.ConfirmTypeInterrupted {
+		t.Fatal("expected interrupted confirm dialog to be shown")
+	}
+
+	newModel, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = newModel.(Model)
+	if status := m.state.GetFeature("test-feature-1").Status; status != "pending" {
+		t.Errorf("expected confirming to reset the feature to pending, got %q", status)
+	}
+}
+
+func TestInterruptedDialogWaitsForStaleDialog(t *testing.T) {
+	m := initialModel(filepath.Join(t.TempDir(), "PRD.md"))
+	m.prd = mockPRD()
+	m.state = mockState()
+	m.state.PRDHash = "recorded-against-older-prd"
+	m.state.UpdateFeature("test-feature-1", "running")
+
+	m.checkStale()
+	m.checkInterrupted()
+	if m.confirmDialog.Type() != layout.ConfirmTypeStale {
+		t.Fatalf("expected the stale dialog first, got %v", m.confirmDialog.Type())
+	}
+
+	newModel, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	m = newModel.(Model)
+	if !m.confirmDialog.IsVisible() || m.confirmDialog.Type() != layout.ConfirmTypeInterrupted {
+		t.Fatal("expected the interrupted dialog once the stale one was answered")
+	}
+
+	newModel, _ = m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	m = newModel.(Model)
+	if status := m.state.GetFeature("test-feature-1").Status; status != "pending" {
+		t.Errorf("expected the interrupted feature reset to pending, got %q", status)
+	}
+	if m.confirmDialog.IsVisible() {
+		t.Error("expected no dialog left to answer")
+	}
+}
+
+func TestRunConfigFromPRDReachesManager(t *testing.T) {
+	prd := mockPRD()
+	prd.MaxConcurrent = 5
+	prd.MaxRetries = 1
+
+	orders := map[string][]tea.Msg{
+		"prd first":   {prdLoadedMsg{prd: prd}, stateLoadedMsg{state: mockState()}},
+		"state first": {stateLoadedMsg{state: mockState()}, prdLoadedMsg{prd: prd}},
+	}
+	for name, msgs := range orders {
+		t.Run(name, func(t *testing.T) {
+			var model tea.Model = initialModel(filepath.Join(t.TempDir(), "PRD.md"))
+			for _, msg := range msgs {
+				model, _ = model.Update(msg)
+			}
+			m := model.(Model)
+
+			cfg := m.manager.GetConfig()
+			if cfg.MaxConcurrent != 5 || cfg.MaxRetries != 1 {
+				t.Errorf("expected manager config concurrent=5 retries=1, got %+v", cfg)
+			}
+			if m.state.Config.MaxConcurrent != 5 || m.state.Config.MaxRetries != 1 {
+				t.Errorf("expected state config to follow PRD, got %+v", m.state.Config)
+			}
+		})
+	}
+}
+
+func TestRunConfigKeepsStateWithoutPRDSettings(t *testing.T) {
+	var model tea.Model = initialModel(filepath.Join(t.TempDir(), "PRD.md"))
+	model, _ = model.Update(prdLoadedMsg{prd: mockPRD()})
+	model, _ = model.Update(stateLoadedMsg{state: mockState()})
+	m := model.(Model)
+
+	cfg := m.manager.GetConfig()
+	if cfg.MaxConcurrent != m.state.Config.MaxConcurrent || cfg.MaxRetries != m.state.Config.MaxRetries {
+		t.Errorf("expected manager config to match state %+v, got %+v", m.state.Config, cfg)
+	}
+}
+
+func TestNextModelCycle(t *testing.T) {
+	tests := []struct {
+		current  string
+		expected string
+	}{
+		{"haiku", "sonnet"},
+		{"sonnet", "opus"},
+		{"opus", "auto"},
+		{"auto", "haiku"},
+		{"", "opus"},
+		{"unknown", "haiku"},
+	}
+
+	for _, tt := range tests {
+		if got := nextModel(tt.current); got != tt.expected {
+			t.Errorf("nextModel(%q) = %q, want %q", tt.current, got, tt.expected)
+		}
+	}
+}
+
+func TestCycleModelPendingOnly(t *testing.T) {
+	m := initialModel("test.md")
+	m.prd = mockPRD()
+	m.prd.Features[0].Model = "haiku"
+	m.prd.Features = append(m.prd.Features, parser.Feature{ID: "test-feature-2", Title: "Test Feature 2", Model: "haiku"})
+	m.state = mockState()
+	m.state.InitFeature("test-feature-2", "Test Feature 2")
+	m.state.UpdateFeature("test-feature-2", "running")
+
+	m.cycleModel("test-feature-1")
+	m.cycleModel("test-feature-2")
+
+	if m.prd.Features[0].Model != "sonnet" {
+		t.Errorf("expected pending feature to move to sonnet, got %q", m.prd.Features[0].Model)
+	}
+	if m.prd.Features[1].Model != "haiku" {
+		t.Errorf("expected running feature to keep its model, got %q", m.prd.Features[1].Model)
+	}
+
+	items := m.buildTaskItems()
+	if items[0].Model != "sonnet" || !items[0].ModelChanged {
+		t.Errorf("expected task list to show overridden model, got %q (changed=%v)", items[0].Model, items[0].ModelChanged)
+	}
+}
+
+func TestLegendToggle(t *testing.T) {
+	var model tea.Model = initialModel("test.md")
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m := model.(Model)
+
+	if !m.layout.ShowLegend() {
+		t.Fatal("Legend should be shown by default")
+	}
+	withLegend := m.splitPane.ContentHeight()
+
+	newModel, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+	m = newModel.(Model)
+	if m.layout.ShowLegend() {
+		t.Error("Legend should be hidden after pressing l")
+	}
+	if m.splitPane.ContentHeight() != withLegend+1 {
+		t.Errorf("Expected panes to grow by one line when the legend is hidden, got %d -> %d",
+			withLegend, m.splitPane.ContentHeight())
+	}
+}
+
+func TestSortCycleKey(t *testing.T) {
+	var model tea.Model = initialModel("test.md")
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m := model.(Model)
+
+	if m.taskList.SortMode() != layout.SortDefault {
+		t.Fatalf("Expected PRD order by default, got %s", m.taskList.SortMode())
+	}
+
+	newModel, _ := m.handleKeyPress(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	m = newModel.(Model)
+	if m.taskList.SortMode() != layout.SortStatus {
+		t.Errorf("Expected status sort after pressing o, got %s", m.taskList.SortMode())
+	}
+	if m.statusMsg != "Sort: status" {
+		t.Errorf("Expected sort status message, got %q", m.statusMsg)
+	}
+}
+
+func TestSpawnedChildPersistsToManifest(t *testing.T) {
+	prdDir := filepath.Join(t.TempDir(), "PRD")
+	os.MkdirAll(prdDir, 0755)
+	mf := manifest.New("PRD.md", "Spawn Test")
+	mf.Features = append(mf.Features, manifest.ManifestFeature{
+		ID: "01", Dir: "01-root", Title: "Root", Status: "running",
+	})
+	mf.SetPath(filepath.Join(prdDir, "manifest.json"))
+	if err := mf.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := initialModelForManifest(prdDir)
+	m.state = state.NewProgress()
+	m.state.SetPathDirect(filepath.Join(prdDir, "progress.json"))
+	updated, _ := m.Update(loadManifest(prdDir)())
+	m = updated.(Model)
+	m.spawnHandler.RegisterRootFeature("01", "Root")
+	m.spawnHandler.SetFeatureRunning("01")
+
+	updated, _ = m.handleSpawnRequest(spawnRequestMsg{
+		parentID: "01",
+		request:  &rlm.SpawnRequest{Title: "Write tests"},
+	})
+	m = updated.(Model)
+	children := m.state.GetChildFeatures("01")
+	if len(children) != 1 {
+		t.Fatalf("expected one child in progress, got %v", children)
+	}
+	childID := children[0]
+
+	reloaded, err := manifest.Load(prdDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	child := reloaded.GetFeature(childID)
+	if child == nil {
+		t.Fatalf("expected child %s in reloaded manifest", childID)
+	}
+	if child.ParentID != "01" || child.Depth != 1 {
+		t.Errorf("expected child of 01 at depth 1, got parent %q depth %d", child.ParentID, child.Depth)
+	}
+	if parent := reloaded.GetFeature("01"); len(parent.Children) != 1 || parent.Children[0] != childID {
+		t.Errorf("expected parent to list child, got %v", parent.Children)
+	}
+
+	// A fresh TUI keeps the child out of the root features but restores it
+	// under its parent
+	restarted := initialModelForManifest(prdDir)
+	restarted.state = state.NewProgress()
+	updated, _ = restarted.Update(loadManifest(prdDir)())
+	restarted = updated.(Model)
+	if len(restarted.prd.Features) != 1 {
+		t.Errorf("expected only the root feature in the PRD, got %d", len(restarted.prd.Features))
+	}
+	if got := restarted.state.GetFeatureParent(childID); got != "01" {
+		t.Errorf("expected restored child parent 01, got %q", got)
+	}
+}
+
+func TestSpawnedChildrenQueueAtParentLimit(t *testing.T) {
+	prdDir := filepath.Join(t.TempDir(), "PRD")
+	os.MkdirAll(prdDir, 0755)
+	mf := manifest.New("PRD.md", "Spawn Test")
+	mf.Features = append(mf.Features, manifest.ManifestFeature{
+		ID: "01", Dir: "01-root", Title: "Root", Status: "running", MaxChildrenConcurrent: 2,
+	})
+	mf.SetPath(filepath.Join(prdDir, "manifest.json"))
+	if err := mf.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := initialModelForManifest(prdDir)
+	m.state = state.NewProgress()
+	m.state.SetPathDirect(filepath.Join(prdDir, "progress.json"))
+	updated, _ := m.Update(loadManifest(prdDir)())
+	m = updated.(Model)
+	m.spawnHandler.RegisterRootFeature("01", "Root")
+	m.spawnHandler.SetFeatureRunning("01")
+
+	var cmds []tea.Cmd
+	for _, title := range []string{"Models", "Handlers", "Tests"} {
+		updated, cmd := m.handleSpawnRequest(spawnRequestMsg{
+			parentID: "01",
+			request:  &rlm.SpawnRequest{Title: title},
+		})
+		m = updated.(Model)
+		cmds = append(cmds, cmd)
+	}
+	if cmds[0] == nil || cmds[1] == nil {
+		t.Fatal("expected the first two children to start")
+	}
+	if cmds[2] != nil {
+		t.Error("expected the third child to wait for a free slot")
+	}
+	running := m.childExecutor.GetRunningChildren("01")
+	queued := m.childExecutor.GetQueuedChildren("01")
+	if len(running) != 2 || len(queued) != 1 {
+		t.Fatalf("expected 2 running and 1 queued, got %v and %v", running, queued)
+	}
+	if got := m.state.GetFeature(queued[0]).Status; got != "pending" {
+		t.Errorf("expected the queued child to stay pending, got %q", got)
+	}
+
+	if cmd := m.startQueuedChild(running[0]); cmd == nil {
+		t.Error("expected the queued child to start once a running one finished")
+	}
+	if queued := m.childExecutor.GetQueuedChildren("01"); len(queued) != 0 {
+		t.Errorf("expected the queue to be empty, got %v", queued)
+	}
+}
+
+func TestInstanceStartedRecordsChildLimit(t *testing.T) {
+	m := initialModel(filepath.Join(t.TempDir(), "PRD.md"))
+	m.prd = mockPRD()
+	m.prd.Features[0].MaxChildrenConcurrent = 2
+	m.state = mockState()
+
+	updated, _ := m.Update(instanceStartedMsg{featureID: "test-feature-1"})
+	m = updated.(Model)
+	if got := m.state.GetMaxChildrenConcurrent("test-feature-1"); got != 2 {
+		t.Errorf("expected the feature's child limit recorded in progress, got %d", got)
+	}
+	if got := m.getParentChildLimit("test-feature-1"); got != 2 {
+		t.Errorf("expected a child limit of 2, got %d", got)
+	}
+}
+
+func TestSkippedFeatureShowsInListAndInspectView(t *testing.T) {
+	m := initialModel("test.md")
+	m.prd = mockPRD()
+	m.state = mockState()
+	m.state.InitFeature("test-feature-1", "Test Feature 1")
+	m.state.SkipFeature("test-feature-1", "parent failed under strict isolation")
+	m.modal.SetSize(100, 50)
+
+	items := m.buildTaskItems()
+	if len(items) != 1 || items[0].Status != "skipped" {
+		t.Fatalf("expected one skipped item, got %+v", items)
+	}
+
+	m.currentView = viewInspect
+	m.inspecting = "test-feature-1"
+	if view := m.renderInspectView(); !strings.Contains(view, "Skipped: parent failed under strict isolation") {
+		t.Error("expected inspect view to show the skip reason")
+	}
+}
+
+func TestEditNotesSavesToManifestAndShowsInInspectView(t *testing.T) {
+	prdDir := filepath.Join(t.TempDir(), "PRD")
+	os.MkdirAll(prdDir, 0755)
+	mf := manifest.New("PRD.md", "Notes Test")
+	mf.Features = append(mf.Features, manifest.ManifestFeature{
+		ID: "01", Dir: "01-root", Title: "Root", Status: "pending", Notes: "Old note",
+	})
+	mf.SetPath(filepath.Join(prdDir, "manifest.json"))
+	if err := mf.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	var editedPath string
+	origEditor := editorProcess
+	editorProcess = func(path string) *exec.Cmd {
+		editedPath = path
+		return exec.Command("true")
+	}
+	t.Cleanup(func() { editorProcess = origEditor })
+
+	m := initialModelForManifest(prdDir)
+	m.state = state.NewProgress()
+	updated, _ := m.Update(loadManifest(prdDir)())
+	m = updated.(Model)
+	m.modal.SetSize(100, 50)
+
+	if cmd := m.editNotes("01"); cmd == nil {
+		t.Fatal("expected an editor command")
+	}
+	data, err := os.ReadFile(editedPath)
+	if err != nil || string(data) != "Old note" {
+		t.Fatalf("expected editor to open the current notes, got %q (%v)", data, err)
+	}
+
+	// The user rewrites the notes and closes the editor
+	os.WriteFile(editedPath, []byte("Reviewed by Sam\nhttps://example.com/pr/42\n"), 0644)
+	updated, _ = m.Update(notesEditedMsg{featureID: "01", path: editedPath})
+	m = updated.(Model)
+
+	if _, err := os.Stat(editedPath); !os.IsNotExist(err) {
+		t.Error("expected the temporary notes file to be removed")
+	}
+	reloaded, err := manifest.Load(prdDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.GetFeatureNotes("01"); got != "Reviewed by Sam\nhttps://example.com/pr/42" {
+		t.Errorf("expected notes saved to the manifest, got %q", got)
+	}
+
+	m.currentView = viewInspect
+	m.inspecting = "01"
+	view := m.renderInspectView()
+	for _, want := range []string{"Notes:", "Reviewed by Sam", "https://example.com/pr/42"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected inspect view to contain %q", want)
+		}
+	}
+}
+
+func TestInspectViewShowsAcceptanceCriteria(t *testing.T) {
+	prdDir := filepath.Join(t.TempDir(), "PRD")
+	os.MkdirAll(filepath.Join(prdDir, "01-auth"), 0755)
+	os.WriteFile(filepath.Join(prdDir, "01-auth", "feature.md"), []byte(`# Shop
+
+## Overview
+
+Acceptance: not part of the feature
+
+---
+
+## Auth
+
+- [ ] Add login handler
+
+Acceptance: Login returns a session cookie
+Acceptance: Logout clears it
+`), 0644)
+	mf := manifest.New("PRD.md", "Shop")
+	mf.Features = append(mf.Features, manifest.ManifestFeature{ID: "01", Dir: "01-auth", Title: "Auth", Status: "completed"})
+	mf.SetPath(filepath.Join(prdDir, "manifest.json"))
+	if err := mf.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := initialModelForManifest(prdDir)
+	m.state = state.NewProgress()
+	updated, _ := m.Update(loadManifest(prdDir)())
+	m = updated.(Model)
+	m.modal.SetSize(100, 50)
+
+	if _, ok := m.specs["01"]; !ok {
+		t.Fatal("expected the feature.md to be parsed on load")
+	}
+	want := []string{"Login returns a session cookie", "Logout clears it"}
+	if got := m.acceptanceCriteria(*m.findFeature("01")); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("expected criteria %q, got %q", want, got)
+	}
+
+	m.currentView = viewInspect
+	m.inspecting = "01"
+	view := m.renderInspectView()
+	for _, line := range []string{"Acceptance criteria:", "☐ Login returns a session cookie", "☐ Logout clears it"} {
+		if !strings.Contains(view, line) {
+			t.Errorf("expected inspect view to contain %q", line)
+		}
+	}
+}
+
+func TestAcceptanceCriteriaFromParsedPRD(t *testing.T) {
+	f := parser.Feature{Title: "Auth", AcceptanceCriteria: []string{" Login works ", ""}}
+	if got := (Model{}).acceptanceCriteria(f); len(got) != 1 || got[0] != "Login works" {
+		t.Errorf("expected the parsed criteria trimmed, got %q", got)
+	}
+	if got := (Model{}).acceptanceCriteria(parser.Feature{Title: "Empty"}); got != nil {
+		t.Errorf("expected no criteria, got %q", got)
+	}
+}
+
+func TestEditNotesNeedsManifest(t *testing.T) {
+	m := initialModel("test.md")
+	m.prd = mockPRD()
+	m.state = mockState()
+
+	if cmd := m.editNotes("test-feature-1"); cmd != nil {
+		t.Error("expected no editor without a manifest")
+	}
+	if !strings.Contains(m.statusMsg, "manifest") {
+		t.Errorf("expected status to explain notes need a manifest, got %q", m.statusMsg)
+	}
+}
+
+func TestDependencyChainModal(t *testing.T) {
+	prdDir := filepath.Join(t.TempDir(), "PRD")
+	os.MkdirAll(prdDir, 0755)
+	mf := manifest.New("PRD.md", "Deps Test")
+	mf.Features = append(mf.Features,
+		manifest.ManifestFeature{ID: "01", Dir: "01-schema", Title: "Schema", Status: "completed"},
+		manifest.ManifestFeature{ID: "02", Dir: "02-auth", Title: "Auth", Status: "failed", DependsOn: []string{"01"}},
+		manifest.ManifestFeature{ID: "03", Dir: "03-api", Title: "API", Status: "pending", DependsOn: []string{"02"}},
+	)
+	mf.SetPath(filepath.Join(prdDir, "manifest.json"))
+	if err := mf.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := initialModelForManifest(prdDir)
+	m.state = state.NewProgress()
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	m = updated.(Model)
+	updated, _ = m.Update(loadManifest(prdDir)())
+	m = updated.(Model)
+	m.taskList.SetItems(m.buildTaskItems())
+	m.taskList.SetSelected(2)
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i")})
+	m = updated.(Model)
+	if !m.depsModal.IsVisible() {
+		t.Fatal("expected the dependency modal to open")
+	}
+	view := m.View()
+	for _, want := range []string{"03 API", "02 Auth (failed)", "01 Schema (completed)", "Waiting on 1 of 2"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("expected modal to contain %q", want)
+		}
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.depsModal.IsVisible() {
+		t.Error("expected esc to close the dependency modal")
+	}
+}
+
+func TestFormatModelSwitchesExplainsEscalation(t *testing.T) {
+	at := time.Date(2026, 1, 1, 14, 5, 0, 0, time.UTC)
+	lines := formatModelSwitches([]state.ModelSwitchState{
+		{Timestamp: at, ToModel: "haiku", Reason: "initial", Details: "auto mode initial selection"},
+		{Timestamp: at, FromModel: "haiku", ToModel: "sonnet", Reason: "multiple_errors",
+			Details: "2+ tool errors with haiku", Evidence: `2 consecutive Bash errors; last: "exit status 1"`},
+	})
+
+	want := []string{
+		"14:05:00 haiku → sonnet (multiple_errors): 2+ tool errors with haiku",
+		`  2 consecutive Bash errors; last: "exit status 1"`,
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected lines:\n%s\nwant:\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestFormatResultStats(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		turns    int
+		want     string
+	}{
+		{123456 * time.Millisecond, 14, "Claude: 2m3s, 14 turns"},
+		{5 * time.Second, 1, "Claude: 5s, 1 turn"},
+		{0, 3, "Claude: 3 turns"},
+		{0, 0, ""},
+	}
+	for _, tt := range tests {
+		if got := formatResultStats(tt.duration, tt.turns); got != tt.want {
+			t.Errorf("formatResultStats(%v, %d) = %q, want %q", tt.duration, tt.turns, got, tt.want)
+		}
+	}
+}
+
+func TestIdleLabel(t *testing.T) {
+	tests := []struct {
+		idle      time.Duration
+		threshold time.Duration
+		want      string
+	}{
+		{90 * time.Second, 2 * time.Minute, ""},
+		{2 * time.Minute, 2 * time.Minute, "idle 2m"},
+		{2*time.Minute + 59*time.Second, 2 * time.Minute, "idle 2m"},
+		{75*time.Minute + 10*time.Second, 2 * time.Minute, "idle 1h15m"},
+		{45 * time.Second, 30 * time.Second, "idle 45s"},
+		{time.Hour, 0, ""},
+	}
+	for _, tt := range tests {
+		if got := idleLabel(tt.idle, tt.threshold); got != tt.want {
+			t.Errorf("idleLabel(%v, %v) = %q, want %q", tt.idle, tt.threshold, got, tt.want)
+		}
+	}
+}
+
+func TestIdleThreshold(t *testing.T) {
+	m := Model{}
+	if got := m.idleThreshold(); got != defaultIdleWarning {
+		t.Errorf("expected the default threshold, got %v", got)
+	}
+	m.idleWarning = 5 * time.Minute
+	if got := m.idleThreshold(); got != 5*time.Minute {
+		t.Errorf("expected 5m, got %v", got)
+	}
+	m.idleWarning = -1
+	if got := m.idleThreshold(); got != 0 {
+		t.Errorf("expected warnings off, got %v", got)
+	}
+}
+
+func TestFormatModelSwitchesExplainsInitialModel(t *testing.T) {
+	at := time.Date(2026, 1, 1, 14, 5, 0, 0, time.UTC)
+	lines := formatModelSwitches([]state.ModelSwitchState{
+		{Timestamp: at, ToModel: "haiku", Reason: "initial", Details: "moderate task (<=5 tasks) - starting with haiku (leaf: no, tasks: 4, complexity: moderate)"},
+	})
+	want := "14:05:00 started on haiku: moderate task (<=5 tasks) - starting with haiku (leaf: no, tasks: 4, complexity: moderate)"
+	if len(lines) != 1 || lines[0] != want {
+		t.Errorf("unexpected lines %q", lines)
+	}
+}
+
+func TestStartFeatureCountsManifestTasksForAutoModel(t *testing.T) {
+	mgr := runner.NewManager(t.TempDir())
+	mgr.SetExecutorFactory(func(ctx context.Context, dir string, args []string) runner.Executor {
+		return &blockingExecutor{ctx: ctx}
+	})
+	defer mgr.StopAll()
+
+	// In manifest mode the tasks are only in the feature.md held in the
+	// description, and a count of 0 would always start on the leaf model
+	feature := parser.Feature{
+		ID:          "01",
+		Title:       "Search",
+		Model:       "auto",
+		Description: "## Search\n\n- [ ] Index\n- [ ] Rank\n- [ ] Paginate\n- [ ] Highlight\n",
+	}
+	msg, ok := startFeature(feature, "", t.TempDir(), mgr, 1)().(instanceStartedMsg)
+	if !ok || msg.err != nil {
+		t.Fatalf("expected the feature to start, got %+v", msg)
+	}
+	explanation, ok := msg.instance.ExplainModel()
+	if !ok {
+		t.Fatal("expected an auto model explanation")
+	}
+	if explanation.TaskCount != 4 || explanation.IsLeafTask {
+		t.Errorf("expected the feature.md's 4 tasks to be counted, got %d (leaf: %v)", explanation.TaskCount, explanation.IsLeafTask)
+	}
+}
+
+// failingExecutor stands in for a claude process whose tests fail. Like a
+// real process, Wait returns once its output has been read.
+type failingExecutor struct {
+	read sync.WaitGroup
+}
+
+func (e *failingExecutor) Pipes() (io.Reader, io.Reader, error) {
+	e.read.Add(2)
+	stdout := strings.NewReader(`{"type":"assistant","message":{"content":"2 failed"}}` + "\n")
+	return &drainReader{r: stdout, done: e.read.Done}, &drainReader{r: strings.NewReader(""), done: e.read.Done}, nil
+}
+func (e *failingExecutor) Start() error { return nil }
+func (e *failingExecutor) Wait() error  { e.read.Wait(); return nil }
+
+// drainReader calls done once the wrapped reader is exhausted
+type drainReader struct {
+	r    io.Reader
+	done func()
+	once sync.Once
+}
+
+func (d *drainReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	if err != nil {
+		d.once.Do(d.done)
+	}
+	return n, err
+}
+
+// blockingExecutor stands in for a claude process that hangs until it's
+// stopped
+type blockingExecutor struct {
+	ctx context.Context
+}
+
+func (e *blockingExecutor) Pipes() (io.Reader, io.Reader, error) {
+	pr, pw := io.Pipe()
+	go func() {
+		<-e.ctx.Done()
+		pw.Close()
+	}()
+	return pr, strings.NewReader(""), nil
+}
+func (e *blockingExecutor) Start() error { return nil }
+func (e *blockingExecutor) Wait() error {
+	<-e.ctx.Done()
+	return e.ctx.Err()
+}
+
+func TestStopAndResetFeature(t *testing.T) {
+	prdDir := filepath.Join(t.TempDir(), "PRD")
+	os.MkdirAll(prdDir, 0755)
+	mf := manifest.New("PRD.md", "Stop Reset Test")
+	mf.Features = append(mf.Features, manifest.ManifestFeature{ID: "01", Dir: "01-search", Title: "Search", Status: "pending"})
+	mf.SetPath(filepath.Join(prdDir, "manifest.json"))
+	if err := mf.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := initialModelForManifest(prdDir)
+	m.state = state.NewProgress()
+	m.state.SetPathDirect(filepath.Join(prdDir, "progress.json"))
+	updated, _ := m.Update(loadManifest(prdDir)())
+	m = updated.(Model)
+	m.manager.SetExecutorFactory(func(ctx context.Context, dir string, args []string) runner.Executor {
+		return &blockingExecutor{ctx: ctx}
+	})
+
+	inst, err := m.manager.StartInstance("01", "sonnet", "Build search")
+	if err != nil {
+		t.Fatalf("StartInstance failed: %v", err)
+	}
+	m.state.UpdateFeature("01", "running")
+	m.manifest.UpdateFeatureStatus("01", "running")
+	m.taskList.SetItems(m.buildTaskItems())
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlX})
+	m = updated.(Model)
+
+	if m.manager.GetInstance("01") != nil {
+		t.Error("expected the instance to be cleared")
+	}
+	if f := m.state.GetFeature("01"); f.Status != "pending" || f.Attempts != 0 {
+		t.Errorf("expected the feature reset to pending with no attempts, got %s after %d", f.Status, f.Attempts)
+	}
+	if f := m.manifest.GetFeature("01"); f.Status != "pending" {
+		t.Errorf("expected manifest status pending, got %q", f.Status)
+	}
+
+	// The stopped instance finishing mustn't mark the reset feature failed
+	for range inst.OutputChannel() {
+	}
+	updated, _ = m.Update(instanceDoneMsg{featureID: "01", status: inst.GetStatus(), instance: inst})
+	m = updated.(Model)
+	if got := m.getFeatureStatus("01"); got != "pending" {
+		t.Errorf("expected 01 to stay pending once the stopped instance finished, got %q", got)
+	}
+}
+
+func TestInspectViewChecksOffTasks(t *testing.T) {
+	prdDir := filepath.Join(t.TempDir(), "PRD")
+	os.MkdirAll(filepath.Join(prdDir, "01-search"), 0755)
+	os.WriteFile(filepath.Join(prdDir, "01-search", "feature.md"), []byte("# Project\n\n## Search\n\n- [ ] Index documents\n- [ ] Rank results\n"), 0644)
+	mf := manifest.New("PRD.md", "Task Test")
+	mf.Features = append(mf.Features, manifest.ManifestFeature{ID: "01", Dir: "01-search", Title: "Search", Status: "failed"})
+	mf.SetPath(filepath.Join(prdDir, "manifest.json"))
+	if err := mf.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := initialModelForManifest(prdDir)
+	m.state = state.NewProgress()
+	m.state.SetPathDirect(filepath.Join(prdDir, "progress.json"))
+	updated, _ := m.Update(loadManifest(prdDir)())
+	m = updated.(Model)
+	updated, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
+	m = updated.(Model)
+	m.currentView = viewInspect
+	m.inspecting = "01"
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	m = updated.(Model)
+	if done := m.state.GetTaskStates("01")[parser.TaskID("Rank results")]; !done {
+		t.Fatal("expected task 2 to be checked off")
+	}
+	if got := m.renderInspectView(); !strings.Contains(got, "2 [x] Rank results") {
+		t.Errorf("expected the inspect view to show task 2 done, got:\n%s", got)
+	}
+
+	loaded, err := state.LoadProgressFromPath(filepath.Join(prdDir, "progress.json"))
+	if err != nil {
+		t.Fatalf("failed to load progress: %v", err)
+	}
+	if !loaded.GetTaskStates("01")[parser.TaskID("Rank results")] {
+		t.Error("expected the checked-off task to be saved")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	m = updated.(Model)
+	if m.state.GetTaskStates("01")[parser.TaskID("Rank results")] {
+		t.Error("expected pressing 2 again to uncheck the task")
+	}
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("7")})
+	m = updated.(Model)
+	if len(m.state.GetTaskStates("01")) != 1 {
+		t.Error("expected a key past the last task to do nothing")
+	}
+}
+
+func TestStartDueRetryWaitsForFreeSlot(t *testing.T) {
+	prdDir := filepath.Join(t.TempDir(), "PRD")
+	os.MkdirAll(prdDir, 0755)
+	mf := manifest.New("PRD.md", "Retry Slot Test")
+	mf.Features = append(mf.Features,
+		manifest.ManifestFeature{ID: "01", Dir: "01-search", Title: "Search", Status: "failed"},
+		manifest.ManifestFeature{ID: "02", Dir: "02-docs", Title: "Docs", Status: "running"},
+	)
+	mf.SetPath(filepath.Join(prdDir, "manifest.json"))
+	if err := mf.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := initialModelForManifest(prdDir)
+	m.state = state.NewProgress()
+	m.state.SetPathDirect(filepath.Join(prdDir, "progress.json"))
+	updated, _ := m.Update(loadManifest(prdDir)())
+	m = updated.(Model)
+	config := runner.DefaultConfig()
+	config.MaxConcurrent = 1
+	m.manager.SetConfig(config)
+	m.manager.SetExecutorFactory(func(ctx context.Context, dir string, args []string) runner.Executor {
+		return &blockingExecutor{ctx: ctx}
+	})
+	running, err := m.manager.StartInstance("02", "sonnet", "Write docs")
+	if err != nil {
+		t.Fatalf("StartInstance failed: %v", err)
+	}
+	defer m.manager.StopAll()
+
+	m.autoMode = true
+	cmd := m.scheduleRetry(*m.findFeature("01"), 0)
+	if cmd == nil {
+		t.Fatal("expected a command to schedule the retry")
+	}
+	updated, cmd = m.Update(retryDueMsg{feature: *m.findFeature("01")})
+	m = updated.(Model)
+	if !m.retryWaiting["01"] || cmd == nil {
+		t.Fatal("expected the retry to keep waiting while the only slot is taken")
+	}
+	if m.manager.GetInstance("01") != nil {
+		t.Error("expected the retry not to start over the concurrency limit")
+	}
+
+	m.manager.StopInstance("02")
+	for range running.OutputChannel() {
+	}
+	updated, cmd = m.Update(retryDueMsg{feature: *m.findFeature("01")})
+	m = updated.(Model)
+	if m.retryWaiting["01"] || cmd == nil {
+		t.Error("expected the retry to start once the slot was freed")
+	}
+}
+
+// failFeatureWithOnFailure runs feature 01 to a failure with no retries left
+// under the given On-Failure: action and returns the model once it's handled
+func failFeatureWithOnFailure(t *testing.T, action string) Model {
+	t.Helper()
+	prdDir := filepath.Join(t.TempDir(), "PRD")
+	os.MkdirAll(prdDir, 0755)
+	mf := manifest.New("PRD.md", "On-Failure Test")
+	mf.Features = append(mf.Features,
+		manifest.ManifestFeature{ID: "01", Dir: "01-search", Title: "Search", Status: "pending", OnFailure: action},
+		manifest.ManifestFeature{ID: "02", Dir: "02-docs", Title: "Docs", Status: "pending"},
+	)
+	mf.SetPath(filepath.Join(prdDir, "manifest.json"))
+	if err := mf.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := initialModelForManifest(prdDir)
+	m.state = state.NewProgress()
+	m.state.SetPathDirect(filepath.Join(prdDir, "progress.json"))
+	updated, _ := m.Update(loadManifest(prdDir)())
+	m = updated.(Model)
+	m.manager.SetExecutorFactory(func(ctx context.Context, dir string, args []string) runner.Executor {
+		return &failingExecutor{}
+	})
+
+	inst, err := m.manager.StartInstance("01", "opus", "Build search")
+	if err != nil {
+		t.Fatalf("StartInstance failed: %v", err)
+	}
+	for range inst.OutputChannel() {
+	}
+	// Use up every attempt so no retry is left
+	m.autoMode = true
+	for m.state.CanRetry("01") {
+		m.state.UpdateFeature("01", "running")
+	}
+
+	updated, _ = m.Update(instanceDoneMsg{featureID: "01", status: "failed"})
+	return updated.(Model)
+}
+
+func TestOnFailureActions(t *testing.T) {
+	t.Run("skip", func(t *testing.T) {
+		m := failFeatureWithOnFailure(t, parser.OnFailureSkip)
+		if got := m.getFeatureStatus("01"); got != "skipped" {
+			t.Errorf("expected 01 skipped, got %q", got)
+		}
+		if reason := m.state.GetSkipReason("01"); !strings.Contains(reason, "2 tests failed") {
+			t.Errorf("expected the failure in the skip reason, got %q", reason)
+		}
+		if f := m.manifest.GetFeature("01"); f.Status != manifest.StatusSkipped {
+			t.Errorf("expected manifest status skipped, got %q", f.Status)
+		}
+		if !m.autoMode {
+			t.Error("expected auto mode to carry on")
+		}
+	})
+
+	t.Run("abort", func(t *testing.T) {
+		m := failFeatureWithOnFailure(t, parser.OnFailureAbort)
+		if got := m.getFeatureStatus("01"); got != "failed" {
+			t.Errorf("expected 01 failed, got %q", got)
+		}
+		if m.autoMode {
+			t.Error("expected auto mode to stop")
+		}
+		if !strings.Contains(m.statusMsg, "Run aborted") {
+			t.Errorf("expected an abort status, got %q", m.statusMsg)
+		}
+	})
+
+	t.Run("continue", func(t *testing.T) {
+		m := failFeatureWithOnFailure(t, parser.OnFailureContinue)
+		if got := m.getFeatureStatus("01"); got != "failed" {
+			t.Errorf("expected 01 failed, got %q", got)
+		}
+		if f := m.manifest.GetFeature("01"); f.Status != "failed" {
+			t.Errorf("expected manifest status failed, got %q", f.Status)
+		}
+		if !m.autoMode {
+			t.Error("expected auto mode to carry on")
+		}
+	})
+}
+
+func TestApplySpawnLimits(t *testing.T) {
+	spawnPastLimit := func(opts Options, children int) error {
+		handler := rlm.NewSpawnHandler(rlm.NewManager(), nil)
+		applySpawnLimits(handler, opts)
+		handler.RegisterRootFeature("01", "Parent").SetStatus("running")
+		for i := 0; i < children; i++ {
+			if _, err := handler.SpawnChild("01", &rlm.SpawnRequest{Title: fmt.Sprintf("Child %d", i)}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := spawnPastLimit(Options{MaxSpawnsPerRoot: 2}, 3); !errors.Is(err, rlm.ErrMaxSpawnsExceeded) {
+		t.Errorf("expected the third spawn to be rejected, got %v", err)
+	}
+	if err := spawnPastLimit(Options{}, rlm.DefaultMaxSpawnsPerRoot+1); !errors.Is(err, rlm.ErrMaxSpawnsExceeded) {
+		t.Errorf("expected the default cap to apply, got %v", err)
+	}
+	if err := spawnPastLimit(Options{MaxSpawnsPerRoot: -1, MaxTotalSpawns: -1}, rlm.DefaultMaxTotalSpawns+1); err != nil {
+		t.Errorf("expected no cap, got %v", err)
+	}
+}
+
+func TestAutoModeWaitsForReservedBudget(t *testing.T) {
+	prdDir := filepath.Join(t.TempDir(), "PRD")
+	os.MkdirAll(prdDir, 0755)
+	mf := manifest.New("PRD.md", "Budget Wait Test")
+	mf.BudgetUSD = 5
+	mf.Features = append(mf.Features,
+		manifest.ManifestFeature{ID: "01", Dir: "01-search", Title: "Search", Status: "running"},
+		manifest.ManifestFeature{ID: "02", Dir: "02-docs", Title: "Docs", Status: "pending"},
+	)
+	mf.SetPath(filepath.Join(prdDir, "manifest.json"))
+	if err := mf.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := initialModelForManifest(prdDir)
+	m.state = state.NewProgress()
+	updated, _ := m.Update(loadManifest(prdDir)())
+	m = updated.(Model)
+	m.autoMode = true
+
+	refused := fmt.Errorf("%w: $4.00 committed + $2.00 reserved would exceed $5.00", runner.ErrBudgetReserved)
+	updated, _ = m.Update(instanceStartedMsg{featureID: "02", err: refused})
+	m = updated.(Model)
+	if m.budgetWaiting != "02" {
+		t.Fatalf("expected feature 02 to wait for budget, got %q", m.budgetWaiting)
+	}
+	if !strings.Contains(m.statusMsg, "Docs waits for a running feature to finish") {
+		t.Errorf("expected a waiting status, got %q", m.statusMsg)
+	}
+	if status := m.getFeatureStatus("02"); status != "pending" {
+		t.Errorf("expected feature 02 to stay pending, got %q", status)
+	}
+
+	updated, _ = m.autoStartNext()
+	if got := updated.(Model).statusMsg; strings.HasPrefix(got, "Starting") {
+		t.Errorf("expected no start attempt while waiting, got status %q", got)
+	}
+
+	updated, _ = m.Update(instanceDoneMsg{featureID: "01", status: "completed"})
+	m = updated.(Model)
+	if m.budgetWaiting != "" {
+		t.Errorf("expected a finished feature to end the wait, still waiting on %q", m.budgetWaiting)
+	}
+}
+
+func TestBuildTaskItemsMarksBlockedFeatures(t *testing.T) {
+	prdDir := filepath.Join(t.TempDir(), "PRD")
+	os.MkdirAll(prdDir, 0755)
+	mf := manifest.New("PRD.md", "Blocked Test")
+	mf.Features = append(mf.Features,
+		manifest.ManifestFeature{ID: "01", Dir: "01-base", Title: "Base", Status: "failed"},
+		manifest.ManifestFeature{ID: "02", Dir: "02-next", Title: "Next", Status: "pending", DependsOn: []string{"01"}},
+		manifest.ManifestFeature{ID: "03", Dir: "03-free", Title: "Free", Status: "pending"},
+	)
+	mf.SetPath(filepath.Join(prdDir, "manifest.json"))
+	if err := mf.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := initialModelForManifest(prdDir)
+	updated, _ := m.Update(loadManifest(prdDir)())
+	m = updated.(Model)
+
+	statuses := make(map[string]string)
+	for _, item := range m.buildTaskItems() {
+		statuses[item.ID] = item.Status
+	}
+	if statuses["02"] != "blocked" {
+		t.Errorf("expected 02 blocked on its failed dependency, got %q", statuses["02"])
+	}
+	if statuses["03"] != "pending" {
+		t.Errorf("expected 03 pending, got %q", statuses["03"])
+	}
+}
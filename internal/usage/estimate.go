@@ -0,0 +1,19 @@
+package usage
+
+// CharsPerToken is the heuristic ratio used to approximate token counts from
+// text when no tokenizer is available
+const CharsPerToken = 4
+
+// EstimateTokens approximates the token count of text using CharsPerToken,
+// rounding up so any non-empty text counts as at least one token
+func EstimateTokens(text string) int64 {
+	return int64((len(text) + CharsPerToken - 1) / CharsPerToken)
+}
+
+// EstimateInputCost projects the input cost of sending a prompt to a model.
+// It covers only the initial prompt, not tool results or output, so it is a
+// lower bound on what a feature will actually cost.
+func EstimateInputCost(prompt string, model string) (tokens int64, cost float64) {
+	tokens = EstimateTokens(prompt)
+	return tokens, EstimateCost(tokens, 0, 0, 0, model)
+}
@@ -0,0 +1,61 @@
+package usage
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected int64
+	}{
+		{"", 0},
+		{"a", 1},
+		{"abcd", 1},
+		{"abcde", 2},
+		{strings.Repeat("x", 4000), 1000},
+	}
+
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.text); got != tt.expected {
+			t.Errorf("EstimateTokens(len %d) = %d, expected %d", len(tt.text), got, tt.expected)
+		}
+	}
+}
+
+func TestEstimateInputCost(t *testing.T) {
+	// 4M chars ≈ 1M tokens, so the cost equals the per-million input price
+	prompt := strings.Repeat("x", 4_000_000)
+
+	tests := []struct {
+		model    string
+		expected float64
+	}{
+		{"haiku", HaikuInputPrice},
+		{"sonnet", SonnetInputPrice},
+		{"opus", OpusInputPrice},
+	}
+
+	for _, tt := range tests {
+		tokens, cost := EstimateInputCost(prompt, tt.model)
+		if tokens != 1_000_000 {
+			t.Errorf("%s: expected 1000000 tokens, got %d", tt.model, tokens)
+		}
+		if math.Abs(cost-tt.expected) > 0.0001 {
+			t.Errorf("%s: expected cost $%.4f, got $%.4f", tt.model, tt.expected, cost)
+		}
+	}
+}
+
+func TestEstimateInputCostSmallPrompt(t *testing.T) {
+	// 8000 chars ≈ 2000 tokens at $3/M = $0.006
+	tokens, cost := EstimateInputCost(strings.Repeat("x", 8000), "sonnet")
+	if tokens != 2000 {
+		t.Errorf("expected 2000 tokens, got %d", tokens)
+	}
+	if math.Abs(cost-0.006) > 0.000001 {
+		t.Errorf("expected cost $0.006, got $%f", cost)
+	}
+}
@@ -6,55 +6,75 @@ const (
 	// Claude 3.5 Sonnet (claude-3-5-sonnet-20241022)
 	SonnetInputPrice       = 3.00   // $3.00 per million input tokens
 	SonnetOutputPrice      = 15.00  // $15.00 per million output tokens
-	SonnetCacheWritePrice  = 3.75   // $3.75 per million cache write tokens
+	SonnetCacheWritePrice  = 3.75   // $3.75 per million cache write tokens (5 minute TTL)
+	SonnetCacheWrite1hPrice = 6.00  // $6.00 per million cache write tokens (1 hour TTL)
 	SonnetCacheReadPrice   = 0.30   // $0.30 per million cache read tokens
 
 	// Claude 3.5 Haiku (claude-3-5-haiku-20241022)
 	HaikuInputPrice        = 0.80   // $0.80 per million input tokens
 	HaikuOutputPrice       = 4.00   // $4.00 per million output tokens
-	HaikuCacheWritePrice   = 1.00   // $1.00 per million cache write tokens
+	HaikuCacheWritePrice   = 1.00   // $1.00 per million cache write tokens (5 minute TTL)
+	HaikuCacheWrite1hPrice = 1.60   // $1.60 per million cache write tokens (1 hour TTL)
 	HaikuCacheReadPrice    = 0.08   // $0.08 per million cache read tokens
 
 	// Claude 3 Opus (claude-3-opus-20240229)
 	OpusInputPrice         = 15.00  // $15.00 per million input tokens
 	OpusOutputPrice        = 75.00  // $75.00 per million output tokens
-	OpusCacheWritePrice    = 18.75  // $18.75 per million cache write tokens
+	OpusCacheWritePrice    = 18.75  // $18.75 per million cache write tokens (5 minute TTL)
+	OpusCacheWrite1hPrice  = 30.00  // $30.00 per million cache write tokens (1 hour TTL)
 	OpusCacheReadPrice     = 1.50   // $1.50 per million cache read tokens
 )
 
-// ModelPricing holds pricing for a specific model
+// ModelPricing holds pricing for a specific model. CacheWritePrice is the
+// default 5 minute cache tier; CacheWrite1hPrice applies to 1 hour writes.
 type ModelPricing struct {
-	InputPrice      float64
-	OutputPrice     float64
-	CacheWritePrice float64
-	CacheReadPrice  float64
+	InputPrice        float64
+	OutputPrice       float64
+	CacheWritePrice   float64
+	CacheWrite1hPrice float64
+	CacheReadPrice    float64
 }
 
-// GetPricing returns pricing for a model name
+// GetPricing returns pricing for a model name. Models without pricing of
+// their own are priced as sonnet.
 func GetPricing(model string) ModelPricing {
+	pricing, _ := LookupPricing(model)
+	return pricing
+}
+
+// LookupPricing returns pricing for a model name, and whether the model has
+// pricing of its own rather than sonnet's as a fallback
+func LookupPricing(model string) (ModelPricing, bool) {
 	switch model {
 	case "haiku", "claude-3-5-haiku-20241022", "claude-3-haiku":
 		return ModelPricing{
-			InputPrice:      HaikuInputPrice,
-			OutputPrice:     HaikuOutputPrice,
-			CacheWritePrice: HaikuCacheWritePrice,
-			CacheReadPrice:  HaikuCacheReadPrice,
-		}
+			InputPrice:        HaikuInputPrice,
+			OutputPrice:       HaikuOutputPrice,
+			CacheWritePrice:   HaikuCacheWritePrice,
+			CacheWrite1hPrice: HaikuCacheWrite1hPrice,
+			CacheReadPrice:    HaikuCacheReadPrice,
+		}, true
 	case "opus", "claude-3-opus-20240229", "claude-3-opus", "claude-opus-4-5-20251101":
 		return ModelPricing{
-			InputPrice:      OpusInputPrice,
-			OutputPrice:     OpusOutputPrice,
-			CacheWritePrice: OpusCacheWritePrice,
-			CacheReadPrice:  OpusCacheReadPrice,
-		}
-	default: // sonnet is default
-		return ModelPricing{
-			InputPrice:      SonnetInputPrice,
-			OutputPrice:     SonnetOutputPrice,
-			CacheWritePrice: SonnetCacheWritePrice,
-			CacheReadPrice:  SonnetCacheReadPrice,
-		}
+			InputPrice:        OpusInputPrice,
+			OutputPrice:       OpusOutputPrice,
+			CacheWritePrice:   OpusCacheWritePrice,
+			CacheWrite1hPrice: OpusCacheWrite1hPrice,
+			CacheReadPrice:    OpusCacheReadPrice,
+		}, true
+	}
+	sonnet := ModelPricing{
+		InputPrice:        SonnetInputPrice,
+		OutputPrice:       SonnetOutputPrice,
+		CacheWritePrice:   SonnetCacheWritePrice,
+		CacheWrite1hPrice: SonnetCacheWrite1hPrice,
+		CacheReadPrice:    SonnetCacheReadPrice,
 	}
+	switch model {
+	case "sonnet", "claude-3-5-sonnet-20241022":
+		return sonnet, true
+	}
+	return sonnet, false
 }
 
 // CalculateCost calculates the cost based on token usage and model
@@ -63,26 +83,32 @@ func CalculateCost(u *TokenUsage, model string) float64 {
 		return 0
 	}
 
-	pricing := GetPricing(model)
-
 	u.mu.RLock()
 	defer u.mu.RUnlock()
 
-	inputCost := float64(u.InputTokens) * pricing.InputPrice / 1_000_000
-	outputCost := float64(u.OutputTokens) * pricing.OutputPrice / 1_000_000
-	cacheWriteCost := float64(u.CacheWriteTokens) * pricing.CacheWritePrice / 1_000_000
-	cacheReadCost := float64(u.CacheReadTokens) * pricing.CacheReadPrice / 1_000_000
-
-	return inputCost + outputCost + cacheWriteCost + cacheReadCost
+	return EstimateCostTiered(u.InputTokens, u.OutputTokens, u.CacheWriteTokens, u.CacheWrite1hTokens, u.CacheReadTokens, model)
 }
 
 // EstimateCost calculates cost from token counts and model (without needing TokenUsage struct)
 func EstimateCost(input, output, cacheWrite, cacheRead int64, model string) float64 {
+	return EstimateCostTiered(input, output, cacheWrite, 0, cacheRead, model)
+}
+
+// EstimateCostTiered is EstimateCost with cache writes split by TTL.
+// cacheWrite is the total written to the cache, of which cacheWrite1h went to
+// the 1 hour tier; the rest is priced as 5 minute writes.
+func EstimateCostTiered(input, output, cacheWrite, cacheWrite1h, cacheRead int64, model string) float64 {
 	pricing := GetPricing(model)
 
+	if cacheWrite1h > cacheWrite {
+		cacheWrite1h = cacheWrite
+	}
+	cacheWrite5m := cacheWrite - cacheWrite1h
+
 	inputCost := float64(input) * pricing.InputPrice / 1_000_000
 	outputCost := float64(output) * pricing.OutputPrice / 1_000_000
-	cacheWriteCost := float64(cacheWrite) * pricing.CacheWritePrice / 1_000_000
+	cacheWriteCost := float64(cacheWrite5m)*pricing.CacheWritePrice/1_000_000 +
+		float64(cacheWrite1h)*pricing.CacheWrite1hPrice/1_000_000
 	cacheReadCost := float64(cacheRead) * pricing.CacheReadPrice / 1_000_000
 
 	return inputCost + outputCost + cacheWriteCost + cacheReadCost
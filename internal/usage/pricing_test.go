@@ -96,6 +96,42 @@ func TestCalculateCostWithCache(t *testing.T) {
 	}
 }
 
+func TestCalculateCostWithTieredCache(t *testing.T) {
+	u := New()
+	u.ParseLine(`{"type":"assistant","usage":{"input_tokens":0,"output_tokens":0,"cache_creation_input_tokens":3000000,"cache_creation":{"ephemeral_5m_input_tokens":1000000,"ephemeral_1h_input_tokens":2000000}}}`)
+
+	tests := []struct {
+		model    string
+		expected float64
+	}{
+		{"sonnet", SonnetCacheWritePrice + 2*SonnetCacheWrite1hPrice}, // $3.75 + $12 = $15.75
+		{"haiku", HaikuCacheWritePrice + 2*HaikuCacheWrite1hPrice},    // $1 + $3.20 = $4.20
+		{"opus", OpusCacheWritePrice + 2*OpusCacheWrite1hPrice},       // $18.75 + $60 = $78.75
+	}
+	for _, tc := range tests {
+		if cost := CalculateCost(u, tc.model); math.Abs(cost-tc.expected) > 0.001 {
+			t.Errorf("%s: expected cost %f, got %f", tc.model, tc.expected, cost)
+		}
+		if cost := u.GetEstimatedCost(tc.model); math.Abs(cost-tc.expected) > 0.001 {
+			t.Errorf("%s: expected estimated cost %f, got %f", tc.model, tc.expected, cost)
+		}
+	}
+}
+
+func TestEstimateCostTieredUntieredWritesUse5mPrice(t *testing.T) {
+	untiered := EstimateCost(0, 0, 1000000, 0, "sonnet")
+	tiered := EstimateCostTiered(0, 0, 1000000, 0, 0, "sonnet")
+	if untiered != tiered || math.Abs(tiered-SonnetCacheWritePrice) > 0.0001 {
+		t.Errorf("expected both costs %f, got %f and %f", SonnetCacheWritePrice, untiered, tiered)
+	}
+
+	// 1h tokens are capped at the aggregate
+	capped := EstimateCostTiered(0, 0, 1000000, 5000000, 0, "sonnet")
+	if math.Abs(capped-SonnetCacheWrite1hPrice) > 0.0001 {
+		t.Errorf("expected capped cost %f, got %f", SonnetCacheWrite1hPrice, capped)
+	}
+}
+
 func TestCalculateCostNil(t *testing.T) {
 	cost := CalculateCost(nil, "sonnet")
 	if cost != 0 {
@@ -208,6 +244,21 @@ func TestTokenUsageCompactWithCostEmpty(t *testing.T) {
 	}
 }
 
+func TestLookupPricing(t *testing.T) {
+	for _, model := range []string{"sonnet", "haiku", "opus", "claude-3-5-haiku-20241022"} {
+		if _, priced := LookupPricing(model); !priced {
+			t.Errorf("expected %q to have pricing", model)
+		}
+	}
+	pricing, priced := LookupPricing("gpt")
+	if priced {
+		t.Error("expected an unknown model to have no pricing of its own")
+	}
+	if pricing != GetPricing("sonnet") {
+		t.Errorf("expected an unknown model priced as sonnet, got %+v", pricing)
+	}
+}
+
 func TestPricingConstants(t *testing.T) {
 	if SonnetInputPrice <= 0 {
 		t.Error("SonnetInputPrice should be positive")
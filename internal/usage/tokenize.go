@@ -0,0 +1,112 @@
+package usage
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// wordTokenChars is the longest letter run counted as a single token;
+	// BPE vocabularies hold whole tokens for most common words
+	wordTokenChars = 10
+	// longWordCharsPerToken splits letter runs beyond wordTokenChars, such
+	// as rare words or identifiers, into pieces of about this many letters
+	longWordCharsPerToken = 4
+	// digitsPerToken matches tokenizers that split numbers into groups of
+	// up to three digits
+	digitsPerToken = 3
+	// symbolsPerToken is the average length of punctuation pieces such as
+	// "**", "##" or ":="
+	symbolsPerToken = 2
+)
+
+// CountTokens approximates the number of tokens a BPE tokenizer splits text
+// into. It mirrors BPE pre-tokenization rather than dividing by a fixed
+// ratio: words (split at camelCase humps) cost one token with their leading
+// space, long words, numbers and punctuation runs cost a token per few
+// characters, each whitespace run costs one token and non-ASCII characters
+// cost one or two. It tracks real token counts far more closely than
+// EstimateTokens on code, paths and markdown, and errs towards
+// overcounting so text trimmed to a budget stays within it.
+func CountTokens(text string) int64 {
+	var tokens int64
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case r >= utf8.RuneSelf:
+			// Multi-byte characters are rarely merged with their neighbours
+			tokens += int64((size + 1) / 2)
+			i += size
+		case r == ' ' && i+1 < len(text) && isWordByte(text[i+1]):
+			// A single space is part of the word that follows it
+			i++
+		case isSpaceByte(text[i]):
+			j := i + 1
+			for j < len(text) && isSpaceByte(text[j]) {
+				j++
+			}
+			// Leave a final space to join the word that follows
+			if j-i > 1 && text[j-1] == ' ' && j < len(text) && isWordByte(text[j]) {
+				j--
+			}
+			tokens++
+			i = j
+		case isLetterByte(text[i]):
+			j := i + 1
+			for j < len(text) && isLetterByte(text[j]) && !isHump(text[j-1], text[j]) {
+				j++
+			}
+			tokens += wordTokens(j - i)
+			i = j
+		case isDigitByte(text[i]):
+			j := i + 1
+			for j < len(text) && isDigitByte(text[j]) {
+				j++
+			}
+			tokens += int64((j - i + digitsPerToken - 1) / digitsPerToken)
+			i = j
+		default:
+			j := i + 1
+			for j < len(text) && text[j] < utf8.RuneSelf && isSymbolByte(text[j]) {
+				j++
+			}
+			tokens += int64((j - i + symbolsPerToken - 1) / symbolsPerToken)
+			i = j
+		}
+	}
+	return tokens
+}
+
+// wordTokens returns the tokens counted for a run of n letters
+func wordTokens(n int) int64 {
+	if n <= wordTokenChars {
+		return 1
+	}
+	return 1 + int64((n-wordTokenChars+longWordCharsPerToken-1)/longWordCharsPerToken)
+}
+
+// isHump reports whether a letter run should split between prev and next, as
+// in camelCase identifiers
+func isHump(prev, next byte) bool {
+	return prev >= 'a' && prev <= 'z' && next >= 'A' && next <= 'Z'
+}
+
+func isLetterByte(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
+
+func isDigitByte(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+func isWordByte(b byte) bool {
+	return isLetterByte(b) || isDigitByte(b)
+}
+
+func isSpaceByte(b byte) bool {
+	return b < utf8.RuneSelf && unicode.IsSpace(rune(b))
+}
+
+func isSymbolByte(b byte) bool {
+	return !isWordByte(b) && !isSpaceByte(b)
+}
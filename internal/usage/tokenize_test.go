@@ -0,0 +1,52 @@
+package usage
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCountTokens(t *testing.T) {
+	// Expected counts are what a BPE tokenizer produces, give or take the
+	// heuristic's bias towards overcounting
+	tests := []struct {
+		name     string
+		text     string
+		expected int64
+	}{
+		{"empty", "", 0},
+		{"word", "hello", 1},
+		{"words share leading spaces", "the quick brown fox", 4},
+		{"camelCase splits at humps", "parseOutputLine", 3},
+		{"long words split", "internationalization", 4},
+		{"digits group in threes", "1234567", 3},
+		{"punctuation runs", "**Status:**", 4},
+		{"newline runs", "a\n\n\nb", 3},
+		{"path", "internal/runner/runner.go", 7},
+		{"non-ASCII", "完了", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CountTokens(tt.text); got != tt.expected {
+				t.Errorf("CountTokens(%q) = %d, expected %d", tt.text, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCountTokensCalibration(t *testing.T) {
+	// English prose runs at roughly 1.3 tokens per word with BPE; the flat
+	// character ratio is close here but drifts on code and markdown
+	prose := strings.Repeat("The feature should retry the migration once the lock has been released. ", 50)
+	words := int64(len(strings.Fields(prose)))
+	got := CountTokens(prose)
+	if got < words || got > words*3/2 {
+		t.Errorf("prose: %d tokens for %d words, expected between 1 and 1.5 per word", got, words)
+	}
+
+	// Dense code has more tokens per character than prose
+	code := strings.Repeat("if err := m.Save(); err != nil {\n\treturn fmt.Errorf(\"save: %w\", err)\n}\n", 20)
+	if CountTokens(code) <= EstimateTokens(code) {
+		t.Errorf("code: expected more tokens than the character heuristic's %d, got %d", EstimateTokens(code), CountTokens(code))
+	}
+}
@@ -10,12 +10,19 @@ import (
 type TokenUsage struct {
 	mu sync.RWMutex
 
-	InputTokens      int64   `json:"input_tokens"`
-	OutputTokens     int64   `json:"output_tokens"`
-	CacheReadTokens  int64   `json:"cache_read_tokens,omitempty"`
-	CacheWriteTokens int64   `json:"cache_write_tokens,omitempty"`
-	TotalTokens      int64   `json:"total_tokens"`
-	CostUSD          float64 `json:"cost_usd,omitempty"`
+	InputTokens      int64 `json:"input_tokens"`
+	OutputTokens     int64 `json:"output_tokens"`
+	CacheReadTokens  int64 `json:"cache_read_tokens,omitempty"`
+	CacheWriteTokens int64 `json:"cache_write_tokens,omitempty"`
+	// CacheWrite1hTokens is the part of CacheWriteTokens written to the
+	// 1 hour cache tier; the rest went to the default 5 minute tier
+	CacheWrite1hTokens int64   `json:"cache_write_1h_tokens,omitempty"`
+	TotalTokens        int64   `json:"total_tokens"`
+	CostUSD            float64 `json:"cost_usd,omitempty"`
+
+	// streamed is the usage counted so far for the message being streamed
+	// via message_start/message_delta events
+	streamed StreamUsage
 }
 
 // StreamUsage represents usage data from Claude Code stream-json format
@@ -24,6 +31,32 @@ type StreamUsage struct {
 	OutputTokens     int64 `json:"output_tokens,omitempty"`
 	CacheReadTokens  int64 `json:"cache_read_input_tokens,omitempty"`
 	CacheWriteTokens int64 `json:"cache_creation_input_tokens,omitempty"`
+
+	// CacheCreation splits CacheWriteTokens by cache TTL in newer formats
+	CacheCreation *CacheCreationUsage `json:"cache_creation,omitempty"`
+}
+
+// CacheCreationUsage is the per-tier breakdown of cache writes
+type CacheCreationUsage struct {
+	Ephemeral5mTokens int64 `json:"ephemeral_5m_input_tokens,omitempty"`
+	Ephemeral1hTokens int64 `json:"ephemeral_1h_input_tokens,omitempty"`
+}
+
+// cacheWriteTokens returns the total cache writes, falling back to the sum of
+// the tiers when only the breakdown is reported
+func (su *StreamUsage) cacheWriteTokens() int64 {
+	if su.CacheWriteTokens == 0 && su.CacheCreation != nil {
+		return su.CacheCreation.Ephemeral5mTokens + su.CacheCreation.Ephemeral1hTokens
+	}
+	return su.CacheWriteTokens
+}
+
+// cacheWrite1hTokens returns the cache writes made to the 1 hour tier
+func (su *StreamUsage) cacheWrite1hTokens() int64 {
+	if su.CacheCreation == nil {
+		return 0
+	}
+	return su.CacheCreation.Ephemeral1hTokens
 }
 
 // StreamMessage represents a stream-json message that may contain usage data
@@ -32,6 +65,7 @@ type StreamMessage struct {
 	Usage   *StreamUsage    `json:"usage,omitempty"`
 	CostUSD float64         `json:"cost_usd,omitempty"`
 	Message json.RawMessage `json:"message,omitempty"`
+	Event   json.RawMessage `json:"event,omitempty"`
 }
 
 // NestedMessage represents the message block that may contain usage
@@ -62,6 +96,29 @@ func (t *TokenUsage) ParseLine(line string) bool {
 func (t *TokenUsage) parseMessage(msg *StreamMessage) bool {
 	var updated bool
 
+	switch msg.Type {
+	case "stream_event":
+		// Raw API events wrapped by the CLI's partial message output
+		var event StreamMessage
+		if err := json.Unmarshal(msg.Event, &event); err != nil {
+			return false
+		}
+		return t.parseMessage(&event)
+	case "message_start":
+		var nested NestedMessage
+		if err := json.Unmarshal(msg.Message, &nested); err != nil || nested.Usage == nil {
+			return false
+		}
+		t.startStream(nested.Usage)
+		return true
+	case "message_delta":
+		if msg.Usage == nil {
+			return false
+		}
+		t.addStreamDelta(msg.Usage)
+		return true
+	}
+
 	// Check top-level usage field
 	if msg.Usage != nil {
 		t.addUsage(msg.Usage, msg.CostUSD)
@@ -96,11 +153,54 @@ func (t *TokenUsage) addUsage(su *StreamUsage, cost float64) {
 	t.InputTokens += su.InputTokens
 	t.OutputTokens += su.OutputTokens
 	t.CacheReadTokens += su.CacheReadTokens
-	t.CacheWriteTokens += su.CacheWriteTokens
+	t.CacheWriteTokens += su.cacheWriteTokens()
+	t.CacheWrite1hTokens += su.cacheWrite1hTokens()
 	t.TotalTokens = t.InputTokens + t.OutputTokens
 	t.CostUSD += cost
 }
 
+// startStream counts the usage reported when a streamed message starts and
+// records it as the baseline for the message's deltas
+func (t *TokenUsage) startStream(su *StreamUsage) {
+	t.addUsage(su, 0)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.streamed = StreamUsage{
+		InputTokens:      su.InputTokens,
+		OutputTokens:     su.OutputTokens,
+		CacheReadTokens:  su.CacheReadTokens,
+		CacheWriteTokens: su.cacheWriteTokens(),
+		CacheCreation:    &CacheCreationUsage{Ephemeral1hTokens: su.cacheWrite1hTokens()},
+	}
+}
+
+// addStreamDelta counts a message_delta's usage. Delta usage is cumulative
+// for the message, so only the growth since the last event is added.
+func (t *TokenUsage) addStreamDelta(su *StreamUsage) {
+	growth := func(current int64, counted *int64) int64 {
+		if current <= *counted {
+			return 0
+		}
+		diff := current - *counted
+		*counted = current
+		return diff
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.InputTokens += growth(su.InputTokens, &t.streamed.InputTokens)
+	t.OutputTokens += growth(su.OutputTokens, &t.streamed.OutputTokens)
+	t.CacheReadTokens += growth(su.CacheReadTokens, &t.streamed.CacheReadTokens)
+	t.CacheWriteTokens += growth(su.cacheWriteTokens(), &t.streamed.CacheWriteTokens)
+	if t.streamed.CacheCreation == nil {
+		t.streamed.CacheCreation = &CacheCreationUsage{}
+	}
+	t.CacheWrite1hTokens += growth(su.cacheWrite1hTokens(), &t.streamed.CacheCreation.Ephemeral1hTokens)
+	t.TotalTokens = t.InputTokens + t.OutputTokens
+}
+
 // Add merges another TokenUsage into this one
 func (t *TokenUsage) Add(other *TokenUsage) {
 	if other == nil {
@@ -117,6 +217,7 @@ func (t *TokenUsage) Add(other *TokenUsage) {
 	t.OutputTokens += other.OutputTokens
 	t.CacheReadTokens += other.CacheReadTokens
 	t.CacheWriteTokens += other.CacheWriteTokens
+	t.CacheWrite1hTokens += other.CacheWrite1hTokens
 	t.TotalTokens += other.TotalTokens
 	t.CostUSD += other.CostUSD
 }
@@ -127,12 +228,13 @@ func (t *TokenUsage) Snapshot() TokenUsage {
 	defer t.mu.RUnlock()
 
 	return TokenUsage{
-		InputTokens:      t.InputTokens,
-		OutputTokens:     t.OutputTokens,
-		CacheReadTokens:  t.CacheReadTokens,
-		CacheWriteTokens: t.CacheWriteTokens,
-		TotalTokens:      t.TotalTokens,
-		CostUSD:          t.CostUSD,
+		InputTokens:        t.InputTokens,
+		OutputTokens:       t.OutputTokens,
+		CacheReadTokens:    t.CacheReadTokens,
+		CacheWriteTokens:   t.CacheWriteTokens,
+		CacheWrite1hTokens: t.CacheWrite1hTokens,
+		TotalTokens:        t.TotalTokens,
+		CostUSD:            t.CostUSD,
 	}
 }
 
@@ -145,8 +247,10 @@ func (t *TokenUsage) Reset() {
 	t.OutputTokens = 0
 	t.CacheReadTokens = 0
 	t.CacheWriteTokens = 0
+	t.CacheWrite1hTokens = 0
 	t.TotalTokens = 0
 	t.CostUSD = 0
+	t.streamed = StreamUsage{}
 }
 
 // IsEmpty returns true if no tokens have been recorded
@@ -227,7 +331,7 @@ func (t *TokenUsage) GetEstimatedCost(model string) float64 {
 	if t.CostUSD > 0 {
 		return t.CostUSD
 	}
-	return EstimateCost(t.InputTokens, t.OutputTokens, t.CacheWriteTokens, t.CacheReadTokens, model)
+	return EstimateCostTiered(t.InputTokens, t.OutputTokens, t.CacheWriteTokens, t.CacheWrite1hTokens, t.CacheReadTokens, model)
 }
 
 // CompactWithCost returns compact string with cost
@@ -241,7 +345,7 @@ func (t *TokenUsage) CompactWithCost(model string) string {
 
 	cost := t.CostUSD
 	if cost == 0 {
-		cost = EstimateCost(t.InputTokens, t.OutputTokens, t.CacheWriteTokens, t.CacheReadTokens, model)
+		cost = EstimateCostTiered(t.InputTokens, t.OutputTokens, t.CacheWriteTokens, t.CacheWrite1hTokens, t.CacheReadTokens, model)
 	}
 
 	if cost > 0 {